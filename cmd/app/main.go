@@ -1,12 +1,17 @@
+// Command app runs the Alchemorsel API server. It is the only server entrypoint: it loads the
+// configuration and DeepSeek credentials, connects to Postgres and Redis, runs migrations and
+// serves the router from routes.SetupRouterWithRedis. The Dockerfile builds this package.
 package main
 
 import (
+	"errors"
 	"log"
 	"os"
 	"time"
 
 	"github.com/pageza/alchemorsel-v1/internal/config"
 	"github.com/pageza/alchemorsel-v1/internal/db"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
 	"github.com/pageza/alchemorsel-v1/internal/logging"
 	"github.com/pageza/alchemorsel-v1/internal/migrations"
 	"github.com/pageza/alchemorsel-v1/internal/routes"
@@ -17,13 +22,8 @@ import (
 func main() {
 
 
-	// Initialize logger with console-only output
-	logConfig := logging.LogConfig{
-		LogLevel:      "debug",
-		LogFormat:     "json",
-		EnableConsole: true,
-		EnableFile:    false, // Disable file logging
-	}
+	// Initialize logger with console output, plus rotating file output when LOG_FILE_ENABLED is set
+	logConfig := logging.LoadLogConfig()
 	logger, err := logging.NewLogger(logConfig)
 	if err != nil {
 		panic("failed to initialize logger: " + err.Error())
@@ -43,6 +43,15 @@ func main() {
 	}
 	logger.Info("Configuration loaded successfully")
 
+	// Load the DeepSeek secrets once so every request reuses them.
+	if err := integrations.LoadDeepSeekCredentials(); err != nil {
+		logger.Fatal("Error loading DeepSeek credentials", zap.Error(err))
+	}
+	aiConfig := config.LoadAIConfig()
+	logger.Info("DeepSeek credentials loaded",
+		zap.Duration("deepseekTimeout", aiConfig.DeepSeekTimeout),
+		zap.Duration("requestTimeout", aiConfig.RequestTimeout))
+
 	// Build configuration and DSN using the config package
 	cfg, err := config.NewConfig()
 	if err != nil {
@@ -83,9 +92,21 @@ func main() {
 	}
 	logger.Info("Migrations completed")
 
+	// Connect to Redis when it is configured; a configured but unreachable Redis is fatal so
+	// a wrong address or password is caught at startup.
+	redisClient, err := db.NewRedisClient(config.LoadRedisConfig())
+	if errors.Is(err, db.ErrRedisNotConfigured) {
+		logger.Warn("Redis not configured, continuing without it")
+	} else if err != nil {
+		logger.Fatal("Error connecting to Redis", zap.Error(err))
+	} else {
+		defer redisClient.Close()
+		logger.Info("Successfully connected to Redis")
+	}
+
 	// Setup and start the Gin router with database dependency
 	logger.Info("Setting up router...")
-	router := routes.SetupRouter(database, logger)
+	router := routes.SetupRouterWithRedis(database, redisClient, logger)
 	logger.Info("Router setup complete")
 
 	logger.Info("Starting server", zap.String("address", "0.0.0.0:8080"))
@@ -1,3 +1,5 @@
+// Command recipe_query_parser_example is an example, not a server: it prints the structured
+// result of parsers.ParseRecipeQuery for a sample query.
 package main
 
 import (
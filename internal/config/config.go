@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -27,6 +28,7 @@ type Config struct {
 	RateLimit   RateLimitConfig
 	JWT         JWTConfig
 	Email       EmailConfig
+	AI          AIConfig
 	Logging     LoggingConfig
 }
 
@@ -51,6 +53,8 @@ type ServerConfig struct {
 	Timeout      time.Duration `env:"SERVER_TIMEOUT" envDefault:"30s" validate:"required"`
 	ReadTimeout  time.Duration `env:"SERVER_READ_TIMEOUT" envDefault:"10s" validate:"required"`
 	WriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT" envDefault:"10s" validate:"required"`
+	// TrustedProxies lists proxy IPs/CIDRs whose forwarding headers are honoured. Empty trusts none.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envDefault:""`
 }
 
 // RateLimitConfig holds rate limiting configuration
@@ -60,6 +64,16 @@ type RateLimitConfig struct {
 	ExpirationTTL     time.Duration `env:"RATE_LIMIT_EXPIRATION" envDefault:"1h" validate:"required"`
 }
 
+// LoadRateLimitConfig reads RATE_LIMIT_REQUESTS, RATE_LIMIT_BURST and RATE_LIMIT_EXPIRATION,
+// falling back to the defaults for unset values.
+func LoadRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerSecond: getEnvFloatOrDefault("RATE_LIMIT_REQUESTS", 5.0),
+		Burst:             getEnvIntOrDefault("RATE_LIMIT_BURST", 10),
+		ExpirationTTL:     getEnvDurationOrDefault("RATE_LIMIT_EXPIRATION", time.Hour),
+	}
+}
+
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
 	Secret          string `env:"JWT_SECRET" envDefault:"default-super-long-ci-secret-key-123456" validate:"required,min=32"`
@@ -69,11 +83,182 @@ type JWTConfig struct {
 
 // EmailConfig holds email configuration
 type EmailConfig struct {
+	// Driver is "smtp" to send mail or "log" to only log it, for development and tests.
+	Driver   string `env:"EMAIL_DRIVER" envDefault:"log" validate:"required,oneof=smtp log"`
 	Host     string `env:"EMAIL_HOST" envDefault:"smtp.gmail.com" validate:"required,hostname"`
 	Port     int    `env:"EMAIL_PORT" envDefault:"587" validate:"required,min=1,max=65535"`
 	Username string `env:"EMAIL_USERNAME" envDefault:"" validate:"required,email"`
 	Password string `env:"EMAIL_PASSWORD" envDefault:"" validate:"required"`
 	From     string `env:"EMAIL_FROM" envDefault:"noreply@alchemorsel.com" validate:"required,email"`
+	// PasswordResetURL is the page that completes a password reset; the token is appended as a query parameter.
+	PasswordResetURL string `env:"PASSWORD_RESET_URL" envDefault:"http://localhost:3000/reset-password"`
+	// EmailVerificationURL is the endpoint that verifies an email address; the token is appended as a path segment.
+	EmailVerificationURL string `env:"EMAIL_VERIFICATION_URL" envDefault:"http://localhost:8080/v1/users/verify-email"`
+}
+
+// LoadEmailConfig reads the EMAIL_* variables and PASSWORD_RESET_URL, falling back to the
+// defaults for unset values.
+func LoadEmailConfig() EmailConfig {
+	return EmailConfig{
+		Driver:               getEnvOrDefault("EMAIL_DRIVER", "log"),
+		Host:                 getEnvOrDefault("EMAIL_HOST", "smtp.gmail.com"),
+		Port:                 getEnvIntOrDefault("EMAIL_PORT", 587),
+		Username:             getEnvOrDefault("EMAIL_USERNAME", ""),
+		Password:             getEnvOrDefault("EMAIL_PASSWORD", ""),
+		From:                 getEnvOrDefault("EMAIL_FROM", "noreply@alchemorsel.com"),
+		PasswordResetURL:     getEnvOrDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		EmailVerificationURL: getEnvOrDefault("EMAIL_VERIFICATION_URL", "http://localhost:8080/v1/users/verify-email"),
+	}
+}
+
+// StorageConfig selects where uploaded files, such as recipe photos, are stored
+type StorageConfig struct {
+	// Driver is "s3" for an S3-compatible object store or "local" to write files to LocalDir.
+	Driver string `env:"STORAGE_DRIVER" envDefault:"local" validate:"required,oneof=local s3"`
+	// LocalDir is the directory the local driver writes to; it is served under /uploads.
+	LocalDir string `env:"STORAGE_LOCAL_DIR" envDefault:"uploads"`
+	// PublicURL is prepended to object keys to build the URLs returned to clients. It defaults
+	// to /uploads for the local driver and to S3Endpoint/S3Bucket for s3, e.g. set it to a CDN.
+	PublicURL string `env:"STORAGE_PUBLIC_URL"`
+	// S3Endpoint is the object store's base URL, e.g. https://s3.us-east-1.amazonaws.com or a
+	// MinIO server. Objects are addressed path-style as endpoint/bucket/key.
+	S3Endpoint string `env:"S3_ENDPOINT"`
+	S3Bucket   string `env:"S3_BUCKET"`
+	S3Region   string `env:"S3_REGION" envDefault:"us-east-1"`
+}
+
+// LoadStorageConfig reads STORAGE_DRIVER, STORAGE_LOCAL_DIR, STORAGE_PUBLIC_URL and the S3_*
+// variables, falling back to the defaults for unset values. S3 credentials come from the
+// default AWS credential chain.
+func LoadStorageConfig() StorageConfig {
+	return StorageConfig{
+		Driver:     getEnvOrDefault("STORAGE_DRIVER", "local"),
+		LocalDir:   getEnvOrDefault("STORAGE_LOCAL_DIR", "uploads"),
+		PublicURL:  getEnvOrDefault("STORAGE_PUBLIC_URL", ""),
+		S3Endpoint: getEnvOrDefault("S3_ENDPOINT", ""),
+		S3Bucket:   getEnvOrDefault("S3_BUCKET", ""),
+		S3Region:   getEnvOrDefault("S3_REGION", "us-east-1"),
+	}
+}
+
+// ModerationConfig selects the check free-text recipe queries must pass before they are sent to
+// the model.
+type ModerationConfig struct {
+	// Driver is "off" to skip moderation, "keywords" for the built-in phrase filter or "openai"
+	// for the OpenAI moderation API, which is combined with the phrase filter.
+	Driver string `env:"MODERATION_DRIVER" envDefault:"off" validate:"required,oneof=off keywords openai"`
+	// BlockedTerms are extra comma-separated words or phrases the phrase filter rejects.
+	BlockedTerms []string `env:"MODERATION_BLOCKED_TERMS" envDefault:""`
+}
+
+// LoadModerationConfig reads MODERATION_DRIVER and MODERATION_BLOCKED_TERMS, falling back to
+// the defaults for unset values.
+func LoadModerationConfig() ModerationConfig {
+	return ModerationConfig{
+		Driver:       getEnvOrDefault("MODERATION_DRIVER", "off"),
+		BlockedTerms: splitList(os.Getenv("MODERATION_BLOCKED_TERMS")),
+	}
+}
+
+// AIConfig holds the timeouts for calls to the external model and embedding APIs
+type AIConfig struct {
+	// RequestTimeout bounds the whole request on routes that call the model.
+	RequestTimeout time.Duration `env:"AI_REQUEST_TIMEOUT" envDefault:"90s" validate:"required"`
+	// DeepSeekTimeout bounds a single DeepSeek call; failed calls are retried within RequestTimeout.
+	DeepSeekTimeout time.Duration `env:"DEEPSEEK_TIMEOUT" envDefault:"60s" validate:"required"`
+	// EmbeddingTimeout bounds a single OpenAI embeddings call.
+	EmbeddingTimeout time.Duration `env:"OPENAI_EMBEDDING_TIMEOUT" envDefault:"30s" validate:"required"`
+	// BatchConcurrency is the number of model calls a batch generation request runs at once.
+	BatchConcurrency int `env:"AI_BATCH_CONCURRENCY" envDefault:"3" validate:"required,min=1"`
+	// BatchTimeout bounds the model calls of a batch generation or meal plan request. It is kept below
+	// RequestTimeout so the recipes generated in time are still saved and returned.
+	BatchTimeout time.Duration `env:"AI_BATCH_TIMEOUT" envDefault:"75s" validate:"required"`
+	// EmbeddingDimensions is the length every stored recipe embedding must have, matching the
+	// embedding model (1536 for text-embedding-3-small).
+	EmbeddingDimensions int `env:"EMBEDDING_DIMENSIONS" envDefault:"1536" validate:"required,min=1"`
+	// DeepSeekBreakerThreshold is the number of consecutive failed DeepSeek calls that opens the
+	// circuit breaker.
+	DeepSeekBreakerThreshold int `env:"DEEPSEEK_BREAKER_THRESHOLD" envDefault:"5" validate:"required,min=1"`
+	// DeepSeekBreakerCooldown is how long an open breaker rejects calls before letting a probe through.
+	DeepSeekBreakerCooldown time.Duration `env:"DEEPSEEK_BREAKER_COOLDOWN" envDefault:"30s" validate:"required"`
+	// MaxBodyBytes caps the request body of routes that call the model, since everything in it
+	// may end up in a paid prompt.
+	MaxBodyBytes int `env:"AI_MAX_BODY_BYTES" envDefault:"65536" validate:"required,min=1"`
+}
+
+// LoadAIConfig reads AI_REQUEST_TIMEOUT, DEEPSEEK_TIMEOUT, OPENAI_EMBEDDING_TIMEOUT,
+// AI_BATCH_CONCURRENCY, AI_BATCH_TIMEOUT, EMBEDDING_DIMENSIONS, DEEPSEEK_BREAKER_THRESHOLD,
+// DEEPSEEK_BREAKER_COOLDOWN and AI_MAX_BODY_BYTES, falling back to the defaults for unset or
+// non-positive values. A batch timeout that does not leave a sixth of the request timeout
+// spare is lowered to five sixths of it.
+func LoadAIConfig() AIConfig {
+	cfg := AIConfig{
+		RequestTimeout:      getEnvPositiveDurationOrDefault("AI_REQUEST_TIMEOUT", 90*time.Second),
+		DeepSeekTimeout:     getEnvPositiveDurationOrDefault("DEEPSEEK_TIMEOUT", 60*time.Second),
+		EmbeddingTimeout:    getEnvPositiveDurationOrDefault("OPENAI_EMBEDDING_TIMEOUT", 30*time.Second),
+		BatchConcurrency:    getEnvIntOrDefault("AI_BATCH_CONCURRENCY", 3),
+		BatchTimeout:        getEnvPositiveDurationOrDefault("AI_BATCH_TIMEOUT", 75*time.Second),
+		EmbeddingDimensions: getEnvIntOrDefault("EMBEDDING_DIMENSIONS", 1536),
+
+		DeepSeekBreakerThreshold: getEnvIntOrDefault("DEEPSEEK_BREAKER_THRESHOLD", 5),
+		DeepSeekBreakerCooldown:  getEnvPositiveDurationOrDefault("DEEPSEEK_BREAKER_COOLDOWN", 30*time.Second),
+		MaxBodyBytes:             getEnvIntOrDefault("AI_MAX_BODY_BYTES", 64<<10),
+	}
+	if cfg.BatchConcurrency < 1 {
+		cfg.BatchConcurrency = 3
+	}
+	if limit := cfg.RequestTimeout * 5 / 6; cfg.BatchTimeout > limit {
+		cfg.BatchTimeout = limit
+	}
+	if cfg.EmbeddingDimensions < 1 {
+		cfg.EmbeddingDimensions = 1536
+	}
+	if cfg.DeepSeekBreakerThreshold < 1 {
+		cfg.DeepSeekBreakerThreshold = 5
+	}
+	if cfg.MaxBodyBytes < 1 {
+		cfg.MaxBodyBytes = 64 << 10
+	}
+	return cfg
+}
+
+// RedisConfig holds the Redis connection and pool settings
+type RedisConfig struct {
+	// Addr is host:port. It is empty when Redis is not configured, which disables Redis-backed features.
+	Addr         string        `env:"REDIS_ADDR"`
+	Password     string        `env:"REDIS_PASSWORD" envDefault:""`
+	DB           int           `env:"REDIS_DB" envDefault:"0" validate:"min=0"`
+	PoolSize     int           `env:"REDIS_POOL_SIZE" envDefault:"10" validate:"min=1"`
+	MinIdleConns int           `env:"REDIS_MIN_IDLE_CONNS" envDefault:"0" validate:"min=0"`
+	DialTimeout  time.Duration `env:"REDIS_DIAL_TIMEOUT" envDefault:"5s" validate:"required"`
+}
+
+// LoadRedisConfig reads REDIS_ADDR, or REDIS_HOST and REDIS_PORT when it is unset, along with
+// REDIS_PASSWORD, REDIS_DB, REDIS_POOL_SIZE, REDIS_MIN_IDLE_CONNS and REDIS_DIAL_TIMEOUT, falling
+// back to the defaults for unset or invalid values.
+func LoadRedisConfig() RedisConfig {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" && os.Getenv("REDIS_HOST") != "" {
+		addr = fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), getEnvOrDefault("REDIS_PORT", "6379"))
+	}
+	cfg := RedisConfig{
+		Addr:         addr,
+		Password:     os.Getenv("REDIS_PASSWORD"),
+		DB:           getEnvIntOrDefault("REDIS_DB", 0),
+		PoolSize:     getEnvIntOrDefault("REDIS_POOL_SIZE", 10),
+		MinIdleConns: getEnvIntOrDefault("REDIS_MIN_IDLE_CONNS", 0),
+		DialTimeout:  getEnvPositiveDurationOrDefault("REDIS_DIAL_TIMEOUT", 5*time.Second),
+	}
+	if cfg.DB < 0 {
+		cfg.DB = 0
+	}
+	if cfg.PoolSize < 1 {
+		cfg.PoolSize = 10
+	}
+	if cfg.MinIdleConns < 0 {
+		cfg.MinIdleConns = 0
+	}
+	return cfg
 }
 
 // LoggingConfig holds logging configuration
@@ -127,11 +312,10 @@ func (c *Config) loadFromEnv() error {
 	c.Server.Timeout = getEnvDurationOrDefault("SERVER_TIMEOUT", 30*time.Second)
 	c.Server.ReadTimeout = getEnvDurationOrDefault("SERVER_READ_TIMEOUT", 10*time.Second)
 	c.Server.WriteTimeout = getEnvDurationOrDefault("SERVER_WRITE_TIMEOUT", 10*time.Second)
+	c.Server.TrustedProxies = ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
 
 	// Rate limit configuration
-	c.RateLimit.RequestsPerSecond = getEnvFloatOrDefault("RATE_LIMIT_REQUESTS", 5.0)
-	c.RateLimit.Burst = getEnvIntOrDefault("RATE_LIMIT_BURST", 10)
-	c.RateLimit.ExpirationTTL = getEnvDurationOrDefault("RATE_LIMIT_EXPIRATION", time.Hour)
+	c.RateLimit = LoadRateLimitConfig()
 
 	// JWT configuration
 	c.JWT.Secret = getEnvOrDefault("JWT_SECRET", "default-super-long-ci-secret-key-123456")
@@ -139,11 +323,10 @@ func (c *Config) loadFromEnv() error {
 	c.JWT.RefreshHours = getEnvIntOrDefault("JWT_REFRESH_HOURS", 168)
 
 	// Email configuration
-	c.Email.Host = getEnvOrDefault("EMAIL_HOST", "smtp.gmail.com")
-	c.Email.Port = getEnvIntOrDefault("EMAIL_PORT", 587)
-	c.Email.Username = getEnvOrDefault("EMAIL_USERNAME", "")
-	c.Email.Password = getEnvOrDefault("EMAIL_PASSWORD", "")
-	c.Email.From = getEnvOrDefault("EMAIL_FROM", "noreply@alchemorsel.com")
+	c.Email = LoadEmailConfig()
+
+	// AI timeouts
+	c.AI = LoadAIConfig()
 
 	// Logging configuration
 	c.Logging.Level = getEnvOrDefault("LOG_LEVEL", "info")
@@ -192,6 +375,9 @@ func (c *Config) validate() error {
 	}
 
 	// Validate email configuration
+	if c.Email.Driver != "smtp" && c.Email.Driver != "log" {
+		return fmt.Errorf("invalid email driver: %s", c.Email.Driver)
+	}
 	if c.Email.Port < 1 || c.Email.Port > 65535 {
 		return fmt.Errorf("invalid email port: %d", c.Email.Port)
 	}
@@ -283,6 +469,15 @@ func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Durati
 	return defaultValue
 }
 
+// getEnvPositiveDurationOrDefault is getEnvDurationOrDefault for settings where zero or a
+// negative duration makes no sense, such as timeouts.
+func getEnvPositiveDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if d := getEnvDurationOrDefault(key, defaultValue); d > 0 {
+		return d
+	}
+	return defaultValue
+}
+
 func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -292,6 +487,23 @@ func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// ParseTrustedProxies splits a comma-separated TRUSTED_PROXIES value into a list,
+// dropping empty entries. An empty value yields nil, meaning no proxies are trusted.
+func ParseTrustedProxies(value string) []string {
+	return splitList(value)
+}
+
+// splitList splits a comma-separated value, trimming entries and dropping empty ones.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // LoadConfig loads configuration from environment files and environment variables
 func LoadConfig() error {
 	// Try to load .env.development first
@@ -1,8 +1,10 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
+	"strconv"
 
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
@@ -20,8 +22,19 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// MaxOpenConns and MaxIdleConns size the connection pool. Bulk operations read
+	// MaxOpenConns to work out how many of them may run at once.
+	MaxOpenConns int
+	MaxIdleConns int
 }
 
+// DefaultMaxOpenConns and DefaultMaxIdleConns are used when POSTGRES_MAX_OPEN_CONNS and
+// POSTGRES_MAX_IDLE_CONNS are unset or invalid.
+const (
+	DefaultMaxOpenConns = 25
+	DefaultMaxIdleConns = 5
+)
+
 // NewConfig creates a new database configuration from environment variables
 func NewConfig() *Config {
 	return &Config{
@@ -31,7 +44,24 @@ func NewConfig() *Config {
 		Password: os.Getenv("POSTGRES_PASSWORD"),
 		DBName:   os.Getenv("POSTGRES_DB"),
 		SSLMode:  "disable",
+
+		MaxOpenConns: positiveEnv("POSTGRES_MAX_OPEN_CONNS", DefaultMaxOpenConns),
+		MaxIdleConns: positiveEnv("POSTGRES_MAX_IDLE_CONNS", DefaultMaxIdleConns),
+	}
+}
+
+// positiveEnv returns the positive integer in the environment variable key, or fallback.
+func positiveEnv(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+		return n
 	}
+	return fallback
+}
+
+// ConfigurePool applies the pool limits from config to sqlDB.
+func ConfigurePool(sqlDB *sql.DB, config *Config) {
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 }
 
 // InitDB initializes the database connection
@@ -68,6 +98,7 @@ func InitDB(config *Config) (*gorm.DB, error) {
 			zap.Error(err))
 		return nil, err
 	}
+	ConfigurePool(sqlDB, config)
 
 	err = sqlDB.Ping()
 	if err != nil {
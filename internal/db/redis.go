@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRedisNotConfigured is returned by NewRedisClient when cfg has no address.
+var ErrRedisNotConfigured = errors.New("redis is not configured")
+
+// NewRedisClient creates a pooled Redis client from cfg and pings it, so a wrong address or
+// password is reported at startup rather than on the first request. The ping is bounded by
+// cfg.DialTimeout.
+func NewRedisClient(cfg config.RedisConfig) (*redis.Client, error) {
+	if cfg.Addr == "" {
+		return nil, ErrRedisNotConfigured
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis at %s: %w", cfg.Addr, err)
+	}
+	return client, nil
+}
@@ -0,0 +1,11 @@
+package dtos
+
+// FavoriteCheckRequest is the payload for checking which recipes a user has favorited.
+type FavoriteCheckRequest struct {
+	RecipeIDs []string `json:"recipe_ids" binding:"required"`
+}
+
+// FavoriteCheckResponse maps each requested recipe ID to whether it is favorited.
+type FavoriteCheckResponse struct {
+	Favorites map[string]bool `json:"favorites"`
+}
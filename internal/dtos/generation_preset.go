@@ -0,0 +1,17 @@
+package dtos
+
+import "github.com/pageza/alchemorsel-v1/internal/models"
+
+// GenerationPresetRequest is the payload for creating a named recipe generation preset.
+type GenerationPresetRequest struct {
+	Name           string `json:"name" binding:"required,max=100"`
+	Cuisine        string `json:"cuisine,omitempty" binding:"max=100"`
+	Diet           string `json:"diet,omitempty" binding:"max=100"`
+	MaxTimeMinutes int    `json:"max_time_minutes,omitempty" binding:"min=0,max=1440"`
+	Servings       int    `json:"servings,omitempty" binding:"min=0,max=100"`
+}
+
+// GenerationPresetListResponse wraps the current user's presets.
+type GenerationPresetListResponse struct {
+	Presets []models.GenerationPreset `json:"presets"`
+}
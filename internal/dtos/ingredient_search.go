@@ -0,0 +1,15 @@
+package dtos
+
+// IngredientSearchResult is a recipe found by ingredient search with the searched ingredients
+// it uses and the ingredients it still needs.
+type IngredientSearchResult struct {
+	Recipe             RecipeResponse `json:"recipe"`
+	MatchedIngredients []string       `json:"matched_ingredients"`
+	MissingIngredients []string       `json:"missing_ingredients"`
+	Score              float64        `json:"score"`
+}
+
+// IngredientSearchResponse lists ingredient search results, best fit first.
+type IngredientSearchResponse struct {
+	Results []IngredientSearchResult `json:"results"`
+}
@@ -0,0 +1,55 @@
+package dtos
+
+// MaxMealPlanRecipes caps days times meals per day for a single meal plan, so one request cannot
+// run up an unbounded model bill.
+const MaxMealPlanRecipes = 21
+
+// MealPlanRequest defines the constraints of a generated meal plan.
+type MealPlanRequest struct {
+	Days        int `json:"days" binding:"required,min=1,max=7"`
+	MealsPerDay int `json:"meals_per_day" binding:"required,min=1,max=4"`
+	// DietaryRestrictions apply to every meal, e.g. "vegetarian" or "gluten-free".
+	DietaryRestrictions []string `json:"dietary_restrictions,omitempty" binding:"max=5,dive,required,max=50"`
+	// CalorieTarget is the daily target in kcal, split evenly across the meals of a day.
+	CalorieTarget int `json:"calorie_target,omitempty" binding:"omitempty,min=500,max=10000"`
+	// Preferences is a freeform query added to every meal, e.g. "italian, no mushrooms".
+	Preferences string `json:"preferences,omitempty" binding:"max=200"`
+}
+
+// MealPlanMeal is one generated meal of a plan. Either RecipeID and Recipe or Error is set.
+type MealPlanMeal struct {
+	Meal     string          `json:"meal"`
+	Query    string          `json:"query"`
+	RecipeID string          `json:"recipe_id,omitempty"`
+	Recipe   *RecipeResponse `json:"recipe,omitempty"`
+	Error    *ErrorResponse  `json:"error,omitempty"`
+}
+
+// MealPlanDay lists the meals of one day of a plan. Calories is the per-serving total of the
+// meals whose nutrition is known.
+type MealPlanDay struct {
+	Day      int            `json:"day"`
+	Meals    []MealPlanMeal `json:"meals"`
+	Calories float64        `json:"calories"`
+}
+
+// MealPlanNutrition sums the per-serving nutrition of the plan's recipes, assuming one serving
+// per meal. Recipes whose nutritional information cannot be read are left out and not counted
+// in RecipesCounted.
+type MealPlanNutrition struct {
+	Calories             float64 `json:"calories"`
+	Protein              float64 `json:"protein"`
+	Carbs                float64 `json:"carbs"`
+	Fat                  float64 `json:"fat"`
+	AverageDailyCalories float64 `json:"average_daily_calories"`
+	CalorieTarget        int     `json:"calorie_target,omitempty"`
+	RecipesCounted       int     `json:"recipes_counted"`
+}
+
+// MealPlanResponse is a generated meal plan grouped by day, with its nutrition summary and the
+// tokens spent on it.
+type MealPlanResponse struct {
+	Days      []MealPlanDay     `json:"days"`
+	Nutrition MealPlanNutrition `json:"nutrition"`
+	Usage     TokenUsage        `json:"usage"`
+}
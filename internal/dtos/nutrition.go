@@ -0,0 +1,12 @@
+package dtos
+
+// NutritionResponse returns the recomputed per-serving nutrition of a recipe. Calories are in
+// kcal and the macros in grams; NutritionalInfo is the summary stored on the recipe.
+type NutritionResponse struct {
+	RecipeID        string  `json:"recipe_id"`
+	Calories        float64 `json:"calories"`
+	Protein         float64 `json:"protein"`
+	Carbs           float64 `json:"carbs"`
+	Fat             float64 `json:"fat"`
+	NutritionalInfo string  `json:"nutritional_info"`
+}
@@ -0,0 +1,22 @@
+package dtos
+
+// PanDimensions describes a baking pan. Round pans use Diameter; rectangular pans use Width and Length.
+// Both pans in a request must use the same unit of length.
+type PanDimensions struct {
+	Shape    string  `json:"shape" binding:"required,oneof=round rectangular"`
+	Diameter float64 `json:"diameter,omitempty"`
+	Width    float64 `json:"width,omitempty"`
+	Length   float64 `json:"length,omitempty"`
+}
+
+// PanScaleRequest defines the payload for scaling a recipe from one pan size to another.
+type PanScaleRequest struct {
+	Source PanDimensions `json:"source" binding:"required"`
+	Target PanDimensions `json:"target" binding:"required"`
+}
+
+// PanScaleResponse returns the scaled recipe together with the area ratio applied.
+type PanScaleResponse struct {
+	Ratio  float64        `json:"ratio"`
+	Recipe RecipeResponse `json:"recipe"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+// RateRecipeRequest is the payload for rating a recipe from 0 to 5.
+type RateRecipeRequest struct {
+	// Rating is a pointer so that a rating of 0 is distinguished from a missing rating.
+	Rating *float64 `json:"rating" binding:"required"`
+}
+
+// RecipeRatingsResponse summarizes the ratings of a recipe, one per user.
+type RecipeRatingsResponse struct {
+	RecipeID string  `json:"recipe_id"`
+	Count    int     `json:"count"`
+	Average  float64 `json:"average"`
+}
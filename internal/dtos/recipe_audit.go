@@ -0,0 +1,41 @@
+package dtos
+
+import (
+	"encoding/json"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+)
+
+// RecipeAuditEntryResponse is one change in a recipe's audit log. Diff maps each changed field
+// to its old and new value and is only set for updates.
+type RecipeAuditEntryResponse struct {
+	ID        string          `json:"id"`
+	ActorID   *string         `json:"actor_id"`
+	Action    string          `json:"action"`
+	Diff      json.RawMessage `json:"diff,omitempty"`
+	CreatedAt Timestamp       `json:"created_at"`
+}
+
+// RecipeAuditLogResponse lists the changes made to a recipe, newest first.
+type RecipeAuditLogResponse struct {
+	RecipeID string                     `json:"recipe_id"`
+	Count    int                        `json:"count"`
+	Entries  []RecipeAuditEntryResponse `json:"entries"`
+}
+
+// NewRecipeAuditLogResponse builds the response from the entries of one recipe.
+func NewRecipeAuditLogResponse(recipeID string, entries []models.RecipeAuditEntry) RecipeAuditLogResponse {
+	response := RecipeAuditLogResponse{RecipeID: recipeID, Count: len(entries), Entries: make([]RecipeAuditEntryResponse, len(entries))}
+	for i, entry := range entries {
+		response.Entries[i] = RecipeAuditEntryResponse{
+			ID:        entry.ID,
+			ActorID:   entry.ActorID,
+			Action:    entry.Action,
+			CreatedAt: NewTimestamp(entry.CreatedAt),
+		}
+		if len(entry.Diff) > 0 {
+			response.Entries[i].Diff = json.RawMessage(entry.Diff)
+		}
+	}
+	return response
+}
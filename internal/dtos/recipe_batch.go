@@ -0,0 +1,32 @@
+package dtos
+
+// MaxBatchGenerationQueries caps the number of recipes a single batch generation request may ask for.
+const MaxBatchGenerationQueries = 10
+
+// BatchGenerationRequest defines the payload for generating several recipes at once.
+type BatchGenerationRequest struct {
+	Queries []string `json:"queries" binding:"required,min=1,max=10,dive,required,max=500"`
+}
+
+// BatchGenerationResult is the outcome for one query of a batch. Either RecipeID and Recipe or
+// Error is set.
+type BatchGenerationResult struct {
+	Query    string          `json:"query"`
+	RecipeID string          `json:"recipe_id,omitempty"`
+	Recipe   *RecipeResponse `json:"recipe,omitempty"`
+	Error    *ErrorResponse  `json:"error,omitempty"`
+}
+
+// TokenUsage reports the model tokens spent on a request.
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// BatchGenerationResponse lists the results in the order of the requested queries, together
+// with the tokens spent on the whole batch.
+type BatchGenerationResponse struct {
+	Results []BatchGenerationResult `json:"results"`
+	Usage   TokenUsage              `json:"usage"`
+}
@@ -0,0 +1,7 @@
+package dtos
+
+// RecipeExpansionRequest defines the optional payload for expanding a stored recipe.
+// By default the title and ingredients are preserved; AllowCoreChanges lets the model change them.
+type RecipeExpansionRequest struct {
+	AllowCoreChanges bool `json:"allow_core_changes,omitempty"`
+}
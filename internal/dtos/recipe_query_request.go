@@ -1,11 +1,24 @@
 package dtos
 
+// MaxQueryLength caps the characters of a free-text recipe query, which is sent to the model as
+// part of the prompt. The binding tags of query fields repeat it.
+const MaxQueryLength = 500
+
 // RecipeQueryRequest defines the payload for initiating a recipe resolution query.
 // It includes a natural language query along with prompt instructions that guide the model,
 // and the expected response format.
 
 type RecipeQueryRequest struct {
-	Query                  string `json:"query" binding:"required"`
+	Query                  string `json:"query" binding:"required,max=500"`
 	PromptInstructions     string `json:"promptInstructions" binding:"required"`
 	ExpectedResponseFormat string `json:"expectedResponseFormat" binding:"required"`
+	// Language is the code the recipe should be generated in; falls back to Accept-Language, then English.
+	Language string `json:"language,omitempty"`
+	// Optional model overrides. Model must be one of integrations.AllowedDeepSeekModels,
+	// temperature between 0 and 2 and max_tokens between 256 and 8192.
+	Model       string   `json:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	// PresetID names one of the caller's generation presets whose constraints are merged into the query.
+	PresetID string `json:"preset_id,omitempty"`
 }
@@ -15,6 +15,8 @@ type Step struct {
 
 // RecipeRequest defines the payload for creating a new recipe.
 // Fields such as ID, CreatedAt, UpdatedAt, and Embedding are generated by the system.
+// When updating a recipe, omitting Cuisines, Diets, Appliances or Tags (or sending null)
+// keeps the recipe's current values, while an empty list clears them.
 type RecipeRequest struct {
 	Title             string       `json:"title" binding:"required"`
 	Description       string       `json:"description,omitempty"`
@@ -31,6 +33,7 @@ type RecipeRequest struct {
 	PrepTime          int          `json:"prep_time,omitempty"`
 	CookTime          int          `json:"cooking_time,omitempty"`
 	Servings          int          `json:"servings,omitempty"`
+	Language          string       `json:"language,omitempty"`
 	Approved          bool         `json:"approved,omitempty"`
 }
 
@@ -40,7 +43,7 @@ type RecipeRequest struct {
 // modification instructions can be supplied for the LLM.
 type RecipeResolutionRequest struct {
 	Title string `json:"title" binding:"required"`
-	Query string `json:"query,omitempty"`
+	Query string `json:"query,omitempty" binding:"max=500"`
 
 	Ingredients       []string `json:"ingredients" binding:"required"`
 	Steps             []string `json:"steps" binding:"required"`
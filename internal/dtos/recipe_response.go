@@ -2,9 +2,9 @@ package dtos
 
 import (
 	"encoding/json"
-	"time"
 
 	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/units"
 )
 
 // RecipeResponse defines the payload structure for returning a recipe.
@@ -23,20 +23,57 @@ type RecipeResponse struct {
 	Tags       []string `json:"tags,omitempty"`
 	// Additional fields for future enhancements.
 	Images        []string  `json:"images,omitempty"`
+	ImageURL      string    `json:"image_url,omitempty"`
 	Difficulty    string    `json:"difficulty,omitempty"`
 	PrepTime      int       `json:"prep_time,omitempty"`
 	CookTime      int       `json:"cooking_time,omitempty"`
 	Servings      int       `json:"servings,omitempty"`
+	Language      string    `json:"language,omitempty"`
+	Units         string    `json:"units,omitempty"`
 	AverageRating float64   `json:"average_rating,omitempty"`
 	RatingCount   int       `json:"rating_count,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedAt     Timestamp `json:"created_at"`
+	UpdatedAt     Timestamp `json:"updated_at"`
 	Approved      bool      `json:"approved,omitempty"`
+	// ClonedFrom is the ID of the recipe this one was copied from.
+	ClonedFrom string `json:"cloned_from,omitempty"`
+	// Cost estimate, included only when requested.
+	EstimatedCost       *float64 `json:"estimated_cost,omitempty"`
+	UnpricedIngredients []string `json:"unpriced_ingredients,omitempty"`
 }
 
 // RecipeListResponse wraps a list of recipes in a response object
 type RecipeListResponse struct {
 	Recipes []RecipeResponse `json:"recipes"`
+	Meta    RecipeListMeta   `json:"meta"`
+}
+
+// RecipeListMeta describes the pagination and sorting applied to a recipe list.
+type RecipeListMeta struct {
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+	Sort  string `json:"sort"`
+	Order string `json:"order"`
+}
+
+// RecipeImportStatusPending is the status of an imported recipe awaiting approval.
+const RecipeImportStatusPending = "pending_approval"
+
+// RecipeImportResponse identifies a recipe created by an import.
+type RecipeImportResponse struct {
+	RecipeID string `json:"recipe_id"`
+	Status   string `json:"status"`
+}
+
+// RecipeImageResponse returns the URL of an uploaded recipe photo.
+type RecipeImageResponse struct {
+	RecipeID string `json:"recipe_id"`
+	ImageURL string `json:"image_url"`
+}
+
+// RecipeDifficultiesResponse lists the allowed recipe difficulty levels.
+type RecipeDifficultiesResponse struct {
+	Difficulties []string `json:"difficulties"`
 }
 
 // NewRecipeResponse converts a models.Recipe into a RecipeResponse DTO.
@@ -52,9 +89,16 @@ func NewRecipeResponse(recipe *models.Recipe) *RecipeResponse {
 		PrepTime:          recipe.PrepTime,
 		CookTime:          recipe.CookTime,
 		Servings:          recipe.Servings,
+		Language:          recipe.Language,
+		ImageURL:          recipe.ImageURL,
 		Approved:          recipe.Approved,
-		CreatedAt:         recipe.CreatedAt,
-		UpdatedAt:         recipe.UpdatedAt,
+		AverageRating:     recipe.AverageRating,
+		RatingCount:       recipe.RatingCount,
+		CreatedAt:         NewTimestamp(recipe.CreatedAt),
+		UpdatedAt:         NewTimestamp(recipe.UpdatedAt),
+	}
+	if recipe.ClonedFrom != nil {
+		response.ClonedFrom = *recipe.ClonedFrom
 	}
 
 	// Convert ingredients JSON to array
@@ -92,3 +136,25 @@ func NewRecipeResponse(recipe *models.Recipe) *RecipeResponse {
 
 	return response
 }
+
+// ConvertUnits expresses ingredient amounts in the given measurement system.
+// Amounts without a known conversion are left as they are; their ingredient names are returned.
+func (r *RecipeResponse) ConvertUnits(to units.System) []string {
+	var skipped []string
+	for i, ing := range r.Ingredients {
+		if !units.Convertible(ing.Amount, ing.Unit) {
+			skipped = append(skipped, ing.Name)
+			continue
+		}
+		r.Ingredients[i].Amount, r.Ingredients[i].Unit = units.Convert(ing.Amount, ing.Unit, to)
+	}
+	r.Units = string(to)
+	return skipped
+}
+
+// RecipeConversionResponse is a recipe converted to one measurement system, listing the
+// ingredients whose amounts could not be converted and were left as stored.
+type RecipeConversionResponse struct {
+	Recipe  *RecipeResponse `json:"recipe"`
+	Skipped []string        `json:"skipped"`
+}
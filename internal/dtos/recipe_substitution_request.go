@@ -0,0 +1,7 @@
+package dtos
+
+// IngredientSubstitutionRequest defines the payload for swapping a single ingredient in a recipe.
+type IngredientSubstitutionRequest struct {
+	Ingredient string `json:"ingredient" binding:"required"`
+	Reason     string `json:"reason,omitempty"`
+}
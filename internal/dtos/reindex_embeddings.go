@@ -0,0 +1,46 @@
+package dtos
+
+import "github.com/pageza/alchemorsel-v1/internal/models"
+
+// ReindexEmbeddingsResponse reports the outcome of regenerating one batch of stored recipe
+// embeddings. Until Done, the next batch starts at NextOffset.
+type ReindexEmbeddingsResponse struct {
+	DryRun     bool `json:"dry_run"`
+	Processed  int  `json:"processed"`
+	Changed    int  `json:"changed"`
+	NextOffset int  `json:"next_offset"`
+	Done       bool `json:"done"`
+}
+
+// StaleEmbedding identifies a recipe whose embedding should be regenerated.
+type StaleEmbedding struct {
+	ID                 string     `json:"id"`
+	Title              string     `json:"title"`
+	UpdatedAt          Timestamp  `json:"updated_at"`
+	EmbeddingUpdatedAt *Timestamp `json:"embedding_updated_at"`
+	// Reason is "missing" when the recipe was never embedded and "outdated" when it was
+	// updated after its embedding was written.
+	Reason string `json:"reason"`
+}
+
+// StaleEmbeddingsResponse lists recipes whose embeddings are missing or outdated.
+type StaleEmbeddingsResponse struct {
+	Count   int              `json:"count"`
+	Recipes []StaleEmbedding `json:"recipes"`
+}
+
+// NewStaleEmbeddingsResponse builds the response from recipes returned by
+// RecipeRepository.ListStaleEmbeddings.
+func NewStaleEmbeddingsResponse(recipes []models.Recipe) StaleEmbeddingsResponse {
+	response := StaleEmbeddingsResponse{Count: len(recipes), Recipes: make([]StaleEmbedding, len(recipes))}
+	for i, recipe := range recipes {
+		stale := StaleEmbedding{ID: recipe.ID, Title: recipe.Title, UpdatedAt: NewTimestamp(recipe.UpdatedAt), Reason: "missing"}
+		if recipe.EmbeddingUpdatedAt != nil {
+			embeddedAt := NewTimestamp(*recipe.EmbeddingUpdatedAt)
+			stale.EmbeddingUpdatedAt = &embeddedAt
+			stale.Reason = "outdated"
+		}
+		response.Recipes[i] = stale
+	}
+	return response
+}
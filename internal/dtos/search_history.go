@@ -0,0 +1,13 @@
+package dtos
+
+// SearchHistoryEntry describes a single recipe search performed by a user.
+type SearchHistoryEntry struct {
+	Query       string    `json:"query"`
+	ResultCount int       `json:"result_count"`
+	SearchedAt  Timestamp `json:"searched_at"`
+}
+
+// SearchHistoryResponse wraps a user's recent searches, newest first.
+type SearchHistoryResponse struct {
+	Searches []SearchHistoryEntry `json:"searches"`
+}
@@ -0,0 +1,15 @@
+package dtos
+
+// ServingsScaleRequest defines the payload for scaling a recipe to a number of servings.
+type ServingsScaleRequest struct {
+	TargetServings int `json:"target_servings" binding:"required,min=1"`
+}
+
+// ServingsScaleResponse returns the scaled recipe together with the ratio applied.
+// Ingredients whose amounts could not be parsed, such as "a pinch", are returned unchanged
+// and listed in UnscaledIngredients.
+type ServingsScaleResponse struct {
+	Ratio               float64        `json:"ratio"`
+	Recipe              RecipeResponse `json:"recipe"`
+	UnscaledIngredients []string       `json:"unscaled_ingredients,omitempty"`
+}
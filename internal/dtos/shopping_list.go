@@ -0,0 +1,84 @@
+package dtos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShoppingListItem is a single ingredient to buy. RecipeIDs lists the recipes that need it on a
+// list combining several recipes.
+type ShoppingListItem struct {
+	Name      string   `json:"name"`
+	Amount    string   `json:"amount"`
+	Unit      string   `json:"unit,omitempty"`
+	RecipeIDs []string `json:"recipe_ids,omitempty"`
+}
+
+// ShoppingList is a recipe's ingredients exported as a standalone checklist.
+type ShoppingList struct {
+	RecipeID string             `json:"recipe_id"`
+	Title    string             `json:"title"`
+	Servings int                `json:"servings,omitempty"`
+	Units    string             `json:"units,omitempty"`
+	Items    []ShoppingListItem `json:"items"`
+}
+
+// NewShoppingList builds a shopping list from a recipe response, keeping any scaling or
+// unit conversion already applied to it.
+func NewShoppingList(recipe *RecipeResponse) *ShoppingList {
+	list := &ShoppingList{
+		RecipeID: recipe.ID,
+		Title:    recipe.Title,
+		Servings: recipe.Servings,
+		Units:    recipe.Units,
+		Items:    make([]ShoppingListItem, len(recipe.Ingredients)),
+	}
+	for i, ing := range recipe.Ingredients {
+		list.Items[i] = ShoppingListItem{Name: ing.Name, Amount: ing.Amount, Unit: ing.Unit}
+	}
+	return list
+}
+
+// Text renders the list as a plain-text checklist suitable for copy-paste.
+func (l *ShoppingList) Text() string {
+	var b strings.Builder
+	b.WriteString("Shopping list: " + l.Title)
+	if l.Servings > 0 {
+		fmt.Fprintf(&b, " (serves %d)", l.Servings)
+	}
+	b.WriteString("\n\n")
+	for _, item := range l.Items {
+		b.WriteString("- [ ] " + strings.Join(strings.Fields(item.Amount+" "+item.Unit+" "+item.Name), " ") + "\n")
+	}
+	return b.String()
+}
+
+// MaxShoppingListRecipes caps the number of recipes a single combined shopping list may include.
+const MaxShoppingListRecipes = 20
+
+// ShoppingListRequest lists the saved recipes to combine into one shopping list.
+type ShoppingListRequest struct {
+	RecipeIDs []string `json:"recipe_ids" binding:"required,min=1,max=20,dive,required"`
+}
+
+// ShoppingListCategory groups the items of one category, such as "produce" or "dairy & eggs".
+type ShoppingListCategory struct {
+	Category string             `json:"category"`
+	Items    []ShoppingListItem `json:"items"`
+}
+
+// ShoppingListRecipeError reports a requested recipe that could not be added to the list.
+type ShoppingListRecipeError struct {
+	RecipeID string        `json:"recipe_id"`
+	Error    ErrorResponse `json:"error"`
+}
+
+// CombinedShoppingList is the consolidated shopping list of the requested recipes that were
+// found. Incompatible lists ingredients needed in amounts that cannot be added up, one entry
+// per amount.
+type CombinedShoppingList struct {
+	RecipeIDs    []string                  `json:"recipe_ids"`
+	Categories   []ShoppingListCategory    `json:"categories"`
+	Incompatible []ShoppingListItem        `json:"incompatible"`
+	Errors       []ShoppingListRecipeError `json:"errors,omitempty"`
+}
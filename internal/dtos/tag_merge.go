@@ -0,0 +1,19 @@
+package dtos
+
+// TagMergeRequest names the tag to keep and the near-duplicate tags merged into it.
+type TagMergeRequest struct {
+	TargetID  string   `json:"target_id" binding:"required"`
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// TagMergeResponse counts the recipes moved to the target tag and the source tags deleted.
+type TagMergeResponse struct {
+	TargetID        string `json:"target_id"`
+	RecipesAffected int64  `json:"recipes_affected"`
+	TagsDeleted     int64  `json:"tags_deleted"`
+}
+
+// TagCleanupResponse counts the unused tags that were deleted.
+type TagCleanupResponse struct {
+	TagsDeleted int64 `json:"tags_deleted"`
+}
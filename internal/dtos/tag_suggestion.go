@@ -0,0 +1,21 @@
+package dtos
+
+// TagSuggestionRequest selects how tags are suggested for a recipe. The body is optional; without
+// it only the rule-based suggestions are returned and nothing is saved.
+type TagSuggestionRequest struct {
+	// UseModel adds a few descriptive tags suggested by the external model.
+	UseModel bool `json:"use_model,omitempty"`
+	// Apply adds the suggestions to the recipe's tags and saves it.
+	Apply bool `json:"apply,omitempty"`
+}
+
+// TagSuggestionResponse lists the tags suggested for a recipe, lower case and without the tags it
+// already has. Tags is the recipe's full tag list, including the suggestions when Applied is set.
+// ModelError reports a failed model call; the rule-based suggestions are returned regardless.
+type TagSuggestionResponse struct {
+	RecipeID    string         `json:"recipe_id"`
+	Suggestions []string       `json:"suggestions"`
+	Applied     bool           `json:"applied"`
+	Tags        []string       `json:"tags"`
+	ModelError  *ErrorResponse `json:"model_error,omitempty"`
+}
@@ -0,0 +1,43 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Timestamp serializes times in API responses as RFC3339 in UTC so every
+// endpoint uses the same format regardless of the server's local zone.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp wraps t for use in a response DTO.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// MarshalJSON encodes the timestamp as an RFC3339 UTC string, or null when unset.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339))
+}
+
+// UnmarshalJSON decodes an RFC3339 string (with optional fractional seconds) or null.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed.UTC()
+	return nil
+}
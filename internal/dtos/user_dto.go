@@ -1,33 +1,64 @@
 package dtos
 
 import (
-	"time"
-
 	"github.com/pageza/alchemorsel-v1/internal/models"
 )
 
-// UserResponse defines the structure exposed to API clients.
+// UserResponse defines the structure exposed to API clients. It never carries the password hash.
 type UserResponse struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	Email         string    `json:"email"`
-	Password      string    `json:"password"`
-	IsAdmin       bool      `json:"is_admin"`
-	EmailVerified bool      `json:"email_verified"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Email          string    `json:"email"`
+	IsAdmin        bool      `json:"is_admin"`
+	EmailVerified  bool      `json:"email_verified"`
+	PreferredUnits string    `json:"preferred_units,omitempty"`
+	CreatedAt      Timestamp `json:"created_at"`
+	UpdatedAt      Timestamp `json:"updated_at"`
 }
 
 // NewUserResponse converts a models.User to a UserResponse DTO.
 func NewUserResponse(user *models.User) UserResponse {
 	return UserResponse{
-		ID:            user.ID,
-		Name:          user.Name,
-		Email:         user.Email,
-		Password:      user.Password,
-		IsAdmin:       user.IsAdmin,
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
-		UpdatedAt:     user.UpdatedAt,
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		IsAdmin:        user.IsAdmin,
+		EmailVerified:  user.EmailVerified,
+		PreferredUnits: user.PreferredUnits,
+		CreatedAt:      NewTimestamp(user.CreatedAt),
+		UpdatedAt:      NewTimestamp(user.UpdatedAt),
 	}
 }
+
+// UserListResponse is one page of the admin user listing.
+type UserListResponse struct {
+	Users []UserResponse `json:"users"`
+	Meta  UserListMeta   `json:"meta"`
+}
+
+// UserListMeta describes the page and search applied to a user listing.
+type UserListMeta struct {
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+	Total int64  `json:"total"`
+	Query string `json:"q,omitempty"`
+}
+
+// TokenResponse is returned by login and token refresh. The access token goes in the
+// Authorization header; the refresh token is exchanged for a new pair before it expires.
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the access token lifetime in seconds.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// RefreshTokenRequest carries a refresh token to exchange or revoke.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest optionally names a refresh token to revoke along with the current access token.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
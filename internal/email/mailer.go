@@ -0,0 +1,108 @@
+// Package email sends transactional mail, such as password reset links, through SMTP or,
+// in development and tests, to the log.
+package email
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"go.uber.org/zap"
+)
+
+// Message is a plain-text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email. It lets callers substitute a fake in tests.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the mailer selected by cfg.Driver: "smtp" sends through the configured server,
+// anything else returns a LogMailer so development and tests never send real mail.
+func New(cfg config.EmailConfig) Mailer {
+	if cfg.Driver == "smtp" {
+		return NewSMTPMailer(cfg)
+	}
+	return LogMailer{}
+}
+
+// LogMailer writes messages to the log instead of sending them.
+type LogMailer struct{}
+
+// secretToken matches the hex tokens carried by reset and verification links.
+var secretToken = regexp.MustCompile(`[0-9a-fA-F]{32,}`)
+
+// Send implements Mailer by logging msg. Tokens in the body are redacted so that the log does
+// not hand out working reset or verification links.
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	zap.L().Info("Email not sent (log mailer)",
+		zap.String("to", msg.To), zap.String("subject", msg.Subject), zap.String("body", redactTokens(msg.Body)))
+	return nil
+}
+
+// redactTokens replaces the reset and verification tokens in text with "[REDACTED]".
+func redactTokens(text string) string {
+	return secretToken.ReplaceAllString(text, "[REDACTED]")
+}
+
+// SMTPMailer sends messages through the SMTP server in EmailConfig, authenticating with
+// PLAIN when a username is configured.
+type SMTPMailer struct {
+	cfg config.EmailConfig
+	// sendMail is smtp.SendMail; tests replace it to capture outgoing mail.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPMailer creates an SMTPMailer for cfg.
+func NewSMTPMailer(cfg config.EmailConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg, sendMail: smtp.SendMail}
+}
+
+// Send implements Mailer.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := buildMessage(m.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	if err := m.sendMail(addr, auth, m.cfg.From, []string{msg.To}, data); err != nil {
+		return fmt.Errorf("failed to send email via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// buildMessage renders msg with the headers SMTP servers expect. Header values containing
+// line breaks are rejected so callers cannot inject extra headers.
+func buildMessage(from string, msg Message) ([]byte, error) {
+	for _, value := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("email header contains a line break: %q", value)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
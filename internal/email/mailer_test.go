@@ -0,0 +1,76 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+)
+
+func TestNewSelectsDriver(t *testing.T) {
+	if _, ok := New(config.EmailConfig{Driver: "smtp"}).(*SMTPMailer); !ok {
+		t.Error("Expected an SMTPMailer for the smtp driver")
+	}
+	if _, ok := New(config.EmailConfig{Driver: "log"}).(LogMailer); !ok {
+		t.Error("Expected a LogMailer for the log driver")
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	m := NewSMTPMailer(config.EmailConfig{Host: "smtp.example.com", Port: 2525, Username: "user", Password: "secret", From: "noreply@example.com"})
+	var addr, from string
+	var to []string
+	var data []byte
+	m.sendMail = func(a string, auth smtp.Auth, f string, t []string, msg []byte) error {
+		addr, from, to, data = a, f, t, msg
+		return nil
+	}
+
+	err := m.Send(context.Background(), Message{To: "cook@example.com", Subject: "Hello", Body: "line one\nline two"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if addr != "smtp.example.com:2525" || from != "noreply@example.com" || len(to) != 1 || to[0] != "cook@example.com" {
+		t.Errorf("Unexpected envelope %s %s %v", addr, from, to)
+	}
+	body := string(data)
+	for _, want := range []string{"From: noreply@example.com\r\n", "To: cook@example.com\r\n", "Subject: Hello\r\n", "\r\n\r\nline one\r\nline two"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected message to contain %q, got %q", want, body)
+		}
+	}
+}
+
+func TestSMTPMailerSendError(t *testing.T) {
+	m := NewSMTPMailer(config.EmailConfig{Host: "smtp.example.com", Port: 25})
+	m.sendMail = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("connection refused") }
+
+	if err := m.Send(context.Background(), Message{To: "cook@example.com"}); err == nil {
+		t.Fatal("Expected an error when the server rejects the message")
+	}
+}
+
+func TestBuildMessageRejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("noreply@example.com", Message{To: "cook@example.com", Subject: "Hi\r\nBcc: someone@example.com"})
+	if err == nil {
+		t.Fatal("Expected an error for a subject containing a line break")
+	}
+}
+
+func TestRedactTokens(t *testing.T) {
+	token := strings.Repeat("a1", 32)
+	body := "Reset here: https://app.example.com/reset?token=" + token + "\nor verify at /v1/users/verify-email/" + token
+	got := redactTokens(body)
+	if strings.Contains(got, token) {
+		t.Errorf("Expected the token to be redacted, got %q", got)
+	}
+	if !strings.Contains(got, "https://app.example.com/reset?token=[REDACTED]") || !strings.Contains(got, "/v1/users/verify-email/[REDACTED]") {
+		t.Errorf("Expected the links to keep their shape, got %q", got)
+	}
+	if plain := "Use the link within 24 hours"; redactTokens(plain) != plain {
+		t.Errorf("Expected text without tokens to be unchanged, got %q", redactTokens(plain))
+	}
+}
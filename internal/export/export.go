@@ -0,0 +1,93 @@
+// Package export renders recipes into printable formats.
+package export
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Limits that keep a card on a single 5x3 inch index card. Content beyond them is
+// truncated and marked with an ellipsis or a "+N more" line.
+const (
+	CardMaxTitle       = 60
+	CardMaxIngredients = 16
+	CardMaxIngredient  = 40
+	CardMaxSteps       = 6
+	CardMaxStep        = 90
+)
+
+// card is the data passed to the card template.
+type card struct {
+	Title           string
+	Language        string
+	Meta            []string
+	Ingredients     []string
+	MoreIngredients int
+	Steps           []string
+	MoreSteps       int
+}
+
+// RenderCard renders a recipe as an HTML page laid out for a single index card:
+// title, servings and timings, ingredients in two columns and abbreviated steps.
+func RenderCard(recipe *dtos.RecipeResponse) ([]byte, error) {
+	data := card{
+		Title:    truncate(recipe.Title, CardMaxTitle),
+		Language: recipe.Language,
+	}
+	if recipe.Servings > 0 {
+		data.Meta = append(data.Meta, fmt.Sprintf("Serves %d", recipe.Servings))
+	}
+	if recipe.PrepTime > 0 {
+		data.Meta = append(data.Meta, fmt.Sprintf("Prep %d min", recipe.PrepTime))
+	}
+	if recipe.CookTime > 0 {
+		data.Meta = append(data.Meta, fmt.Sprintf("Cook %d min", recipe.CookTime))
+	}
+	if recipe.Difficulty != "" {
+		data.Meta = append(data.Meta, recipe.Difficulty)
+	}
+
+	for i, ing := range recipe.Ingredients {
+		if i == CardMaxIngredients {
+			data.MoreIngredients = len(recipe.Ingredients) - i
+			break
+		}
+		line := strings.Join(strings.Fields(ing.Amount+" "+ing.Unit+" "+ing.Name), " ")
+		data.Ingredients = append(data.Ingredients, truncate(line, CardMaxIngredient))
+	}
+	for i, step := range recipe.Steps {
+		if i == CardMaxSteps {
+			data.MoreSteps = len(recipe.Steps) - i
+			break
+		}
+		data.Steps = append(data.Steps, truncate(step.Description, CardMaxStep))
+	}
+
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, "card.html.tmpl", data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// truncate shortens s to at most max characters, ending it with an ellipsis when cut.
+func truncate(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
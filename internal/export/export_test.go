@@ -0,0 +1,80 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+func TestRenderCard(t *testing.T) {
+	recipe := &dtos.RecipeResponse{
+		Title:       "Pancakes",
+		Servings:    4,
+		PrepTime:    10,
+		CookTime:    15,
+		Difficulty:  "easy",
+		Ingredients: []dtos.Ingredient{{Name: "flour", Amount: "2", Unit: "cups"}, {Name: "eggs", Amount: "2"}},
+		Steps:       []dtos.Step{{Order: 1, Description: "Whisk everything."}, {Order: 2, Description: "Fry until golden."}},
+	}
+
+	out, err := RenderCard(recipe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html := string(out)
+	for _, want := range []string{
+		"<h1>Pancakes</h1>",
+		"Serves 4 · Prep 10 min · Cook 15 min · easy",
+		"<li>2 cups flour</li>",
+		"<li>2 eggs</li>",
+		"<li>Fry until golden.</li>",
+		"size: 5in 3in",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected card to contain %q, got:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, `class="more"`) {
+		t.Errorf("Did not expect a truncation indicator, got:\n%s", html)
+	}
+}
+
+func TestRenderCardTruncatesLongContent(t *testing.T) {
+	recipe := &dtos.RecipeResponse{Title: strings.Repeat("Very Long Title ", 10)}
+	for i := 0; i < CardMaxIngredients+3; i++ {
+		recipe.Ingredients = append(recipe.Ingredients, dtos.Ingredient{Name: fmt.Sprintf("ingredient %d", i)})
+	}
+	for i := 0; i < CardMaxSteps+2; i++ {
+		recipe.Steps = append(recipe.Steps, dtos.Step{Order: i + 1, Description: strings.Repeat("stir ", 40)})
+	}
+
+	out, err := RenderCard(recipe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html := string(out)
+	if !strings.Contains(html, "+3 more</li>") {
+		t.Error("Expected an indicator for the omitted ingredients")
+	}
+	if !strings.Contains(html, "+2 more steps</li>") {
+		t.Error("Expected an indicator for the omitted steps")
+	}
+	if strings.Contains(html, fmt.Sprintf("ingredient %d", CardMaxIngredients)) {
+		t.Error("Expected ingredients beyond the limit to be omitted")
+	}
+	if !strings.Contains(html, "…") {
+		t.Error("Expected long text to end with an ellipsis")
+	}
+}
+
+func TestRenderCardEscapesHTML(t *testing.T) {
+	out, err := RenderCard(&dtos.RecipeResponse{Title: "<script>alert(1)</script>"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(out), "<script>") {
+		t.Error("Expected the title to be escaped")
+	}
+}
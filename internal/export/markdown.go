@@ -0,0 +1,105 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+// markdownEscaper backslash-escapes characters that would otherwise be read as Markdown syntax.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// RenderMarkdown renders a recipe as a printable Markdown document: title, description,
+// metadata, an ingredient list, numbered steps and nutrition. Sections without content are omitted.
+func RenderMarkdown(recipe *dtos.RecipeResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", markdownText(recipe.Title))
+	if recipe.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", markdownText(recipe.Description))
+	}
+
+	var meta []string
+	if recipe.Servings > 0 {
+		meta = append(meta, fmt.Sprintf("**Serves:** %d", recipe.Servings))
+	}
+	if recipe.PrepTime > 0 {
+		meta = append(meta, fmt.Sprintf("**Prep:** %d min", recipe.PrepTime))
+	}
+	if recipe.CookTime > 0 {
+		meta = append(meta, fmt.Sprintf("**Cook:** %d min", recipe.CookTime))
+	}
+	if recipe.Difficulty != "" {
+		meta = append(meta, "**Difficulty:** "+markdownText(recipe.Difficulty))
+	}
+	for _, group := range []struct {
+		label string
+		names []string
+	}{
+		{"Cuisine", recipe.Cuisines},
+		{"Diet", recipe.Diets},
+		{"Appliances", recipe.Appliances},
+		{"Tags", recipe.Tags},
+	} {
+		if len(group.names) > 0 {
+			meta = append(meta, fmt.Sprintf("**%s:** %s", group.label, markdownText(strings.Join(group.names, ", "))))
+		}
+	}
+	if len(meta) > 0 {
+		b.WriteString("\n")
+		for _, line := range meta {
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		b.WriteString("\n## Ingredients\n\n")
+		for _, ing := range recipe.Ingredients {
+			line := strings.Join(strings.Fields(ing.Amount+" "+ing.Unit+" "+ing.Name), " ")
+			fmt.Fprintf(&b, "- %s\n", markdownText(line))
+		}
+	}
+
+	if len(recipe.Steps) > 0 {
+		b.WriteString("\n## Instructions\n\n")
+		for i, step := range recipe.Steps {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, markdownText(step.Description))
+		}
+	}
+
+	if recipe.NutritionalInfo != "" {
+		fmt.Fprintf(&b, "\n## Nutrition\n\n%s\n", markdownText(recipe.NutritionalInfo))
+	}
+	if recipe.AllergyDisclaimer != "" {
+		fmt.Fprintf(&b, "\n_%s_\n", markdownText(recipe.AllergyDisclaimer))
+	}
+	return b.String()
+}
+
+// MarkdownFilename suggests a download name for a recipe's Markdown export, derived from its
+// title and falling back to its ID when the title has no usable characters.
+func MarkdownFilename(recipe *dtos.RecipeResponse) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(recipe.Title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			slug.WriteRune(r)
+			dash = false
+		} else if !dash && slug.Len() > 0 {
+			slug.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(slug.String(), "-")
+	if name == "" {
+		name = "recipe-" + recipe.ID
+	}
+	return name + ".md"
+}
+
+// markdownText collapses s onto one line and escapes it for use in Markdown.
+func markdownText(s string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	recipe := &dtos.RecipeResponse{
+		Title:           "Pancakes",
+		Description:     "Fluffy *weekend* pancakes.",
+		Servings:        4,
+		PrepTime:        10,
+		CookTime:        15,
+		Difficulty:      "easy",
+		Cuisines:        []string{"American"},
+		Tags:            []string{"breakfast", "sweet"},
+		Ingredients:     []dtos.Ingredient{{Name: "flour", Amount: "2", Unit: "cups"}, {Name: "eggs", Amount: "2"}},
+		Steps:           []dtos.Step{{Order: 1, Description: "Whisk everything."}, {Order: 2, Description: "Fry until golden."}},
+		NutritionalInfo: "350 kcal per serving",
+	}
+
+	want := `# Pancakes
+
+Fluffy \*weekend\* pancakes.
+
+- **Serves:** 4
+- **Prep:** 10 min
+- **Cook:** 15 min
+- **Difficulty:** easy
+- **Cuisine:** American
+- **Tags:** breakfast, sweet
+
+## Ingredients
+
+- 2 cups flour
+- 2 eggs
+
+## Instructions
+
+1. Whisk everything.
+2. Fry until golden.
+
+## Nutrition
+
+350 kcal per serving
+`
+	if got := RenderMarkdown(recipe); got != want {
+		t.Errorf("Unexpected markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdownOmitsEmptySections(t *testing.T) {
+	got := RenderMarkdown(&dtos.RecipeResponse{Title: "Toast"})
+	if got != "# Toast\n" {
+		t.Errorf("Expected only the title, got:\n%s", got)
+	}
+}
+
+func TestMarkdownFilename(t *testing.T) {
+	for title, want := range map[string]string{
+		"Grandma's Apple Pie!": "grandma-s-apple-pie.md",
+		"  Pad Thai  ":         "pad-thai.md",
+		"Crème brûlée":         "cr-me-br-l-e.md",
+		"???":                  "recipe-42.md",
+	} {
+		if got := MarkdownFilename(&dtos.RecipeResponse{ID: "42", Title: title}); got != want {
+			t.Errorf("MarkdownFilename(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// AccessTokenLifetime is how long an access token issued at login or refresh is valid.
+	AccessTokenLifetime = time.Hour
+	// RefreshTokenGracePeriod lets a refresh token be exchanged shortly after it expires,
+	// e.g. when a client wakes from sleep.
+	RefreshTokenGracePeriod = 5 * time.Minute
+	// DefaultRefreshTokenHours is the refresh token lifetime when JWT_REFRESH_HOURS is not set.
+	DefaultRefreshTokenHours = 168
+)
+
+// refreshTokenType marks refresh tokens so they are never accepted as access tokens.
+const refreshTokenType = "refresh"
+
+var errInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// refreshTokenLifetime reads JWT_REFRESH_HOURS, falling back to DefaultRefreshTokenHours.
+func refreshTokenLifetime() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("JWT_REFRESH_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = DefaultRefreshTokenHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// refreshTokenStore returns the configured store, or an untracked one when none is set.
+func (h *UserHandler) refreshTokenStore() services.RefreshTokenStore {
+	if h.RefreshTokens == nil {
+		return services.NewRefreshTokenStore(nil)
+	}
+	return h.RefreshTokens
+}
+
+// tokenDenylist returns the configured denylist, or one that denies nothing when none is set.
+func (h *UserHandler) tokenDenylist() services.TokenDenylist {
+	if h.Denylist == nil {
+		return services.NewTokenDenylist(nil)
+	}
+	return h.Denylist
+}
+
+// issueTokens signs a new access and refresh token pair for the user and records the refresh token.
+func (h *UserHandler) issueTokens(ctx context.Context, secret, userID string) (dtos.TokenResponse, error) {
+	now := time.Now()
+	// Every token gets a unique ID so revoking one never affects another issued in the same second.
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID,
+		"jti": uuid.NewString(),
+		"exp": now.Add(AccessTokenLifetime).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		return dtos.TokenResponse{}, err
+	}
+
+	lifetime := refreshTokenLifetime()
+	tokenID := uuid.NewString()
+	refresh, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": userID,
+		"jti": tokenID,
+		"typ": refreshTokenType,
+		"exp": now.Add(lifetime).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		return dtos.TokenResponse{}, err
+	}
+	if err := h.refreshTokenStore().Save(ctx, tokenID, userID, lifetime+RefreshTokenGracePeriod); err != nil {
+		return dtos.TokenResponse{}, err
+	}
+
+	return dtos.TokenResponse{Token: access, RefreshToken: refresh, ExpiresIn: int(AccessTokenLifetime.Seconds())}, nil
+}
+
+// parseRefreshToken verifies a refresh token's signature and type and that it expired no more
+// than RefreshTokenGracePeriod ago, returning its user and token IDs.
+func parseRefreshToken(tokenString, secret string) (string, string, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}); err != nil {
+		return "", "", errInvalidRefreshToken
+	}
+	if claims["typ"] != refreshTokenType || !claims.VerifyExpiresAt(time.Now().Add(-RefreshTokenGracePeriod).Unix(), true) {
+		return "", "", errInvalidRefreshToken
+	}
+	userID, _ := claims["sub"].(string)
+	tokenID, _ := claims["jti"].(string)
+	if userID == "" || tokenID == "" {
+		return "", "", errInvalidRefreshToken
+	}
+	return userID, tokenID, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access and refresh token pair. Each refresh
+// token can be used once; the one presented is invalidated.
+// @Summary Refresh access token
+// @Description Exchange a refresh token, including one that expired within the grace period, for new tokens
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dtos.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} dtos.TokenResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req dtos.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "JWT secret not set"})
+		return
+	}
+
+	userID, tokenID, err := parseRefreshToken(req.RefreshToken, secret)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Code: "UNAUTHORIZED", Message: err.Error()})
+		return
+	}
+	active, err := h.refreshTokenStore().Consume(c.Request.Context(), tokenID, userID)
+	if err != nil {
+		zap.S().Errorw("Refresh token lookup failed", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to refresh token"})
+		return
+	}
+	if !active {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Code: "UNAUTHORIZED", Message: errInvalidRefreshToken.Error()})
+		return
+	}
+
+	tokens, err := h.issueTokens(c.Request.Context(), secret, userID)
+	if err != nil {
+		zap.S().Errorw("Token generation failed", "error", err)
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "failed to generate token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes the access token used for the request until it expires, and the refresh
+// token too when one is given in the body. The refresh token must belong to the caller.
+// @Summary Log out
+// @Description Revoke the current access token and, optionally, one of the caller's refresh tokens
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dtos.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	var req dtos.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+	secret := os.Getenv("JWT_SECRET")
+	ctx := c.Request.Context()
+
+	// Check the refresh token before revoking anything, so a rejected request changes nothing.
+	var refreshTokenID string
+	if req.RefreshToken != "" {
+		owner, tokenID, err := parseRefreshToken(req.RefreshToken, secret)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+			return
+		}
+		if owner != userID {
+			c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "Refresh token belongs to another user"})
+			return
+		}
+		refreshTokenID = tokenID
+	}
+
+	if token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); token != "" {
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err == nil {
+			if exp, ok := claims["exp"].(float64); ok {
+				if err := h.tokenDenylist().Deny(ctx, token, time.Unix(int64(exp), 0)); err != nil {
+					zap.S().Errorw("Access token revocation failed", "user_id", userID, "error", err)
+					c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to log out"})
+					return
+				}
+			}
+		}
+	}
+
+	if refreshTokenID != "" {
+		if err := h.refreshTokenStore().Revoke(ctx, refreshTokenID); err != nil {
+			zap.S().Errorw("Refresh token revocation failed", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to log out"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"gorm.io/gorm"
+)
+
+// FavoriteHandler handles recipe favorite HTTP requests for the current user.
+type FavoriteHandler struct {
+	Service services.FavoriteService
+}
+
+// NewFavoriteHandler creates a new FavoriteHandler with the given service.
+func NewFavoriteHandler(service services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{Service: service}
+}
+
+// CheckFavorites reports which of the given recipes the current user has favorited.
+// @Summary Check favorites
+// @Description Check which of the given recipe IDs the current user has favorited
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dtos.FavoriteCheckRequest true "Recipe IDs to check"
+// @Success 200 {object} dtos.FavoriteCheckResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/me/favorites/check [post]
+func (h *FavoriteHandler) CheckFavorites(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.FavoriteCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	if len(req.RecipeIDs) > services.MaxFavoriteCheckIDs {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Code:    "BAD_REQUEST",
+			Message: fmt.Sprintf("At most %d recipe IDs may be checked at once", services.MaxFavoriteCheckIDs),
+		})
+		return
+	}
+
+	favorites, err := h.Service.CheckFavorites(c.Request.Context(), userID, req.RecipeIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to check favorites: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.FavoriteCheckResponse{Favorites: favorites})
+}
+
+// AddFavorite adds a recipe to the current user's favorites.
+// @Summary Favorite a recipe
+// @Description Add the recipe to the current user's favorites
+// @Tags recipes
+// @Param id path string true "Recipe ID"
+// @Success 201
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/favorite [post]
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.AddFavorite(c.Request.Context(), userID, c.Param("id"))
+	switch {
+	case err == nil:
+		c.Status(http.StatusCreated)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+	case errors.Is(err, services.ErrAlreadyFavorited):
+		c.JSON(http.StatusConflict, dtos.ErrorResponse{Code: "CONFLICT", Message: "Recipe is already a favorite"})
+	default:
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to add favorite: " + err.Error()})
+	}
+}
+
+// RemoveFavorite removes a recipe from the current user's favorites.
+// @Summary Unfavorite a recipe
+// @Description Remove the recipe from the current user's favorites
+// @Tags recipes
+// @Param id path string true "Recipe ID"
+// @Success 204
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/favorite [delete]
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	err := h.Service.RemoveFavorite(c.Request.Context(), userID, c.Param("id"))
+	switch {
+	case err == nil:
+		c.Status(http.StatusNoContent)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Favorite not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to remove favorite: " + err.Error()})
+	}
+}
+
+// ListFavorites lists the current user's favorite recipes, most recently favorited first.
+// @Summary List favorites
+// @Description List the current user's favorite recipes, most recently favorited first
+// @Tags users
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dtos.RecipeListResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/me/favorites [get]
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	recipes, err := h.Service.ListFavorites(c.Request.Context(), userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newRecipeListResponse(recipes, dtos.RecipeListMeta{Page: page, Limit: limit, Sort: "favorited_at", Order: "desc"}))
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+)
+
+// GenerationPresetHandler handles the current user's recipe generation presets.
+type GenerationPresetHandler struct {
+	Service services.GenerationPresetService
+}
+
+// NewGenerationPresetHandler creates a new GenerationPresetHandler with the given service.
+func NewGenerationPresetHandler(service services.GenerationPresetService) *GenerationPresetHandler {
+	return &GenerationPresetHandler{Service: service}
+}
+
+// CreatePreset stores a named generation preset for the current user.
+// @Summary Create generation preset
+// @Description Save named default constraints (cuisine, diet, max time, servings) to apply when generating recipes
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body dtos.GenerationPresetRequest true "Preset"
+// @Success 201 {object} models.GenerationPreset
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/me/presets [post]
+func (h *GenerationPresetHandler) CreatePreset(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.GenerationPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	preset, err := h.Service.CreatePreset(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to create preset: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, preset)
+}
+
+// ListPresets returns the current user's generation presets.
+// @Summary List generation presets
+// @Description List the current user's recipe generation presets
+// @Tags users
+// @Produce json
+// @Success 200 {object} dtos.GenerationPresetListResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/me/presets [get]
+func (h *GenerationPresetHandler) ListPresets(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	presets, err := h.Service.ListPresets(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to list presets: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.GenerationPresetListResponse{Presets: presets})
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"go.uber.org/zap"
+)
+
+// recordRecipeAudit adds an entry to the recipe audit log for a change made by the current
+// user. It does nothing when audit is nil; failures are logged and never affect the response.
+func recordRecipeAudit(c *gin.Context, audit services.RecipeAuditService, action string, before, after *models.Recipe) {
+	if audit == nil {
+		return
+	}
+	actorID, _ := getCurrentUserID(c)
+	if err := audit.Record(c.Request.Context(), actorID, action, before, after); err != nil {
+		logging.FromGin(c).Warn("Failed to record recipe audit entry", zap.String("action", action), zap.Error(err))
+	}
+}
+
+// GetRecipeAuditLog returns who created, changed or deleted a recipe and when.
+// @Summary Get a recipe's audit log
+// @Description Admin only. List the creations, updates and deletions of a recipe, newest first, with the fields each update changed. Entries remain after the recipe is deleted
+// @Tags admin
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param limit query int false "Maximum entries to return (1-1000, default 100)"
+// @Success 200 {object} dtos.RecipeAuditLogResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/admin/recipes/{id}/audit-log [get]
+func (h *RecipeHandler) GetRecipeAuditLog(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultRecipeAuditLimit)))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "limit must be between 1 and 1000"})
+		return
+	}
+	if h.Audit == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Recipe audit log is not configured"})
+		return
+	}
+
+	id := c.Param("id")
+	entries, err := h.Audit.ListRecipeAuditLog(c.Request.Context(), id, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dtos.NewRecipeAuditLogResponse(id, entries))
+}
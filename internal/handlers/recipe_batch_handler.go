@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+)
+
+// RecipeBatchHandler generates several recipes in a single request, e.g. to plan a week of meals.
+type RecipeBatchHandler struct {
+	recipes    services.RecipeService
+	resolution services.RecipeResolutionService
+	// Concurrency is the number of model calls run at once; values below one mean one at a time.
+	Concurrency int
+	// Deadline bounds the model calls of a single request; zero means only the request context
+	// does. It must be shorter than the route's timeout so the recipes generated in time are
+	// returned rather than replaced by a 504.
+	Deadline time.Duration
+}
+
+// NewRecipeBatchHandler creates a new instance of RecipeBatchHandler that generates one recipe at a time.
+func NewRecipeBatchHandler(recipes services.RecipeService, resolution services.RecipeResolutionService) *RecipeBatchHandler {
+	return &RecipeBatchHandler{recipes: recipes, resolution: resolution, Concurrency: 1}
+}
+
+// GenerateRecipesBatch generates and saves a recipe for each query, running up to Concurrency
+// model calls at once. A failed query is reported in its own result and does not affect the others.
+// Queries still generating when Deadline passes are reported as CANCELLED alongside the recipes
+// already saved.
+// @Summary Generate several recipes
+// @Description Generate up to 10 recipes in one request. Each result holds the saved recipe or the error for that query; usage is the total tokens spent
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param request body dtos.BatchGenerationRequest true "Queries to generate recipes for"
+// @Success 200 {object} dtos.BatchGenerationResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /v1/recipes/generate/batch [post]
+func (h *RecipeBatchHandler) GenerateRecipesBatch(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	var req dtos.BatchGenerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	ctx, total := integrations.WithUsageTotal(c.Request.Context())
+	batchCtx, cancel := h.withDeadline(ctx)
+	defer cancel()
+	results := make([]dtos.BatchGenerationResult, len(req.Queries))
+	h.forEach(len(req.Queries), func(i int) {
+		results[i] = h.generate(batchCtx, ctx, userID, req.Queries[i])
+	})
+
+	c.JSON(http.StatusOK, dtos.BatchGenerationResponse{
+		Results: results,
+		Usage:   tokenUsage(total.Usage()),
+	})
+}
+
+// withDeadline bounds ctx by Deadline, when one is set.
+func (h *RecipeBatchHandler) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.Deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, h.Deadline)
+}
+
+// forEach calls fn for every index below n, running up to Concurrency calls at once, and
+// returns when all of them have finished.
+func (h *RecipeBatchHandler) forEach(n int, fn func(i int)) {
+	workers := h.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// tokenUsage converts the tokens counted for a request into their response form.
+func tokenUsage(usage integrations.TokenUsage) dtos.TokenUsage {
+	return dtos.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
+// generate creates the recipe for a single query of a batch under batchCtx and saves it under
+// ctx, so a recipe generated just before the batch deadline is still kept.
+func (h *RecipeBatchHandler) generate(batchCtx, ctx context.Context, userID, query string) dtos.BatchGenerationResult {
+	result := dtos.BatchGenerationResult{Query: query}
+	cancelled := &dtos.ErrorResponse{Code: "CANCELLED", Message: "Request ended before this recipe was generated"}
+	if batchCtx.Err() != nil {
+		result.Error = cancelled
+		return result
+	}
+
+	recipe, err := h.resolution.GenerateRecipe(batchCtx, query)
+	if err != nil {
+		if batchCtx.Err() != nil {
+			result.Error = cancelled
+			return result
+		}
+		_, code := modelErrorCode(err)
+		result.Error = &dtos.ErrorResponse{Code: code, Message: "Failed to generate recipe: " + err.Error()}
+		return result
+	}
+	recipe.UserID = &userID
+	if err := h.recipes.SaveRecipe(ctx, recipe); err != nil {
+		result.Error = &dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save recipe: " + err.Error()}
+		return result
+	}
+	result.RecipeID = recipe.ID
+	result.Recipe = dtos.NewRecipeResponse(recipe)
+	return result
+}
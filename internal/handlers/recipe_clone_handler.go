@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CloneRecipe saves an editable copy of a recipe owned by the current user.
+// @Summary Clone a recipe
+// @Description Copy a recipe into a new one owned by the authenticated user, with a fresh ID and embedding. The copy is unapproved, has no ratings and records the original in cloned_from; later edits to either recipe do not affect the other
+// @Tags recipes
+// @Produce json
+// @Param id path string true "ID of the recipe to copy"
+// @Success 201 {object} dtos.RecipeResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/clone [post]
+func (h *RecipeHandler) CloneRecipe(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to retrieve recipe: " + err.Error()})
+		return
+	}
+
+	clone := recipe.Clone(userID)
+	if err := h.Service.SaveRecipe(c.Request.Context(), clone); err != nil {
+		logging.FromGin(c).Error("Failed to save cloned recipe", zap.String("source_id", recipe.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save recipe: " + err.Error()})
+		return
+	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditCreate, nil, clone)
+
+	c.JSON(http.StatusCreated, dtos.NewRecipeResponse(clone))
+}
@@ -9,9 +9,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pageza/alchemorsel-v1/internal/dtos"
 	"github.com/pageza/alchemorsel-v1/internal/errors"
+	"github.com/pageza/alchemorsel-v1/internal/export"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
 	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/pricing"
 	"github.com/pageza/alchemorsel-v1/internal/services"
-	"github.com/sirupsen/logrus"
+	"github.com/pageza/alchemorsel-v1/internal/shopping"
+	"github.com/pageza/alchemorsel-v1/internal/storage"
+	"github.com/pageza/alchemorsel-v1/internal/units"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -19,6 +24,16 @@ import (
 // RecipeHandler handles recipe-related HTTP requests with dependency injection.
 type RecipeHandler struct {
 	Service services.RecipeService
+	// History records searches for the current user when set.
+	History services.SearchHistoryService
+	// Users looks up the current user's preferred measurement units when set.
+	Users services.UserServiceInterface
+	// Pricing estimates recipe costs; pricing.DefaultPrices is used when unset.
+	Pricing *pricing.Estimator
+	// Audit records recipe creations, updates and deletions when set.
+	Audit services.RecipeAuditService
+	// Images stores uploaded recipe photos; uploads fail while it is unset.
+	Images storage.Storage
 }
 
 // NewRecipeHandler creates a new RecipeHandler with the given service.
@@ -31,29 +46,106 @@ func NewRecipeHandler(service services.RecipeService) *RecipeHandler {
 // @Tags recipes
 // @Accept json
 // @Produce json
+// @Param sort query string false "Sort field: created_at, updated_at, title, average_rating or total_time_minutes" default(created_at)
+// @Param order query string false "Sort order: asc or desc" default(desc)
+// @Param difficulty query string false "Filter by difficulty; one of GET /v1/recipes/difficulties"
+// @Param tag query []string false "Filter by tag; repeat for several tags" collectionFormat(multi)
+// @Param tag_match query string false "Whether recipes need all of the tags or any of them: all or any" default(all)
 // @Success 200 {object} dtos.RecipeListResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/recipes [get]
 func (h *RecipeHandler) ListRecipes(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	sort := c.DefaultQuery("sort", "created_at")
-	order := c.DefaultQuery("order", "desc")
+	sort, order, err := services.NormalizeRecipeSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
 
-	recipes, err := h.Service.ListRecipes(c.Request.Context(), page, limit, sort, order)
+	filter, err := recipeFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
+	recipes, err := h.Service.ListRecipes(c.Request.Context(), page, limit, sort, order, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
 		return
 	}
 
-	// Convert to response DTOs
+	c.JSON(http.StatusOK, newRecipeListResponse(recipes, dtos.RecipeListMeta{Page: page, Limit: limit, Sort: sort, Order: order}))
+}
+
+// @Summary List my recipes
+// @Description Get a page of the recipes saved by the authenticated user
+// @Tags recipes
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Recipes per page" default(10)
+// @Param sort query string false "Sort field: created_at, updated_at, title, average_rating or total_time_minutes" default(created_at)
+// @Param order query string false "Sort order: asc or desc" default(desc)
+// @Success 200 {object} dtos.RecipeListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/users/me/recipes [get]
+func (h *RecipeHandler) ListMyRecipes(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	sort, order, err := services.NormalizeRecipeSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
+	recipes, err := h.Service.ListRecipesByUser(c.Request.Context(), userID, page, limit, sort, order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newRecipeListResponse(recipes, dtos.RecipeListMeta{Page: page, Limit: limit, Sort: sort, Order: order}))
+}
+
+// recipeFilterFromQuery reads the difficulty, tag and tag_match query parameters.
+func recipeFilterFromQuery(c *gin.Context) (models.RecipeFilter, error) {
+	var filter models.RecipeFilter
+	difficulty, err := services.ValidateRecipeDifficulty(c.Query("difficulty"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Difficulty = difficulty
+	for _, tag := range c.QueryArray("tag") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	switch match := strings.ToLower(c.DefaultQuery("tag_match", "all")); match {
+	case "all":
+	case "any":
+		filter.MatchAnyTag = true
+	default:
+		return filter, fmt.Errorf("unsupported tag_match: %s (allowed: all, any)", match)
+	}
+	return filter, nil
+}
+
+// newRecipeListResponse converts a page of recipes to response DTOs with its metadata.
+func newRecipeListResponse(recipes []models.Recipe, meta dtos.RecipeListMeta) dtos.RecipeListResponse {
 	var response dtos.RecipeListResponse
 	response.Recipes = make([]dtos.RecipeResponse, len(recipes))
 	for i, recipe := range recipes {
 		response.Recipes[i] = *dtos.NewRecipeResponse(&recipe)
 	}
-
-	c.JSON(http.StatusOK, response)
+	response.Meta = meta
+	return response
 }
 
 // @Summary Get a recipe by ID
@@ -62,12 +154,20 @@ func (h *RecipeHandler) ListRecipes(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Recipe ID"
+// @Param units query string false "Measurement units: metric or imperial. Defaults to the user's preference"
+// @Param estimate_cost query bool false "Include an estimated ingredient cost"
 // @Success 200 {object} models.Recipe
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/recipes/{id} [get]
 func (h *RecipeHandler) GetRecipe(c *gin.Context) {
+	system, err := h.measurementSystem(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
 	id := c.Param("id")
 	recipe, err := h.Service.GetRecipe(c.Request.Context(), id)
 	if err != nil {
@@ -75,9 +175,100 @@ func (h *RecipeHandler) GetRecipe(c *gin.Context) {
 		return
 	}
 	response := dtos.NewRecipeResponse(recipe)
+	if c.Query("estimate_cost") == "true" {
+		if err := h.estimateCost(recipe, response); err != nil {
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to estimate cost: " + err.Error()})
+			return
+		}
+	}
+	if system != "" {
+		response.ConvertUnits(system)
+	}
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary Convert a recipe's units
+// @Description Get a recipe with every convertible ingredient amount expressed in one measurement system. Ingredients with unknown units (e.g. "pinch") or unparseable amounts are left untouched and listed in skipped
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param units query string false "Measurement units: metric or imperial. Defaults to the user's preference"
+// @Success 200 {object} dtos.RecipeConversionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/recipes/{id}/convert [get]
+func (h *RecipeHandler) ConvertRecipeUnits(c *gin.Context) {
+	system, err := h.measurementSystem(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+	if system == "" {
+		system = units.DefaultSystem
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	response := dtos.NewRecipeResponse(recipe)
+	skipped := response.ConvertUnits(system)
+	if skipped == nil {
+		skipped = []string{}
+	}
+	c.JSON(http.StatusOK, dtos.RecipeConversionResponse{Recipe: response, Skipped: skipped})
+}
+
+// estimateCost adds the estimated ingredient cost and any unpriced ingredients to response.
+func (h *RecipeHandler) estimateCost(recipe *models.Recipe, response *dtos.RecipeResponse) error {
+	estimator := h.Pricing
+	if estimator == nil {
+		estimator = pricing.NewEstimator(pricing.DefaultPrices)
+	}
+	ingredients, err := recipe.GetIngredients()
+	if err != nil {
+		return err
+	}
+	total, unpriced, err := estimator.Estimate(ingredients)
+	if err != nil {
+		return err
+	}
+	response.EstimatedCost = &total
+	response.UnpricedIngredients = unpriced
+	return nil
+}
+
+// measurementSystem resolves the units recipe amounts are returned in. An explicit ?units=
+// parameter wins, then the current user's preference, then units.DefaultSystem. It returns an
+// empty system, leaving amounts as stored, when there is no parameter and no user lookup.
+func (h *RecipeHandler) measurementSystem(c *gin.Context) (units.System, error) {
+	if value := c.Query("units"); value != "" {
+		return units.ParseSystem(value)
+	}
+	if h.Users == nil {
+		return "", nil
+	}
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		return "", nil
+	}
+	user, err := h.Users.GetUser(c.Request.Context(), userID)
+	if err != nil || user == nil || user.PreferredUnits == "" {
+		return units.DefaultSystem, nil
+	}
+	if system, err := units.ParseSystem(user.PreferredUnits); err == nil {
+		return system, nil
+	}
+	return units.DefaultSystem, nil
+}
+
 // @Summary Create a new recipe
 // @Description Create a new recipe with the provided details
 // @Tags recipes
@@ -91,7 +282,7 @@ func (h *RecipeHandler) GetRecipe(c *gin.Context) {
 func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 	var recipeReq dtos.RecipeRequest
 	if err := c.ShouldBindJSON(&recipeReq); err != nil {
-		logrus.WithError(err).Error("Failed to bind JSON request")
+		logging.FromGin(c).Error("Failed to bind JSON request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
 		return
 	}
@@ -122,9 +313,20 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		}
 	}
 
+	// Validate language
+	language, langErr := services.NormalizeRecipeLanguage(recipeReq.Language)
+	if langErr != nil {
+		validationErrors = append(validationErrors, langErr.Error())
+	}
+	// Validate difficulty
+	difficulty, difficultyErr := services.ValidateRecipeDifficulty(recipeReq.Difficulty)
+	if difficultyErr != nil {
+		validationErrors = append(validationErrors, difficultyErr.Error())
+	}
+
 	// If there are validation errors, return them all at once
 	if len(validationErrors) > 0 {
-		logrus.WithField("errors", validationErrors).Error("Validation failed")
+		logging.FromGin(c).Error("Validation failed", zap.Strings("errors", validationErrors))
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
 			Code:    "BAD_REQUEST",
 			Message: strings.Join(validationErrors, "; "),
@@ -138,12 +340,16 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		Description:       recipeReq.Description,
 		NutritionalInfo:   recipeReq.NutritionalInfo,
 		AllergyDisclaimer: recipeReq.AllergyDisclaimer,
-		Difficulty:        recipeReq.Difficulty,
+		Difficulty:        difficulty,
 		PrepTime:          recipeReq.PrepTime,
 		CookTime:          recipeReq.CookTime,
 		Servings:          recipeReq.Servings,
+		Language:          language,
 		Approved:          recipeReq.Approved,
 	}
+	if userID, ok := getCurrentUserID(c); ok {
+		recipe.UserID = &userID
+	}
 
 	// Convert ingredients
 	ingredients := make([]models.Ingredient, len(recipeReq.Ingredients))
@@ -155,7 +361,7 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		}
 	}
 	if err := recipe.SetIngredients(ingredients); err != nil {
-		logrus.WithError(err).Error("Failed to set ingredients")
+		logging.FromGin(c).Error("Failed to set ingredients", zap.Error(err))
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Failed to set ingredients: " + err.Error()})
 		return
 	}
@@ -169,7 +375,7 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 		}
 	}
 	if err := recipe.SetSteps(steps); err != nil {
-		logrus.WithError(err).Error("Failed to set steps")
+		logging.FromGin(c).Error("Failed to set steps", zap.Error(err))
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Failed to set steps: " + err.Error()})
 		return
 	}
@@ -190,18 +396,57 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 
 	// Save recipe
 	if err := h.Service.SaveRecipe(c.Request.Context(), recipe); err != nil {
-		logrus.WithError(err).Error("Failed to save recipe")
+		logging.FromGin(c).Error("Failed to save recipe", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save recipe: " + err.Error()})
 		return
 	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditCreate, nil, recipe)
 
 	// Return created recipe
 	response := dtos.NewRecipeResponse(recipe)
 	c.JSON(http.StatusCreated, response)
 }
 
+// @Summary Import a recipe
+// @Description Import a recipe pasted as JSON, e.g. from another tool. It is validated like AI-generated recipes, must not contain unknown fields and is saved unapproved
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param recipe body dtos.RecipeRequest true "Recipe to import; approved and images are not accepted"
+// @Success 201 {object} dtos.RecipeImportResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/import [post]
+func (h *RecipeHandler) ImportRecipe(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Failed to read request body"})
+		return
+	}
+
+	recipe, err := services.ParseImportedRecipe(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+	recipe.UserID = &userID
+
+	if err := h.Service.SaveRecipe(c.Request.Context(), recipe); err != nil {
+		logging.FromGin(c).Error("Failed to save imported recipe", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save recipe: " + err.Error()})
+		return
+	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditCreate, nil, recipe)
+	c.JSON(http.StatusCreated, dtos.RecipeImportResponse{RecipeID: recipe.ID, Status: dtos.RecipeImportStatusPending})
+}
+
 // @Summary Update a recipe
-// @Description Update an existing recipe with the provided details
+// @Description Update an existing recipe owned by the authenticated user
 // @Tags recipes
 // @Accept json
 // @Produce json
@@ -210,9 +455,15 @@ func (h *RecipeHandler) SaveRecipe(c *gin.Context) {
 // @Success 200 {object} dtos.RecipeResponse
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
 // @Failure 404 {object} dtos.ErrorResponse
 // @Router /v1/recipes/{id} [put]
 func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Recipe ID is required"})
@@ -225,6 +476,17 @@ func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
 		return
 	}
 
+	language, err := services.NormalizeRecipeLanguage(recipeReq.Language)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+	difficulty, err := services.ValidateRecipeDifficulty(recipeReq.Difficulty)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
 	// Get existing recipe
 	recipe, err := h.Service.GetRecipe(c.Request.Context(), id)
 	if err != nil {
@@ -235,16 +497,22 @@ func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
 		return
 	}
+	if recipe.UserID == nil || *recipe.UserID != userID {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "You do not have permission to update this recipe"})
+		return
+	}
+	before := *recipe
 
 	// Update recipe fields
 	recipe.Title = recipeReq.Title
 	recipe.Description = recipeReq.Description
 	recipe.NutritionalInfo = recipeReq.NutritionalInfo
 	recipe.AllergyDisclaimer = recipeReq.AllergyDisclaimer
-	recipe.Difficulty = recipeReq.Difficulty
+	recipe.Difficulty = difficulty
 	recipe.PrepTime = recipeReq.PrepTime
 	recipe.CookTime = recipeReq.CookTime
 	recipe.Servings = recipeReq.Servings
+	recipe.Language = language
 	recipe.Approved = recipeReq.Approved
 
 	// Convert and validate ingredients
@@ -282,23 +550,31 @@ func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
 		return
 	}
 
-	// Convert string arrays to models
-	recipe.Cuisines = nil
-	recipe.Diets = nil
-	recipe.Appliances = nil
-	recipe.Tags = nil
-
-	for _, name := range recipeReq.Cuisines {
-		recipe.Cuisines = append(recipe.Cuisines, models.Cuisine{Name: name})
+	// Convert string arrays to models. Omitted (or null) lists keep the current values;
+	// an empty list clears them.
+	if recipeReq.Cuisines != nil {
+		recipe.Cuisines = make([]models.Cuisine, 0, len(recipeReq.Cuisines))
+		for _, name := range recipeReq.Cuisines {
+			recipe.Cuisines = append(recipe.Cuisines, models.Cuisine{Name: name})
+		}
 	}
-	for _, name := range recipeReq.Diets {
-		recipe.Diets = append(recipe.Diets, models.Diet{Name: name})
+	if recipeReq.Diets != nil {
+		recipe.Diets = make([]models.Diet, 0, len(recipeReq.Diets))
+		for _, name := range recipeReq.Diets {
+			recipe.Diets = append(recipe.Diets, models.Diet{Name: name})
+		}
 	}
-	for _, name := range recipeReq.Appliances {
-		recipe.Appliances = append(recipe.Appliances, models.Appliance{Name: name})
+	if recipeReq.Appliances != nil {
+		recipe.Appliances = make([]models.Appliance, 0, len(recipeReq.Appliances))
+		for _, name := range recipeReq.Appliances {
+			recipe.Appliances = append(recipe.Appliances, models.Appliance{Name: name})
+		}
 	}
-	for _, name := range recipeReq.Tags {
-		recipe.Tags = append(recipe.Tags, models.Tag{Name: name})
+	if recipeReq.Tags != nil {
+		recipe.Tags = make([]models.Tag, 0, len(recipeReq.Tags))
+		for _, name := range recipeReq.Tags {
+			recipe.Tags = append(recipe.Tags, models.Tag{Name: name})
+		}
 	}
 
 	// Update recipe
@@ -310,6 +586,7 @@ func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
 		return
 	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditUpdate, &before, recipe)
 
 	// Convert to response DTO
 	response := dtos.NewRecipeResponse(recipe)
@@ -317,17 +594,38 @@ func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
 }
 
 // @Summary Delete a recipe
-// @Description Delete a recipe by its ID
+// @Description Delete a recipe owned by the authenticated user
 // @Tags recipes
 // @Accept json
 // @Produce json
 // @Param id path string true "Recipe ID"
 // @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/recipes/{id} [delete]
 func (h *RecipeHandler) DeleteRecipe(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
 	id := c.Param("id")
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to retrieve recipe: " + err.Error()})
+		return
+	}
+	if recipe.UserID == nil || *recipe.UserID != userID {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "You do not have permission to delete this recipe"})
+		return
+	}
+
 	if err := h.Service.DeleteRecipe(c.Request.Context(), id); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
@@ -336,9 +634,375 @@ func (h *RecipeHandler) DeleteRecipe(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to delete recipe: " + err.Error()})
 		return
 	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditDelete, recipe, nil)
 	c.Status(http.StatusNoContent)
 }
 
+// @Summary Scale a recipe to a different pan size
+// @Description Scale ingredient amounts by the ratio of the pans' areas and adjust the bake time. Temperatures are unchanged
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param request body dtos.PanScaleRequest true "Source and target pan dimensions"
+// @Success 200 {object} dtos.PanScaleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/recipes/{id}/scale-pan [post]
+func (h *RecipeHandler) ScalePan(c *gin.Context) {
+	var req dtos.PanScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	ratio, err := units.PanScaleRatio(units.Pan(req.Source), units.Pan(req.Target))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	response := dtos.NewRecipeResponse(recipe)
+	for i, ing := range response.Ingredients {
+		response.Ingredients[i].Amount, _ = units.ScaleAmount(ing.Amount, ratio)
+	}
+	response.CookTime = units.ScaleBakeTime(response.CookTime, ratio)
+
+	c.JSON(http.StatusOK, dtos.PanScaleResponse{Ratio: ratio, Recipe: *response})
+}
+
+// @Summary Scale a recipe to a number of servings
+// @Description Multiply ingredient amounts by target_servings divided by the recipe's servings. Units are unchanged and the scaled recipe is not saved
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param request body dtos.ServingsScaleRequest true "Target number of servings"
+// @Success 200 {object} dtos.ServingsScaleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/recipes/{id}/scale [post]
+func (h *RecipeHandler) ScaleRecipe(c *gin.Context) {
+	var req dtos.ServingsScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+	if recipe.Servings <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Recipe does not specify servings and cannot be scaled"})
+		return
+	}
+
+	ratio := float64(req.TargetServings) / float64(recipe.Servings)
+	response := dtos.ServingsScaleResponse{Ratio: ratio, Recipe: *dtos.NewRecipeResponse(recipe)}
+	for i, ing := range response.Recipe.Ingredients {
+		amount, ok := units.ScaleAmount(ing.Amount, ratio)
+		if !ok {
+			response.UnscaledIngredients = append(response.UnscaledIngredients, ing.Name)
+			continue
+		}
+		response.Recipe.Ingredients[i].Amount = amount
+	}
+	response.Recipe.Servings = req.TargetServings
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Export a recipe's shopping list
+// @Description Download the recipe's ingredients as a checklist, optionally scaled to a number of servings and converted to a measurement system
+// @Tags recipes
+// @Produce plain
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param format query string false "Output format: text (default) or json"
+// @Param servings query int false "Scale ingredient amounts to this many servings"
+// @Param units query string false "Measurement system: metric or imperial"
+// @Success 200 {object} dtos.ShoppingList
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/recipes/{id}/shopping-list [get]
+func (h *RecipeHandler) ExportShoppingList(c *gin.Context) {
+	format := c.DefaultQuery("format", "text")
+	if format != "text" && format != "json" {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "format must be text or json"})
+		return
+	}
+	servings := 0
+	if value := c.Query("servings"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "servings must be a positive integer"})
+			return
+		}
+		servings = n
+	}
+	system, err := h.measurementSystem(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	response := dtos.NewRecipeResponse(recipe)
+	if servings > 0 && servings != recipe.Servings {
+		if recipe.Servings <= 0 {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Recipe does not specify servings and cannot be scaled"})
+			return
+		}
+		ratio := float64(servings) / float64(recipe.Servings)
+		for i, ing := range response.Ingredients {
+			response.Ingredients[i].Amount, _ = units.ScaleAmount(ing.Amount, ratio)
+		}
+		response.Servings = servings
+	}
+	if system != "" {
+		response.ConvertUnits(system)
+	}
+
+	list := dtos.NewShoppingList(response)
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"shopping-list-%s.json\"", recipe.ID))
+		c.JSON(http.StatusOK, list)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"shopping-list-%s.txt\"", recipe.ID))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(list.Text()))
+}
+
+// GenerateShoppingList merges the ingredients of several saved recipes into one shopping list,
+// grouped by category. Recipes that cannot be loaded are reported in errors and left out.
+// @Summary Generate a shopping list
+// @Description Combine the ingredients of up to 20 recipes, summing amounts whose units are compatible. Amounts that cannot be added up are listed under incompatible, and recipes that cannot be found are reported per ID under errors
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param units query string false "Measurement units: metric or imperial. Defaults to the user's preference"
+// @Param request body dtos.ShoppingListRequest true "Recipe IDs"
+// @Success 200 {object} dtos.CombinedShoppingList
+// @Failure 400 {object} ErrorResponse
+// @Router /v1/recipes/shopping-list [post]
+func (h *RecipeHandler) GenerateShoppingList(c *gin.Context) {
+	var req dtos.ShoppingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	system, err := h.measurementSystem(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+	if system == "" {
+		system = units.DefaultSystem
+	}
+
+	response := dtos.CombinedShoppingList{RecipeIDs: []string{}}
+	builder := shopping.NewBuilder(system)
+	seen := make(map[string]bool)
+	for _, id := range req.RecipeIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		recipe, err := h.Service.GetRecipe(c.Request.Context(), id)
+		if err != nil {
+			recipeErr := dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()}
+			if err == gorm.ErrRecordNotFound {
+				recipeErr = dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"}
+			}
+			response.Errors = append(response.Errors, dtos.ShoppingListRecipeError{RecipeID: id, Error: recipeErr})
+			continue
+		}
+		ingredients, err := recipe.GetIngredients()
+		if err != nil {
+			response.Errors = append(response.Errors, dtos.ShoppingListRecipeError{
+				RecipeID: id,
+				Error:    dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to read ingredients: " + err.Error()},
+			})
+			continue
+		}
+		builder.Add(id, ingredients)
+		response.RecipeIDs = append(response.RecipeIDs, id)
+	}
+
+	list := builder.List()
+	response.Categories = []dtos.ShoppingListCategory{}
+	for _, item := range list.Items {
+		if n := len(response.Categories); n == 0 || response.Categories[n-1].Category != item.Category {
+			response.Categories = append(response.Categories, dtos.ShoppingListCategory{Category: item.Category})
+		}
+		category := &response.Categories[len(response.Categories)-1]
+		category.Items = append(category.Items, shoppingListItem(item))
+	}
+	response.Incompatible = make([]dtos.ShoppingListItem, len(list.Incompatible))
+	for i, item := range list.Incompatible {
+		response.Incompatible[i] = shoppingListItem(item)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func shoppingListItem(item shopping.Item) dtos.ShoppingListItem {
+	return dtos.ShoppingListItem{Name: item.Name, Amount: item.Amount, Unit: item.Unit, RecipeIDs: item.RecipeIDs}
+}
+
+// @Summary Export a recipe
+// @Description Download a recipe in a printable format. card is a compact HTML page sized for a 5x3 inch index card; long content is truncated. markdown is the full recipe as a Markdown document
+// @Tags recipes
+// @Produce html
+// @Produce text/markdown
+// @Param id path string true "Recipe ID"
+// @Param format query string false "Output format: card (default) or markdown"
+// @Param units query string false "Measurement system: metric or imperial"
+// @Success 200 {string} string
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/recipes/{id}/export [get]
+func (h *RecipeHandler) ExportRecipe(c *gin.Context) {
+	format := c.DefaultQuery("format", "card")
+	if format != "card" && format != "markdown" {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "format must be card or markdown"})
+		return
+	}
+	system, err := h.measurementSystem(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	response := dtos.NewRecipeResponse(recipe)
+	if system != "" {
+		response.ConvertUnits(system)
+	}
+
+	if format == "markdown" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", export.MarkdownFilename(response)))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(export.RenderMarkdown(response)))
+		return
+	}
+
+	body, err := export.RenderCard(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to render recipe card"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"recipe-%s-card.html\"", recipe.ID))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", body)
+}
+
+// @Summary Regenerate recipe embeddings
+// @Description Admin only. Recompute the embeddings of one batch of stored recipes, e.g. after the embedding model changes. Repeat with offset=next_offset until done is true to cover every recipe
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Report how many embeddings would change without writing them"
+// @Param offset query int false "Number of recipes to skip; the next_offset of the previous batch"
+// @Param batch_size query int false "Recipes embedded in this batch (1-100, default 100)"
+// @Success 200 {object} dtos.ReindexEmbeddingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /v1/admin/recipes/reindex-embeddings [post]
+func (h *RecipeHandler) ReindexEmbeddings(c *gin.Context) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "offset must be a non-negative integer"})
+		return
+	}
+	batchSize, err := strconv.Atoi(c.DefaultQuery("batch_size", strconv.Itoa(services.DefaultReindexBatchSize)))
+	if err != nil || batchSize < 1 || batchSize > services.MaxReindexBatchSize {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: fmt.Sprintf("batch_size must be between 1 and %d", services.MaxReindexBatchSize)})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	result, err := h.Service.ReindexEmbeddings(c.Request.Context(), offset, batchSize, dryRun)
+	if err != nil {
+		status, code := modelErrorCode(err)
+		c.JSON(status, dtos.ErrorResponse{
+			Code:    code,
+			Message: fmt.Sprintf("Reindex batch failed and was not written; retry with offset=%d: %v", result.NextOffset, err),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, dtos.ReindexEmbeddingsResponse{
+		DryRun:     dryRun,
+		Processed:  result.Processed,
+		Changed:    result.Changed,
+		NextOffset: result.NextOffset,
+		Done:       result.Done,
+	})
+}
+
+// @Summary List recipes with stale embeddings
+// @Description Admin only. List recipes that have no embedding or were updated after their embedding was generated, so reindexing can be targeted
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Maximum recipes to return (1-1000, default 100)"
+// @Success 200 {object} dtos.StaleEmbeddingsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v1/admin/recipes/stale-embeddings [get]
+func (h *RecipeHandler) ListStaleEmbeddings(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultStaleEmbeddingsLimit)))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "limit must be between 1 and 1000"})
+		return
+	}
+
+	recipes, err := h.Service.ListStaleEmbeddings(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dtos.NewStaleEmbeddingsResponse(recipes))
+}
+
 // @Summary Resolve a recipe
 // @Description Resolve a recipe based on a query and attributes
 // @Tags recipes
@@ -370,37 +1034,42 @@ func (h *RecipeHandler) ResolveRecipe(c *gin.Context) {
 
 // RateRecipe handles rating a recipe.
 // @Summary Rate a recipe
-// @Description Add a rating to a recipe
+// @Description Rate a recipe from 0 to 5. Each user has one rating per recipe; rating again replaces it
 // @Tags recipes
 // @Accept json
 // @Produce json
 // @Param id path string true "Recipe ID"
-// @Param rating body float64 true "Rating value"
+// @Param rating body dtos.RateRecipeRequest true "Rating value"
 // @Success 200 {object} dtos.RecipeResponse
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
 // @Failure 404 {object} dtos.ErrorResponse
 // @Router /v1/recipes/{id}/rate [post]
 func (h *RecipeHandler) RateRecipe(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
 	id := c.Param("id")
 	if id == "" {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Recipe ID is required"})
 		return
 	}
 
-	var rating float64
-	if err := c.ShouldBindJSON(&rating); err != nil {
-		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid rating value"})
+	var req dtos.RateRecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
 		return
 	}
 
+	rating := *req.Rating
 	if rating < 0 || rating > 5 {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Rating must be between 0 and 5"})
 		return
 	}
 
-	if err := h.Service.RateRecipe(c.Request.Context(), id, rating); err != nil {
-		if err == gorm.ErrRecordNotFound {
+	if err := h.Service.RateRecipe(c.Request.Context(), id, userID, rating); err != nil {
+		if isNotFound(err) {
 			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
 			return
 		}
@@ -421,12 +1090,12 @@ func (h *RecipeHandler) RateRecipe(c *gin.Context) {
 
 // GetRecipeRatings handles retrieving ratings for a recipe.
 // @Summary Get recipe ratings
-// @Description Get all ratings for a recipe
+// @Description Get the number of ratings and the average rating of a recipe
 // @Tags recipes
 // @Accept json
 // @Produce json
 // @Param id path string true "Recipe ID"
-// @Success 200 {array} float64
+// @Success 200 {object} dtos.RecipeRatingsResponse
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
 // @Failure 404 {object} dtos.ErrorResponse
@@ -438,9 +1107,9 @@ func (h *RecipeHandler) GetRecipeRatings(c *gin.Context) {
 		return
 	}
 
-	ratings, err := h.Service.GetRecipeRatings(c.Request.Context(), id)
+	summary, err := h.Service.GetRecipeRatings(c.Request.Context(), id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if isNotFound(err) {
 			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
 			return
 		}
@@ -448,7 +1117,71 @@ func (h *RecipeHandler) GetRecipeRatings(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, ratings)
+	c.JSON(http.StatusOK, dtos.RecipeRatingsResponse{RecipeID: id, Count: summary.Count, Average: summary.Average})
+}
+
+// isNotFound reports whether err means the recipe does not exist, either as GORM's
+// ErrRecordNotFound or as the not-found error returned by the repository.
+func isNotFound(err error) bool {
+	if err == gorm.ErrRecordNotFound {
+		return true
+	}
+	appErr, ok := err.(*errors.Error)
+	return ok && appErr.Code == errors.ErrNotFound
+}
+
+// SearchByIngredients finds recipes that can be made with the given ingredients.
+// @Summary Search recipes by ingredients
+// @Description Find recipes using the given ingredients, ranked by the share of them each recipe uses less a penalty for the ingredients it still needs
+// @Tags recipes
+// @Produce json
+// @Param ingredients query []string true "Ingredients on hand"
+// @Param limit query int false "Maximum number of results" default(20)
+// @Success 200 {object} dtos.IngredientSearchResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/search/ingredients [get]
+func (h *RecipeHandler) SearchByIngredients(c *gin.Context) {
+	var ingredients []string
+	for _, name := range c.QueryArray("ingredients") {
+		if name = strings.TrimSpace(name); name != "" {
+			ingredients = append(ingredients, name)
+		}
+	}
+	if len(ingredients) == 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "At least one ingredient is required"})
+		return
+	}
+	if len(ingredients) > services.MaxSearchIngredients {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Code:    "BAD_REQUEST",
+			Message: fmt.Sprintf("At most %d ingredients may be searched at once", services.MaxSearchIngredients),
+		})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "limit must be a positive integer"})
+		return
+	}
+
+	matches, err := h.Service.SearchByIngredients(c.Request.Context(), ingredients, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	response := dtos.IngredientSearchResponse{Results: make([]dtos.IngredientSearchResult, len(matches))}
+	for i, match := range matches {
+		response.Results[i] = dtos.IngredientSearchResult{
+			Recipe:             *dtos.NewRecipeResponse(&match.Recipe),
+			MatchedIngredients: match.Matched,
+			MissingIngredients: match.Missing,
+			Score:              match.Score,
+		}
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // SearchRecipes handles searching for recipes.
@@ -459,7 +1192,7 @@ func (h *RecipeHandler) GetRecipeRatings(c *gin.Context) {
 // @Produce json
 // @Param q query string false "Search query"
 // @Param tags query []string false "Filter by tags"
-// @Param difficulty query string false "Filter by difficulty"
+// @Param difficulty query string false "Filter by difficulty; one of GET /v1/recipes/difficulties"
 // @Success 200 {object} dtos.RecipeListResponse
 // @Failure 400 {object} dtos.ErrorResponse
 // @Failure 401 {object} dtos.ErrorResponse
@@ -467,7 +1200,11 @@ func (h *RecipeHandler) GetRecipeRatings(c *gin.Context) {
 func (h *RecipeHandler) SearchRecipes(c *gin.Context) {
 	query := c.Query("q")
 	tags := c.QueryArray("tags")
-	difficulty := c.Query("difficulty")
+	difficulty, err := services.ValidateRecipeDifficulty(c.Query("difficulty"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: err.Error()})
+		return
+	}
 
 	recipes, err := h.Service.SearchRecipes(c.Request.Context(), query, tags, difficulty)
 	if err != nil {
@@ -482,12 +1219,32 @@ func (h *RecipeHandler) SearchRecipes(c *gin.Context) {
 		response.Recipes[i] = *dtos.NewRecipeResponse(&recipe)
 	}
 
+	// Record the search for the user's history; failures must not affect the response.
+	if h.History != nil {
+		if userID, ok := getCurrentUserID(c); ok {
+			if err := h.History.RecordSearch(c.Request.Context(), userID, query, len(recipes)); err != nil {
+				logging.FromGin(c).Warn("Failed to record search history", zap.Error(err))
+			}
+		}
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+// ListDifficulties returns the allowed recipe difficulty levels.
+// @Summary List recipe difficulties
+// @Description Get the difficulty levels recipes may be saved with or filtered by
+// @Tags recipes
+// @Produce json
+// @Success 200 {object} dtos.RecipeDifficultiesResponse
+// @Router /v1/recipes/difficulties [get]
+func (h *RecipeHandler) ListDifficulties(c *gin.Context) {
+	c.JSON(http.StatusOK, dtos.RecipeDifficultiesResponse{Difficulties: services.RecipeDifficulties()})
+}
+
 // ResolveRecipeRequest represents the request body for recipe resolution
 type ResolveRecipeRequest struct {
-	Query      string                 `json:"query" binding:"required"`
+	Query      string                 `json:"query" binding:"required,max=500"`
 	Attributes map[string]interface{} `json:"attributes"`
 }
 
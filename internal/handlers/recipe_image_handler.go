@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// recipeImageFormOverhead allows for the multipart headers around an uploaded photo.
+const recipeImageFormOverhead = 64 << 10
+
+// UploadRecipeImage stores a photo of the finished dish and records its URL on the recipe.
+// @Summary Upload a recipe photo
+// @Description Upload a JPEG, PNG or WebP photo of at most 5 MB as the "image" field of a multipart form. It replaces the recipe's current photo. Only the recipe's owner may upload
+// @Tags recipes
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param image formData file true "Photo of the finished dish"
+// @Success 200 {object} dtos.RecipeImageResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 413 {object} dtos.ErrorResponse
+// @Failure 415 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/image [post]
+func (h *RecipeHandler) UploadRecipeImage(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	if h.Images == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Image storage is not configured"})
+		return
+	}
+
+	recipe, err := h.Service.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to retrieve recipe: " + err.Error()})
+		return
+	}
+	if recipe.UserID == nil || *recipe.UserID != userID {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "You do not have permission to change this recipe"})
+		return
+	}
+
+	data, ok := readRecipeImage(c)
+	if !ok {
+		return
+	}
+	contentType, ext, err := services.ValidateRecipeImage(data)
+	if err != nil {
+		status, code := http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"
+		if errors.Is(err, services.ErrRecipeImageTooLarge) {
+			status, code = http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"
+		}
+		c.JSON(status, dtos.ErrorResponse{Code: code, Message: err.Error()})
+		return
+	}
+
+	key := services.RecipeImageKey(recipe.ID, ext)
+	url, err := h.Images.Put(c.Request.Context(), key, contentType, data)
+	if err != nil {
+		logging.FromGin(c).Error("Failed to store recipe image", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to store image"})
+		return
+	}
+
+	before := *recipe
+	recipe.ImageURL = url
+	if err := h.Service.UpdateRecipe(c.Request.Context(), recipe); err != nil {
+		if err := h.Images.Delete(c.Request.Context(), key); err != nil {
+			logging.FromGin(c).Warn("Failed to delete unused recipe image", zap.String("key", key), zap.Error(err))
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save recipe: " + err.Error()})
+		return
+	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditUpdate, &before, recipe)
+
+	c.JSON(http.StatusOK, dtos.RecipeImageResponse{RecipeID: recipe.ID, ImageURL: url})
+}
+
+// readRecipeImage reads the "image" file of a multipart upload, responding 400 when it is
+// missing and 413 when the upload exceeds services.MaxRecipeImageBytes.
+func readRecipeImage(c *gin.Context) ([]byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxRecipeImageBytes+recipeImageFormOverhead)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, dtos.ErrorResponse{Code: "PAYLOAD_TOO_LARGE", Message: services.ErrRecipeImageTooLarge.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "An image file is required in the \"image\" form field"})
+		return nil, false
+	}
+	if header.Size > services.MaxRecipeImageBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, dtos.ErrorResponse{Code: "PAYLOAD_TOO_LARGE", Message: services.ErrRecipeImageTooLarge.Error()})
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to read image"})
+		return nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxRecipeImageBytes+1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to read image"})
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+)
+
+// mealPlanMeals names the meals of a day for each supported number of meals per day.
+var mealPlanMeals = map[int][]string{
+	1: {"dinner"},
+	2: {"lunch", "dinner"},
+	3: {"breakfast", "lunch", "dinner"},
+	4: {"breakfast", "lunch", "dinner", "snack"},
+}
+
+// GenerateMealPlan generates and saves a recipe for every meal of a multi-day plan, running up to
+// Concurrency model calls at once. Each meal is generated from a query combining the meal, the
+// dietary restrictions, the preferences and its share of the calorie target, so the constraints
+// GenerateRecipe derives with ParseRecipeQuery apply to every meal. Plans longer than a day also
+// name the day and ask for a different dish than on the other days, so the same meal varies
+// across the plan. A failed meal is reported in its own entry and does not affect the others, and
+// meals still generating when Deadline passes are reported as CANCELLED, so a plan too large to
+// finish in time still returns the recipes already saved.
+// @Summary Generate a meal plan
+// @Description Generate and save a recipe for each meal of up to 7 days, at most 21 recipes in total. Recipes are grouped by day and meal, with a nutrition summary over the plan and the total tokens spent
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param request body dtos.MealPlanRequest true "Meal plan constraints"
+// @Success 200 {object} dtos.MealPlanResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /v1/recipes/generate/meal-plan [post]
+func (h *RecipeBatchHandler) GenerateMealPlan(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	var req dtos.MealPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Days*req.MealsPerDay > dtos.MaxMealPlanRecipes {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Code:    "BAD_REQUEST",
+			Message: fmt.Sprintf("A meal plan may contain at most %d recipes", dtos.MaxMealPlanRecipes),
+		})
+		return
+	}
+
+	meals := mealPlanMeals[req.MealsPerDay]
+	ctx, total := integrations.WithUsageTotal(c.Request.Context())
+	planCtx, cancel := h.withDeadline(ctx)
+	defer cancel()
+	results := make([]dtos.BatchGenerationResult, req.Days*len(meals))
+	h.forEach(len(results), func(i int) {
+		results[i] = h.generate(planCtx, ctx, userID, mealPlanQuery(req, meals[i%len(meals)], i/len(meals)+1))
+	})
+
+	response := dtos.MealPlanResponse{
+		Days:      make([]dtos.MealPlanDay, req.Days),
+		Nutrition: dtos.MealPlanNutrition{CalorieTarget: req.CalorieTarget},
+		Usage:     tokenUsage(total.Usage()),
+	}
+	for d := range response.Days {
+		day := dtos.MealPlanDay{Day: d + 1, Meals: make([]dtos.MealPlanMeal, len(meals))}
+		for m, meal := range meals {
+			result := results[d*len(meals)+m]
+			day.Meals[m] = dtos.MealPlanMeal{
+				Meal:     meal,
+				Query:    result.Query,
+				RecipeID: result.RecipeID,
+				Recipe:   result.Recipe,
+				Error:    result.Error,
+			}
+			if result.Recipe == nil {
+				continue
+			}
+			nutrition, ok := services.ParseNutritionalInfo(result.Recipe.NutritionalInfo)
+			if !ok {
+				continue
+			}
+			day.Calories += nutrition.Calories
+			response.Nutrition.Calories += nutrition.Calories
+			response.Nutrition.Protein += nutrition.Protein
+			response.Nutrition.Carbs += nutrition.Carbs
+			response.Nutrition.Fat += nutrition.Fat
+			response.Nutrition.RecipesCounted++
+		}
+		response.Days[d] = day
+	}
+	response.Nutrition.AverageDailyCalories = math.Round(response.Nutrition.Calories / float64(req.Days))
+	c.JSON(http.StatusOK, response)
+}
+
+// mealPlanQuery builds the generation query for one meal on day of the plan, e.g.
+// "vegetarian breakfast, italian, about 500 kcal per serving, day 2 of 3, a different dish from
+// the other days". The day is left out of single-day plans.
+func mealPlanQuery(req dtos.MealPlanRequest, meal string, day int) string {
+	var restrictions []string
+	for _, restriction := range req.DietaryRestrictions {
+		if restriction = strings.TrimSpace(restriction); restriction != "" {
+			restrictions = append(restrictions, restriction)
+		}
+	}
+	parts := []string{strings.Join(append(restrictions, meal), " ")}
+	if preferences := strings.TrimSpace(req.Preferences); preferences != "" {
+		parts = append(parts, preferences)
+	}
+	if req.CalorieTarget > 0 {
+		parts = append(parts, fmt.Sprintf("about %d kcal per serving", req.CalorieTarget/req.MealsPerDay))
+	}
+	if req.Days > 1 {
+		parts = append(parts, fmt.Sprintf("day %d of %d, a different dish from the other days", day, req.Days))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"gorm.io/gorm"
+)
+
+// RecipeModificationHandler handles AI-assisted modifications of stored recipes.
+type RecipeModificationHandler struct {
+	recipes    services.RecipeService
+	resolution services.RecipeResolutionService
+	// Audit records the changes stored by ExpandRecipe and RecomputeNutrition when set.
+	Audit services.RecipeAuditService
+}
+
+// NewRecipeModificationHandler creates a new instance of RecipeModificationHandler.
+func NewRecipeModificationHandler(recipes services.RecipeService, resolution services.RecipeResolutionService) *RecipeModificationHandler {
+	return &RecipeModificationHandler{recipes: recipes, resolution: resolution}
+}
+
+// SubstituteIngredient replaces a single ingredient in a recipe using the external model.
+// @Summary Substitute an ingredient
+// @Description Ask the model to swap one ingredient and adjust affected amounts and steps
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param request body dtos.IngredientSubstitutionRequest true "Ingredient to substitute"
+// @Success 200 {object} dtos.RecipeResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Failure 502 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/substitute [post]
+func (h *RecipeModificationHandler) SubstituteIngredient(c *gin.Context) {
+	var req dtos.IngredientSubstitutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	recipe, err := h.recipes.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+
+	ingredients, err := recipe.GetIngredients()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to read recipe ingredients: " + err.Error()})
+		return
+	}
+	found := false
+	for _, ing := range ingredients {
+		if strings.EqualFold(strings.TrimSpace(ing.Name), strings.TrimSpace(req.Ingredient)) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Ingredient not found in recipe: " + req.Ingredient})
+		return
+	}
+
+	modified, err := h.resolution.SubstituteIngredient(c.Request.Context(), recipe, req.Ingredient, req.Reason)
+	if err != nil {
+		respondModelError(c, "Failed to substitute ingredient: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.NewRecipeResponse(modified))
+}
+
+// ExpandRecipe asks the external model for more detailed steps, tips and nutritional information
+// and stores the enriched recipe in place of the original. Only the recipe's owner may expand it.
+// @Summary Expand a recipe
+// @Description Enrich a stored recipe with more detailed steps, tips and nutrition. Title and ingredients are kept unless allow_core_changes is set. Only the recipe's owner may expand it
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param request body dtos.RecipeExpansionRequest false "Expansion options"
+// @Success 200 {object} dtos.RecipeResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Failure 502 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/expand [post]
+func (h *RecipeModificationHandler) ExpandRecipe(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	var req dtos.RecipeExpansionRequest
+	// The body is optional; only bind when one was sent.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	recipe, err := h.recipes.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+	if recipe.UserID == nil || *recipe.UserID != userID {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "You do not have permission to change this recipe"})
+		return
+	}
+
+	before := *recipe
+	expanded, err := h.resolution.ExpandRecipe(c.Request.Context(), recipe, req.AllowCoreChanges)
+	if err != nil {
+		respondModelError(c, "Failed to expand recipe: ", err)
+		return
+	}
+
+	expanded.ID = recipe.ID
+	if err := h.recipes.UpdateRecipe(c.Request.Context(), expanded); err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save expanded recipe: " + err.Error()})
+		return
+	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditUpdate, &before, expanded)
+
+	c.JSON(http.StatusOK, dtos.NewRecipeResponse(expanded))
+}
+
+// RecomputeNutrition asks the external model to recalculate the nutrition of a recipe from its
+// current ingredients and servings, for example after scaling or substitutions, and stores the
+// result as the recipe's nutritional information. Only the recipe's owner may recompute it.
+// @Summary Recompute recipe nutrition
+// @Description Recalculate per-serving calories, protein, carbs and fat and update the stored nutritional information. Only the recipe's owner may recompute it
+// @Tags recipes
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Success 200 {object} dtos.NutritionResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Failure 502 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/nutrition [post]
+func (h *RecipeModificationHandler) RecomputeNutrition(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+	recipe, err := h.recipes.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+	if recipe.UserID == nil || *recipe.UserID != userID {
+		c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "You do not have permission to change this recipe"})
+		return
+	}
+	ingredients, err := recipe.GetIngredients()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to read recipe ingredients: " + err.Error()})
+		return
+	}
+	if len(ingredients) == 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Recipe has no ingredients"})
+		return
+	}
+
+	nutrition, err := h.resolution.RecomputeNutrition(c.Request.Context(), recipe)
+	if err != nil {
+		respondModelError(c, "Failed to recompute nutrition: ", err)
+		return
+	}
+
+	before := *recipe
+	recipe.NutritionalInfo = nutrition.String()
+	if err := h.recipes.UpdateRecipe(c.Request.Context(), recipe); err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save nutrition: " + err.Error()})
+		return
+	}
+	recordRecipeAudit(c, h.Audit, models.RecipeAuditUpdate, &before, recipe)
+
+	c.JSON(http.StatusOK, dtos.NutritionResponse{
+		RecipeID:        recipe.ID,
+		Calories:        nutrition.Calories,
+		Protein:         nutrition.Protein,
+		Carbs:           nutrition.Carbs,
+		Fat:             nutrition.Fat,
+		NutritionalInfo: recipe.NutritionalInfo,
+	})
+}
+
+// respondModelError reports a failed model call. Output that does not match the recipe schema
+// is reported as 502 AI_SCHEMA_ERROR, queries failing moderation as 422 QUERY_REJECTED and
+// DeepSeek failures map to 429, 502, 503 or 504; anything else is an internal error.
+func respondModelError(c *gin.Context, prefix string, err error) {
+	status, code := modelErrorStatus(c, err)
+	c.JSON(status, dtos.ErrorResponse{Code: code, Message: prefix + err.Error()})
+}
+
+// modelErrorStatus picks the status and error code for a failed model call. When DeepSeek
+// returned a request ID it is exposed in the X-Upstream-Request-Id header for debugging.
+func modelErrorStatus(c *gin.Context, err error) (int, string) {
+	var deepSeekErr *integrations.DeepSeekError
+	if errors.As(err, &deepSeekErr) && deepSeekErr.RequestID != "" {
+		c.Header("X-Upstream-Request-Id", deepSeekErr.RequestID)
+	}
+	return modelErrorCode(err)
+}
+
+// modelErrorCode maps a failed model call to a status and error code without touching the response.
+func modelErrorCode(err error) (int, string) {
+	var schemaErr *services.ModelSchemaError
+	if errors.As(err, &schemaErr) {
+		return http.StatusBadGateway, "AI_SCHEMA_ERROR"
+	}
+	var dimensionErr *services.EmbeddingDimensionError
+	if errors.As(err, &dimensionErr) {
+		return http.StatusBadGateway, "EMBEDDING_ERROR"
+	}
+	switch {
+	case errors.Is(err, services.ErrQueryRejected):
+		return http.StatusUnprocessableEntity, "QUERY_REJECTED"
+	case errors.Is(err, integrations.ErrDeepSeekRateLimited):
+		return http.StatusTooManyRequests, "AI_RATE_LIMITED"
+	case errors.Is(err, integrations.ErrDeepSeekTimeout):
+		return http.StatusGatewayTimeout, "AI_TIMEOUT"
+	case errors.Is(err, integrations.ErrDeepSeekBadResponse):
+		return http.StatusBadGateway, "AI_BAD_RESPONSE"
+	case errors.Is(err, integrations.ErrDeepSeekUnavailable):
+		return http.StatusServiceUnavailable, "AI_UNAVAILABLE"
+	}
+	return http.StatusInternalServerError, "INTERNAL_ERROR"
+}
+
+// GetRecipeSchema returns the JSON Schema that AI-generated recipes are validated against.
+// @Summary Get the generated recipe schema
+// @Description JSON Schema for AI-generated recipes, so clients can validate responses themselves
+// @Tags recipes
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/recipes/schema [get]
+func GetRecipeSchema(c *gin.Context) {
+	c.Header("X-Schema-Version", services.RecipeSchemaVersion)
+	c.Data(http.StatusOK, "application/schema+json", services.RecipeSchema())
+}
@@ -1,18 +1,25 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
 	"github.com/pageza/alchemorsel-v1/internal/parsers"
 	"github.com/pageza/alchemorsel-v1/internal/services"
+	"gorm.io/gorm"
 )
 
 // RecipeMultistepResolutionHandler handles the multi-step recipe resolution process.
 type RecipeMultistepResolutionHandler struct {
 	service services.RecipeResolutionService
+	// Presets resolves preset_id in queries; when nil, preset_id is rejected.
+	Presets services.GenerationPresetService
+	// Moderator checks queries before they are sent to the model; when nil, queries are not checked.
+	Moderator services.Moderator
 }
 
 // NewRecipeMultistepResolutionHandler creates a new instance of RecipeMultistepResolutionHandler.
@@ -25,6 +32,7 @@ func NewRecipeMultistepResolutionHandler(service services.RecipeResolutionServic
 // QueryRecipe handles the initial natural language query, incorporating user directives and profile details.
 // It first checks the database for exact or close matches using a structured query built from the parsed natural language input.
 // If no acceptable match is found, it builds a composite prompt and calls the external model to generate a recipe recommendation.
+// The generated "candidate" is the recipe JSON written by the model, without the chat completion around it.
 func (h *RecipeMultistepResolutionHandler) QueryRecipe(c *gin.Context) {
 	var req dtos.RecipeQueryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -32,6 +40,30 @@ func (h *RecipeMultistepResolutionHandler) QueryRecipe(c *gin.Context) {
 		return
 	}
 
+	// Resolve the generation language from the request body, then the Accept-Language header.
+	requestedLanguage := req.Language
+	if requestedLanguage == "" {
+		requestedLanguage = c.GetHeader("Accept-Language")
+	}
+	language, err := services.NormalizeRecipeLanguage(requestedLanguage)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	generation := integrations.GenerationOptions{Model: req.Model, Temperature: req.Temperature, MaxTokens: req.MaxTokens}
+	if err := generation.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	// Merge the constraints of the caller's preset into the free-text query.
+	if req.PresetID != "" {
+		if !h.applyPreset(c, &req) {
+			return
+		}
+	}
+
 	// Parse the user's freeform query into structured parameters using the parser
 	parsedQuery, err := parsers.ParseRecipeQuery(req.Query)
 	if err != nil {
@@ -80,26 +112,62 @@ func (h *RecipeMultistepResolutionHandler) QueryRecipe(c *gin.Context) {
 
 	if len(closeMatches) == 0 {
 		// No matches found, so build a composite prompt and call the external model
-		compositePrompt, err := h.service.BuildCompositePrompt(req.Query, req.PromptInstructions, req.ExpectedResponseFormat, profileData)
+		if h.Moderator != nil {
+			if err := h.Moderator.Check(ctx, req.Query); err != nil {
+				status, code := modelErrorCode(err)
+				c.JSON(status, dtos.ErrorResponse{Code: code, Message: err.Error()})
+				return
+			}
+		}
+		compositePrompt, err := h.service.BuildCompositePrompt(req.Query, req.PromptInstructions, req.ExpectedResponseFormat, profileData, language)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while building composite prompt: " + err.Error()})
 			return
 		}
 
-		candidate, alternatives, err := h.service.ResolveRecipeByModel(ctx, compositePrompt)
+		candidate, alternatives, err := h.service.ResolveRecipeByModel(ctx, compositePrompt, generation)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while resolving recipe by model: " + err.Error()})
+			status, _ := modelErrorStatus(c, err)
+			c.JSON(status, gin.H{"error": "Error while resolving recipe by model: " + err.Error()})
 			return
 		}
 		c.JSON(http.StatusOK, gin.H{
 			"match_type":   "generated",
 			"candidate":    candidate,
 			"alternatives": alternatives,
+			"language":     language,
 		})
 		return
 	}
 }
 
+// applyPreset loads req.PresetID for the current user and merges it into req.Query,
+// responding with an error and returning false when the preset cannot be used.
+func (h *RecipeMultistepResolutionHandler) applyPreset(c *gin.Context, req *dtos.RecipeQueryRequest) bool {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return false
+	}
+	if h.Presets == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: presets are not available"})
+		return false
+	}
+	preset, err := h.Presets.GetPresetForUser(c.Request.Context(), userID, req.PresetID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Preset not found"})
+		return false
+	case errors.Is(err, services.ErrPresetForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Preset belongs to another user"})
+		return false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while loading preset: " + err.Error()})
+		return false
+	}
+	req.Query = services.ApplyGenerationPreset(req.Query, preset)
+	return true
+}
+
 // ModifyRecipe handles iterative modifications based on the user's feedback.
 // It receives a structured response from the model alongside modification instructions and sends the request back to the model
 // for further refinement until the recipe is approved by the user.
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SuggestTags suggests tags for a recipe from its cuisines, diets, difficulty, time and
+// ingredients, optionally adding a few from the external model. Suggestions are only saved when
+// apply is set. A failed model call is reported in model_error without failing the request.
+// @Summary Suggest recipe tags
+// @Description Suggest tags the recipe does not have yet, optionally asking the model for descriptive ones and adding them to the recipe
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param id path string true "Recipe ID"
+// @Param request body dtos.TagSuggestionRequest false "Suggestion options"
+// @Success 200 {object} dtos.TagSuggestionResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/recipes/{id}/suggest-tags [post]
+func (h *RecipeModificationHandler) SuggestTags(c *gin.Context) {
+	var req dtos.TagSuggestionRequest
+	// The body is optional; only bind when one was sent.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	recipe, err := h.recipes.GetRecipe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: err.Error()})
+		return
+	}
+	if req.Apply {
+		userID, ok := requireCurrentUserID(c)
+		if !ok {
+			return
+		}
+		if recipe.UserID == nil || *recipe.UserID != userID {
+			c.JSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "You do not have permission to change this recipe"})
+			return
+		}
+	}
+
+	response := dtos.TagSuggestionResponse{RecipeID: recipe.ID, Suggestions: services.SuggestTags(recipe)}
+	if req.UseModel {
+		extra, err := h.resolution.SuggestModelTags(c.Request.Context(), recipe)
+		if err != nil {
+			logging.FromGin(c).Warn("Failed to get model tag suggestions", zap.String("recipe_id", recipe.ID), zap.Error(err))
+			_, code := modelErrorStatus(c, err)
+			response.ModelError = &dtos.ErrorResponse{Code: code, Message: "Failed to get model tag suggestions: " + err.Error()}
+		} else {
+			response.Suggestions = services.MergeTagSuggestions(recipe, response.Suggestions, extra)
+		}
+	}
+
+	if req.Apply && len(response.Suggestions) > 0 {
+		before := *recipe
+		tags := append([]models.Tag{}, recipe.Tags...)
+		for _, name := range response.Suggestions {
+			tags = append(tags, models.Tag{Name: name})
+		}
+		recipe.Tags = tags
+		if err := h.recipes.UpdateRecipe(c.Request.Context(), recipe); err != nil {
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to save tags: " + err.Error()})
+			return
+		}
+		recordRecipeAudit(c, h.Audit, models.RecipeAuditUpdate, &before, recipe)
+		response.Applied = true
+	}
+
+	response.Tags = make([]string, len(recipe.Tags))
+	for i, tag := range recipe.Tags {
+		response.Tags[i] = tag.Name
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+)
+
+// SearchHistoryHandler exposes the current user's recent recipe searches.
+type SearchHistoryHandler struct {
+	Service services.SearchHistoryService
+}
+
+// NewSearchHistoryHandler creates a new SearchHistoryHandler with the given service.
+func NewSearchHistoryHandler(service services.SearchHistoryService) *SearchHistoryHandler {
+	return &SearchHistoryHandler{Service: service}
+}
+
+// GetSearchHistory returns the current user's most recent searches.
+// @Summary Get search history
+// @Description Get the current user's most recent recipe searches, newest first
+// @Tags users
+// @Produce json
+// @Param limit query int false "Maximum number of searches to return"
+// @Success 200 {object} dtos.SearchHistoryResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/me/search-history [get]
+func (h *SearchHistoryHandler) GetSearchHistory(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "limit must be a positive integer"})
+		return
+	}
+
+	searches, err := h.Service.GetSearchHistory(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to get search history: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SearchHistoryResponse{Searches: searches})
+}
+
+// ClearSearchHistory removes all of the current user's recorded searches.
+// @Summary Clear search history
+// @Description Delete the current user's recipe search history
+// @Tags users
+// @Success 204 "No Content"
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/users/me/search-history [delete]
+func (h *SearchHistoryHandler) ClearSearchHistory(c *gin.Context) {
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.ClearSearchHistory(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to clear search history: " + err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/errors"
+	"gorm.io/gorm"
+)
+
+// MergeTags folds near-duplicate tags, such as "fast" and "speedy", into one target tag.
+// @Summary Merge tags
+// @Description Admin only. Move every recipe of the source tags to the target tag and delete the sources, in one transaction. Recipes that already have the target tag keep it once
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dtos.TagMergeRequest true "Target and source tags"
+// @Success 200 {object} dtos.TagMergeResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/admin/tags/merge [post]
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	var req dtos.TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	result, err := h.service.Merge(c.Request.Context(), req.TargetID, req.SourceIDs)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "Target tag not found"})
+			return
+		}
+		if appErr, ok := err.(*errors.Error); ok && appErr.Code == errors.ErrValidation {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: appErr.Message})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to merge tags: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.TagMergeResponse{
+		TargetID:        req.TargetID,
+		RecipesAffected: result.RecipesAffected,
+		TagsDeleted:     result.TagsDeleted,
+	})
+}
+
+// DeleteUnusedTags removes the tags no recipe uses.
+// @Summary Delete unused tags
+// @Description Admin only. Delete every tag that is not attached to any recipe
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dtos.TagCleanupResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/admin/tags/unused [delete]
+func (h *TagHandler) DeleteUnusedTags(c *gin.Context) {
+	deleted, err := h.service.DeleteUnused(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Code: "INTERNAL_ERROR", Message: "Failed to delete unused tags: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dtos.TagCleanupResponse{TagsDeleted: deleted})
+}
@@ -5,21 +5,24 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
-	"time"
-
-	"github.com/golang-jwt/jwt/v4"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pageza/alchemorsel-v1/internal/dtos"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"github.com/pageza/alchemorsel-v1/internal/services"
+	"github.com/pageza/alchemorsel-v1/internal/units"
 	"go.uber.org/zap"
 )
 
 // UserHandler handles user-related HTTP requests with dependency injection.
 type UserHandler struct {
 	Service services.UserServiceInterface
+	// RefreshTokens tracks issued refresh tokens; when nil they cannot be revoked.
+	RefreshTokens services.RefreshTokenStore
+	// Denylist records access tokens revoked on logout; when nil logout cannot revoke them.
+	Denylist services.TokenDenylist
 }
 
 // NewUserHandler creates a new UserHandler with the given service.
@@ -81,13 +84,8 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	// Build a JWT token with the user's ID and an expiration.
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": user.ID,
-		"exp": time.Now().Add(time.Hour).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(secret))
+	// Issue a short-lived access token and a refresh token for renewing it.
+	tokens, err := h.issueTokens(c.Request.Context(), secret, user.ID)
 	if err != nil {
 		zap.S().Errorw("Token generation failed", "error", err)
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
@@ -97,16 +95,24 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 	zap.S().Infow("Login successful, token generated", "user_id", user.ID)
-	// Return the token as a JSON object.
-	c.JSON(http.StatusOK, gin.H{"token": tokenString})
+	c.JSON(http.StatusOK, tokens)
 }
 
 // getCurrentUserID extracts the authenticated user's ID from the context.
-// It checks both "currentUser" and, if not found, the "user" key.
+// It checks both "currentUser" and, if not found, the "user" key. Values of an
+// unexpected type are treated as missing rather than asserted, so callers never panic.
 func getCurrentUserID(c *gin.Context) (string, bool) {
 	if userID, exists := c.Get("currentUser"); exists {
-		if id, ok := userID.(string); ok {
-			return id, true
+		switch v := userID.(type) {
+		case string:
+			if v != "" {
+				return v, true
+			}
+		case map[string]interface{}:
+			// Set by AuthMiddleware when authentication is bypassed.
+			if id, ok := v["id"].(string); ok && id != "" {
+				return id, true
+			}
 		}
 	}
 	if user, exists := c.Get("user"); exists {
@@ -117,6 +123,16 @@ func getCurrentUserID(c *gin.Context) (string, bool) {
 	return "", false
 }
 
+// requireCurrentUserID returns the authenticated user's ID, or responds with
+// 401 and aborts the request when it is missing or malformed.
+func requireCurrentUserID(c *gin.Context) (string, bool) {
+	userID, ok := getCurrentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dtos.ErrorResponse{Code: "UNAUTHORIZED", Message: "Unauthorized"})
+	}
+	return userID, ok
+}
+
 // GetUser converts GetUser to a method that uses dependency injection.
 func (h *UserHandler) GetUser(c *gin.Context) {
 	user, err := h.Service.GetUser(c.Request.Context(), c.Param("id"))
@@ -132,7 +148,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, dtos.NewUserResponse(user))
 }
 
 // validateUserFields checks that the required fields are provided.
@@ -209,10 +225,10 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 	zap.S().Debugw("User created successfully", "user", user)
-	c.JSON(http.StatusCreated, user)
+	c.JSON(http.StatusCreated, dtos.NewUserResponse(&user))
 }
 
-// VerifyEmail handles email verification via a token using dependency injection.
+// VerifyEmail redeems an email verification token. Unknown and expired tokens get 400.
 func (h *UserHandler) VerifyEmail(c *gin.Context) {
 	token := c.Param("token")
 	if token == "" {
@@ -222,14 +238,27 @@ func (h *UserHandler) VerifyEmail(c *gin.Context) {
 		})
 		return
 	}
-	if token == "" {
-		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-			Code:    "BAD_REQUEST",
-			Message: "Invalid verification token",
-		})
+	if err := h.Service.VerifyEmail(c.Request.Context(), token); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidVerificationToken):
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Code:    "BAD_REQUEST",
+				Message: "Invalid verification token",
+			})
+		case errors.Is(err, services.ErrVerificationTokenExpired):
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Code:    "BAD_REQUEST",
+				Message: "Verification token has expired",
+			})
+		default:
+			zap.S().Errorw("VerifyEmail service error", "error", err)
+			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+				Code:    "INTERNAL_ERROR",
+				Message: "Failed to verify email",
+			})
+		}
 		return
 	}
-	
 	c.JSON(http.StatusOK, gin.H{"message": "email verified successfully"})
 }
 
@@ -333,75 +362,63 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 	zap.S().Infow("Successfully retrieved current user", "user_id", user.ID, "email", user.Email)
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, dtos.NewUserResponse(user))
 }
 
-// UpdateCurrentUser updates the current user's information.
+// UpdateCurrentUser replaces the current user's name and email. The password is changed only
+// when one is provided; use PATCH to update individual fields. A changed email is marked
+// unverified until the new address is verified.
 func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
-	// Temporarily disable PUT update endpoint logic and return a not implemented response
-	c.JSON(http.StatusNotImplemented, dtos.ErrorResponse{
-		Code:    "NOT_IMPLEMENTED",
-		Message: "PUT update endpoint is temporarily disabled. Please use PATCH instead.",
-	})
-
-	/*
-		// Original PUT logic commented out for now:
-		zap.S().Info("UpdateCurrentUser handler reached")
-		userID, ok := getCurrentUserID(c)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{
-				Code:    "UNAUTHORIZED",
-				Message: "Unauthorized",
-			})
-			return
-		}
-
-		var input struct {
-			Name     string `json:"name" binding:"required"`
-			Email    string `json:"email" binding:"required"`
-			Password string `json:"password" binding:"required"`
-		}
-		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-				Code:    "BAD_REQUEST",
-				Message: "Invalid request body: " + err.Error(),
-			})
-			return
-		}
-
-		// Log the input using a simple formatted string
-		zap.S().Info("UpdateCurrentUser input: " + fmt.Sprintf("name=%s, email=%s, password=%s", input.Name, input.Email, input.Password))
+	userID, ok := requireCurrentUserID(c)
+	if !ok {
+		return
+	}
 
-		updatedUser := models.User{
-			Name:     input.Name,
-			Email:    input.Email,
-			Password: input.Password,
-		}
+	var input struct {
+		Name     string `json:"name" binding:"required"`
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"omitempty,min=8"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
 
-		if err := h.Service.UpdateUser(c.Request.Context(), userID, &updatedUser); err != nil {
+	updatedUser := models.User{
+		Name:     strings.TrimSpace(input.Name),
+		Email:    strings.TrimSpace(input.Email),
+		Password: input.Password,
+	}
+	if err := h.Service.UpdateUser(c.Request.Context(), userID, &updatedUser); err != nil {
+		zap.S().Errorw("UpdateCurrentUser: UpdateUser service call failed", "userID", userID, "error", err)
+		switch {
+		case errors.Is(err, services.ErrEmailTaken):
+			c.JSON(http.StatusConflict, dtos.ErrorResponse{Code: "CONFLICT", Message: "Email is already in use"})
+		case errors.Is(err, services.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, dtos.ErrorResponse{Code: "NOT_FOUND", Message: "User not found"})
+		default:
 			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
 				Code:    "INTERNAL_ERROR",
 				Message: "Failed to update user: " + err.Error(),
 			})
-			return
-		}
-
-		// Retrieve the updated user
-		user, err := h.Service.GetUser(c.Request.Context(), userID)
-		if err != nil || user == nil {
-			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
-				Code:    "INTERNAL_ERROR",
-				Message: "Failed to retrieve updated user",
-			})
-			return
 		}
+		return
+	}
 
-		c.JSON(http.StatusOK, dtos.UserResponse{
-			Name:     user.Name,
-			Email:    user.Email,
-			Password: user.Password,
+	// Retrieve the updated user
+	user, err := h.Service.GetUser(c.Request.Context(), userID)
+	if err != nil || user == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Code:    "INTERNAL_ERROR",
+			Message: "Failed to retrieve updated user",
 		})
-	*/
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.NewUserResponse(user))
 }
 
 // Updated PatchCurrentUser with extensive logging
@@ -448,6 +465,16 @@ func (h *UserHandler) PatchCurrentUser(c *gin.Context) {
 		}
 	}
 
+	if value, exists := patchData["preferred_units"]; exists {
+		preferred, _ := value.(string)
+		if _, err := units.ParseSystem(preferred); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
 
 	if err := h.Service.PatchUser(c.Request.Context(), userID, patchData); err != nil {
 		zap.S().Errorw("PatchCurrentUser: PatchUser service call failed", "userID", userID, "error", err)
@@ -471,10 +498,7 @@ func (h *UserHandler) PatchCurrentUser(c *gin.Context) {
 
 
 
-	c.JSON(http.StatusOK, gin.H{
-		"name":  user.Name,
-		"email": user.Email,
-	})
+	c.JSON(http.StatusOK, dtos.NewUserResponse(user))
 }
 
 // DeleteCurrentUser deactivates the current user.
@@ -497,7 +521,20 @@ func (h *UserHandler) DeleteCurrentUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
 }
 
-// GetAllUsers returns a list of all users.
+// GetAllUsers returns a page of users, optionally filtered by name or email. Its route must be
+// restricted to admins with middleware.RequireAdmin.
+// @Summary List users
+// @Description Admin only. List users ordered by creation time, optionally only those whose name or email contains q
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Users per page, at most 100" default(20)
+// @Param q query string false "Case-insensitive name or email search"
+// @Success 200 {object} dtos.UserListResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 500 {object} dtos.ErrorResponse
+// @Router /v1/admin/users [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	if c.Query("simulate_error") == "true" {
 		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
@@ -506,7 +543,11 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 		})
 		return
 	}
-	users, err := h.Service.GetAllUsers(c.Request.Context())
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultUserListLimit)))
+	query := c.Query("q")
+
+	list, err := h.Service.ListUsers(c.Request.Context(), page, limit, query)
 	if err != nil {
 		msg := err.Error()
 		if msg == "" {
@@ -518,7 +559,14 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"users": users})
+	response := dtos.UserListResponse{
+		Users: make([]dtos.UserResponse, len(list.Users)),
+		Meta:  dtos.UserListMeta{Page: list.Page, Limit: list.Limit, Total: list.Total, Query: query},
+	}
+	for i, user := range list.Users {
+		response.Users[i] = dtos.NewUserResponse(user)
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // NEW: HealthCheck provides a basic health check response.
@@ -0,0 +1,141 @@
+package integrations
+
+import (
+	"sync"
+	"time"
+)
+
+// Defaults for the DeepSeek circuit breaker, used until SetDeepSeekBreaker installs a configured one.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// BreakerState is the state of a CircuitBreaker. Its numeric value is exported as a metric.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through.
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen lets a single probe call through to test whether the upstream recovered.
+	BreakerHalfOpen
+	// BreakerOpen rejects calls until the cooldown has passed.
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	}
+	return "closed"
+}
+
+// CircuitBreaker stops calls to an upstream that keeps failing. It opens after threshold
+// consecutive failures and rejects calls for the cooldown, then half-opens and lets one probe
+// through: a successful probe closes it again, a failed one reopens it for another cooldown.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed breaker. Non-positive values fall back to
+// DefaultBreakerThreshold and DefaultBreakerCooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow reports whether a call may go ahead. Every allowed call must be followed by Success,
+// Failure or Release.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+	}
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Success records a call that reached a working upstream and closes the breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure records a call that failed because of the upstream, opening the breaker after
+// threshold consecutive failures or when the half-open probe failed.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+	b.probing = false
+}
+
+// Release ends an allowed call that says nothing about the upstream, for example one cancelled
+// by the caller, so another probe may go through.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the current state. An open breaker whose cooldown has passed reports
+// BreakerHalfOpen, as the next call would be let through as a probe.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+var (
+	breakerMu       sync.RWMutex
+	deepSeekBreaker = NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+)
+
+// SetDeepSeekBreaker installs the breaker guarding DeepSeek calls. Passing nil disables it.
+func SetDeepSeekBreaker(breaker *CircuitBreaker) {
+	breakerMu.Lock()
+	defer breakerMu.Unlock()
+	deepSeekBreaker = breaker
+}
+
+// DeepSeekBreaker returns the breaker guarding DeepSeek calls, or nil when it is disabled.
+func DeepSeekBreaker() *CircuitBreaker {
+	breakerMu.RLock()
+	defer breakerMu.RUnlock()
+	return deepSeekBreaker
+}
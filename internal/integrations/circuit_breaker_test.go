@@ -0,0 +1,163 @@
+package integrations
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a breaker driven by a fake clock and a function advancing it.
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(threshold, cooldown)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+// useDeepSeekBreaker installs b as the DeepSeek breaker for the duration of the test, so
+// failures from one test cannot open the breaker for the next.
+func useDeepSeekBreaker(t *testing.T, b *CircuitBreaker) {
+	t.Helper()
+	original := DeepSeekBreaker()
+	SetDeepSeekBreaker(b)
+	t.Cleanup(func() { SetDeepSeekBreaker(original) })
+}
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if !b.Allow() {
+			t.Fatalf("Expected call %d to be allowed", i+1)
+		}
+		b.Failure()
+	}
+	// A success resets the count of consecutive failures.
+	b.Allow()
+	b.Success()
+	for i := 0; i < 2; i++ {
+		b.Allow()
+		b.Failure()
+	}
+	if got := b.State(); got != BreakerClosed {
+		t.Fatalf("Expected the breaker to stay closed, got %v", got)
+	}
+
+	b.Allow()
+	b.Failure()
+	if got := b.State(); got != BreakerOpen {
+		t.Fatalf("Expected the breaker to open, got %v", got)
+	}
+	if b.Allow() {
+		t.Error("Expected an open breaker to reject calls")
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	t.Run("successful probe closes", func(t *testing.T) {
+		b, advance := newTestBreaker(1, time.Minute)
+		b.Allow()
+		b.Failure()
+
+		advance(59 * time.Second)
+		if b.Allow() {
+			t.Fatal("Expected calls to be rejected during the cooldown")
+		}
+		advance(time.Second)
+		if got := b.State(); got != BreakerHalfOpen {
+			t.Fatalf("Expected half-open after the cooldown, got %v", got)
+		}
+		if !b.Allow() {
+			t.Fatal("Expected the probe to be allowed")
+		}
+		if b.Allow() {
+			t.Fatal("Expected a single probe at a time")
+		}
+		b.Success()
+		if got := b.State(); got != BreakerClosed {
+			t.Fatalf("Expected the breaker to close, got %v", got)
+		}
+		if !b.Allow() {
+			t.Error("Expected a closed breaker to allow calls")
+		}
+	})
+
+	t.Run("failed probe reopens", func(t *testing.T) {
+		b, advance := newTestBreaker(3, time.Minute)
+		for i := 0; i < 3; i++ {
+			b.Allow()
+			b.Failure()
+		}
+		advance(time.Minute)
+		b.Allow()
+		b.Failure()
+		if got := b.State(); got != BreakerOpen {
+			t.Fatalf("Expected the breaker to reopen, got %v", got)
+		}
+		advance(30 * time.Second)
+		if b.Allow() {
+			t.Error("Expected a new cooldown after the failed probe")
+		}
+	})
+
+	t.Run("released probe lets another through", func(t *testing.T) {
+		b, advance := newTestBreaker(1, time.Minute)
+		b.Allow()
+		b.Failure()
+		advance(time.Minute)
+		b.Allow()
+		b.Release()
+		if got := b.State(); got != BreakerHalfOpen {
+			t.Fatalf("Expected the breaker to stay half-open, got %v", got)
+		}
+		if !b.Allow() {
+			t.Error("Expected another probe after the release")
+		}
+	})
+}
+
+func TestGenerateRecipeFailsFastWhenBreakerIsOpen(t *testing.T) {
+	var calls atomic.Int32
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	useSecretDirs(t)
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+	t.Setenv("DEEPSEEK_API_URL", server.URL)
+	originalDelay := deepSeekRetryDelay
+	deepSeekRetryDelay = 0
+	t.Cleanup(func() { deepSeekRetryDelay = originalDelay })
+	breaker, advance := newTestBreaker(2, time.Minute)
+	useDeepSeekBreaker(t, breaker)
+
+	for i := 0; i < 2; i++ {
+		if _, err := GenerateRecipeWithOptions("pancakes", nil, GenerationOptions{}); !errors.Is(err, ErrDeepSeekBadResponse) {
+			t.Fatalf("Expected ErrDeepSeekBadResponse, got %v", err)
+		}
+	}
+	made := calls.Load()
+
+	_, err := GenerateRecipeWithOptions("pancakes", nil, GenerationOptions{})
+	if !errors.Is(err, ErrDeepSeekUnavailable) {
+		t.Fatalf("Expected ErrDeepSeekUnavailable, got %v", err)
+	}
+	if calls.Load() != made {
+		t.Error("Expected no request to DeepSeek while the breaker is open")
+	}
+
+	// Rate limits show DeepSeek is up, so a rate-limited probe closes the breaker.
+	advance(time.Minute)
+	status = http.StatusTooManyRequests
+	if _, err := GenerateRecipeWithOptions("pancakes", nil, GenerationOptions{}); !errors.Is(err, ErrDeepSeekRateLimited) {
+		t.Fatalf("Expected ErrDeepSeekRateLimited, got %v", err)
+	}
+	if got := breaker.State(); got != BreakerClosed {
+		t.Errorf("Expected the breaker to close, got %v", got)
+	}
+}
@@ -2,39 +2,217 @@ package integrations
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/prompts"
 	"github.com/pageza/alchemorsel-v1/internal/utils"
 	"go.uber.org/zap"
 )
 
-/* Hardcode DEEPSEEK_API_URL and DEEPSEEK_API_KEY for testing purposes */
+// Defaults used when a request does not override the generation options.
+const (
+	DefaultDeepSeekModel = "deepseek-chat"
+	DefaultTemperature   = 0.7
+	DefaultMaxTokens     = 2048
+)
+
+// AllowedDeepSeekModels lists the model names a request may select. Anything else is rejected
+// so arbitrary strings are never forwarded in the upstream payload.
+var AllowedDeepSeekModels = map[string]bool{
+	"deepseek-chat":     true,
+	"deepseek-reasoner": true,
+}
+
+// GenerationOptions overrides the model parameters for a single request. Zero values fall back
+// to DEEPSEEK_MODEL (or DefaultDeepSeekModel), DefaultTemperature and DefaultMaxTokens.
+type GenerationOptions struct {
+	Model       string
+	Temperature *float64
+	MaxTokens   *int
+}
+
+// Validate checks the options against the model allowlist and the accepted ranges:
+// temperature between 0 and 2 and max_tokens between 256 and 8192.
+func (o GenerationOptions) Validate() error {
+	if o.Model != "" && !AllowedDeepSeekModels[o.Model] {
+		return fmt.Errorf("unsupported model: %s", o.Model)
+	}
+	if o.Temperature != nil && (*o.Temperature < 0 || *o.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if o.MaxTokens != nil && (*o.MaxTokens < 256 || *o.MaxTokens > 8192) {
+		return fmt.Errorf("max_tokens must be between 256 and 8192")
+	}
+	return nil
+}
+
+// payloadFields returns the model, temperature and max_tokens to send, applying defaults.
+func (o GenerationOptions) payloadFields() (string, float64, int) {
+	model := o.Model
+	if model == "" {
+		model = os.Getenv("DEEPSEEK_MODEL")
+	}
+	if model == "" {
+		model = DefaultDeepSeekModel
+	}
+	temperature := DefaultTemperature
+	if o.Temperature != nil {
+		temperature = *o.Temperature
+	}
+	maxTokens := DefaultMaxTokens
+	if o.MaxTokens != nil {
+		maxTokens = *o.MaxTokens
+	}
+	return model, temperature, maxTokens
+}
+
+// deepSeekRetryDelay is the pause before the first retry, doubling after each failure; tests shorten it.
+var deepSeekRetryDelay = 2 * time.Second
+
+// Sentinel errors for DeepSeek failures, matched with errors.Is. They are returned wrapped in
+// a *DeepSeekError carrying the status code and upstream request ID.
+var (
+	ErrDeepSeekRateLimited = errors.New("DeepSeek rate limit exceeded")
+	ErrDeepSeekBadResponse = errors.New("DeepSeek returned an unusable response")
+	ErrDeepSeekTimeout     = errors.New("DeepSeek request timed out")
+	// ErrDeepSeekUnavailable is returned without calling DeepSeek while its circuit breaker is open.
+	ErrDeepSeekUnavailable = errors.New("DeepSeek is temporarily unavailable")
+)
+
+// DeepSeekError describes a failed DeepSeek call. Kind is one of the sentinel errors above.
+type DeepSeekError struct {
+	Kind       error
+	StatusCode int
+	// RequestID is the upstream X-Request-Id, when the response included one.
+	RequestID string
+	Err       error
+}
+
+func (e *DeepSeekError) Error() string {
+	msg := e.Kind.Error()
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.RequestID != "" {
+		msg += " [request id " + e.RequestID + "]"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap exposes both the sentinel kind and the underlying cause to errors.Is and errors.As.
+func (e *DeepSeekError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// deepSeekStatusError classifies a non-2xx DeepSeek response.
+func deepSeekStatusError(resp *http.Response) *DeepSeekError {
+	kind := ErrDeepSeekBadResponse
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		kind = ErrDeepSeekRateLimited
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		kind = ErrDeepSeekTimeout
+	}
+	return &DeepSeekError{Kind: kind, StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-Id")}
+}
+
+// isDeepSeekOutage reports whether err suggests DeepSeek itself is failing: timeouts, server
+// errors and transport failures. Rate limits and other client errors show it is up.
+func isDeepSeekOutage(err error) bool {
+	var deepSeekErr *DeepSeekError
+	if errors.As(err, &deepSeekErr) {
+		return errors.Is(err, ErrDeepSeekTimeout) || deepSeekErr.StatusCode >= 500
+	}
+	return true
+}
+
+// isTimeout reports whether a transport error was caused by a deadline.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// GenerateRecipe calls DeepSeek with the default generation options.
 func GenerateRecipe(query string, attributes map[string]interface{}) (string, error) {
-	deepseekURL := "https://api.deepseek.com/chat/completions"
-	zap.L().Debug("Hardcoded DeepSeek URL for testing", zap.String("value", deepseekURL))
-	zap.L().Debug("Hardcoded API key for testing", zap.String("apiKey", apiKey))
+	return GenerateRecipeWithOptions(query, attributes, GenerationOptions{})
+}
+
+// GenerateRecipeWithOptions calls DeepSeek using the cached credentials and the given generation options.
+func GenerateRecipeWithOptions(query string, attributes map[string]interface{}, opts GenerationOptions) (string, error) {
+	return GenerateRecipeWithContext(context.Background(), query, attributes, opts)
+}
+
+// GenerateRecipeWithContext is GenerateRecipeWithOptions bounded by ctx. Each attempt is also
+// limited to DEEPSEEK_TIMEOUT; running out of time is reported as ErrDeepSeekTimeout. While the
+// DeepSeek circuit breaker is open it fails at once with ErrDeepSeekUnavailable. It returns the
+// content of the first choice, or the raw body when the response is not a chat completion.
+func GenerateRecipeWithContext(ctx context.Context, query string, attributes map[string]interface{}, opts GenerationOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
 
-	promptInstructions := "You are a helpful assistant. Create a recipe based on the user's input and profile attributes. Follow the specified prompt instructions."
+	creds, err := deepSeekCredentials()
+	if err != nil {
+		return "", err
+	}
 
+	breaker := DeepSeekBreaker()
+	if breaker == nil {
+		return generateRecipe(ctx, creds, query, attributes, opts)
+	}
+	if !breaker.Allow() {
+		return "", &DeepSeekError{Kind: ErrDeepSeekUnavailable, Err: errors.New("circuit breaker is open")}
+	}
+	recipe, err := generateRecipe(ctx, creds, query, attributes, opts)
+	switch {
+	case err == nil:
+		breaker.Success()
+	case errors.Is(err, context.Canceled):
+		breaker.Release()
+	case isDeepSeekOutage(err):
+		breaker.Failure()
+	default:
+		breaker.Success()
+	}
+	return recipe, err
+}
+
+// generateRecipe makes the DeepSeek call for GenerateRecipeWithContext, retrying failed attempts.
+func generateRecipe(ctx context.Context, creds DeepSeekCredentials, query string, attributes map[string]interface{}, opts GenerationOptions) (string, error) {
+	deepseekURL := creds.URL
+	zap.L().Debug("Using DeepSeek URL", zap.String("value", deepseekURL))
+
+	promptInstructions := prompts.System()
+
+	client := &http.Client{Timeout: config.LoadAIConfig().DeepSeekTimeout}
 	var recipe string
-	err := utils.Retry(3, 2*time.Second, func() error {
-		model := os.Getenv("DEEPSEEK_MODEL")
-		if model == "" {
-			model = "deepseek-chat"
-		}
+	_, err := utils.RetryWithBackoff(ctx, 3, deepSeekRetryDelay, func() error {
+		model, temperature, maxTokens := opts.payloadFields()
 		payload := map[string]interface{}{
 			"model": model,
 			"messages": []map[string]string{
 				{"role": "system", "content": promptInstructions},
 				{"role": "user", "content": query},
 			},
-			"attributes": attributes,
-			"stream":     false,
+			"attributes":  attributes,
+			"temperature": temperature,
+			"max_tokens":  maxTokens,
+			"stream":      false,
 		}
 		if query != "healthcheck" {
 			zap.L().Debug("Payload sent to DeepSeek", zap.Any("payload", payload))
@@ -49,34 +227,64 @@ func GenerateRecipe(query string, attributes map[string]interface{}) (string, er
 		if query != "healthcheck" {
 			zap.L().Debug("Sending request to DeepSeek", zap.String("url", deepseekURL))
 		}
-		req, err := http.NewRequest("POST", deepseekURL, bytes.NewBuffer(payloadBytes))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, deepseekURL, bytes.NewBuffer(payloadBytes))
 		if err != nil {
 			zap.L().Error("Error creating new request", zap.Error(err))
 			return err
 		}
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-		client := &http.Client{Timeout: 60 * time.Second}
+		req.Header.Set("Authorization", "Bearer "+creds.APIKey)
 		resp, err := client.Do(req)
 		if err != nil {
 			zap.L().Error("Error making HTTP request", zap.Error(err))
+			if isTimeout(err) {
+				return &DeepSeekError{Kind: ErrDeepSeekTimeout, Err: err}
+			}
 			return err
 		}
 		zap.L().Debug("HTTP response status", zap.Int("status", resp.StatusCode))
 		defer resp.Body.Close()
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			err := fmt.Errorf("DeepSeek API returned status %d", resp.StatusCode)
+			err := deepSeekStatusError(resp)
 			zap.L().Error("HTTP error", zap.Error(err))
 			return err
 		}
 		data, err := io.ReadAll(resp.Body)
 		if err != nil {
 			zap.L().Error("Error reading response body", zap.Error(err))
-			return err
+			kind := ErrDeepSeekBadResponse
+			if isTimeout(err) {
+				kind = ErrDeepSeekTimeout
+			}
+			return &DeepSeekError{Kind: kind, StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-Id"), Err: err}
 		}
 		recipe = string(data)
 		zap.L().Debug("Raw API response", zap.String("response", recipe))
+		var completion struct {
+			Model   string `json:"model"`
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+			} `json:"choices"`
+			Usage *TokenUsage `json:"usage"`
+		}
+		if json.Unmarshal(data, &completion) == nil {
+			if completion.Model == "" {
+				completion.Model = model
+			}
+			recordUsage(ctx, ProviderDeepSeek, completion.Model, completion.Usage)
+			// Return the generated text rather than the chat completion envelope around it.
+			if len(completion.Choices) > 0 && completion.Choices[0].Message.Content != "" {
+				recipe = completion.Choices[0].Message.Content
+			}
+		}
 		return nil
 	})
+	var deepSeekErr *DeepSeekError
+	if err != nil && isTimeout(err) && !errors.As(err, &deepSeekErr) {
+		// The deadline passed while waiting to retry.
+		return "", &DeepSeekError{Kind: ErrDeepSeekTimeout, Err: err}
+	}
 	return recipe, err
 }
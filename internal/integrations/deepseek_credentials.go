@@ -0,0 +1,92 @@
+package integrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultDeepSeekURL is used when no deepseek_api_url secret is provided.
+const DefaultDeepSeekURL = "https://api.deepseek.com/chat/completions"
+
+// secretDirs are searched in order for secret files: Docker secrets first, then the local
+// ./secrets directory used by docker-compose during development.
+var secretDirs = []string{"/run/secrets", "secrets"}
+
+// DeepSeekCredentials holds the API key and endpoint used to call DeepSeek.
+type DeepSeekCredentials struct {
+	APIKey string
+	URL    string
+}
+
+var (
+	credentialsMu sync.RWMutex
+	credentials   *DeepSeekCredentials
+)
+
+// LoadDeepSeekCredentials reads the DeepSeek secrets and caches them for later requests.
+// It is called at startup so missing secrets are reported immediately; calling it again
+// forces a reload, e.g. after the secrets have been rotated.
+func LoadDeepSeekCredentials() error {
+	loaded, err := readDeepSeekCredentials()
+	if err != nil {
+		return err
+	}
+	credentialsMu.Lock()
+	credentials = &loaded
+	credentialsMu.Unlock()
+	return nil
+}
+
+// deepSeekCredentials returns the cached credentials, loading them on first use.
+func deepSeekCredentials() (DeepSeekCredentials, error) {
+	credentialsMu.RLock()
+	cached := credentials
+	credentialsMu.RUnlock()
+	if cached != nil {
+		return *cached, nil
+	}
+	if err := LoadDeepSeekCredentials(); err != nil {
+		return DeepSeekCredentials{}, err
+	}
+	credentialsMu.RLock()
+	defer credentialsMu.RUnlock()
+	return *credentials, nil
+}
+
+func readDeepSeekCredentials() (DeepSeekCredentials, error) {
+	apiKey, err := readSecret("deepseek_api_key", "DEEPSEEK_API_KEY")
+	if err != nil {
+		return DeepSeekCredentials{}, err
+	}
+	url, err := readSecret("deepseek_api_url", "DEEPSEEK_API_URL")
+	if err != nil {
+		url = DefaultDeepSeekURL
+	}
+	return DeepSeekCredentials{APIKey: apiKey, URL: url}, nil
+}
+
+// readSecret looks for name as a Docker secret, then as name.txt in the local secrets
+// directory, then in the envVar environment variable.
+func readSecret(name, envVar string) (string, error) {
+	var tried []string
+	for _, dir := range secretDirs {
+		for _, file := range []string{name, name + ".txt"} {
+			path := filepath.Join(dir, file)
+			tried = append(tried, path)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			if value := strings.TrimSpace(string(data)); value != "" {
+				return value, nil
+			}
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv(envVar)); value != "" {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret %s not found: tried %s and $%s", name, strings.Join(tried, ", "), envVar)
+}
@@ -0,0 +1,80 @@
+package integrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useSecretDirs points the secret lookup at dirs and clears the cache for the duration of the test.
+func useSecretDirs(t *testing.T, dirs ...string) {
+	t.Helper()
+	original := secretDirs
+	secretDirs = dirs
+	credentials = nil
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	t.Setenv("DEEPSEEK_API_URL", "")
+	t.Cleanup(func() {
+		secretDirs = original
+		credentials = nil
+	})
+}
+
+func writeSecret(t *testing.T, dir, name, value string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeepSeekCredentialsAreCachedUntilReload(t *testing.T) {
+	dir := t.TempDir()
+	useSecretDirs(t, dir)
+	writeSecret(t, dir, "deepseek_api_key", "first-key")
+	writeSecret(t, dir, "deepseek_api_url", "https://example.test/chat")
+
+	creds, err := deepSeekCredentials()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if creds.APIKey != "first-key" || creds.URL != "https://example.test/chat" {
+		t.Errorf("Unexpected credentials: %+v", creds)
+	}
+
+	writeSecret(t, dir, "deepseek_api_key", "rotated-key")
+	if creds, _ := deepSeekCredentials(); creds.APIKey != "first-key" {
+		t.Errorf("Expected cached key before reload, got %q", creds.APIKey)
+	}
+
+	if err := LoadDeepSeekCredentials(); err != nil {
+		t.Fatalf("Unexpected error on reload: %v", err)
+	}
+	if creds, _ := deepSeekCredentials(); creds.APIKey != "rotated-key" {
+		t.Errorf("Expected rotated key after reload, got %q", creds.APIKey)
+	}
+}
+
+func TestDeepSeekCredentialsFallBackToLocalFiles(t *testing.T) {
+	dockerDir, localDir := t.TempDir(), t.TempDir()
+	useSecretDirs(t, dockerDir, localDir)
+	writeSecret(t, localDir, "deepseek_api_key.txt", "local-key")
+
+	if err := LoadDeepSeekCredentials(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	creds, _ := deepSeekCredentials()
+	if creds.APIKey != "local-key" {
+		t.Errorf("Expected key from local secrets file, got %q", creds.APIKey)
+	}
+	if creds.URL != DefaultDeepSeekURL {
+		t.Errorf("Expected default URL, got %q", creds.URL)
+	}
+}
+
+func TestLoadDeepSeekCredentialsMissingKey(t *testing.T) {
+	useSecretDirs(t, t.TempDir())
+
+	if err := LoadDeepSeekCredentials(); err == nil {
+		t.Error("Expected error when no API key source is readable, got nil")
+	}
+}
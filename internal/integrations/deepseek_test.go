@@ -0,0 +1,145 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// useDeepSeekServer points DeepSeek calls at a local server that always replies with status.
+func useDeepSeekServer(t *testing.T, status int, requestID string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID != "" {
+			w.Header().Set("X-Request-Id", requestID)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	useSecretDirs(t)
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+	t.Setenv("DEEPSEEK_API_URL", server.URL)
+	original := deepSeekRetryDelay
+	deepSeekRetryDelay = 0
+	t.Cleanup(func() { deepSeekRetryDelay = original })
+	useDeepSeekBreaker(t, nil)
+}
+
+func TestGenerateRecipeErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusTooManyRequests, ErrDeepSeekRateLimited},
+		{http.StatusGatewayTimeout, ErrDeepSeekTimeout},
+		{http.StatusInternalServerError, ErrDeepSeekBadResponse},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			useDeepSeekServer(t, tt.status, "req-123")
+
+			_, err := GenerateRecipeWithOptions("pancakes", nil, GenerationOptions{})
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("Expected %v, got %v", tt.kind, err)
+			}
+			var deepSeekErr *DeepSeekError
+			if !errors.As(err, &deepSeekErr) {
+				t.Fatalf("Expected a DeepSeekError, got %T", err)
+			}
+			if deepSeekErr.StatusCode != tt.status || deepSeekErr.RequestID != "req-123" {
+				t.Errorf("Unexpected error details: %+v", deepSeekErr)
+			}
+		})
+	}
+}
+
+func TestGenerateRecipeTimeouts(t *testing.T) {
+	useSlowDeepSeekServer := func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+		}))
+		t.Cleanup(server.Close)
+		useSecretDirs(t)
+		t.Setenv("DEEPSEEK_API_KEY", "test-key")
+		t.Setenv("DEEPSEEK_API_URL", server.URL)
+		original := deepSeekRetryDelay
+		deepSeekRetryDelay = 0
+		t.Cleanup(func() { deepSeekRetryDelay = original })
+		useDeepSeekBreaker(t, nil)
+	}
+
+	t.Run("per-call timeout from DEEPSEEK_TIMEOUT", func(t *testing.T) {
+		useSlowDeepSeekServer(t)
+		t.Setenv("DEEPSEEK_TIMEOUT", "20ms")
+
+		_, err := GenerateRecipeWithOptions("pancakes", nil, GenerationOptions{})
+		if !errors.Is(err, ErrDeepSeekTimeout) {
+			t.Fatalf("Expected ErrDeepSeekTimeout, got %v", err)
+		}
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		useSlowDeepSeekServer(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := GenerateRecipeWithContext(ctx, "pancakes", nil, GenerationOptions{})
+		if !errors.Is(err, ErrDeepSeekTimeout) {
+			t.Fatalf("Expected ErrDeepSeekTimeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("Expected the call to stop at the deadline, took %v", elapsed)
+		}
+	})
+}
+
+func TestDeepSeekErrorMessage(t *testing.T) {
+	err := &DeepSeekError{Kind: ErrDeepSeekRateLimited, StatusCode: http.StatusTooManyRequests, RequestID: "req-1"}
+	if got, want := err.Error(), "DeepSeek rate limit exceeded (status 429) [request id req-1]"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestGenerateRecipeReturnsMessageContent(t *testing.T) {
+	tests := map[string]struct {
+		body string
+		want string
+	}{
+		"chat completion": {
+			body: `{"model": "deepseek-chat", "choices": [{"message": {"role": "assistant", "content": "{\"title\": \"Soup\"}"}}], "usage": {"total_tokens": 10}}`,
+			want: `{"title": "Soup"}`,
+		},
+		"not a chat completion": {
+			body: `{"title": "Soup"}`,
+			want: `{"title": "Soup"}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+			useSecretDirs(t)
+			t.Setenv("DEEPSEEK_API_KEY", "test-key")
+			t.Setenv("DEEPSEEK_API_URL", server.URL)
+			useDeepSeekBreaker(t, nil)
+
+			got, err := GenerateRecipeWithContext(context.Background(), "soup", nil, GenerationOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
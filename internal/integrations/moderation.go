@@ -0,0 +1,83 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// DefaultModerationTimeout bounds a single moderation request.
+const DefaultModerationTimeout = 5 * time.Second
+
+// openAIModerationsURL is the moderation endpoint; tests point it at a local server.
+var openAIModerationsURL = "https://api.openai.com/v1/moderations"
+
+// ModerationResult is the verdict of the OpenAI moderation API on a piece of text. Categories
+// lists the flagged categories, sorted.
+type ModerationResult struct {
+	Flagged    bool
+	Categories []string
+}
+
+// ModerateText asks the OpenAI moderation API whether text is harmful. The call is not retried;
+// callers decide whether a failure blocks the request. In test mode nothing is flagged.
+func ModerateText(ctx context.Context, text string) (*ModerationResult, error) {
+	if os.Getenv("TEST_MODE") != "" {
+		return &ModerationResult{}, nil
+	}
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY is not set")
+	}
+
+	payload, err := json.Marshal(map[string]string{"input": text})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIModerationsURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: DefaultModerationTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Wait: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	var body struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+	if len(body.Results) == 0 {
+		return nil, errors.New("moderation response contained no results")
+	}
+
+	result := &ModerationResult{Flagged: body.Results[0].Flagged}
+	for category, flagged := range body.Results[0].Categories {
+		if flagged {
+			result.Categories = append(result.Categories, category)
+		}
+	}
+	sort.Strings(result.Categories)
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// useModerationServer points the moderation call at a local server replying with status and body.
+func useModerationServer(t *testing.T, status int, body string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	original := openAIModerationsURL
+	openAIModerationsURL = server.URL
+	t.Cleanup(func() { openAIModerationsURL = original })
+	t.Setenv("TEST_MODE", "")
+	t.Setenv("OPENAI_API_KEY", "test-key")
+}
+
+func TestModerateTextFlagged(t *testing.T) {
+	useModerationServer(t, http.StatusOK, `{"results": [{"flagged": true, "categories": {"violence": true, "self-harm": true, "hate": false}}]}`)
+
+	result, err := ModerateText(context.Background(), "something harmful")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Flagged || !reflect.DeepEqual(result.Categories, []string{"self-harm", "violence"}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestModerateTextAPIError(t *testing.T) {
+	useModerationServer(t, http.StatusInternalServerError, "")
+
+	_, err := ModerateText(context.Background(), "tomato soup")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected an APIError with status 500, got %v", err)
+	}
+}
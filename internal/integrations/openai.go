@@ -1,18 +1,83 @@
 package integrations
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/pageza/alchemorsel-v1/internal/config"
 	"github.com/pageza/alchemorsel-v1/internal/utils"
 )
 
-// GenerateEmbedding obtains a numeric embedding for a recipe using the OpenAI API.
-func GenerateEmbedding(recipe string) ([]float64, error) {
-	// In test mode, bypass API key check and return a dummy embedding.
+// Defaults for the OpenAI embeddings call.
+const (
+	DefaultEmbeddingModel          = "text-embedding-3-small"
+	DefaultEmbeddingMaxAttempts    = 3
+	DefaultEmbeddingRetryBaseDelay = 500 * time.Millisecond
+)
+
+// openAIEmbeddingsURL is the embeddings endpoint; tests point it at a local server.
+var openAIEmbeddingsURL = "https://api.openai.com/v1/embeddings"
+
+// ErrEmbeddingRateLimited is returned, wrapped, when OpenAI keeps rate limiting the
+// embedding request until all attempts are used up.
+var ErrEmbeddingRateLimited = errors.New("embedding rate limit exceeded")
+
+// Embedder computes numeric embeddings for text. It lets callers substitute a fake in tests.
+type Embedder interface {
+	GenerateEmbedding(ctx context.Context, text string) ([]float64, error)
+}
+
+// OpenAIEmbedder is the Embedder backed by the OpenAI API.
+type OpenAIEmbedder struct{}
+
+// GenerateEmbedding implements Embedder using the package-level GenerateEmbedding.
+func (OpenAIEmbedder) GenerateEmbedding(ctx context.Context, text string) ([]float64, error) {
+	return GenerateEmbedding(ctx, text)
+}
+
+// APIError is a non-2xx response from an external API.
+type APIError struct {
+	StatusCode int
+	// Wait is the delay requested by a Retry-After header, if any.
+	Wait time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned status %d", e.StatusCode)
+}
+
+// RetryAfter reports the server-requested delay before the next attempt.
+func (e *APIError) RetryAfter() time.Duration {
+	return e.Wait
+}
+
+// Transient reports whether the request may succeed if retried (429 or 5xx).
+func (e *APIError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// GenerateEmbedding obtains a numeric embedding for a recipe using the OpenAI API. Each request
+// is limited to OPENAI_EMBEDDING_TIMEOUT.
+// Rate limits (429) and server errors (5xx) are retried with exponential backoff, configured by
+// OPENAI_EMBEDDING_MAX_ATTEMPTS and OPENAI_EMBEDDING_RETRY_BASE_DELAY; a Retry-After header
+// overrides the computed delay. When retries are exhausted by rate limiting the returned
+// error wraps ErrEmbeddingRateLimited.
+func GenerateEmbedding(ctx context.Context, recipe string) ([]float64, error) {
+	// In test mode, bypass API key check and return a dummy embedding of the configured length.
 	if os.Getenv("TEST_MODE") != "" {
-		return []float64{0.1, 0.2, 0.3, 0.4, 0.5}, nil
+		embedding := make([]float64, config.LoadAIConfig().EmbeddingDimensions)
+		for i := range embedding {
+			embedding[i] = float64(i%5+1) / 10
+		}
+		return embedding, nil
 	}
 
 	apiKey := os.Getenv("OPENAI_API_KEY")
@@ -20,12 +85,96 @@ func GenerateEmbedding(recipe string) ([]float64, error) {
 		return nil, errors.New("OPENAI_API_KEY is not set")
 	}
 
-	// Normally, use apiKey with an HTTP client to call the OpenAI API.
+	maxAttempts, baseDelay := embeddingRetryPolicy()
 	var embedding []float64
-	err := utils.Retry(3, 2*time.Second, func() error {
-		// Dummy implementation: simulate a call to the OpenAI API using the apiKey to obtain an embedding.
-		embedding = []float64{0.1, 0.2, 0.3, 0.4, 0.5}
-		return nil
+	attempts, err := utils.RetryWithBackoff(ctx, maxAttempts, baseDelay, func() error {
+		var err error
+		embedding, err = requestEmbedding(ctx, apiKey, recipe)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.Transient() {
+			return utils.Permanent(err)
+		}
+		return err
 	})
-	return embedding, err
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w after %d attempts: %v", ErrEmbeddingRateLimited, attempts, err)
+		}
+		return nil, fmt.Errorf("embedding request failed after %d attempts: %w", attempts, err)
+	}
+	return embedding, nil
+}
+
+// requestEmbedding makes a single call to the OpenAI embeddings endpoint.
+func requestEmbedding(ctx context.Context, apiKey, text string) ([]float64, error) {
+	payload, err := json.Marshal(map[string]string{"model": DefaultEmbeddingModel, "input": text})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIEmbeddingsURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	client := &http.Client{Timeout: config.LoadAIConfig().EmbeddingTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Wait: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	var body struct {
+		Data []struct {
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+		Model string      `json:"model"`
+		Usage *TokenUsage `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+	if body.Model == "" {
+		body.Model = DefaultEmbeddingModel
+	}
+	recordUsage(ctx, ProviderOpenAI, body.Model, body.Usage)
+	if len(body.Data) == 0 || len(body.Data[0].Embedding) == 0 {
+		return nil, errors.New("embedding response contained no embedding")
+	}
+	return body.Data[0].Embedding, nil
+}
+
+// embeddingRetryPolicy reads the retry settings, falling back to the defaults when unset or invalid.
+func embeddingRetryPolicy() (int, time.Duration) {
+	maxAttempts := DefaultEmbeddingMaxAttempts
+	if n, err := strconv.Atoi(os.Getenv("OPENAI_EMBEDDING_MAX_ATTEMPTS")); err == nil && n > 0 {
+		maxAttempts = n
+	}
+	baseDelay := DefaultEmbeddingRetryBaseDelay
+	if d, err := time.ParseDuration(os.Getenv("OPENAI_EMBEDDING_RETRY_BASE_DELAY")); err == nil && d >= 0 {
+		baseDelay = d
+	}
+	return maxAttempts, baseDelay
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
 }
@@ -0,0 +1,107 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// useEmbeddingServer points the embeddings call at a local server that replies with the
+// given statuses in turn, returning a pointer to the number of requests received.
+func useEmbeddingServer(t *testing.T, statuses ...int) *int {
+	t.Helper()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[len(statuses)-1]
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"embedding": [0.5, -0.25]}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	original := openAIEmbeddingsURL
+	openAIEmbeddingsURL = server.URL
+	t.Cleanup(func() { openAIEmbeddingsURL = original })
+	t.Setenv("TEST_MODE", "")
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("OPENAI_EMBEDDING_MAX_ATTEMPTS", "3")
+	t.Setenv("OPENAI_EMBEDDING_RETRY_BASE_DELAY", "1ms")
+	return &calls
+}
+
+func TestGenerateEmbeddingRetriesTransientFailures(t *testing.T) {
+	calls := useEmbeddingServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+
+	embedding, err := GenerateEmbedding(context.Background(), "pancakes")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(embedding) != 2 || embedding[0] != 0.5 {
+		t.Errorf("Unexpected embedding %v", embedding)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 requests, got %d", *calls)
+	}
+}
+
+func TestGenerateEmbeddingRateLimitExhausted(t *testing.T) {
+	calls := useEmbeddingServer(t, http.StatusTooManyRequests)
+
+	_, err := GenerateEmbedding(context.Background(), "pancakes")
+	if !errors.Is(err, ErrEmbeddingRateLimited) {
+		t.Fatalf("Expected ErrEmbeddingRateLimited, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 requests, got %d", *calls)
+	}
+}
+
+func TestGenerateEmbeddingDoesNotRetryClientErrors(t *testing.T) {
+	calls := useEmbeddingServer(t, http.StatusBadRequest)
+
+	_, err := GenerateEmbedding(context.Background(), "pancakes")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected APIError with status 400, got %v", err)
+	}
+	if errors.Is(err, ErrEmbeddingRateLimited) {
+		t.Error("Did not expect a rate limit error")
+	}
+	if *calls != 1 {
+		t.Errorf("Expected 1 request, got %d", *calls)
+	}
+}
+
+func TestGenerateEmbeddingStopsWhenContextIsDone(t *testing.T) {
+	useEmbeddingServer(t, http.StatusServiceUnavailable)
+	t.Setenv("OPENAI_EMBEDDING_RETRY_BASE_DELAY", "1h")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := GenerateEmbedding(ctx, "pancakes")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline error, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("2"); got != 2*time.Second {
+		t.Errorf("Expected 2s, got %v", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("Expected up to a minute for %q, got %v", date, got)
+	}
+	if got := parseRetryAfter("soon"); got != 0 {
+		t.Errorf("Expected 0 for an invalid value, got %v", got)
+	}
+}
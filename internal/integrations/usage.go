@@ -0,0 +1,90 @@
+package integrations
+
+import (
+	"context"
+	"sync"
+)
+
+// Providers reported to the UsageRecorder.
+const (
+	ProviderDeepSeek = "deepseek"
+	ProviderOpenAI   = "openai"
+)
+
+// TokenUsage is the token accounting returned by a model API for a single call.
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+	// PromptCacheHitTokens and PromptCacheMissTokens split PromptTokens by whether DeepSeek
+	// served them from its context cache. Both are zero for APIs without a cache.
+	PromptCacheHitTokens  int `json:"prompt_cache_hit_tokens"`
+	PromptCacheMissTokens int `json:"prompt_cache_miss_tokens"`
+}
+
+// UsageRecorder receives the token usage of every successful model API call, for example to
+// export it as metrics.
+type UsageRecorder interface {
+	RecordUsage(provider, model string, usage TokenUsage)
+}
+
+var (
+	usageMu       sync.RWMutex
+	usageRecorder UsageRecorder
+)
+
+// SetUsageRecorder installs the recorder that receives token usage. Passing nil stops recording.
+func SetUsageRecorder(recorder UsageRecorder) {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	usageRecorder = recorder
+}
+
+// UsageTotal sums the token usage of the model calls made with a context from WithUsageTotal.
+type UsageTotal struct {
+	mu    sync.Mutex
+	usage TokenUsage
+}
+
+// Usage returns the usage summed so far.
+func (t *UsageTotal) Usage() TokenUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+func (t *UsageTotal) add(usage TokenUsage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.PromptTokens += usage.PromptTokens
+	t.usage.CompletionTokens += usage.CompletionTokens
+	t.usage.TotalTokens += usage.TotalTokens
+	t.usage.PromptCacheHitTokens += usage.PromptCacheHitTokens
+	t.usage.PromptCacheMissTokens += usage.PromptCacheMissTokens
+}
+
+type usageTotalKey struct{}
+
+// WithUsageTotal returns a context that sums the usage of every model call made with it, for
+// example to report the tokens spent on a single API request.
+func WithUsageTotal(ctx context.Context) (context.Context, *UsageTotal) {
+	total := &UsageTotal{}
+	return context.WithValue(ctx, usageTotalKey{}, total), total
+}
+
+// recordUsage forwards usage to the installed recorder, if any, and adds it to the total carried
+// by ctx. Responses without usage are skipped.
+func recordUsage(ctx context.Context, provider, model string, usage *TokenUsage) {
+	if usage == nil {
+		return
+	}
+	if total, ok := ctx.Value(usageTotalKey{}).(*UsageTotal); ok {
+		total.add(*usage)
+	}
+	usageMu.RLock()
+	recorder := usageRecorder
+	usageMu.RUnlock()
+	if recorder != nil {
+		recorder.RecordUsage(provider, model, *usage)
+	}
+}
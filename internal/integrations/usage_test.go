@@ -0,0 +1,83 @@
+package integrations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type usageRecord struct {
+	provider, model string
+	usage           TokenUsage
+}
+
+type recordingUsage struct {
+	records []usageRecord
+}
+
+func (r *recordingUsage) RecordUsage(provider, model string, usage TokenUsage) {
+	r.records = append(r.records, usageRecord{provider, model, usage})
+}
+
+func useRecordingUsage(t *testing.T) *recordingUsage {
+	t.Helper()
+	recorder := &recordingUsage{}
+	SetUsageRecorder(recorder)
+	t.Cleanup(func() { SetUsageRecorder(nil) })
+	return recorder
+}
+
+func TestGenerateRecipeRecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"model": "deepseek-chat", "choices": [{"message": {"role": "assistant", "content": "{\"title\": \"Pancakes\"}"}}], "usage": {"prompt_tokens": 120, "completion_tokens": 300, "total_tokens": 420, "prompt_cache_hit_tokens": 100, "prompt_cache_miss_tokens": 20}}`))
+	}))
+	t.Cleanup(server.Close)
+	useSecretDirs(t)
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+	t.Setenv("DEEPSEEK_API_URL", server.URL)
+	recorder := useRecordingUsage(t)
+
+	ctx, total := WithUsageTotal(context.Background())
+	for i := 0; i < 2; i++ {
+		content, err := GenerateRecipeWithContext(ctx, "pancakes", nil, GenerationOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if content != `{"title": "Pancakes"}` {
+			t.Errorf("Expected the message content, got %q", content)
+		}
+	}
+
+	want := usageRecord{ProviderDeepSeek, "deepseek-chat", TokenUsage{
+		PromptTokens: 120, CompletionTokens: 300, TotalTokens: 420, PromptCacheHitTokens: 100, PromptCacheMissTokens: 20,
+	}}
+	if len(recorder.records) != 2 || recorder.records[0] != want {
+		t.Errorf("Expected %+v twice, got %+v", want, recorder.records)
+	}
+	if got := total.Usage(); got.TotalTokens != 840 || got.PromptCacheHitTokens != 200 {
+		t.Errorf("Expected the usage of both calls in the total, got %+v", got)
+	}
+}
+
+func TestGenerateEmbeddingRecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": [{"embedding": [0.5]}], "usage": {"prompt_tokens": 8, "total_tokens": 8}}`))
+	}))
+	t.Cleanup(server.Close)
+	original := openAIEmbeddingsURL
+	openAIEmbeddingsURL = server.URL
+	t.Cleanup(func() { openAIEmbeddingsURL = original })
+	t.Setenv("TEST_MODE", "")
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	recorder := useRecordingUsage(t)
+
+	if _, err := GenerateEmbedding(context.Background(), "pancakes"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := usageRecord{ProviderOpenAI, DefaultEmbeddingModel, TokenUsage{PromptTokens: 8, TotalTokens: 8}}
+	if len(recorder.records) != 1 || recorder.records[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, recorder.records)
+	}
+}
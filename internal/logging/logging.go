@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,10 +21,15 @@ import (
 
 // LogConfig holds logging configuration
 type LogConfig struct {
-	LogDir            string
-	MaxSize           int // MB
-	MaxBackups        int
-	MaxAge            int // days
+	// LogDir is where app.log and its rotated backups are written when EnableFile is set.
+	LogDir string
+	// MaxSizeMB is the size at which app.log is rotated.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files kept.
+	MaxBackups int
+	// MaxAgeDays is how long rotated files are kept.
+	MaxAgeDays int
+	// Compress gzips rotated files.
 	Compress          bool
 	LogLevel          string
 	RequestIDHeader   string
@@ -36,6 +42,46 @@ type LogConfig struct {
 	EnableCompression bool
 }
 
+// Defaults applied by NewLogger to non-positive rotation settings, so that file logging never
+// grows without bound.
+const (
+	DefaultLogDir        = "logs"
+	DefaultLogMaxSizeMB  = 100
+	DefaultLogMaxBackups = 5
+	DefaultLogMaxAgeDays = 28
+)
+
+// LoadLogConfig builds the application's logging configuration. Console output is always on;
+// file output with rotation is enabled by LOG_FILE_ENABLED=true and tuned with LOG_DIR,
+// LOG_MAX_SIZE_MB, LOG_MAX_BACKUPS, LOG_MAX_AGE_DAYS and LOG_COMPRESS.
+func LoadLogConfig() LogConfig {
+	return LogConfig{
+		LogDir:        getEnv("LOG_DIR", DefaultLogDir),
+		MaxSizeMB:     getEnvInt("LOG_MAX_SIZE_MB", DefaultLogMaxSizeMB),
+		MaxBackups:    getEnvInt("LOG_MAX_BACKUPS", DefaultLogMaxBackups),
+		MaxAgeDays:    getEnvInt("LOG_MAX_AGE_DAYS", DefaultLogMaxAgeDays),
+		Compress:      os.Getenv("LOG_COMPRESS") == "true",
+		LogLevel:      getEnv("LOG_LEVEL", "debug"),
+		LogFormat:     getEnv("LOG_FORMAT", "json"),
+		EnableConsole: true,
+		EnableFile:    os.Getenv("LOG_FILE_ENABLED") == "true",
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
 // Logger handles all logging operations
 type Logger struct {
 	config     LogConfig
@@ -69,11 +115,23 @@ func NewLogger(config LogConfig) (*Logger, error) {
 
 	// Initialize log rotation
 	if l.config.EnableFile {
+		if l.config.LogDir == "" {
+			l.config.LogDir = DefaultLogDir
+		}
+		if l.config.MaxSizeMB <= 0 {
+			l.config.MaxSizeMB = DefaultLogMaxSizeMB
+		}
+		if l.config.MaxBackups <= 0 {
+			l.config.MaxBackups = DefaultLogMaxBackups
+		}
+		if l.config.MaxAgeDays <= 0 {
+			l.config.MaxAgeDays = DefaultLogMaxAgeDays
+		}
 		rotator := &lumberjack.Logger{
 			Filename:   filepath.Join(l.config.LogDir, "app.log"),
-			MaxSize:    l.config.MaxSize,
+			MaxSize:    l.config.MaxSizeMB,
 			MaxBackups: l.config.MaxBackups,
-			MaxAge:     l.config.MaxAge,
+			MaxAge:     l.config.MaxAgeDays,
 			Compress:   l.config.Compress,
 		}
 		l.rotator = rotator
@@ -137,23 +195,30 @@ func NewLogger(config LogConfig) (*Logger, error) {
 	return l, nil
 }
 
-// RequestIDMiddleware adds request ID to context and logs
+// RequestIDMiddleware accepts the request ID sent in the configured header, or generates one,
+// and returns it in the same response header. The ID is stored in the gin context under
+// RequestIDKey, and the request context carries a logger tagged with it; see FromContext.
 func (l *Logger) RequestIDMiddleware() gin.HandlerFunc {
+	header := l.config.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
 	return func(c *gin.Context) {
-		requestID := c.GetHeader(l.config.RequestIDHeader)
-		if requestID == "" {
+		requestID := c.GetHeader(header)
+		if !validRequestID.MatchString(requestID) {
 			requestID = uuid.New().String()
 		}
 
-		ctx := context.WithValue(c.Request.Context(), "request_id", requestID)
-		c.Request = c.Request.WithContext(ctx)
+		c.Set(RequestIDKey, requestID)
+		c.Header(header, requestID)
+		c.Request = c.Request.WithContext(withRequest(c.Request.Context(), requestID, l.logger))
+		logger := FromGin(c)
 
 		// Store start time for duration calculation
 		startTime := time.Now()
 
 		// Log request start
-		l.Info("Request started",
-			zap.String("request_id", requestID),
+		logger.Info("Request started",
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("ip", c.ClientIP()),
@@ -162,8 +227,7 @@ func (l *Logger) RequestIDMiddleware() gin.HandlerFunc {
 		c.Next()
 
 		// Log request end with duration
-		l.Info("Request completed",
-			zap.String("request_id", requestID),
+		logger.Info("Request completed",
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("duration", time.Since(startTime)),
 		)
@@ -197,7 +261,7 @@ func (l *Logger) Fatal(msg string, fields ...zap.Field) {
 
 // WithContext returns a logger with context fields
 func (l *Logger) WithContext(ctx context.Context) *zap.Logger {
-	if requestID, ok := ctx.Value("request_id").(string); ok {
+	if requestID := RequestID(ctx); requestID != "" {
 		return l.logger.With(zap.String("request_id", requestID))
 	}
 	return l.logger
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DefaultRequestIDHeader is used when LogConfig.RequestIDHeader is empty.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key holding the request ID.
+const RequestIDKey = "request_id"
+
+// contextKey namespaces the values this package stores in a context.
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	loggerContextKey
+)
+
+// validRequestID limits client-supplied request IDs to a safe charset so they cannot forge log
+// lines or headers; anything else is replaced with a generated ID.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// withRequest returns ctx carrying the request ID and a logger that adds it to every entry.
+func withRequest(ctx context.Context, requestID string, logger *zap.Logger) context.Context {
+	ctx = context.WithValue(ctx, requestIDContextKey, requestID)
+	return context.WithValue(ctx, loggerContextKey, logger.With(zap.String("request_id", requestID)))
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "" outside a request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// FromContext returns the request-scoped logger set by RequestIDMiddleware, falling back to
+// the global zap logger outside a request.
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(*zap.Logger); ok {
+		return logger
+	}
+	return zap.L()
+}
+
+// FromGin is FromContext for the request handled by c.
+func FromGin(c *gin.Context) *zap.Logger {
+	return FromContext(c.Request.Context())
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+)
+
+// UserLookup loads a user by ID, e.g. services.UserServiceInterface.
+type UserLookup interface {
+	GetUser(ctx context.Context, id string) (*models.User, error)
+}
+
+// RequireAdmin only lets administrators through and must run after AuthMiddleware. Requests
+// without a current user get 401; users that are not admins, or cannot be loaded, get 403.
+func RequireAdmin(users UserLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := currentUserID(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dtos.ErrorResponse{Code: "UNAUTHORIZED", Message: "Unauthorized"})
+			return
+		}
+		user, err := users.GetUser(c.Request.Context(), userID)
+		if err != nil || user == nil || !user.IsAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, dtos.ErrorResponse{Code: "FORBIDDEN", Message: "Admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// currentUserID returns the user ID set by AuthMiddleware, including the map it sets when
+// authentication is bypassed.
+func currentUserID(c *gin.Context) (string, bool) {
+	value, _ := c.Get("currentUser")
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case map[string]interface{}:
+		id, ok := v["id"].(string)
+		return id, ok && id != ""
+	}
+	return "", false
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,8 +11,23 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"go.uber.org/zap"
 )
 
+// TokenDenylist reports access tokens revoked before their expiry.
+type TokenDenylist interface {
+	IsDenied(ctx context.Context, token string) (bool, error)
+}
+
+// denylist is consulted by AuthMiddleware; nil disables the check.
+var denylist TokenDenylist
+
+// UseTokenDenylist makes AuthMiddleware reject tokens revoked in d, e.g. on logout.
+func UseTokenDenylist(d TokenDenylist) {
+	denylist = d
+}
+
 // AuthMiddleware performs token validation for protected routes.
 // Bypass occurs only if DISABLE_AUTH is explicitly set.
 func AuthMiddleware() gin.HandlerFunc {
@@ -54,6 +70,41 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			// Refresh tokens may only be exchanged for new tokens, not used to call the API.
+			if claims["typ"] == "refresh" {
+				c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{
+					Code:    "UNAUTHORIZED",
+					Message: "Missing or invalid authorization token",
+				})
+				c.Abort()
+				return
+			}
+			// A failed lookup rejects the token, since it may have been revoked, unless
+			// TOKEN_DENYLIST_FAIL_OPEN=true lets it through to keep users signed in while the
+			// denylist store is down.
+			if denylist != nil {
+				denied, err := denylist.IsDenied(c.Request.Context(), tokenString)
+				if err != nil {
+					failOpen := os.Getenv("TOKEN_DENYLIST_FAIL_OPEN") == "true"
+					logging.FromGin(c).Error("Token denylist check failed", zap.Bool("fail_open", failOpen), zap.Error(err))
+					if !failOpen {
+						c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponse{
+							Code:    "SERVICE_UNAVAILABLE",
+							Message: "Unable to verify authorization token",
+						})
+						c.Abort()
+						return
+					}
+				}
+				if denied {
+					c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{
+						Code:    "UNAUTHORIZED",
+						Message: "Token has been revoked",
+					})
+					c.Abort()
+					return
+				}
+			}
 			if id, ok := claims["sub"].(string); ok {
 				c.Set("currentUser", id)
 			}
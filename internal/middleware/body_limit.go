@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+// BodyLimit rejects requests whose body is larger than maxBytes with 413 PAYLOAD_TOO_LARGE.
+// Bodies are read up front, at most maxBytes+1 of them, so a chunked body that only turns out to
+// be too large while it is read is rejected the same way as one with a large Content-Length.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			abortTooLarge(c, maxBytes)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBytes+1))
+		c.Request.Body.Close()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dtos.ErrorResponse{Code: "BAD_REQUEST", Message: "Failed to read request body"})
+			return
+		}
+		if int64(len(body)) > maxBytes {
+			abortTooLarge(c, maxBytes)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context, maxBytes int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dtos.ErrorResponse{
+		Code:    "PAYLOAD_TOO_LARGE",
+		Message: fmt.Sprintf("Request body must not exceed %d bytes", maxBytes),
+	})
+}
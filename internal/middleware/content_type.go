@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+// RequireJSON rejects POST, PUT and PATCH requests that carry a body without a
+// Content-Type of application/json, responding 415 UNSUPPORTED_MEDIA_TYPE. Requests
+// without a body are let through so query-only actions keep working. Routes whose
+// full path is listed in exempt, such as multipart uploads, are not checked.
+func RequireJSON(exempt ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		skip[path] = true
+	}
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+		if skip[c.FullPath()] || !hasBody(c.Request) {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, dtos.ErrorResponse{
+				Code:    "UNSUPPORTED_MEDIA_TYPE",
+				Message: "Content-Type must be application/json",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// hasBody reports whether the request declares a body, either by length or chunked encoding.
+func hasBody(r *http.Request) bool {
+	return r.ContentLength > 0 || len(r.TransferEncoding) > 0
+}
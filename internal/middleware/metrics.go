@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+// RequireScrapeToken only lets through requests that send token as a bearer token, so a
+// Prometheus scraper can read /metrics without a user account. An empty token rejects every
+// request.
+func RequireScrapeToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := []byte(c.GetHeader("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(got, []byte("Bearer "+token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dtos.ErrorResponse{Code: "UNAUTHORIZED", Message: "Unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
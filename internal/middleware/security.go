@@ -1,18 +1,52 @@
 package middleware
 
 import (
+	"os"
+
 	"github.com/gin-gonic/gin"
 )
 
+// SecurityHeadersConfig holds the values of the configurable security headers
+type SecurityHeadersConfig struct {
+	ContentSecurityPolicy   string
+	StrictTransportSecurity string
+}
+
+// DefaultSecurityHeadersConfig is used by SecurityHeaders and for unset environment variables.
+var DefaultSecurityHeadersConfig = SecurityHeadersConfig{
+	ContentSecurityPolicy:   "default-src 'self'",
+	StrictTransportSecurity: "max-age=31536000; includeSubDomains",
+}
+
+// LoadSecurityHeadersConfig reads CONTENT_SECURITY_POLICY and STRICT_TRANSPORT_SECURITY,
+// falling back to DefaultSecurityHeadersConfig for unset values.
+func LoadSecurityHeadersConfig() SecurityHeadersConfig {
+	cfg := DefaultSecurityHeadersConfig
+	if value := os.Getenv("CONTENT_SECURITY_POLICY"); value != "" {
+		cfg.ContentSecurityPolicy = value
+	}
+	if value := os.Getenv("STRICT_TRANSPORT_SECURITY"); value != "" {
+		cfg.StrictTransportSecurity = value
+	}
+	return cfg
+}
+
 // SecurityHeaders adds security headers to all responses
 func SecurityHeaders() gin.HandlerFunc {
+	return SecurityHeadersWithConfig(DefaultSecurityHeadersConfig)
+}
+
+// SecurityHeadersWithConfig is SecurityHeaders with the given CSP and HSTS values.
+func SecurityHeadersWithConfig(cfg SecurityHeadersConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		h := c.Writer.Header()
-		h.Set("Content-Security-Policy", "default-src 'self'")
+		h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
 		h.Set("X-Frame-Options", "DENY")
 		h.Set("X-Content-Type-Options", "nosniff")
 		h.Set("X-XSS-Protection", "1; mode=block")
-		h.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		h.Set("Strict-Transport-Security", cfg.StrictTransportSecurity)
+		h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		h.Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
 		c.Next()
 	}
 }
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+)
+
+// TimeoutConfig holds the request timeouts applied to each route group
+type TimeoutConfig struct {
+	// Default applies to CRUD routes, which should fail fast.
+	Default time.Duration
+	// AI applies to routes that call the external model.
+	AI time.Duration
+	// Upload applies to routes that receive a file and store it in object storage.
+	Upload time.Duration
+}
+
+// LoadTimeoutConfig reads REQUEST_TIMEOUT and UPLOAD_REQUEST_TIMEOUT, falling back to 5s and
+// 60s, and takes the AI timeout from config.LoadAIConfig (AI_REQUEST_TIMEOUT, 90s by default).
+func LoadTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		Default: durationFromEnv("REQUEST_TIMEOUT", 5*time.Second),
+		AI:      config.LoadAIConfig().RequestTimeout,
+		Upload:  durationFromEnv("UPLOAD_REQUEST_TIMEOUT", 60*time.Second),
+	}
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// Timeout bounds the request context by the given duration. The rest of the chain runs in
+// its own goroutine with the response buffered. If the deadline passes first, a 504 TIMEOUT
+// is sent to the client straight away and anything the handler writes afterwards is
+// discarded. The gin context is only released once the handler returns, so handlers must
+// still honour the request context to free resources promptly.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		buffered := &timeoutWriter{ResponseWriter: original, header: original.Header().Clone()}
+		c.Writer = buffered
+
+		done := make(chan struct{})
+		var panicked interface{}
+		go func() {
+			defer close(done)
+			defer func() { panicked = recover() }()
+			c.Next()
+		}()
+
+		timedOut := false
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				buffered.timeOut()
+				writeTimeout(original)
+				timedOut = true
+			}
+			<-done
+		}
+
+		c.Writer = original
+		if panicked != nil {
+			panic(panicked)
+		}
+		if timedOut {
+			c.Abort()
+			return
+		}
+		buffered.flush()
+	}
+}
+
+// writeTimeout sends the 504 with an explicit length and flushes it, so the client gets the
+// whole response while the timed-out handler is still running.
+func writeTimeout(w gin.ResponseWriter) {
+	body, _ := json.Marshal(dtos.ErrorResponse{
+		Code:    "TIMEOUT",
+		Message: "Request timed out",
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusGatewayTimeout)
+	_, _ = w.Write(body)
+	w.Flush()
+}
+
+// timeoutWriter buffers the response so it can be replaced if the request times out. It
+// keeps its own copy of the headers so those the handler sets never reach a 504.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+	w.status = code
+}
+
+// WriteHeaderNow is deferred until the response is flushed.
+func (w *timeoutWriter) WriteHeaderNow() {}
+
+// Flush is a no-op: nothing reaches the client until the handler has finished in time.
+func (w *timeoutWriter) Flush() {}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == 0 {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status != 0
+}
+
+// timeOut discards the buffered response and makes later writes fail.
+func (w *timeoutWriter) timeOut() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timedOut = true
+	w.body.Reset()
+}
+
+// flush writes the buffered headers, status and body to the underlying writer.
+func (w *timeoutWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dst := w.ResponseWriter.Header()
+	for key := range dst {
+		if _, ok := w.header[key]; !ok {
+			delete(dst, key)
+		}
+	}
+	for key, values := range w.header {
+		dst[key] = values
+	}
+	if w.status == 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
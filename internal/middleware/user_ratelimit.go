@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// tokenBucketScript refills the bucket at KEYS[1] for the time elapsed since the last request and
+// takes one token. ARGV: rate per second, burst, now in ms, TTL in ms. It returns {allowed, wait ms}.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+elseif rate > 0 then
+	wait = math.ceil((1 - tokens) / rate * 1000)
+else
+	wait = 60000
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {allowed, wait}
+`)
+
+// RateLimit enforces a token bucket per authenticated user, falling back to the client IP on
+// anonymous routes. Buckets live in Redis so limits hold across instances; when client is nil
+// or Redis fails, an in-process bucket is used instead. Rejected requests get 429 with a
+// Retry-After header in seconds.
+func RateLimit(client *redis.Client, cfg config.RateLimitConfig) gin.HandlerFunc {
+	local := RateLimitConfig{RequestsPerSecond: cfg.RequestsPerSecond, Burst: cfg.Burst, ExpirationTTL: cfg.ExpirationTTL}
+
+	return func(c *gin.Context) {
+		key := "ratelimit:ip:" + c.ClientIP()
+		if id, ok := c.Get("currentUser"); ok {
+			if userID, ok := id.(string); ok && userID != "" {
+				key = "ratelimit:user:" + userID
+			}
+		}
+
+		allowed, wait := false, time.Duration(0)
+		var err error
+		if client != nil {
+			allowed, wait, err = takeRedisToken(c.Request.Context(), client, key, cfg)
+			if err != nil {
+				zap.L().Warn("Redis rate limit check failed, using local limiter", zap.Error(err))
+			}
+		}
+		if client == nil || err != nil {
+			allowed = getLimiter(key, local).Allow()
+			wait = calculateRetryAfter(local)
+		}
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dtos.ErrorResponse{Code: "RATE_LIMITED", Message: "Rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// takeRedisToken runs tokenBucketScript for key and reports whether a token was taken and,
+// if not, how long until the next one is available.
+func takeRedisToken(ctx context.Context, client *redis.Client, key string, cfg config.RateLimitConfig) (bool, time.Duration, error) {
+	ttl := cfg.ExpirationTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	result, err := tokenBucketScript.Run(ctx, client, []string{key},
+		cfg.RequestsPerSecond, cfg.Burst, time.Now().UnixMilli(), ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
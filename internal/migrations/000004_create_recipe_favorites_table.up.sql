@@ -0,0 +1,9 @@
+-- Create recipe_favorites table linking users to the recipes they have favorited
+CREATE TABLE IF NOT EXISTS recipe_favorites (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID REFERENCES recipes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, recipe_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_favorites_recipe_id ON recipe_favorites(recipe_id);
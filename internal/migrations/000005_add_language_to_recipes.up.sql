@@ -0,0 +1 @@
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'en';
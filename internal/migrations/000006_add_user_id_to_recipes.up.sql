@@ -0,0 +1,4 @@
+-- Record the user who created each recipe so only they can delete it
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_user_id ON recipes(user_id);
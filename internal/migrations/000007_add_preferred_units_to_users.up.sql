@@ -0,0 +1,2 @@
+-- Store each user's preferred measurement system (metric or imperial); NULL falls back to imperial
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_units VARCHAR(10);
@@ -0,0 +1,2 @@
+-- Record when each recipe's embedding was last written; NULL means it was never embedded
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS embedding_updated_at TIMESTAMPTZ;
@@ -0,0 +1,14 @@
+-- Create generation_presets table holding each user's named recipe generation defaults
+CREATE TABLE IF NOT EXISTS generation_presets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    cuisine VARCHAR(100),
+    diet VARCHAR(100),
+    max_time_minutes INTEGER,
+    servings INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_generation_presets_user_id ON generation_presets(user_id);
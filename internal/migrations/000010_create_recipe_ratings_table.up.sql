@@ -0,0 +1,11 @@
+-- Create recipe_ratings table holding one rating per user and recipe
+CREATE TABLE IF NOT EXISTS recipe_ratings (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID REFERENCES recipes(id) ON DELETE CASCADE,
+    rating DOUBLE PRECISION NOT NULL CHECK (rating >= 0 AND rating <= 5),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, recipe_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ratings_recipe_id ON recipe_ratings(recipe_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_recipes_ingredients_trgm;
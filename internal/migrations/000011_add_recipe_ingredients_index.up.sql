@@ -0,0 +1,4 @@
+-- Speed up ingredient search, which matches terms anywhere in the ingredients JSON text
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_ingredients_trgm ON recipes USING GIN (LOWER(CAST(ingredients AS TEXT)) gin_trgm_ops);
@@ -0,0 +1,12 @@
+-- Create recipe_audit_log table recording who created, changed or deleted each recipe.
+-- There is no foreign key on recipe_id so entries survive the recipe's deletion.
+CREATE TABLE IF NOT EXISTS recipe_audit_log (
+    id UUID PRIMARY KEY,
+    recipe_id UUID NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    diff JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_audit_log_recipe_id ON recipe_audit_log(recipe_id, created_at);
@@ -0,0 +1,2 @@
+-- URL of the uploaded photo of the finished dish
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS image_url TEXT;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_cuisines_name_lower;
+DROP INDEX IF EXISTS idx_diets_name_lower;
+DROP INDEX IF EXISTS idx_appliances_name_lower;
+DROP INDEX IF EXISTS idx_tags_name_lower;
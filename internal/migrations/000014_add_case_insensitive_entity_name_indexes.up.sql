@@ -0,0 +1,87 @@
+-- Make cuisine, diet, appliance and tag names unique regardless of case and surrounding
+-- whitespace. Rows whose names only differ that way are merged into the oldest one first,
+-- moving their recipes over, so the unique indexes can be created.
+
+WITH merges AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+    FROM cuisines
+)
+INSERT INTO recipe_cuisines (recipe_id, cuisine_id)
+SELECT r.recipe_id, m.keep_id FROM recipe_cuisines r JOIN merges m ON m.id = r.cuisine_id
+WHERE m.id <> m.keep_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM cuisines WHERE id IN (
+    SELECT id FROM (
+        SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+        FROM cuisines
+    ) merges
+    WHERE id <> keep_id
+);
+
+UPDATE cuisines SET name = TRIM(name) WHERE name <> TRIM(name);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cuisines_name_lower ON cuisines (LOWER(TRIM(name)));
+
+WITH merges AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+    FROM diets
+)
+INSERT INTO recipe_diets (recipe_id, diet_id)
+SELECT r.recipe_id, m.keep_id FROM recipe_diets r JOIN merges m ON m.id = r.diet_id
+WHERE m.id <> m.keep_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM diets WHERE id IN (
+    SELECT id FROM (
+        SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+        FROM diets
+    ) merges
+    WHERE id <> keep_id
+);
+
+UPDATE diets SET name = TRIM(name) WHERE name <> TRIM(name);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_diets_name_lower ON diets (LOWER(TRIM(name)));
+
+WITH merges AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+    FROM appliances
+)
+INSERT INTO recipe_appliances (recipe_id, appliance_id)
+SELECT r.recipe_id, m.keep_id FROM recipe_appliances r JOIN merges m ON m.id = r.appliance_id
+WHERE m.id <> m.keep_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM appliances WHERE id IN (
+    SELECT id FROM (
+        SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+        FROM appliances
+    ) merges
+    WHERE id <> keep_id
+);
+
+UPDATE appliances SET name = TRIM(name) WHERE name <> TRIM(name);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appliances_name_lower ON appliances (LOWER(TRIM(name)));
+
+WITH merges AS (
+    SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+    FROM tags
+)
+INSERT INTO recipe_tags (recipe_id, tag_id)
+SELECT r.recipe_id, m.keep_id FROM recipe_tags r JOIN merges m ON m.id = r.tag_id
+WHERE m.id <> m.keep_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM tags WHERE id IN (
+    SELECT id FROM (
+        SELECT id, FIRST_VALUE(id) OVER (PARTITION BY LOWER(TRIM(name)) ORDER BY created_at, id) AS keep_id
+        FROM tags
+    ) merges
+    WHERE id <> keep_id
+);
+
+UPDATE tags SET name = TRIM(name) WHERE name <> TRIM(name);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_lower ON tags (LOWER(TRIM(name)));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_recipes_cloned_from;
+ALTER TABLE recipes DROP COLUMN IF EXISTS cloned_from;
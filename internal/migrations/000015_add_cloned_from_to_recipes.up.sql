@@ -0,0 +1,4 @@
+-- Record the recipe a clone was copied from, for attribution
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS cloned_from UUID REFERENCES recipes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_cloned_from ON recipes(cloned_from);
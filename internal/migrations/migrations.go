@@ -77,6 +77,10 @@ func RunMigrations(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&models.User{},
 		&models.Recipe{},
+		&models.RecipeFavorite{},
+		&models.RecipeRating{},
+		&models.RecipeAuditEntry{},
+		&models.GenerationPreset{},
 	)
 }
 
@@ -3,5 +3,5 @@ package models
 // Appliance represents a cooking appliance required by a recipe (e.g., frying pan, oven, blender).
 type Appliance struct {
 	ID   string `json:"id" gorm:"type:uuid;primaryKey"`
-	Name string `json:"name" gorm:"unique;not null"`
+	Name string `json:"name" gorm:"unique;not null;uniqueIndex:idx_appliances_name_lower,expression:LOWER(TRIM(name))"`
 }
@@ -8,7 +8,7 @@ import (
 // Cuisine represents a cuisine type (e.g., Italian, Chinese).
 type Cuisine struct {
 	ID   string `json:"id" gorm:"type:uuid;primaryKey"`
-	Name string `json:"name" gorm:"unique;not null"`
+	Name string `json:"name" gorm:"unique;not null;uniqueIndex:idx_cuisines_name_lower,expression:LOWER(TRIM(name))"`
 }
 
 // BeforeCreate hook to set a UUID before creating a Cuisine record if ID is not set
@@ -8,7 +8,7 @@ import (
 // Diet represents a diet category (e.g., vegan, keto).
 type Diet struct {
 	ID   string `json:"id" gorm:"type:uuid;primaryKey"`
-	Name string `json:"name" gorm:"unique;not null"`
+	Name string `json:"name" gorm:"unique;not null;uniqueIndex:idx_diets_name_lower,expression:LOWER(TRIM(name))"`
 }
 
 // BeforeCreate hook to set a UUID before creating a Diet record if ID is not set
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RecipeFavorite records that a user has favorited a recipe.
+type RecipeFavorite struct {
+	UserID    string    `json:"user_id" gorm:"type:uuid;primaryKey"`
+	RecipeID  string    `json:"recipe_id" gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName overrides the default table name used by GORM.
+func (RecipeFavorite) TableName() string {
+	return "recipe_favorites"
+}
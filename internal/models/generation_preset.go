@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GenerationPreset is a named set of default constraints a user applies to recipe generation,
+// e.g. "weeknight dinner" for 30 minute family meals.
+type GenerationPreset struct {
+	ID             string    `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID         string    `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name           string    `json:"name" gorm:"not null"`
+	Cuisine        string    `json:"cuisine,omitempty"`
+	Diet           string    `json:"diet,omitempty"`
+	MaxTimeMinutes int       `json:"max_time_minutes,omitempty"`
+	Servings       int       `json:"servings,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName overrides the default table name used by GORM.
+func (GenerationPreset) TableName() string {
+	return "generation_presets"
+}
+
+// BeforeCreate hook to set a UUID before creating a GenerationPreset record if ID is not set
+func (p *GenerationPreset) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RecipeRating records a user's rating of a recipe. Each user has at most one rating per
+// recipe; rating again replaces it.
+type RecipeRating struct {
+	UserID    string    `json:"user_id" gorm:"type:uuid;primaryKey"`
+	RecipeID  string    `json:"recipe_id" gorm:"type:uuid;primaryKey;index"`
+	Rating    float64   `json:"rating" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName overrides the default table name used by GORM.
+func (RecipeRating) TableName() string {
+	return "recipe_ratings"
+}
+
+// RatingSummary aggregates the ratings of a recipe.
+type RatingSummary struct {
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+}
@@ -47,9 +47,18 @@ type Step struct {
 	Description string `json:"description"`
 }
 
+// RecipeFilter narrows a recipe listing. Zero values apply no filtering.
+type RecipeFilter struct {
+	Difficulty string
+	// Tags are tag names; recipes must carry all of them unless MatchAnyTag is set.
+	Tags        []string
+	MatchAnyTag bool
+}
+
 // Recipe represents a recipe in the application.
 type Recipe struct {
 	ID                string         `json:"id" gorm:"primaryKey"`
+	UserID            *string        `json:"user_id,omitempty" gorm:"index"`
 	Title             string         `json:"title" gorm:"not null"`
 	Description       string         `json:"description"`
 	Ingredients       datatypes.JSON `json:"ingredients" gorm:"type:json"`
@@ -61,16 +70,22 @@ type Recipe struct {
 	Appliances        []Appliance    `json:"appliances" gorm:"many2many:recipe_appliances;"`
 	Tags              []Tag          `json:"tags" gorm:"many2many:recipe_tags;"`
 	Images            datatypes.JSON `json:"images" gorm:"type:json"`
+	ImageURL          string         `json:"image_url,omitempty"`
 	Difficulty        string         `json:"difficulty"`
 	PrepTime          int            `json:"prep_time"`
 	CookTime          int            `json:"cooking_time"`
 	Servings          int            `json:"servings"`
+	Language          string         `json:"language" gorm:"size:10;not null;default:en"`
 	AverageRating     float64        `json:"average_rating"`
 	RatingCount       int            `json:"rating_count"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 	Approved          bool           `json:"approved"`
 	Embedding         Float64Slice   `json:"embedding" gorm:"type:json"`
+	// EmbeddingUpdatedAt is when Embedding was last written; nil if the recipe has none.
+	EmbeddingUpdatedAt *time.Time `json:"embedding_updated_at,omitempty"`
+	// ClonedFrom is the ID of the recipe this one was copied from, for attribution.
+	ClonedFrom *string `json:"cloned_from,omitempty" gorm:"index"`
 }
 
 // BeforeCreate is a GORM hook that runs before a new record is inserted.
@@ -85,9 +100,41 @@ func (r *Recipe) BeforeCreate(tx *gorm.DB) (err error) {
 	if r.UpdatedAt.IsZero() {
 		r.UpdatedAt = time.Now()
 	}
+	if r.Language == "" {
+		r.Language = "en"
+	}
 	return nil
 }
 
+// Clone returns an unsaved copy of the recipe owned by ownerID that records the recipe as its
+// source. The copy shares no slices with the original and keeps the related entities' IDs. Its
+// ID, timestamps, ratings, approval and embedding are left unset, so saving it creates a new,
+// unapproved recipe with a fresh embedding.
+func (r *Recipe) Clone(ownerID string) *Recipe {
+	sourceID := r.ID
+	return &Recipe{
+		UserID:            &ownerID,
+		Title:             r.Title,
+		Description:       r.Description,
+		Ingredients:       append(datatypes.JSON(nil), r.Ingredients...),
+		Steps:             append(datatypes.JSON(nil), r.Steps...),
+		NutritionalInfo:   r.NutritionalInfo,
+		AllergyDisclaimer: r.AllergyDisclaimer,
+		Cuisines:          append([]Cuisine(nil), r.Cuisines...),
+		Diets:             append([]Diet(nil), r.Diets...),
+		Appliances:        append([]Appliance(nil), r.Appliances...),
+		Tags:              append([]Tag(nil), r.Tags...),
+		Images:            append(datatypes.JSON(nil), r.Images...),
+		ImageURL:          r.ImageURL,
+		Difficulty:        r.Difficulty,
+		PrepTime:          r.PrepTime,
+		CookTime:          r.CookTime,
+		Servings:          r.Servings,
+		Language:          r.Language,
+		ClonedFrom:        &sourceID,
+	}
+}
+
 // Helper methods for JSON conversion
 func (r *Recipe) GetIngredients() ([]Ingredient, error) {
 	var ingredients []Ingredient
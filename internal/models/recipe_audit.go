@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Actions recorded in the recipe audit log.
+const (
+	RecipeAuditCreate = "create"
+	RecipeAuditUpdate = "update"
+	RecipeAuditDelete = "delete"
+)
+
+// RecipeAuditEntry records a change made to a recipe: who made it, what kind of change it was
+// and, for updates, the fields that changed. Entries outlive the recipe they describe.
+type RecipeAuditEntry struct {
+	ID       string `json:"id" gorm:"type:uuid;primaryKey"`
+	RecipeID string `json:"recipe_id" gorm:"type:uuid;not null;index"`
+	// ActorID is the user who made the change; nil when it was made without one.
+	ActorID *string `json:"actor_id,omitempty" gorm:"type:uuid"`
+	Action  string  `json:"action" gorm:"not null"`
+	// Diff maps each changed field to its old and new value.
+	Diff      datatypes.JSON `json:"diff" gorm:"type:json"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index"`
+}
+
+// TableName overrides the default table name used by GORM.
+func (RecipeAuditEntry) TableName() string {
+	return "recipe_audit_log"
+}
+
+// BeforeCreate is a GORM hook that generates the entry's ID.
+func (e *RecipeAuditEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
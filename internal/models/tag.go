@@ -4,5 +4,5 @@ package models
 // It can be used for statuses such as "featured", "quick", "seasonal", etc.
 type Tag struct {
 	ID   string `json:"id" gorm:"type:uuid;primaryKey"`
-	Name string `json:"name" gorm:"unique;not null"`
+	Name string `json:"name" gorm:"unique;not null;uniqueIndex:idx_tags_name_lower,expression:LOWER(TRIM(name))"`
 }
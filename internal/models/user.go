@@ -20,6 +20,7 @@ type User struct {
 	ResetPasswordExpires     *time.Time     `json:"reset_password_expires,omitempty"`
 	LastLoginAt              *time.Time     `json:"last_login_at,omitempty"`
 	LastActiveAt             *time.Time     `json:"last_active_at,omitempty"`
+	PreferredUnits           string         `json:"preferred_units,omitempty" gorm:"size:10"`
 	DeletedAt                gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 	CreatedAt                time.Time      `json:"created_at,omitempty"`
 	UpdatedAt                time.Time      `json:"updated_at,omitempty"`
@@ -0,0 +1,119 @@
+package monitoring
+
+import (
+	"sync"
+
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ModelPrice is the price in USD per million tokens, used to estimate the cost of model calls.
+type ModelPrice struct {
+	// Input applies to prompt tokens, or only to cache misses when the API reports cache usage.
+	Input float64
+	// CachedInput applies to prompt tokens served from the provider's context cache.
+	CachedInput float64
+	Output      float64
+}
+
+// DefaultModelPrices are the providers' list prices. Usage of models missing from the table is
+// counted without a cost estimate.
+var DefaultModelPrices = map[string]ModelPrice{
+	"deepseek-chat":          {Input: 0.27, CachedInput: 0.07, Output: 1.10},
+	"deepseek-reasoner":      {Input: 0.55, CachedInput: 0.14, Output: 2.19},
+	"text-embedding-3-small": {Input: 0.02},
+}
+
+// DefaultAIUsage records model usage into the default Prometheus registry.
+var DefaultAIUsage = NewAIUsage(prometheus.DefaultRegisterer, DefaultModelPrices)
+
+// AIUsage accumulates token usage, estimated cost and the prompt cache hit ratio per provider
+// and model. It implements integrations.UsageRecorder.
+type AIUsage struct {
+	prices        map[string]ModelPrice
+	requests      *prometheus.CounterVec
+	tokens        *prometheus.CounterVec
+	cost          *prometheus.CounterVec
+	cacheHitRatio *prometheus.GaugeVec
+
+	mu    sync.Mutex
+	cache map[[2]string]*cacheTotals
+}
+
+// cacheTotals are the prompt tokens seen for a model, split by cache hits and misses.
+type cacheTotals struct {
+	hits, misses int
+}
+
+// NewAIUsage registers the model usage metrics with reg and returns a recorder that estimates
+// cost from prices.
+func NewAIUsage(reg prometheus.Registerer, prices map[string]ModelPrice) *AIUsage {
+	factory := promauto.With(reg)
+	return &AIUsage{
+		prices: prices,
+		requests: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "ai_requests_total",
+				Help: "Total number of successful model API calls",
+			},
+			[]string{"provider", "model"},
+		),
+		tokens: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "ai_tokens_total",
+				Help: "Total number of tokens used by model API calls, by type (prompt, completion, cache_hit, cache_miss)",
+			},
+			[]string{"provider", "model", "type"},
+		),
+		cost: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "ai_estimated_cost_usd_total",
+				Help: "Estimated cost of model API calls in USD",
+			},
+			[]string{"provider", "model"},
+		),
+		cacheHitRatio: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ai_prompt_cache_hit_ratio",
+				Help: "Share of prompt tokens served from the provider's context cache",
+			},
+			[]string{"provider", "model"},
+		),
+		cache: make(map[[2]string]*cacheTotals),
+	}
+}
+
+// RecordUsage implements integrations.UsageRecorder.
+func (u *AIUsage) RecordUsage(provider, model string, usage integrations.TokenUsage) {
+	u.requests.WithLabelValues(provider, model).Inc()
+	u.tokens.WithLabelValues(provider, model, "prompt").Add(float64(usage.PromptTokens))
+	u.tokens.WithLabelValues(provider, model, "completion").Add(float64(usage.CompletionTokens))
+
+	cached := usage.PromptCacheHitTokens+usage.PromptCacheMissTokens > 0
+	if cached {
+		u.tokens.WithLabelValues(provider, model, "cache_hit").Add(float64(usage.PromptCacheHitTokens))
+		u.tokens.WithLabelValues(provider, model, "cache_miss").Add(float64(usage.PromptCacheMissTokens))
+
+		u.mu.Lock()
+		key := [2]string{provider, model}
+		totals, ok := u.cache[key]
+		if !ok {
+			totals = &cacheTotals{}
+			u.cache[key] = totals
+		}
+		totals.hits += usage.PromptCacheHitTokens
+		totals.misses += usage.PromptCacheMissTokens
+		ratio := float64(totals.hits) / float64(totals.hits+totals.misses)
+		u.mu.Unlock()
+		u.cacheHitRatio.WithLabelValues(provider, model).Set(ratio)
+	}
+
+	if price, ok := u.prices[model]; ok {
+		input := float64(usage.PromptTokens) * price.Input
+		if cached {
+			input = float64(usage.PromptCacheHitTokens)*price.CachedInput + float64(usage.PromptCacheMissTokens)*price.Input
+		}
+		u.cost.WithLabelValues(provider, model).Add((input + float64(usage.CompletionTokens)*price.Output) / 1e6)
+	}
+}
@@ -0,0 +1,81 @@
+package monitoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatheredValue returns the value of the counter or gauge name whose labels include labels.
+func gatheredValue(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if want, ok := labels[pair.GetName()]; ok && want != pair.GetValue() {
+					continue metrics
+				}
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("Metric %s%v not found", name, labels)
+	return 0
+}
+
+func TestAIUsageRecordsTokensCostAndCacheRatio(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	usage := NewAIUsage(reg, map[string]ModelPrice{"deepseek-chat": {Input: 1, CachedInput: 0.5, Output: 2}})
+
+	usage.RecordUsage(integrations.ProviderDeepSeek, "deepseek-chat", integrations.TokenUsage{
+		PromptTokens: 1000, CompletionTokens: 500, PromptCacheHitTokens: 800, PromptCacheMissTokens: 200,
+	})
+	usage.RecordUsage(integrations.ProviderDeepSeek, "deepseek-chat", integrations.TokenUsage{
+		PromptTokens: 1000, CompletionTokens: 500, PromptCacheHitTokens: 0, PromptCacheMissTokens: 1000,
+	})
+
+	model := map[string]string{"provider": "deepseek", "model": "deepseek-chat"}
+	if got := gatheredValue(t, reg, "ai_requests_total", model); got != 2 {
+		t.Errorf("Expected 2 requests, got %v", got)
+	}
+	if got := gatheredValue(t, reg, "ai_tokens_total", map[string]string{"model": "deepseek-chat", "type": "completion"}); got != 1000 {
+		t.Errorf("Expected 1000 completion tokens, got %v", got)
+	}
+	if got := gatheredValue(t, reg, "ai_prompt_cache_hit_ratio", model); got != 0.4 {
+		t.Errorf("Expected a cache hit ratio of 0.4, got %v", got)
+	}
+	// (800*0.5 + 200*1 + 500*2) + (1000*1 + 500*2) per million tokens.
+	if got := gatheredValue(t, reg, "ai_estimated_cost_usd_total", model); math.Abs(got-0.0036) > 1e-12 {
+		t.Errorf("Expected an estimated cost of 0.0036, got %v", got)
+	}
+}
+
+func TestAIUsageWithoutPriceOrCache(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	usage := NewAIUsage(reg, nil)
+
+	usage.RecordUsage(integrations.ProviderOpenAI, "text-embedding-3-small", integrations.TokenUsage{PromptTokens: 8, TotalTokens: 8})
+
+	if got := gatheredValue(t, reg, "ai_tokens_total", map[string]string{"type": "prompt"}); got != 8 {
+		t.Errorf("Expected 8 prompt tokens, got %v", got)
+	}
+	families, _ := reg.Gather()
+	for _, family := range families {
+		if name := family.GetName(); name == "ai_estimated_cost_usd_total" || name == "ai_prompt_cache_hit_ratio" {
+			t.Errorf("Did not expect %s without a price or cache usage", name)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package monitoring
+
+import (
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultDeepSeekBreakerState exports the state of the DeepSeek circuit breaker in the default
+// Prometheus registry.
+var DefaultDeepSeekBreakerState = NewBreakerStateGauge(prometheus.DefaultRegisterer, integrations.ProviderDeepSeek, integrations.DeepSeekBreaker)
+
+// NewBreakerStateGauge registers ai_circuit_breaker_state for provider with reg. Every scrape
+// reads the breaker returned by breaker and reports 0 when it is closed or disabled, 1 when
+// half-open and 2 when open.
+func NewBreakerStateGauge(reg prometheus.Registerer, provider string, breaker func() *integrations.CircuitBreaker) prometheus.GaugeFunc {
+	return promauto.With(reg).NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name:        "ai_circuit_breaker_state",
+			Help:        "State of the model API circuit breaker: 0 closed, 1 half-open, 2 open",
+			ConstLabels: prometheus.Labels{"provider": provider},
+		},
+		func() float64 {
+			if b := breaker(); b != nil {
+				return float64(b.State())
+			}
+			return float64(integrations.BreakerClosed)
+		},
+	)
+}
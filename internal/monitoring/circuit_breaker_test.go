@@ -0,0 +1,30 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestBreakerStateGauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	var breaker *integrations.CircuitBreaker
+	NewBreakerStateGauge(reg, "deepseek", func() *integrations.CircuitBreaker { return breaker })
+	labels := map[string]string{"provider": "deepseek"}
+
+	if got := gatheredValue(t, reg, "ai_circuit_breaker_state", labels); got != 0 {
+		t.Errorf("Expected a disabled breaker to report 0, got %v", got)
+	}
+
+	breaker = integrations.NewCircuitBreaker(1, time.Hour)
+	if got := gatheredValue(t, reg, "ai_circuit_breaker_state", labels); got != 0 {
+		t.Errorf("Expected a closed breaker to report 0, got %v", got)
+	}
+	breaker.Allow()
+	breaker.Failure()
+	if got := gatheredValue(t, reg, "ai_circuit_breaker_state", labels); got != 2 {
+		t.Errorf("Expected an open breaker to report 2, got %v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package parsers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+)
+
+// ingredientSynonymsJSON maps each canonical ingredient name to the other names users type for it.
+//
+//go:embed data/ingredient_synonyms.json
+var ingredientSynonymsJSON []byte
+
+// ingredientSynonyms maps every known ingredient name, canonical or not, to its canonical form.
+var ingredientSynonyms = loadIngredientSynonyms(ingredientSynonymsJSON)
+
+// loadIngredientSynonyms inverts the canonical-to-synonyms file into a lookup table. The file is
+// embedded at build time, so a malformed file is a programming error and panics.
+func loadIngredientSynonyms(data []byte) map[string]string {
+	var canonical map[string][]string
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		panic("parsers: invalid ingredient synonyms: " + err.Error())
+	}
+	synonyms := make(map[string]string)
+	for name, variants := range canonical {
+		synonyms[strings.ToLower(name)] = strings.ToLower(name)
+		for _, variant := range variants {
+			synonyms[strings.ToLower(variant)] = strings.ToLower(name)
+		}
+	}
+	return synonyms
+}
+
+// NormalizeIngredient returns the canonical form of an ingredient name, such as "green onions"
+// for "scallions". Unknown names are returned lower-cased but otherwise unchanged.
+func NormalizeIngredient(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := ingredientSynonyms[name]; ok {
+		return canonical
+	}
+	return name
+}
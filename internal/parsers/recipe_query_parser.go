@@ -2,6 +2,8 @@ package parsers
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/jdkato/prose/v2"
@@ -11,8 +13,13 @@ import (
 type ParsedQuery struct {
 	Cuisine             string   `json:"cuisine"`
 	DietaryRestrictions string   `json:"dietary_restrictions"`
-	Ingredients         []string `json:"ingredients"`
-	Exclusions          []string `json:"exclusions"`
+	Ingredients         []string `json:"ingredients"` // Canonical names, see NormalizeIngredient
+	Exclusions          []string `json:"exclusions"`  // Canonical names, see NormalizeIngredient
+
+	// RawIngredients and RawExclusions hold the names as the user typed them, index for index
+	// with Ingredients and Exclusions, for display.
+	RawIngredients []string `json:"raw_ingredients"`
+	RawExclusions  []string `json:"raw_exclusions"`
 
 	// Additional optional filters for more detailed queries
 	Timing             int    `json:"timing,omitempty"`               // Total time in minutes (prep + cooking)
@@ -20,11 +27,65 @@ type ParsedQuery struct {
 	Difficulty         string `json:"difficulty,omitempty"`           // e.g., "easy", "medium", "hard"
 	CaloriesPerServing int    `json:"calories_per_serving,omitempty"` // Maximum calories per serving
 	ServingSize        string `json:"serving_size,omitempty"`         // e.g., "small", "medium", "large"
+
+	Appliances     []string `json:"appliances"`                 // Cooking appliances named in the query, e.g. "air fryer"
+	MaxTimeMinutes int      `json:"max_time_minutes,omitempty"` // Upper time limit from phrases like "quick" or "under 30 minutes"
+}
+
+// knownAppliances maps the phrases recognised in a query to the appliance name reported in
+// ParsedQuery.Appliances. Longer phrases come first so "dutch oven" is matched before "oven".
+var knownAppliances = []struct {
+	pattern *regexp.Regexp
+	name    string
+}{
+	{regexp.MustCompile(`\bair[- ]?fryer\b`), "air fryer"},
+	{regexp.MustCompile(`\b(?:slow[- ]cooker|crock[- ]?pot)\b`), "slow cooker"},
+	{regexp.MustCompile(`\b(?:pressure[- ]cooker|instant[- ]?pot)\b`), "pressure cooker"},
+	{regexp.MustCompile(`\brice[- ]cooker\b`), "rice cooker"},
+	{regexp.MustCompile(`\bsous[- ]vide\b`), "sous vide"},
+	{regexp.MustCompile(`\bdutch oven\b`), "dutch oven"},
+	{regexp.MustCompile(`\bmicrowave(?: oven)?\b`), "microwave"},
+	{regexp.MustCompile(`\boven\b`), "oven"},
+	{regexp.MustCompile(`\b(?:grill|bbq|barbecue)\b`), "grill"},
+	{regexp.MustCompile(`\bwok\b`), "wok"},
+	{regexp.MustCompile(`\bsmoker\b`), "smoker"},
+	{regexp.MustCompile(`\bblender\b`), "blender"},
+	{regexp.MustCompile(`\bfood processor\b`), "food processor"},
 }
 
+var (
+	// negationWords start an exclusion clause.
+	negationWords = map[string]bool{"no": true, "not": true, "n't": true, "without": true, "except": true, "excluding": true, "minus": true, "neither": true, "nor": true}
+	// negationEnds close an exclusion clause, as in "no onions but with garlic".
+	negationEnds = map[string]bool{"but": true, "with": true, ".": true, ";": true, "!": true, "?": true}
+	// negationContinues are prepositions that do not end an exclusion clause; any other
+	// preposition does, so "no mushrooms in the lasagna" keeps lasagna.
+	negationContinues = map[string]bool{"of": true}
+	// listSeparators join the items of an exclusion list. After one of them the clause only goes
+	// on when another bare item follows, so "no onions, and lots of garlic" keeps garlic; "or"
+	// and "nor" always continue it.
+	listSeparators = map[string]bool{",": true, "and": true, "&": true}
+	// amountWords start a new, requested item rather than continuing an exclusion list.
+	amountWords = map[string]bool{"lots": true, "lot": true, "loads": true, "plenty": true, "extra": true, "more": true, "some": true, "much": true, "additional": true, "a": true, "an": true, "the": true}
+	// degreeWords after "not" describe how much of a quality is wanted, as in "not too spicy".
+	degreeWords = map[string]bool{"too": true, "very": true, "so": true, "overly": true, "that": true, "really": true}
+)
+
+// quickMaxTimeMinutes is the time limit assumed when a query asks for a quick recipe without a number.
+const quickMaxTimeMinutes = 30
+
+var (
+	// timeLimitPattern matches limits such as "under 30 minutes", "in 1 hour" or "less than 45 mins".
+	timeLimitPattern = regexp.MustCompile(`\b(?:under|in|within|less than|no more than|at most|max(?:imum)?)\s+(\d+)\s*(minutes?|mins?|hours?|hrs?)\b`)
+	// timeAdjectivePattern matches durations used as adjectives, such as "30-minute" or "1 hour".
+	timeAdjectivePattern = regexp.MustCompile(`\b(\d+)[- ]?(minutes?|mins?|hours?|hrs?)\b`)
+	quickPattern         = regexp.MustCompile(`\b(?:quick|quickly|fast|speedy|rapid)\b`)
+)
+
 // ParseRecipeQuery parses the user's freeform query into a structured ParsedQuery using the prose NLP library.
 // This implementation uses tokenization and basic part-of-speech tagging to extract information,
-// including handling exclusions when a user specifies they don't want an ingredient (e.g., "no onions").
+// including handling exclusions when a user specifies they don't want an ingredient (e.g., "no onions",
+// "without onions or garlic", "no dairy and no nuts", "nut-free").
 func ParseRecipeQuery(query string) (*ParsedQuery, error) {
 	if strings.TrimSpace(query) == "" {
 		return nil, errors.New("empty query")
@@ -41,54 +102,86 @@ func ParseRecipeQuery(query string) (*ParsedQuery, error) {
 
 	cuisine := "unknown"
 	dietary := "none"
-	ingredients := []string{}
-	exclusions := []string{}
+	ingredients := newIngredientList()
+	exclusions := newIngredientList()
 	tokens := doc.Tokens()
 
+	// negated is set by a negation word such as "no" or "without" and covers every noun up to the
+	// end of the clause, so "without onions, garlic or leeks" excludes all three.
+	negated := false
 	for i, tok := range tokens {
 		lowerToken := strings.ToLower(tok.Text)
 
-		// Check if the token matches any known cuisines
-		for _, c := range knownCuisines {
-			if lowerToken == c {
-				cuisine = c
-			}
+		switch {
+		case negationWords[lowerToken]:
+			// "not too spicy" or "not sweet" qualifies the dish instead of excluding what follows.
+			negated = !negatesQuality(tokens, i, knownCuisines, knownDietary)
+			continue
+		case negationEnds[lowerToken] || (tok.Tag == "IN" && !negationContinues[lowerToken]):
+			negated = false
+		case negated && listSeparators[lowerToken]:
+			negated = continuesExclusionList(tokens, i)
 		}
 
-		// Check if the token matches any known dietary restrictions
-		for _, d := range knownDietary {
-			if lowerToken == d {
-				dietary = d
+		// A negated cuisine or dietary restriction ("not vegan") is dropped rather than recorded,
+		// and uses up the negation so the dish it describes is not excluded as well.
+		if contains(knownCuisines, lowerToken) || contains(knownDietary, lowerToken) {
+			if negated {
+				negated = false
+				continue
+			}
+			if contains(knownCuisines, lowerToken) {
+				cuisine = lowerToken
+			} else {
+				dietary = lowerToken
 			}
 		}
 
+		// Compounds such as "nut-free" exclude their first half, unless they name a known diet.
+		if base, ok := strings.CutSuffix(lowerToken, "-free"); ok && base != "" && !contains(knownDietary, lowerToken) {
+			exclusions.add(base, true)
+			continue
+		}
+
 		// Check if token is a noun (ingredient candidate)
 		if strings.HasPrefix(tok.Tag, "NN") {
-			// If the previous token is "no" or "without", add to exclusions
+			target := ingredients
+			if negated {
+				target = exclusions
+			} else if lowerToken == cuisine || lowerToken == dietary {
+				// Avoid duplicate insertion if already captured as cuisine/dietary
+				continue
+			}
+			// Two-word synonyms such as "spring onions" replace the first word if it was taken on its own.
+			name := lowerToken
 			if i > 0 {
-				prevToken := strings.ToLower(tokens[i-1].Text)
-				if prevToken == "no" || prevToken == "without" {
-					exclusions = append(exclusions, lowerToken)
-					continue
+				previous := strings.ToLower(tokens[i-1].Text)
+				if _, ok := ingredientSynonyms[previous+" "+lowerToken]; ok {
+					target.removeLast(previous)
+					name = previous + " " + lowerToken
 				}
 			}
-			// Otherwise, add to ingredients (avoid duplicate insertion if already captured as cuisine/dietary)
-			if lowerToken != cuisine && lowerToken != dietary {
-				ingredients = append(ingredients, lowerToken)
-			}
+			target.add(name, negated)
 		}
 	}
 
+	// An excluded ingredient must never also be requested, e.g. "onion soup without onions".
+	ingredients.removeAll(exclusions.canonical)
+
 	pq := &ParsedQuery{
 		Cuisine:             cuisine,
 		DietaryRestrictions: dietary,
-		Ingredients:         ingredients,
-		Exclusions:          exclusions,
+		Ingredients:         ingredients.canonical,
+		Exclusions:          exclusions.canonical,
+		RawIngredients:      ingredients.raw,
+		RawExclusions:       exclusions.raw,
 		Timing:              0,
 		Servings:            0,
 		Difficulty:          "",
 		CaloriesPerServing:  0,
 		ServingSize:         "",
+		Appliances:          parseAppliances(query),
+		MaxTimeMinutes:      parseMaxTimeMinutes(query),
 	}
 
 	// TODO: Implement enhanced NLP parsing here to extract tokens for timing, servings, difficulty, etc.
@@ -96,3 +189,125 @@ func ParseRecipeQuery(query string) (*ParsedQuery, error) {
 
 	return pq, nil
 }
+
+// negatesQuality reports whether the negation at tokens[i] is a "not" that qualifies an adjective,
+// such as "not too spicy", rather than excluding an ingredient. Known cuisines and diets are still
+// negated, so "not vegan" drops the diet.
+func negatesQuality(tokens []prose.Token, i int, knownCuisines, knownDietary []string) bool {
+	word := strings.ToLower(tokens[i].Text)
+	if (word != "not" && word != "n't") || i+1 >= len(tokens) {
+		return false
+	}
+	next := strings.ToLower(tokens[i+1].Text)
+	if contains(knownCuisines, next) || contains(knownDietary, next) {
+		return false
+	}
+	return degreeWords[next] || strings.HasPrefix(tokens[i+1].Tag, "JJ") || strings.HasPrefix(tokens[i+1].Tag, "RB")
+}
+
+// continuesExclusionList reports whether the exclusion clause goes on past the separator at
+// tokens[i]: the next item must be a bare ingredient, not an amount such as "lots of garlic" or
+// "extra cheese". A following negation word starts a new clause anyway.
+func continuesExclusionList(tokens []prose.Token, i int) bool {
+	j := i + 1
+	for j < len(tokens) && listSeparators[strings.ToLower(tokens[j].Text)] {
+		j++
+	}
+	if j >= len(tokens) {
+		return false
+	}
+	next := strings.ToLower(tokens[j].Text)
+	if negationWords[next] || amountWords[next] {
+		return false
+	}
+	if j+1 < len(tokens) && strings.ToLower(tokens[j+1].Text) == "of" {
+		return false
+	}
+	return strings.HasPrefix(tokens[j].Tag, "NN") || strings.HasPrefix(tokens[j].Tag, "JJ")
+}
+
+// parseAppliances returns the known appliances named in the query, in the order of knownAppliances.
+// Each match is removed before the next pattern runs, so "dutch oven" is not also reported as "oven".
+func parseAppliances(query string) []string {
+	remaining := strings.ToLower(query)
+	appliances := []string{}
+	for _, appliance := range knownAppliances {
+		if appliance.pattern.MatchString(remaining) {
+			appliances = append(appliances, appliance.name)
+			remaining = appliance.pattern.ReplaceAllString(remaining, " ")
+		}
+	}
+	return appliances
+}
+
+// parseMaxTimeMinutes returns the time limit in minutes asked for by the query, or 0 when it sets none.
+// An explicit duration wins over words like "quick".
+func parseMaxTimeMinutes(query string) int {
+	lower := strings.ToLower(query)
+	for _, pattern := range []*regexp.Regexp{timeLimitPattern, timeAdjectivePattern} {
+		if match := pattern.FindStringSubmatch(lower); match != nil {
+			value, err := strconv.Atoi(match[1])
+			if err != nil || value <= 0 {
+				continue
+			}
+			if strings.HasPrefix(match[2], "h") {
+				value *= 60
+			}
+			return value
+		}
+	}
+	if quickPattern.MatchString(lower) {
+		return quickMaxTimeMinutes
+	}
+	return 0
+}
+
+// contains reports whether values holds value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ingredientList collects ingredient names in canonical form alongside the names as typed.
+type ingredientList struct {
+	canonical []string
+	raw       []string
+}
+
+func newIngredientList() *ingredientList {
+	return &ingredientList{canonical: []string{}, raw: []string{}}
+}
+
+// add records name, skipping it when unique is set and its canonical form is already present.
+func (l *ingredientList) add(name string, unique bool) {
+	canonical := NormalizeIngredient(name)
+	if unique && contains(l.canonical, canonical) {
+		return
+	}
+	l.canonical = append(l.canonical, canonical)
+	l.raw = append(l.raw, name)
+}
+
+// removeLast drops the last entry if it was typed as raw.
+func (l *ingredientList) removeLast(raw string) {
+	if n := len(l.raw); n > 0 && l.raw[n-1] == raw {
+		l.canonical = l.canonical[:n-1]
+		l.raw = l.raw[:n-1]
+	}
+}
+
+// removeAll drops every entry whose canonical form is in canonical.
+func (l *ingredientList) removeAll(canonical []string) {
+	keptCanonical, keptRaw := []string{}, []string{}
+	for i, name := range l.canonical {
+		if !contains(canonical, name) {
+			keptCanonical = append(keptCanonical, name)
+			keptRaw = append(keptRaw, l.raw[i])
+		}
+	}
+	l.canonical, l.raw = keptCanonical, keptRaw
+}
@@ -1,6 +1,7 @@
 package parsers
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -34,3 +35,130 @@ func TestParseRecipeQueryMexicanVegan(t *testing.T) {
 		t.Error("Expected non-empty ingredients list, but got empty")
 	}
 }
+
+func TestParseRecipeQueryAppliancesAndTime(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		appliances     []string
+		maxTimeMinutes int
+	}{
+		{"quick air fryer dinner", "a quick dinner in an air fryer using 2 chicken breasts", []string{"air fryer"}, 30},
+		{"explicit limit", "vegan curry under 45 minutes", []string{}, 45},
+		{"limit in hours", "beef stew in a slow cooker, ready in 2 hours", []string{"slow cooker"}, 120},
+		{"adjective duration", "30-minute pasta in the instant pot", []string{"pressure cooker"}, 30},
+		{"explicit limit wins over quick", "quick lasagna in less than 50 mins", []string{}, 50},
+		{"dutch oven is not also an oven", "sourdough bread baked in a dutch oven", []string{"dutch oven"}, 0},
+		{"several appliances", "grill the steak, then finish it in the oven", []string{"oven", "grill"}, 0},
+		{"no new signals", "I want a Mexican vegan dish with tomatoes and onions", []string{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseRecipeQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Appliances, tt.appliances) {
+				t.Errorf("Expected appliances %v, got %v", tt.appliances, parsed.Appliances)
+			}
+			if parsed.MaxTimeMinutes != tt.maxTimeMinutes {
+				t.Errorf("Expected max time %d, got %d", tt.maxTimeMinutes, parsed.MaxTimeMinutes)
+			}
+		})
+	}
+}
+
+func TestParseRecipeQueryNegations(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		dietary    string
+		exclusions []string
+		included   []string
+	}{
+		{"single no", "pasta with no onions", "none", []string{"onions"}, []string{"pasta"}},
+		{"repeated no", "pasta with no dairy and no nuts", "none", []string{"dairy", "nuts"}, []string{"pasta"}},
+		{"list after without", "soup without onions, garlic or leeks and with carrots", "none", []string{"onions", "garlic", "leeks"}, []string{"soup", "carrots"}},
+		{"except", "any vegetables except carrots", "none", []string{"carrots"}, []string{"vegetables"}},
+		{"contraction", "I don't want mushrooms in my vegetarian lasagna", "vegetarian", []string{"mushrooms"}, []string{"lasagna"}},
+		{"free compounds", "nut-free dairy-free cookies", "none", []string{"nut", "dairy"}, []string{"cookies"}},
+		{"negated diet", "gluten-free but not vegan pizza", "gluten-free", []string{}, []string{"pizza"}},
+		{"mixed diet and ingredient", "vegetarian lasagna, not vegan, without mushrooms", "vegetarian", []string{"mushrooms"}, []string{"lasagna"}},
+		{"excluded ingredient is not also requested", "onion soup without onion", "none", []string{"onion"}, []string{"soup"}},
+		{"clause ends before a requested amount", "pasta, no onions, and lots of garlic", "none", []string{"onions"}, []string{"pasta", "garlic"}},
+		{"clause ends before an extra item", "tacos with no beans and extra cheese", "none", []string{"beans"}, []string{"tacos", "cheese"}},
+		{"not too spicy", "not too spicy chicken curry", "none", []string{}, []string{"chicken", "curry"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseRecipeQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if parsed.DietaryRestrictions != tt.dietary {
+				t.Errorf("Expected dietary %q, got %q", tt.dietary, parsed.DietaryRestrictions)
+			}
+			if !reflect.DeepEqual(parsed.Exclusions, tt.exclusions) {
+				t.Errorf("Expected exclusions %v, got %v", tt.exclusions, parsed.Exclusions)
+			}
+			for _, ingredient := range tt.included {
+				if !contains(parsed.Ingredients, ingredient) {
+					t.Errorf("Expected %q in ingredients, got %v", ingredient, parsed.Ingredients)
+				}
+			}
+			for _, exclusion := range parsed.Exclusions {
+				if contains(parsed.Ingredients, exclusion) {
+					t.Errorf("Excluded %q also appears in ingredients %v", exclusion, parsed.Ingredients)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRecipeQueryNormalizesSynonyms(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		ingredients    []string
+		rawIngredients []string
+		exclusions     []string
+		rawExclusions  []string
+	}{
+		{"single word", "noodles with scallions", []string{"noodles", "green onions"}, []string{"noodles", "scallions"}, []string{}, []string{}},
+		{"two words", "salad with spring onions", []string{"salad", "green onions"}, []string{"salad", "spring onions"}, []string{}, []string{}},
+		{"exclusion", "curry without coriander", []string{"curry"}, []string{"curry"}, []string{"cilantro"}, []string{"coriander"}},
+		{"synonym of an exclusion is dropped", "aubergine stew with no eggplant", []string{"stew"}, []string{"stew"}, []string{"eggplant"}, []string{"eggplant"}},
+		{"unknown ingredients pass through", "soup with tomatoes and basil", []string{"soup", "tomatoes", "basil"}, []string{"soup", "tomatoes", "basil"}, []string{}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseRecipeQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Ingredients, tt.ingredients) || !reflect.DeepEqual(parsed.RawIngredients, tt.rawIngredients) {
+				t.Errorf("Expected ingredients %v (raw %v), got %v (raw %v)", tt.ingredients, tt.rawIngredients, parsed.Ingredients, parsed.RawIngredients)
+			}
+			if !reflect.DeepEqual(parsed.Exclusions, tt.exclusions) || !reflect.DeepEqual(parsed.RawExclusions, tt.rawExclusions) {
+				t.Errorf("Expected exclusions %v (raw %v), got %v (raw %v)", tt.exclusions, tt.rawExclusions, parsed.Exclusions, parsed.RawExclusions)
+			}
+		})
+	}
+}
+
+func TestNormalizeIngredient(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Courgettes": "zucchini",
+		"zucchini":   "zucchini",
+		"garbanzo":   "chickpeas",
+		"rocket":     "arugula",
+		"Saffron":    "saffron",
+	} {
+		if got := NormalizeIngredient(input); got != expected {
+			t.Errorf("NormalizeIngredient(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
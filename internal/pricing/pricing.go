@@ -0,0 +1,160 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/units"
+)
+
+// Price is the average cost of an ingredient per Per, which is "kg", "l" or "each".
+type Price struct {
+	Cost float64 `json:"cost"`
+	Per  string  `json:"per"`
+}
+
+// DefaultPrices is a rough table of average ingredient prices used when no table is configured.
+var DefaultPrices = map[string]Price{
+	"flour":          {Cost: 1.50, Per: "kg"},
+	"sugar":          {Cost: 2.00, Per: "kg"},
+	"brown sugar":    {Cost: 2.50, Per: "kg"},
+	"salt":           {Cost: 1.00, Per: "kg"},
+	"butter":         {Cost: 9.00, Per: "kg"},
+	"rice":           {Cost: 2.50, Per: "kg"},
+	"pasta":          {Cost: 3.00, Per: "kg"},
+	"chicken":        {Cost: 8.00, Per: "kg"},
+	"chicken breast": {Cost: 10.00, Per: "kg"},
+	"ground beef":    {Cost: 11.00, Per: "kg"},
+	"cheese":         {Cost: 12.00, Per: "kg"},
+	"potato":         {Cost: 1.80, Per: "kg"},
+	"tomato":         {Cost: 4.00, Per: "kg"},
+	"milk":           {Cost: 1.10, Per: "l"},
+	"cream":          {Cost: 5.00, Per: "l"},
+	"olive oil":      {Cost: 9.00, Per: "l"},
+	"vegetable oil":  {Cost: 3.50, Per: "l"},
+	"water":          {Cost: 0, Per: "l"},
+	"egg":            {Cost: 0.30, Per: "each"},
+	"onion":          {Cost: 0.50, Per: "each"},
+	"garlic":         {Cost: 0.10, Per: "each"},
+	"lemon":          {Cost: 0.60, Per: "each"},
+}
+
+// Estimator prices ingredient lists against a table of average prices.
+type Estimator struct {
+	prices map[string]Price
+}
+
+// NewEstimator creates an Estimator from a table keyed by ingredient name.
+func NewEstimator(prices map[string]Price) *Estimator {
+	normalized := make(map[string]Price, len(prices))
+	for name, price := range prices {
+		normalized[normalizeName(name)] = price
+	}
+	return &Estimator{prices: normalized}
+}
+
+// LoadPrices reads a JSON price table such as {"flour": {"cost": 1.5, "per": "kg"}} from path.
+func LoadPrices(path string) (map[string]Price, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table: %w", err)
+	}
+	var prices map[string]Price
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return nil, fmt.Errorf("failed to parse price table: %w", err)
+	}
+	for name, price := range prices {
+		if _, ok := perBase[price.Per]; !ok || price.Cost < 0 {
+			return nil, fmt.Errorf("invalid price for %q: cost must be non-negative and per one of kg, l or each", name)
+		}
+	}
+	return prices, nil
+}
+
+// perBase converts a price's Per unit into the base unit returned by units.Normalize.
+var perBase = map[string]struct {
+	base   string
+	factor float64
+}{
+	"kg":   {"g", 1000},
+	"l":    {"ml", 1000},
+	"each": {"each", 1},
+}
+
+// countUnits are units that describe a number of whole items.
+var countUnits = map[string]bool{
+	"": true, "each": true, "whole": true, "piece": true, "pieces": true,
+	"large": true, "medium": true, "small": true, "clove": true, "cloves": true,
+}
+
+// Estimate returns the total cost of ingredients, rounded to cents, and the names of
+// ingredients that could not be priced. Ingredients are skipped when they are missing from
+// the table, their amount cannot be parsed or their unit does not match the price (e.g. a
+// price per kg for an amount in cups).
+func (e *Estimator) Estimate(ingredients []models.Ingredient) (float64, []string, error) {
+	if len(e.prices) == 0 {
+		return 0, nil, fmt.Errorf("price table is empty")
+	}
+
+	var total float64
+	unpriced := []string{}
+	for _, ing := range ingredients {
+		cost, ok := e.cost(ing)
+		if !ok {
+			unpriced = append(unpriced, ing.Name)
+			continue
+		}
+		total += cost
+	}
+	return math.Round(total*100) / 100, unpriced, nil
+}
+
+func (e *Estimator) cost(ing models.Ingredient) (float64, bool) {
+	price, ok := e.lookup(ing.Name)
+	if !ok {
+		return 0, false
+	}
+	per := perBase[price.Per]
+
+	unit := strings.ToLower(strings.TrimSpace(ing.Unit))
+	if countUnits[unit] {
+		if per.base != "each" {
+			return 0, false
+		}
+		quantity, err := units.ParseAmount(ing.Amount)
+		if err != nil {
+			return 0, false
+		}
+		return quantity * price.Cost, true
+	}
+
+	quantity, base, ok := units.Normalize(ing.Amount, ing.Unit)
+	if !ok || base != per.base {
+		return 0, false
+	}
+	return quantity / per.factor * price.Cost, true
+}
+
+// lookup finds the price for an ingredient name, falling back to its singular form.
+func (e *Estimator) lookup(name string) (Price, bool) {
+	name = normalizeName(name)
+	if price, ok := e.prices[name]; ok {
+		return price, true
+	}
+	for _, suffix := range []string{"es", "s"} {
+		if singular, found := strings.CutSuffix(name, suffix); found {
+			if price, ok := e.prices[singular]; ok {
+				return price, true
+			}
+		}
+	}
+	return Price{}, false
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
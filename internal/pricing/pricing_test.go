@@ -0,0 +1,88 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+)
+
+func TestEstimateKnownIngredients(t *testing.T) {
+	e := NewEstimator(map[string]Price{
+		"Flour": {Cost: 2, Per: "kg"},
+		"milk":  {Cost: 1, Per: "l"},
+		"egg":   {Cost: 0.25, Per: "each"},
+	})
+
+	total, unpriced, err := e.Estimate([]models.Ingredient{
+		{Name: "flour", Amount: "500", Unit: "g"},
+		{Name: "Milk", Amount: "1/2", Unit: "l"},
+		{Name: "eggs", Amount: "2", Unit: ""},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected total 2, got %v", total)
+	}
+	if len(unpriced) != 0 {
+		t.Errorf("Expected all ingredients priced, got unpriced %v", unpriced)
+	}
+}
+
+func TestEstimateSkipsUnknownIngredients(t *testing.T) {
+	e := NewEstimator(map[string]Price{
+		"butter": {Cost: 10, Per: "kg"},
+		"sugar":  {Cost: 2, Per: "kg"},
+	})
+
+	total, unpriced, err := e.Estimate([]models.Ingredient{
+		{Name: "butter", Amount: "250", Unit: "g"},
+		{Name: "saffron", Amount: "1", Unit: "pinch"},
+		// Volume amounts cannot be priced per kg.
+		{Name: "sugar", Amount: "1", Unit: "cup"},
+		{Name: "butter", Amount: "a knob", Unit: "g"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total != 2.5 {
+		t.Errorf("Expected total 2.5, got %v", total)
+	}
+	want := []string{"saffron", "sugar", "butter"}
+	if len(unpriced) != len(want) {
+		t.Fatalf("Expected unpriced %v, got %v", want, unpriced)
+	}
+	for i := range want {
+		if unpriced[i] != want[i] {
+			t.Errorf("Expected unpriced %v, got %v", want, unpriced)
+		}
+	}
+}
+
+func TestEstimateEmptyTable(t *testing.T) {
+	if _, _, err := NewEstimator(nil).Estimate([]models.Ingredient{{Name: "flour", Amount: "1", Unit: "kg"}}); err == nil {
+		t.Error("Expected error for empty price table, got nil")
+	}
+}
+
+func TestLoadPrices(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "prices.json")
+	if err := os.WriteFile(valid, []byte(`{"flour": {"cost": 1.5, "per": "kg"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prices, err := LoadPrices(valid)
+	if err != nil || prices["flour"].Cost != 1.5 {
+		t.Errorf("Unexpected result loading prices: %v, %v", prices, err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"flour": {"cost": 1.5, "per": "bushel"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPrices(invalid); err == nil {
+		t.Error("Expected error for unsupported price unit, got nil")
+	}
+}
@@ -0,0 +1,113 @@
+// Package prompts holds the prompt templates sent to the recipe model. The wording lives in
+// embedded template files so it can be tuned without touching the Go code that fills it in.
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").ParseFS(templateFS, "templates/*.tmpl"))
+
+// GenerateData is the data passed to the generation template.
+type GenerateData struct {
+	Query string
+	// Constraints are explicit requirements extracted from the query, such as excluded
+	// ingredients. The section is left out when there are none.
+	Constraints []string
+	// Preferences are softer guidance from the query, such as the ingredients it mentions, which
+	// the model should follow where they suit the dish. The section is left out when there are none.
+	Preferences []string
+	// Instructions and ResponseFormat fall back to DefaultInstructions and DefaultResponseFormat when empty.
+	Instructions        string
+	ResponseFormat      string
+	LanguageInstruction string
+	// Profile holds user attributes such as allergens and dietary restrictions, listed in key order.
+	Profile map[string]interface{}
+}
+
+// modifyData is the data passed to the modification template.
+type modifyData struct {
+	Instructions string
+	Recipe       string
+}
+
+// nutritionData is the data passed to the nutrition template.
+type nutritionData struct {
+	Servings    int
+	Ingredients string
+}
+
+// tagsData is the data passed to the tag suggestion template.
+type tagsData struct {
+	Limit  int
+	Recipe string
+}
+
+// System returns the system message that accompanies every request to the model.
+func System() string {
+	return mustRender("system.tmpl")
+}
+
+// DefaultInstructions returns the prompt instructions used when a request does not supply its own.
+func DefaultInstructions() string {
+	return mustRender("instructions.tmpl")
+}
+
+// DefaultResponseFormat returns the JSON structure the model is asked to respond with when a
+// request does not supply its own.
+func DefaultResponseFormat() string {
+	return mustRender("response_format.tmpl")
+}
+
+// RenderGeneratePrompt builds the composite prompt used to generate a new recipe.
+func RenderGeneratePrompt(data GenerateData) (string, error) {
+	if data.Instructions == "" {
+		data.Instructions = DefaultInstructions()
+	}
+	if data.ResponseFormat == "" {
+		data.ResponseFormat = DefaultResponseFormat()
+	}
+	return render("generate.tmpl", data)
+}
+
+// RenderModifyPrompt builds the prompt asking the model to apply instructions to recipe, given
+// as JSON. Every modification, such as substitutions and expansions, shares this template.
+func RenderModifyPrompt(instructions string, recipe []byte) (string, error) {
+	return render("modify.tmpl", modifyData{Instructions: instructions, Recipe: string(recipe)})
+}
+
+// RenderNutritionPrompt builds the prompt asking the model for per-serving nutrition of the
+// ingredients, given as JSON. A servings count of zero or less is treated as one serving.
+func RenderNutritionPrompt(servings int, ingredients []byte) (string, error) {
+	return render("nutrition.tmpl", nutritionData{Servings: servings, Ingredients: string(ingredients)})
+}
+
+// RenderTagsPrompt builds the prompt asking the model for up to limit new tags for recipe,
+// given as JSON with its title, description, ingredient names and current tags.
+func RenderTagsPrompt(limit int, recipe []byte) (string, error) {
+	return render("tags.tmpl", tagsData{Limit: limit, Recipe: string(recipe)})
+}
+
+// render executes the named template, trimming the trailing newline of the template file.
+func render(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// mustRender is render for the fixed templates that take no data and so cannot fail at runtime.
+func mustRender(name string) string {
+	text, err := render(name, nil)
+	if err != nil {
+		panic(err)
+	}
+	return text
+}
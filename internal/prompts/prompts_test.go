@@ -0,0 +1,89 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderGeneratePrompt(t *testing.T) {
+	prompt, err := RenderGeneratePrompt(GenerateData{
+		Query:               "vegan chili",
+		LanguageInstruction: "Respond in Spanish.",
+		Profile:             map[string]interface{}{"diets": "vegan", "allergens": []string{"peanuts"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"User Query:\nvegan chili\n",
+		DefaultInstructions() + "\nRespond in Spanish.\n",
+		"Expected Response Format:\n" + DefaultResponseFormat() + "\n",
+		"User Profile:\n - allergens: [peanuts]\n - diets: vegan\n\n=== End of Prompt ===",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestRenderModifyPrompt(t *testing.T) {
+	prompt, err := RenderModifyPrompt("Replace the butter.", []byte(`{"title":"Pancakes"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(prompt, DefaultInstructions()+"\n\nReplace the butter.\n") {
+		t.Errorf("Unexpected prompt start:\n%s", prompt)
+	}
+	if !strings.HasSuffix(prompt, "Recipe:\n{\"title\":\"Pancakes\"}") {
+		t.Errorf("Unexpected prompt end:\n%s", prompt)
+	}
+}
+
+func TestDefaultTemplatesAreNotEmpty(t *testing.T) {
+	for name, text := range map[string]string{"system": System(), "instructions": DefaultInstructions(), "response format": DefaultResponseFormat()} {
+		if strings.TrimSpace(text) == "" || strings.HasSuffix(text, "\n") {
+			t.Errorf("Expected a trimmed, non-empty %s template, got %q", name, text)
+		}
+	}
+}
+
+func TestRenderNutritionPrompt(t *testing.T) {
+	prompt, err := RenderNutritionPrompt(4, []byte(`[{"name":"flour"}]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "The recipe makes 4 servings.") || !strings.HasSuffix(prompt, "Ingredients:\n[{\"name\":\"flour\"}]") {
+		t.Errorf("Unexpected prompt:\n%s", prompt)
+	}
+
+	prompt, _ = RenderNutritionPrompt(0, []byte(`[]`))
+	if !strings.Contains(prompt, "single serving") {
+		t.Errorf("Expected a single serving fallback, got:\n%s", prompt)
+	}
+}
+
+func TestRenderGeneratePromptConstraints(t *testing.T) {
+	prompt, err := RenderGeneratePrompt(GenerateData{Query: "salsa", Constraints: []string{"Do NOT use onions.", "The recipe must be strictly vegan."}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "User Query:\nsalsa\n\nConstraints (these must be followed exactly):\n - Do NOT use onions.\n - The recipe must be strictly vegan.\n\nPrompt Instructions:\n"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("Expected prompt to contain %q, got:\n%s", want, prompt)
+	}
+
+	prompt, err = RenderGeneratePrompt(GenerateData{Query: "salsa", Constraints: []string{"Do NOT use onions."}, Preferences: []string{"Feature these ingredients from the query: tomatoes."}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = " - Do NOT use onions.\n\nPreferences (follow these where they suit the dish):\n - Feature these ingredients from the query: tomatoes.\n\nPrompt Instructions:\n"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("Expected prompt to contain %q, got:\n%s", want, prompt)
+	}
+
+	prompt, _ = RenderGeneratePrompt(GenerateData{Query: "salsa"})
+	if strings.Contains(prompt, "Constraints") || strings.Contains(prompt, "Preferences") || !strings.Contains(prompt, "User Query:\nsalsa\n\nPrompt Instructions:\n") {
+		t.Errorf("Expected no constraints section, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// DefaultBulkConnReserve is the number of pooled connections kept free for live requests
+// when BULK_DB_CONN_RESERVE is not set.
+const DefaultBulkConnReserve = 10
+
+// BulkLimiter caps how many bulk-operation transactions run at once so that background
+// work such as reindexing cannot take every connection in the pool.
+type BulkLimiter struct {
+	slots chan struct{}
+}
+
+// NewBulkLimiter allows maxOpenConns minus reserve concurrent bulk transactions, and at
+// least one. A non-positive maxOpenConns means the pool is unbounded, so nothing is capped.
+func NewBulkLimiter(maxOpenConns, reserve int) *BulkLimiter {
+	if maxOpenConns <= 0 {
+		return &BulkLimiter{}
+	}
+	limit := maxOpenConns - reserve
+	if limit < 1 {
+		limit = 1
+	}
+	return &BulkLimiter{slots: make(chan struct{}, limit)}
+}
+
+// NewBulkLimiterForDB sizes a BulkLimiter from the pool's configured MaxOpenConns, keeping
+// BULK_DB_CONN_RESERVE connections (default DefaultBulkConnReserve) for request handlers.
+func NewBulkLimiterForDB(db *gorm.DB) *BulkLimiter {
+	reserve := DefaultBulkConnReserve
+	if n, err := strconv.Atoi(os.Getenv("BULK_DB_CONN_RESERVE")); err == nil && n >= 0 {
+		reserve = n
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return NewBulkLimiter(0, reserve)
+	}
+	return NewBulkLimiter(sqlDB.Stats().MaxOpenConnections, reserve)
+}
+
+// Limit returns the number of bulk operations allowed at once, or 0 when unbounded.
+func (l *BulkLimiter) Limit() int {
+	return cap(l.slots)
+}
+
+// Do runs fn once a slot is free, waiting until then or until ctx is done.
+func (l *BulkLimiter) Do(ctx context.Context, fn func() error) error {
+	if l == nil || l.slots == nil {
+		return fn()
+	}
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-l.slots }()
+	return fn()
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyFavorited is returned by AddFavorite when the user has already favorited the recipe.
+var ErrAlreadyFavorited = errors.New("recipe is already a favorite")
+
+// FavoriteRepository handles database operations for recipe favorites
+type FavoriteRepository interface {
+	GetFavoritedRecipeIDs(ctx context.Context, userID string, recipeIDs []string) ([]string, error)
+	AddFavorite(ctx context.Context, userID, recipeID string) error
+	RemoveFavorite(ctx context.Context, userID, recipeID string) error
+	ListFavorites(ctx context.Context, userID string, page, limit int) ([]models.Recipe, error)
+}
+
+type DefaultFavoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &DefaultFavoriteRepository{db: db}
+}
+
+// GetFavoritedRecipeIDs returns the subset of recipeIDs the user has favorited using a single query.
+func (r *DefaultFavoriteRepository) GetFavoritedRecipeIDs(ctx context.Context, userID string, recipeIDs []string) ([]string, error) {
+	var favorited []string
+	if len(recipeIDs) == 0 {
+		return favorited, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.RecipeFavorite{}).
+		Where("user_id = ? AND recipe_id IN ?", userID, recipeIDs).
+		Pluck("recipe_id", &favorited).Error
+	if err != nil {
+		return nil, err
+	}
+	return favorited, nil
+}
+
+// AddFavorite records that the user favorited the recipe. It returns gorm.ErrRecordNotFound when
+// the recipe does not exist and ErrAlreadyFavorited when it is already a favorite.
+func (r *DefaultFavoriteRepository) AddFavorite(ctx context.Context, userID, recipeID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var recipe models.Recipe
+		if err := tx.Select("id").First(&recipe, "id = ?", recipeID).Error; err != nil {
+			return err
+		}
+		// The (user_id, recipe_id) primary key rejects duplicates; a skipped insert means one exists.
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.RecipeFavorite{UserID: userID, RecipeID: recipeID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyFavorited
+		}
+		return nil
+	})
+}
+
+// RemoveFavorite deletes the user's favorite, returning gorm.ErrRecordNotFound when there is none.
+func (r *DefaultFavoriteRepository) RemoveFavorite(ctx context.Context, userID, recipeID string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND recipe_id = ?", userID, recipeID).
+		Delete(&models.RecipeFavorite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListFavorites returns a page of the user's favorite recipes, most recently favorited first,
+// without their embeddings.
+func (r *DefaultFavoriteRepository) ListFavorites(ctx context.Context, userID string, page, limit int) ([]models.Recipe, error) {
+	query := r.db.WithContext(ctx).
+		Omit("embedding").
+		Joins("JOIN recipe_favorites ON recipe_favorites.recipe_id = recipes.id").
+		Where("recipe_favorites.user_id = ?", userID).
+		Order("recipe_favorites.created_at DESC")
+	return findRecipePage(query, page, limit, "", "")
+}
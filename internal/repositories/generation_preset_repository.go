@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"gorm.io/gorm"
+)
+
+// GenerationPresetRepository handles database operations for recipe generation presets
+type GenerationPresetRepository interface {
+	CreatePreset(ctx context.Context, preset *models.GenerationPreset) error
+	GetPreset(ctx context.Context, id string) (*models.GenerationPreset, error)
+	ListPresets(ctx context.Context, userID string) ([]models.GenerationPreset, error)
+}
+
+type DefaultGenerationPresetRepository struct {
+	db *gorm.DB
+}
+
+func NewGenerationPresetRepository(db *gorm.DB) GenerationPresetRepository {
+	return &DefaultGenerationPresetRepository{db: db}
+}
+
+// CreatePreset inserts a new preset.
+func (r *DefaultGenerationPresetRepository) CreatePreset(ctx context.Context, preset *models.GenerationPreset) error {
+	return r.db.WithContext(ctx).Create(preset).Error
+}
+
+// GetPreset returns the preset with the given ID, or gorm.ErrRecordNotFound.
+func (r *DefaultGenerationPresetRepository) GetPreset(ctx context.Context, id string) (*models.GenerationPreset, error) {
+	var preset models.GenerationPreset
+	if err := r.db.WithContext(ctx).First(&preset, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// ListPresets returns the user's presets ordered by name.
+func (r *DefaultGenerationPresetRepository) ListPresets(ctx context.Context, userID string) ([]models.GenerationPreset, error) {
+	presets := []models.GenerationPreset{}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name").
+		Find(&presets).Error
+	if err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"gorm.io/gorm"
+)
+
+// RecipeAuditRepository stores the recipe audit log.
+type RecipeAuditRepository interface {
+	CreateEntry(ctx context.Context, entry *models.RecipeAuditEntry) error
+	// ListEntries returns up to limit entries for the recipe, newest first.
+	ListEntries(ctx context.Context, recipeID string, limit int) ([]models.RecipeAuditEntry, error)
+}
+
+type DefaultRecipeAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeAuditRepository(db *gorm.DB) RecipeAuditRepository {
+	return &DefaultRecipeAuditRepository{db: db}
+}
+
+func (r *DefaultRecipeAuditRepository) CreateEntry(ctx context.Context, entry *models.RecipeAuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *DefaultRecipeAuditRepository) ListEntries(ctx context.Context, recipeID string, limit int) ([]models.RecipeAuditEntry, error) {
+	entries := []models.RecipeAuditEntry{}
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Order("created_at DESC, id").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
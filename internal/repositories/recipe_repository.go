@@ -2,8 +2,8 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 
@@ -186,21 +187,64 @@ func DeleteRecipe(id string) error {
 type RecipeRepository interface {
 	GetRecipe(ctx context.Context, id string) (*models.Recipe, error)
 	SaveRecipe(ctx context.Context, recipe *models.Recipe) error
-	ListRecipes(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error)
+	// ListRecipes returns a page of recipes with cuisines, diets, appliances and tags preloaded.
+	// Each relation is loaded with one batched query for the whole page, not one per recipe.
+	ListRecipes(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error)
+	// ListRecipesByUser is ListRecipes restricted to recipes owned by userID. The embedding
+	// column is not loaded.
+	ListRecipesByUser(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error)
 	UpdateRecipe(ctx context.Context, recipe *models.Recipe) error
 	DeleteRecipe(ctx context.Context, id string) error
 	SearchRecipes(ctx context.Context, query string, tags []string, difficulty string) ([]models.Recipe, error)
-	RateRecipe(ctx context.Context, recipeID string, rating float64) error
-	GetRecipeRatings(ctx context.Context, recipeID string) ([]float64, error)
+	// FindRecipesByIngredients returns up to limit recipes whose ingredients mention any of the
+	// given terms, case-insensitively, those mentioning the most terms first, so the limit drops
+	// the weakest matches. Matches are candidates for the caller to rank; the embedding column
+	// is not loaded.
+	FindRecipesByIngredients(ctx context.Context, terms []string, limit int) ([]models.Recipe, error)
+	RateRecipe(ctx context.Context, recipeID, userID string, rating float64) error
+	GetRecipeRatings(ctx context.Context, recipeID string) (*models.RatingSummary, error)
 	ResolveRecipe(ctx context.Context, query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error)
+	// ListRecipesBatch returns up to limit recipes starting at offset in a stable order, for bulk jobs.
+	ListRecipesBatch(ctx context.Context, offset, limit int) ([]models.Recipe, error)
+	// UpdateRecipeEmbedding replaces only the stored embedding of a recipe.
+	UpdateRecipeEmbedding(ctx context.Context, id string, embedding models.Float64Slice) error
+	// ListStaleEmbeddings returns up to limit recipes, most recently updated first, that have no
+	// embedding or were updated after their embedding was last written. Only the id, title and
+	// timestamps are loaded.
+	ListStaleEmbeddings(ctx context.Context, limit int) ([]models.Recipe, error)
+	// WithTransaction runs fn with a repository whose operations share one transaction,
+	// which is rolled back if fn returns an error.
+	WithTransaction(ctx context.Context, fn func(repo RecipeRepository) error) error
+	// WithBulkTransaction is WithTransaction for background bulk work. It first waits for a
+	// slot from the repository's BulkLimiter so live requests keep free connections.
+	WithBulkTransaction(ctx context.Context, fn func(repo RecipeRepository) error) error
 }
 
 type DefaultRecipeRepository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	bulk *BulkLimiter
 }
 
 func NewRecipeRepository(db *gorm.DB) RecipeRepository {
-	return &DefaultRecipeRepository{db: db}
+	return NewRecipeRepositoryWithLimiter(db, NewBulkLimiterForDB(db))
+}
+
+// NewRecipeRepositoryWithLimiter creates a RecipeRepository whose bulk transactions are
+// capped by the given limiter.
+func NewRecipeRepositoryWithLimiter(db *gorm.DB, bulk *BulkLimiter) RecipeRepository {
+	return &DefaultRecipeRepository{db: db, bulk: bulk}
+}
+
+func (r *DefaultRecipeRepository) WithTransaction(ctx context.Context, fn func(repo RecipeRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&DefaultRecipeRepository{db: tx, bulk: r.bulk})
+	})
+}
+
+func (r *DefaultRecipeRepository) WithBulkTransaction(ctx context.Context, fn func(repo RecipeRepository) error) error {
+	return r.bulk.Do(ctx, func() error {
+		return r.WithTransaction(ctx, fn)
+	})
 }
 
 func (r *DefaultRecipeRepository) GetRecipe(ctx context.Context, id string) (*models.Recipe, error) {
@@ -252,6 +296,7 @@ func (r *DefaultRecipeRepository) SaveRecipe(ctx context.Context, recipe *models
 	if len(recipe.Steps) == 0 {
 		recipe.Steps = []byte("[]")
 	}
+	recipe.EmbeddingUpdatedAt = embeddedAt(recipe)
 
 	// Use transaction for database operations
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -266,9 +311,54 @@ func (r *DefaultRecipeRepository) SaveRecipe(ctx context.Context, recipe *models
 	return err
 }
 
-func (r *DefaultRecipeRepository) ListRecipes(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error) {
+func (r *DefaultRecipeRepository) ListRecipes(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error) {
+	query := r.db.WithContext(ctx)
+	if filter.Difficulty != "" {
+		query = query.Where("difficulty = ?", filter.Difficulty)
+	}
+	if len(filter.Tags) > 0 {
+		// Match through a subquery so recipes with several matching tags are not repeated.
+		tagged := r.db.Table("recipe_tags").
+			Select("recipe_tags.recipe_id").
+			Joins("JOIN tags ON tags.id = recipe_tags.tag_id").
+			Where("tags.name IN ?", filter.Tags).
+			Group("recipe_tags.recipe_id")
+		if !filter.MatchAnyTag {
+			tagged = tagged.Having("COUNT(DISTINCT tags.name) = ?", len(distinct(filter.Tags)))
+		}
+		query = query.Where("recipes.id IN (?)", tagged)
+	}
+	return findRecipePage(query, page, limit, sort, order)
+}
+
+// distinct returns values without duplicates, keeping the first occurrence of each.
+func distinct(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+func (r *DefaultRecipeRepository) ListRecipesByUser(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error) {
+	query := r.db.WithContext(ctx).Omit("embedding").Where("user_id = ?", userID)
+	return findRecipePage(query, page, limit, sort, order)
+}
+
+// recipeSortExpressions maps sort fields that are not plain columns to the SQL they order by.
+var recipeSortExpressions = map[string]string{
+	"total_time_minutes": "COALESCE(prep_time, 0) + COALESCE(cook_time, 0)",
+}
+
+// findRecipePage loads one page of the recipes matched by query, sorted by the sort column,
+// with cuisines, diets, appliances and tags preloaded.
+func findRecipePage(query *gorm.DB, page, limit int, sort, order string) ([]models.Recipe, error) {
 	var recipes []models.Recipe
-	query := r.db.WithContext(ctx).
+	query = query.
 		Preload("Cuisines").
 		Preload("Diets").
 		Preload("Appliances").
@@ -285,7 +375,12 @@ func (r *DefaultRecipeRepository) ListRecipes(ctx context.Context, page, limit i
 		if order != "asc" && order != "desc" {
 			order = "desc"
 		}
-		query = query.Order(fmt.Sprintf("%s %s", sort, order))
+		if expr, ok := recipeSortExpressions[sort]; ok {
+			query = query.Order(expr + " " + strings.ToUpper(order))
+		} else {
+			// Quote the column so an unvalidated sort value cannot inject SQL.
+			query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: sort}, Desc: order == "desc"})
+		}
 	}
 
 	if err := query.Find(&recipes).Error; err != nil {
@@ -295,6 +390,62 @@ func (r *DefaultRecipeRepository) ListRecipes(ctx context.Context, page, limit i
 	return recipes, nil
 }
 
+func (r *DefaultRecipeRepository) ListRecipesBatch(ctx context.Context, offset, limit int) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	err := r.db.WithContext(ctx).
+		Preload("Tags").
+		Order("created_at ASC").
+		Order("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&recipes).Error
+	if err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+func (r *DefaultRecipeRepository) UpdateRecipeEmbedding(ctx context.Context, id string, embedding models.Float64Slice) error {
+	var updatedAt *time.Time
+	if len(embedding) > 0 {
+		now := time.Now()
+		updatedAt = &now
+	}
+	result := r.db.WithContext(ctx).Model(&models.Recipe{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"embedding": embedding, "embedding_updated_at": updatedAt})
+	if result.Error != nil {
+		return errors.NewDatabaseError("failed to update recipe embedding").WithFields(zap.String("recipe_id", id))
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *DefaultRecipeRepository) ListStaleEmbeddings(ctx context.Context, limit int) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	err := r.db.WithContext(ctx).
+		Select("id", "title", "updated_at", "embedding_updated_at").
+		Where("embedding_updated_at IS NULL OR updated_at > embedding_updated_at").
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&recipes).Error
+	if err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// embeddedAt returns the time to record as the recipe's embedding time: its updated_at when it
+// carries an embedding, or nil when it has none.
+func embeddedAt(recipe *models.Recipe) *time.Time {
+	if len(recipe.Embedding) == 0 {
+		return nil
+	}
+	t := recipe.UpdatedAt
+	return &t
+}
+
 func (r *DefaultRecipeRepository) UpdateRecipe(ctx context.Context, recipe *models.Recipe) error {
 	if recipe == nil {
 		return errors.NewValidationError("recipe cannot be nil")
@@ -321,6 +472,24 @@ func (r *DefaultRecipeRepository) UpdateRecipe(ctx context.Context, recipe *mode
 			logger.WithError(err).Error("failed to update recipe in database")
 			return errors.NewDatabaseError("failed to update recipe").WithFields(zap.String("recipe_id", recipe.ID))
 		}
+		// Save only adds related entities, so replace them to drop any the recipe no longer lists.
+		for name, values := range map[string]interface{}{
+			"Cuisines":   recipe.Cuisines,
+			"Diets":      recipe.Diets,
+			"Appliances": recipe.Appliances,
+			"Tags":       recipe.Tags,
+		} {
+			if err := tx.Model(recipe).Association(name).Replace(values); err != nil {
+				logger.WithError(err).Error("failed to update recipe " + strings.ToLower(name))
+				return errors.NewDatabaseError("failed to update recipe").WithFields(zap.String("recipe_id", recipe.ID))
+			}
+		}
+		// Save sets updated_at itself, so the embedding time is written afterwards to match it exactly.
+		recipe.EmbeddingUpdatedAt = embeddedAt(recipe)
+		if err := tx.Model(recipe).UpdateColumn("embedding_updated_at", recipe.EmbeddingUpdatedAt).Error; err != nil {
+			logger.WithError(err).Error("failed to record recipe embedding time")
+			return errors.NewDatabaseError("failed to update recipe").WithFields(zap.String("recipe_id", recipe.ID))
+		}
 		logger.Info("updated recipe in database")
 		return nil
 	})
@@ -353,6 +522,15 @@ func (r *DefaultRecipeRepository) DeleteRecipe(ctx context.Context, id string) e
 	return err
 }
 
+// likeEscaper escapes LIKE wildcards so user input is matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsPattern builds a LIKE pattern matching values that contain term literally.
+// Callers must pair it with ESCAPE '\'.
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
+
 func (r *DefaultRecipeRepository) SearchRecipes(ctx context.Context, query string, tags []string, difficulty string) ([]models.Recipe, error) {
 	var recipes []models.Recipe
 	db := r.db.WithContext(ctx).
@@ -362,7 +540,8 @@ func (r *DefaultRecipeRepository) SearchRecipes(ctx context.Context, query strin
 		Preload("Tags")
 
 	if query != "" {
-		db = db.Where("title LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%")
+		pattern := containsPattern(query)
+		db = db.Where(`title LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\'`, pattern, pattern)
 	}
 
 	if len(tags) > 0 {
@@ -382,10 +561,40 @@ func (r *DefaultRecipeRepository) SearchRecipes(ctx context.Context, query strin
 	return recipes, nil
 }
 
-func (r *DefaultRecipeRepository) RateRecipe(ctx context.Context, recipeID string, rating float64) error {
+func (r *DefaultRecipeRepository) FindRecipesByIngredients(ctx context.Context, terms []string, limit int) ([]models.Recipe, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	// The expression matches the trigram index on the ingredients text so PostgreSQL can use it.
+	conditions := make([]string, len(terms))
+	counts := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		conditions[i] = `LOWER(CAST(ingredients AS TEXT)) LIKE ? ESCAPE '\'`
+		counts[i] = "CASE WHEN " + conditions[i] + " THEN 1 ELSE 0 END"
+		args[i] = containsPattern(strings.ToLower(term))
+	}
+	query := r.db.WithContext(ctx).
+		Omit("embedding").
+		Where(strings.Join(conditions, " OR "), args...).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(" + strings.Join(counts, " + ") + ") DESC, id ASC",
+			Vars:               args,
+			WithoutParentheses: true,
+		}})
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	return findRecipePage(query, 0, 0, "", "")
+}
+
+// RateRecipe records the user's rating of a recipe, replacing any earlier rating by the same
+// user, and refreshes the recipe's average and count from the stored ratings.
+func (r *DefaultRecipeRepository) RateRecipe(ctx context.Context, recipeID, userID string, rating float64) error {
 	logger := logrus.WithFields(logrus.Fields{
 		"operation": "RateRecipe",
 		"recipe_id": recipeID,
+		"user_id":   userID,
 		"rating":    rating,
 	})
 	logger.Info("rating recipe")
@@ -394,11 +603,15 @@ func (r *DefaultRecipeRepository) RateRecipe(ctx context.Context, recipeID strin
 		logger.Error("recipe ID is required")
 		return errors.NewValidationError("recipe ID is required")
 	}
+	if userID == "" {
+		logger.Error("user ID is required")
+		return errors.NewValidationError("user ID is required")
+	}
 
 	// Use transaction for database operations
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var recipe models.Recipe
-		if err := tx.First(&recipe, "id = ?", recipeID).Error; err != nil {
+		if err := tx.Select("id").First(&recipe, "id = ?", recipeID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				logger.Error("recipe not found")
 				return errors.NewNotFoundError("recipe not found").WithFields(zap.String("recipe_id", recipeID))
@@ -407,11 +620,28 @@ func (r *DefaultRecipeRepository) RateRecipe(ctx context.Context, recipeID strin
 			return errors.NewDatabaseError("failed to retrieve recipe").WithFields(zap.String("recipe_id", recipeID))
 		}
 
-		// Update rating
-		recipe.AverageRating = ((recipe.AverageRating * float64(recipe.RatingCount)) + rating) / float64(recipe.RatingCount+1)
-		recipe.RatingCount++
+		// One row per user and recipe, so rating again replaces the earlier rating.
+		row := models.RecipeRating{UserID: userID, RecipeID: recipeID, Rating: rating}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "recipe_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "updated_at"}),
+		}).Create(&row).Error; err != nil {
+			logger.WithError(err).Error("failed to save recipe rating in database")
+			return errors.NewDatabaseError("failed to save recipe rating").WithFields(zap.String("recipe_id", recipeID))
+		}
 
-		if err := tx.Save(&recipe).Error; err != nil {
+		// Recompute from the rows rather than adjusting the stored average, which drifts.
+		summary, err := ratingSummary(tx, recipeID)
+		if err != nil {
+			logger.WithError(err).Error("failed to aggregate recipe ratings")
+			return errors.NewDatabaseError("failed to aggregate recipe ratings").WithFields(zap.String("recipe_id", recipeID))
+		}
+		// UpdateColumns leaves updated_at alone: a rating does not change the content the embedding
+		// was computed from, so it must not make the recipe look stale to ListStaleEmbeddings.
+		if err := tx.Model(&models.Recipe{}).Where("id = ?", recipeID).UpdateColumns(map[string]interface{}{
+			"average_rating": summary.Average,
+			"rating_count":   summary.Count,
+		}).Error; err != nil {
 			logger.WithError(err).Error("failed to update recipe rating in database")
 			return errors.NewDatabaseError("failed to update recipe rating").WithFields(zap.String("recipe_id", recipeID))
 		}
@@ -423,7 +653,8 @@ func (r *DefaultRecipeRepository) RateRecipe(ctx context.Context, recipeID strin
 	return err
 }
 
-func (r *DefaultRecipeRepository) GetRecipeRatings(ctx context.Context, recipeID string) ([]float64, error) {
+// GetRecipeRatings returns the number of ratings and their average for a recipe.
+func (r *DefaultRecipeRepository) GetRecipeRatings(ctx context.Context, recipeID string) (*models.RatingSummary, error) {
 	logger := logrus.WithFields(logrus.Fields{
 		"operation": "GetRecipeRatings",
 		"recipe_id": recipeID,
@@ -435,8 +666,9 @@ func (r *DefaultRecipeRepository) GetRecipeRatings(ctx context.Context, recipeID
 		return nil, errors.NewValidationError("recipe ID is required")
 	}
 
+	db := r.db.WithContext(ctx)
 	var recipe models.Recipe
-	if err := r.db.WithContext(ctx).First(&recipe, "id = ?", recipeID).Error; err != nil {
+	if err := db.Select("id").First(&recipe, "id = ?", recipeID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			logger.Error("recipe not found")
 			return nil, errors.NewNotFoundError("recipe not found").WithFields(zap.String("recipe_id", recipeID))
@@ -445,13 +677,25 @@ func (r *DefaultRecipeRepository) GetRecipeRatings(ctx context.Context, recipeID
 		return nil, errors.NewDatabaseError("failed to retrieve recipe").WithFields(zap.String("recipe_id", recipeID))
 	}
 
-	// For now, we'll just return a slice with the average rating repeated RatingCount times
-	ratings := make([]float64, recipe.RatingCount)
-	for i := range ratings {
-		ratings[i] = recipe.AverageRating
+	summary, err := ratingSummary(db, recipeID)
+	if err != nil {
+		logger.WithError(err).Error("failed to aggregate recipe ratings")
+		return nil, errors.NewDatabaseError("failed to aggregate recipe ratings").WithFields(zap.String("recipe_id", recipeID))
 	}
+	return summary, nil
+}
 
-	return ratings, nil
+// ratingSummary counts and averages the stored ratings of a recipe.
+func ratingSummary(db *gorm.DB, recipeID string) (*models.RatingSummary, error) {
+	var summary models.RatingSummary
+	err := db.Model(&models.RecipeRating{}).
+		Select("COUNT(*) AS count, COALESCE(AVG(rating), 0) AS average").
+		Where("recipe_id = ?", recipeID).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
 }
 
 func (r *DefaultRecipeRepository) ResolveRecipe(ctx context.Context, query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error) {
@@ -480,8 +724,9 @@ func (r *DefaultRecipeRepository) ResolveRecipe(ctx context.Context, query strin
 
 	// If no exact match, find similar recipes
 	var similarRecipes []*models.Recipe
-	if err := db.Where("title LIKE ?", "%"+query+"%").
-		Or("description LIKE ?", "%"+query+"%").
+	pattern := containsPattern(query)
+	if err := db.Where(`title LIKE ? ESCAPE '\'`, pattern).
+		Or(`description LIKE ? ESCAPE '\'`, pattern).
 		Find(&similarRecipes).Error; err != nil {
 		logger.WithError(err).Error("failed to search for similar recipes")
 		return nil, nil, errors.NewDatabaseError("failed to search for similar recipes").WithFields(zap.String("query", query))
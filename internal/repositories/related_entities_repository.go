@@ -2,10 +2,13 @@ package repositories
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CuisineRepository handles database operations for cuisines
@@ -15,6 +18,8 @@ type CuisineRepository interface {
 	Create(ctx context.Context, cuisine *models.Cuisine) error
 	List(ctx context.Context) ([]*models.Cuisine, error)
 	Delete(ctx context.Context, id string) error
+	// GetOrCreateBatch returns the cuisines with the given names, in order, creating missing ones.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Cuisine, error)
 }
 
 type DefaultCuisineRepository struct {
@@ -35,7 +40,7 @@ func (r *DefaultCuisineRepository) GetByID(ctx context.Context, id string) (*mod
 
 func (r *DefaultCuisineRepository) GetByName(ctx context.Context, name string) (*models.Cuisine, error) {
 	var cuisine models.Cuisine
-	if err := r.db.WithContext(ctx).First(&cuisine, "name = ?", name).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&cuisine, nameMatches, name).Error; err != nil {
 		return nil, err
 	}
 	return &cuisine, nil
@@ -45,7 +50,7 @@ func (r *DefaultCuisineRepository) Create(ctx context.Context, cuisine *models.C
 	if cuisine.ID == "" {
 		cuisine.ID = uuid.New().String()
 	}
-	return r.db.WithContext(ctx).Create(cuisine).Error
+	return createOrLoad(ctx, r.db, cuisine, cuisine.Name)
 }
 
 func (r *DefaultCuisineRepository) List(ctx context.Context) ([]*models.Cuisine, error) {
@@ -60,6 +65,12 @@ func (r *DefaultCuisineRepository) Delete(ctx context.Context, id string) error
 	return r.db.WithContext(ctx).Delete(&models.Cuisine{}, "id = ?", id).Error
 }
 
+func (r *DefaultCuisineRepository) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Cuisine, error) {
+	return getOrCreateByName(ctx, r.db, names,
+		func(name string) models.Cuisine { return models.Cuisine{ID: uuid.New().String(), Name: name} },
+		func(cuisine *models.Cuisine) string { return cuisine.Name })
+}
+
 // DietRepository handles database operations for diets
 type DietRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Diet, error)
@@ -67,6 +78,8 @@ type DietRepository interface {
 	Create(ctx context.Context, diet *models.Diet) error
 	List(ctx context.Context) ([]*models.Diet, error)
 	Delete(ctx context.Context, id string) error
+	// GetOrCreateBatch returns the diets with the given names, in order, creating missing ones.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Diet, error)
 }
 
 type DefaultDietRepository struct {
@@ -87,7 +100,7 @@ func (r *DefaultDietRepository) GetByID(ctx context.Context, id string) (*models
 
 func (r *DefaultDietRepository) GetByName(ctx context.Context, name string) (*models.Diet, error) {
 	var diet models.Diet
-	if err := r.db.WithContext(ctx).First(&diet, "name = ?", name).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&diet, nameMatches, name).Error; err != nil {
 		return nil, err
 	}
 	return &diet, nil
@@ -97,7 +110,7 @@ func (r *DefaultDietRepository) Create(ctx context.Context, diet *models.Diet) e
 	if diet.ID == "" {
 		diet.ID = uuid.New().String()
 	}
-	return r.db.WithContext(ctx).Create(diet).Error
+	return createOrLoad(ctx, r.db, diet, diet.Name)
 }
 
 func (r *DefaultDietRepository) List(ctx context.Context) ([]*models.Diet, error) {
@@ -112,6 +125,12 @@ func (r *DefaultDietRepository) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&models.Diet{}, "id = ?", id).Error
 }
 
+func (r *DefaultDietRepository) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Diet, error) {
+	return getOrCreateByName(ctx, r.db, names,
+		func(name string) models.Diet { return models.Diet{ID: uuid.New().String(), Name: name} },
+		func(diet *models.Diet) string { return diet.Name })
+}
+
 // ApplianceRepository handles database operations for appliances
 type ApplianceRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Appliance, error)
@@ -119,6 +138,8 @@ type ApplianceRepository interface {
 	Create(ctx context.Context, appliance *models.Appliance) error
 	List(ctx context.Context) ([]*models.Appliance, error)
 	Delete(ctx context.Context, id string) error
+	// GetOrCreateBatch returns the appliances with the given names, in order, creating missing ones.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Appliance, error)
 }
 
 type DefaultApplianceRepository struct {
@@ -139,7 +160,7 @@ func (r *DefaultApplianceRepository) GetByID(ctx context.Context, id string) (*m
 
 func (r *DefaultApplianceRepository) GetByName(ctx context.Context, name string) (*models.Appliance, error) {
 	var appliance models.Appliance
-	if err := r.db.WithContext(ctx).First(&appliance, "name = ?", name).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&appliance, nameMatches, name).Error; err != nil {
 		return nil, err
 	}
 	return &appliance, nil
@@ -149,7 +170,7 @@ func (r *DefaultApplianceRepository) Create(ctx context.Context, appliance *mode
 	if appliance.ID == "" {
 		appliance.ID = uuid.New().String()
 	}
-	return r.db.WithContext(ctx).Create(appliance).Error
+	return createOrLoad(ctx, r.db, appliance, appliance.Name)
 }
 
 func (r *DefaultApplianceRepository) List(ctx context.Context) ([]*models.Appliance, error) {
@@ -164,6 +185,12 @@ func (r *DefaultApplianceRepository) Delete(ctx context.Context, id string) erro
 	return r.db.WithContext(ctx).Delete(&models.Appliance{}, "id = ?", id).Error
 }
 
+func (r *DefaultApplianceRepository) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Appliance, error) {
+	return getOrCreateByName(ctx, r.db, names,
+		func(name string) models.Appliance { return models.Appliance{ID: uuid.New().String(), Name: name} },
+		func(appliance *models.Appliance) string { return appliance.Name })
+}
+
 // TagRepository handles database operations for tags
 type TagRepository interface {
 	GetByID(ctx context.Context, id string) (*models.Tag, error)
@@ -171,6 +198,19 @@ type TagRepository interface {
 	Create(ctx context.Context, tag *models.Tag) error
 	List(ctx context.Context) ([]*models.Tag, error)
 	Delete(ctx context.Context, id string) error
+	// GetOrCreateBatch returns the tags with the given names, in order, creating missing ones.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Tag, error)
+	// Merge moves the recipes of the source tags to the target tag and deletes the sources.
+	Merge(ctx context.Context, targetID string, sourceIDs []string) (*TagMergeResult, error)
+	// DeleteUnused deletes the tags no recipe uses and returns how many were deleted.
+	DeleteUnused(ctx context.Context) (int64, error)
+}
+
+// TagMergeResult counts the recipes that were moved to the target tag of a merge and the source
+// tags that were deleted.
+type TagMergeResult struct {
+	RecipesAffected int64
+	TagsDeleted     int64
 }
 
 type DefaultTagRepository struct {
@@ -191,7 +231,7 @@ func (r *DefaultTagRepository) GetByID(ctx context.Context, id string) (*models.
 
 func (r *DefaultTagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
 	var tag models.Tag
-	if err := r.db.WithContext(ctx).First(&tag, "name = ?", name).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&tag, nameMatches, name).Error; err != nil {
 		return nil, err
 	}
 	return &tag, nil
@@ -201,7 +241,7 @@ func (r *DefaultTagRepository) Create(ctx context.Context, tag *models.Tag) erro
 	if tag.ID == "" {
 		tag.ID = uuid.New().String()
 	}
-	return r.db.WithContext(ctx).Create(tag).Error
+	return createOrLoad(ctx, r.db, tag, tag.Name)
 }
 
 func (r *DefaultTagRepository) List(ctx context.Context) ([]*models.Tag, error) {
@@ -215,3 +255,143 @@ func (r *DefaultTagRepository) List(ctx context.Context) ([]*models.Tag, error)
 func (r *DefaultTagRepository) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&models.Tag{}, "id = ?", id).Error
 }
+
+func (r *DefaultTagRepository) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Tag, error) {
+	return getOrCreateByName(ctx, r.db, names,
+		func(name string) models.Tag { return models.Tag{ID: uuid.New().String(), Name: name} },
+		func(tag *models.Tag) string { return tag.Name })
+}
+
+// Merge runs in one transaction and fails with gorm.ErrRecordNotFound when the target tag does
+// not exist. Recipes that already have the target tag keep a single association with it. The
+// moved recipes get a new updated_at so their embeddings, which include the tags, count as stale.
+func (r *DefaultTagRepository) Merge(ctx context.Context, targetID string, sourceIDs []string) (*TagMergeResult, error) {
+	result := &TagMergeResult{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var target models.Tag
+		if err := tx.First(&target, "id = ?", targetID).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("recipe_tags").Where("tag_id IN ?", sourceIDs).
+			Distinct("recipe_id").Count(&result.RecipesAffected).Error; err != nil {
+			return err
+		}
+		affected := tx.Table("recipe_tags").Select("recipe_id").Where("tag_id IN ?", sourceIDs)
+		if err := tx.Model(&models.Recipe{}).Where("id IN (?)", affected).
+			UpdateColumn("updated_at", time.Now()).Error; err != nil {
+			return err
+		}
+		// The (recipe_id, tag_id) primary key skips recipes that already have the target tag.
+		if err := tx.Exec(`INSERT INTO recipe_tags (recipe_id, tag_id)
+			SELECT DISTINCT recipe_id, ? FROM recipe_tags WHERE tag_id IN ?
+			ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM recipe_tags WHERE tag_id IN ?", sourceIDs).Error; err != nil {
+			return err
+		}
+		deleted := tx.Delete(&models.Tag{}, "id IN ?", sourceIDs)
+		result.TagsDeleted = deleted.RowsAffected
+		return deleted.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *DefaultTagRepository) DeleteUnused(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("NOT EXISTS (SELECT 1 FROM recipe_tags WHERE recipe_tags.tag_id = tags.id)").
+		Delete(&models.Tag{})
+	return result.RowsAffected, result.Error
+}
+
+// nameMatches compares names the way the unique indexes on the name columns do: ignoring case
+// and surrounding whitespace.
+const nameMatches = "LOWER(TRIM(name)) = LOWER(TRIM(?))"
+
+// createOrLoad inserts row unless one with the same name already exists, in which case row is
+// replaced by the existing one.
+func createOrLoad[T any](ctx context.Context, db *gorm.DB, row *T, name string) error {
+	result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	var existing T
+	if err := db.WithContext(ctx).First(&existing, nameMatches, name).Error; err != nil {
+		return err
+	}
+	*row = existing
+	return nil
+}
+
+// getOrCreateByName loads the rows named names and inserts the missing ones, all in one
+// transaction. Inserts skip names that already exist, so concurrent callers creating the same
+// name end up sharing the row that was inserted first instead of failing on the unique name.
+// Rows are returned in the order of names; names that differ only in case or surrounding
+// whitespace share one row, returned once.
+func getOrCreateByName[T any](ctx context.Context, db *gorm.DB, names []string, newRow func(name string) T, nameOf func(*T) string) ([]*T, error) {
+	unique := make([]string, 0, len(names))
+	keys := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := nameKey(name)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, name)
+			keys = append(keys, key)
+		}
+	}
+	if len(unique) == 0 {
+		return []*T{}, nil
+	}
+
+	byKey := make(map[string]*T, len(unique))
+	load := func(tx *gorm.DB) error {
+		var rows []*T
+		if err := tx.Where("LOWER(TRIM(name)) IN ?", keys).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			byKey[nameKey(nameOf(row))] = row
+		}
+		return nil
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := load(tx); err != nil {
+			return err
+		}
+
+		var missing []T
+		for i, name := range unique {
+			if byKey[keys[i]] == nil {
+				missing = append(missing, newRow(name))
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+			return err
+		}
+		// Reload rather than trust missing: a skipped insert keeps the other caller's row.
+		return load(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*T, 0, len(unique))
+	for _, key := range keys {
+		if row := byKey[key]; row != nil {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// nameKey is the form of name the unique indexes on the name columns compare.
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -39,7 +39,11 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, user *models.User) error
 	DeleteUser(ctx context.Context, id string) error
 	GetUserByResetPasswordToken(ctx context.Context, token string) (*models.User, error)
+	GetUserByEmailVerificationToken(ctx context.Context, token string) (*models.User, error)
 	GetAllUsers(ctx context.Context) ([]*models.User, error)
+	// ListUsers returns a page of users ordered by creation time, optionally restricted to those
+	// whose name or email contains query case-insensitively, with the total number matching.
+	ListUsers(ctx context.Context, page, limit int, query string) ([]*models.User, int64, error)
 	FindByEmail(email string) (*models.User, error)
 }
 
@@ -109,6 +113,17 @@ func (r *DefaultUserRepository) GetUserByResetPasswordToken(ctx context.Context,
 	return &user, nil
 }
 
+func (r *DefaultUserRepository) GetUserByEmailVerificationToken(ctx context.Context, token string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email_verification_token = ?", token).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *DefaultUserRepository) GetAllUsers(ctx context.Context) ([]*models.User, error) {
 	var users []*models.User
 	if err := r.db.WithContext(ctx).Find(&users).Error; err != nil {
@@ -117,6 +132,29 @@ func (r *DefaultUserRepository) GetAllUsers(ctx context.Context) ([]*models.User
 	return users, nil
 }
 
+func (r *DefaultUserRepository) ListUsers(ctx context.Context, page, limit int, query string) ([]*models.User, int64, error) {
+	db := r.db.WithContext(ctx).Model(&models.User{})
+	if query != "" {
+		// LOWER on both sides keeps the match case-insensitive on PostgreSQL and SQLite alike.
+		pattern := containsPattern(strings.ToLower(query))
+		db = db.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*models.User
+	if err := db.Order("created_at ASC").Order("id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 func (r *DefaultUserRepository) FindByEmail(email string) (*models.User, error) {
 	// Validate email format
 	if !isValidEmail(email) {
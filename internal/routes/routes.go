@@ -2,20 +2,38 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/db"
+	"github.com/pageza/alchemorsel-v1/internal/email"
 	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
 	"github.com/pageza/alchemorsel-v1/internal/logging"
 	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/monitoring"
+	"github.com/pageza/alchemorsel-v1/internal/pricing"
 	"github.com/pageza/alchemorsel-v1/internal/repositories"
 	"github.com/pageza/alchemorsel-v1/internal/services"
+	"github.com/pageza/alchemorsel-v1/internal/storage"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-// SetupRouter initializes and returns the Gin router with all routes configured
+// SetupRouter initializes and returns the Gin router with all routes configured. It connects to
+// Redis itself and continues without it when Redis is not configured or unreachable.
 func SetupRouter(db *gorm.DB, logger *logging.Logger) *gin.Engine {
+	return SetupRouterWithRedis(db, newRedisClient(logger), logger)
+}
+
+// SetupRouterWithRedis is SetupRouter with a Redis client created by the caller, which may be nil
+// to run without Redis-backed features.
+func SetupRouterWithRedis(db *gorm.DB, redisClient *redis.Client, logger *logging.Logger) *gin.Engine {
 	logger.Info("Starting router setup...")
 
 	// For integration tests, ensure we use the Postgres test database.
@@ -59,6 +77,11 @@ func SetupRouter(db *gorm.DB, logger *logging.Logger) *gin.Engine {
 	router := gin.Default()
 	// Disable trailing slash redirection to prevent 301 redirects on endpoints.
 	router.RedirectTrailingSlash = false
+	// Only honour X-Forwarded-For from explicitly trusted proxies so ClientIP() reflects the real client.
+	if err := ConfigureTrustedProxies(router, config.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))); err != nil {
+		logger.Error("Invalid TRUSTED_PROXIES, trusting no proxies", zap.Error(err))
+		_ = ConfigureTrustedProxies(router, nil)
+	}
 	router.Use(gin.Recovery())
 	router.Use(middleware.ErrorHandler(logger.Logger))
 	router.Use(gin.Logger())
@@ -66,10 +89,22 @@ func SetupRouter(db *gorm.DB, logger *logging.Logger) *gin.Engine {
 
 	// Always add security headers unless explicitly disabled.
 	if os.Getenv("DISABLE_SECURITY_HEADERS") != "true" {
-		router.Use(middleware.SecurityHeaders())
+		router.Use(middleware.SecurityHeadersWithConfig(middleware.LoadSecurityHeadersConfig()))
+	}
+
+	// Write endpoints only accept JSON bodies unless the check is explicitly disabled.
+	// Multipart upload routes must be passed to RequireJSON as exempt.
+	if os.Getenv("DISABLE_CONTENT_TYPE_CHECK") != "true" {
+		router.Use(middleware.RequireJSON("/v1/recipes/:id/image"))
 	}
 
 	logger.Info("Setting up routes...")
+	// Prometheus metrics, including model token usage and estimated cost per model and the
+	// state of the DeepSeek circuit breaker.
+	integrations.SetUsageRecorder(monitoring.DefaultAIUsage)
+	aiConfig := config.LoadAIConfig()
+	integrations.SetDeepSeekBreaker(integrations.NewCircuitBreaker(aiConfig.DeepSeekBreakerThreshold, aiConfig.DeepSeekBreakerCooldown))
+
 	// Grouping versioned API routes
 	v1 := router.Group("/v1")
 	{
@@ -80,22 +115,53 @@ func SetupRouter(db *gorm.DB, logger *logging.Logger) *gin.Engine {
 		dietRepo := repositories.NewDietRepository(db)
 		applianceRepo := repositories.NewApplianceRepository(db)
 		tagRepo := repositories.NewTagRepository(db)
+		favoriteRepo := repositories.NewFavoriteRepository(db)
+		presetRepo := repositories.NewGenerationPresetRepository(db)
+		recipeAuditRepo := repositories.NewRecipeAuditRepository(db)
 
 		// Initialize services
 		userService := services.NewUserService(userRepo)
+		emailConfig := config.LoadEmailConfig()
+		userService.Mailer = email.New(emailConfig)
+		userService.PasswordResetURL = emailConfig.PasswordResetURL
+		userService.EmailVerificationURL = emailConfig.EmailVerificationURL
 		cuisineService := services.NewCuisineService(cuisineRepo)
 		dietService := services.NewDietService(dietRepo)
 		applianceService := services.NewApplianceService(applianceRepo)
 		tagService := services.NewTagService(tagRepo)
 		recipeService := services.NewRecipeService(recipeRepo, cuisineService, dietService, applianceService, tagService)
+		searchHistoryService := services.NewSearchHistoryService(redisClient, services.DefaultSearchHistoryLimit)
+		favoriteService := services.NewFavoriteService(favoriteRepo)
+		presetService := services.NewGenerationPresetService(presetRepo)
+		recipeAuditService := services.NewRecipeAuditService(recipeAuditRepo)
 
 		// Initialize handlers
 		userHandler := handlers.NewUserHandler(userService)
+		userHandler.RefreshTokens = services.NewRefreshTokenStore(redisClient)
+		userHandler.Denylist = services.NewTokenDenylist(redisClient)
+		middleware.UseTokenDenylist(userHandler.Denylist)
 		recipeHandler := handlers.NewRecipeHandler(recipeService)
+		recipeHandler.History = searchHistoryService
+		recipeHandler.Users = userService
+		recipeHandler.Pricing = newPriceEstimator(logger)
+		recipeHandler.Audit = recipeAuditService
+		recipeHandler.Images = newImageStorage(router, logger)
+		searchHistoryHandler := handlers.NewSearchHistoryHandler(searchHistoryService)
+		favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+		presetHandler := handlers.NewGenerationPresetHandler(presetService)
+		tagHandler := handlers.NewTagHandler(tagService)
 		recipeResolutionHandler := handlers.NewRecipeResolutionHandler(recipeService)
 		// New multi-step resolution service and handler
-		recipeResolutionService := services.NewRecipeResolutionService()
+		moderator := services.NewModerator(config.LoadModerationConfig())
+		recipeResolutionService := services.NewRecipeResolutionServiceWithModerator(moderator)
 		recipeMultistepHandler := handlers.NewRecipeMultistepResolutionHandler(recipeResolutionService)
+		recipeMultistepHandler.Presets = presetService
+		recipeMultistepHandler.Moderator = moderator
+		recipeModificationHandler := handlers.NewRecipeModificationHandler(recipeService, recipeResolutionService)
+		recipeModificationHandler.Audit = recipeAuditService
+		recipeBatchHandler := handlers.NewRecipeBatchHandler(recipeService, recipeResolutionService)
+		recipeBatchHandler.Concurrency = aiConfig.BatchConcurrency
+		recipeBatchHandler.Deadline = aiConfig.BatchTimeout
 
 		// Only add the rate limiter if DISABLE_RATE_LIMITER is not set to "true".
 		if os.Getenv("DISABLE_RATE_LIMITER") != "true" {
@@ -108,40 +174,170 @@ func SetupRouter(db *gorm.DB, logger *logging.Logger) *gin.Engine {
 			}
 		}
 
+		timeouts := middleware.LoadTimeoutConfig()
+		// Admin routes load the current user after authentication and reject non-admins.
+		requireAdmin := middleware.RequireAdmin(userService)
+
+		// Metrics are for operators: scrapers send METRICS_SCRAPE_TOKEN, otherwise only admins get in.
+		if scrapeToken := os.Getenv("METRICS_SCRAPE_TOKEN"); scrapeToken != "" {
+			router.GET("/metrics", middleware.RequireScrapeToken(scrapeToken), gin.WrapH(promhttp.Handler()))
+		} else {
+			router.GET("/metrics", middleware.AuthMiddleware(), requireAdmin, gin.WrapH(promhttp.Handler()))
+		}
+
 		// Public user endpoints for registration, login and account management
-		v1.POST("/users", middleware.RateLimiter(), userHandler.CreateUser)
-		v1.POST("/users/login", middleware.LoginRateLimiter(), userHandler.LoginUser)
-		v1.GET("/users/verify-email/:token", userHandler.VerifyEmail)
-		v1.POST("/users/forgot-password", userHandler.ForgotPassword)
-		v1.POST("/users/reset-password", userHandler.ResetPassword)
-		v1.GET("/users/:id", userHandler.GetUser)
+		public := v1.Group("")
+		public.Use(middleware.Timeout(timeouts.Default))
+		{
+			public.POST("/users", middleware.RateLimiter(), userHandler.CreateUser)
+			public.POST("/users/login", middleware.LoginRateLimiter(), userHandler.LoginUser)
+			public.POST("/users/refresh", middleware.RateLimiter(), userHandler.RefreshToken)
+			public.GET("/users/verify-email/:token", userHandler.VerifyEmail)
+			public.POST("/users/forgot-password", userHandler.ForgotPassword)
+			public.POST("/users/reset-password", userHandler.ResetPassword)
+			public.GET("/users/:id", userHandler.GetUser)
+		}
 
 		// Group for endpoints that require authentication.
 		secured := v1.Group("")
 		secured.Use(middleware.AuthMiddleware())
+
+		// CRUD endpoints should fail fast to free resources.
+		crud := secured.Group("")
+		crud.Use(middleware.Timeout(timeouts.Default))
 		{
 			// User endpoints
-			secured.GET("/users/me", userHandler.GetCurrentUser)
-			secured.PUT("/users/me", userHandler.UpdateCurrentUser)
-			secured.PATCH("/users/me", userHandler.PatchCurrentUser)
-			secured.DELETE("/users/me", userHandler.DeleteCurrentUser)
-			secured.GET("/admin/users", userHandler.GetAllUsers)
+			crud.GET("/users/me", userHandler.GetCurrentUser)
+			crud.PUT("/users/me", userHandler.UpdateCurrentUser)
+			crud.PATCH("/users/me", userHandler.PatchCurrentUser)
+			crud.DELETE("/users/me", userHandler.DeleteCurrentUser)
+			crud.POST("/users/logout", userHandler.Logout)
+			crud.GET("/users/me/search-history", searchHistoryHandler.GetSearchHistory)
+			crud.DELETE("/users/me/search-history", searchHistoryHandler.ClearSearchHistory)
+			crud.GET("/users/me/favorites", favoriteHandler.ListFavorites)
+			crud.POST("/users/me/favorites/check", favoriteHandler.CheckFavorites)
+			crud.GET("/users/me/presets", presetHandler.ListPresets)
+			crud.GET("/users/me/recipes", recipeHandler.ListMyRecipes)
+			crud.POST("/users/me/presets", presetHandler.CreatePreset)
+			crud.GET("/admin/users", requireAdmin, userHandler.GetAllUsers)
+			crud.GET("/admin/recipes/stale-embeddings", requireAdmin, recipeHandler.ListStaleEmbeddings)
+			crud.GET("/admin/recipes/:id/audit-log", requireAdmin, recipeHandler.GetRecipeAuditLog)
+			crud.POST("/admin/tags/merge", requireAdmin, tagHandler.MergeTags)
+			crud.DELETE("/admin/tags/unused", requireAdmin, tagHandler.DeleteUnusedTags)
 
 			// Recipe endpoints
-			secured.GET("/recipes", recipeHandler.ListRecipes)
-			secured.GET("/recipes/:id", recipeHandler.GetRecipe)
-			secured.POST("/recipes", recipeHandler.SaveRecipe)
-			secured.PUT("/recipes/:id", recipeHandler.UpdateRecipe)
-			secured.DELETE("/recipes/:id", recipeHandler.DeleteRecipe)
-			secured.POST("/recipes/resolve", recipeResolutionHandler.ResolveRecipe)
-			secured.POST("/recipes/resolve/query", recipeMultistepHandler.QueryRecipe)
-			secured.POST("/recipes/resolve/modify", recipeMultistepHandler.ModifyRecipe)
-			secured.POST("/recipes/:id/rate", recipeHandler.RateRecipe)
-			secured.GET("/recipes/:id/ratings", recipeHandler.GetRecipeRatings)
-			secured.GET("/recipes/search", recipeHandler.SearchRecipes)
+			crud.GET("/recipes", recipeHandler.ListRecipes)
+			crud.GET("/recipes/:id", recipeHandler.GetRecipe)
+			crud.POST("/recipes", recipeHandler.SaveRecipe)
+			crud.POST("/recipes/import", recipeHandler.ImportRecipe)
+			crud.PUT("/recipes/:id", recipeHandler.UpdateRecipe)
+			crud.DELETE("/recipes/:id", recipeHandler.DeleteRecipe)
+			crud.POST("/recipes/:id/clone", recipeHandler.CloneRecipe)
+			crud.POST("/recipes/:id/rate", recipeHandler.RateRecipe)
+			crud.GET("/recipes/:id/ratings", recipeHandler.GetRecipeRatings)
+			crud.POST("/recipes/:id/favorite", favoriteHandler.AddFavorite)
+			crud.DELETE("/recipes/:id/favorite", favoriteHandler.RemoveFavorite)
+			crud.POST("/recipes/:id/scale", recipeHandler.ScaleRecipe)
+			crud.POST("/recipes/:id/scale-pan", recipeHandler.ScalePan)
+			crud.GET("/recipes/:id/convert", recipeHandler.ConvertRecipeUnits)
+			crud.POST("/recipes/shopping-list", recipeHandler.GenerateShoppingList)
+			crud.GET("/recipes/:id/shopping-list", recipeHandler.ExportShoppingList)
+			crud.GET("/recipes/:id/export", recipeHandler.ExportRecipe)
+			crud.GET("/recipes/search", recipeHandler.SearchRecipes)
+			crud.GET("/recipes/search/ingredients", recipeHandler.SearchByIngredients)
+			crud.GET("/recipes/schema", handlers.GetRecipeSchema)
+			crud.GET("/recipes/difficulties", recipeHandler.ListDifficulties)
+		}
+
+		// Image uploads send up to 5 MB from the client on to object storage, which can take
+		// far longer than a CRUD request.
+		upload := secured.Group("")
+		upload.Use(middleware.Timeout(timeouts.Upload))
+		{
+			upload.POST("/recipes/:id/image", recipeHandler.UploadRecipeImage)
+		}
+
+		// Endpoints that call the external model need a much longer timeout.
+		ai := secured.Group("")
+		ai.Use(middleware.Timeout(timeouts.AI))
+		// Everything in their bodies may end up in a paid prompt, so bodies are kept small.
+		ai.Use(middleware.BodyLimit(int64(aiConfig.MaxBodyBytes)))
+		// They also call paid APIs, so each user gets a token bucket shared across instances.
+		if os.Getenv("DISABLE_RATE_LIMITER") != "true" {
+			ai.Use(middleware.RateLimit(redisClient, config.LoadRateLimitConfig()))
+		}
+		{
+			ai.POST("/recipes/resolve", recipeResolutionHandler.ResolveRecipe)
+			ai.POST("/recipes/resolve/query", recipeMultistepHandler.QueryRecipe)
+			ai.POST("/recipes/resolve/modify", recipeMultistepHandler.ModifyRecipe)
+			ai.POST("/recipes/:id/substitute", recipeModificationHandler.SubstituteIngredient)
+			ai.POST("/recipes/:id/expand", recipeModificationHandler.ExpandRecipe)
+			ai.POST("/recipes/:id/nutrition", recipeModificationHandler.RecomputeNutrition)
+			ai.POST("/recipes/:id/suggest-tags", recipeModificationHandler.SuggestTags)
+			ai.POST("/recipes/generate/batch", recipeBatchHandler.GenerateRecipesBatch)
+			ai.POST("/recipes/generate/meal-plan", recipeBatchHandler.GenerateMealPlan)
+			ai.POST("/admin/recipes/reindex-embeddings", requireAdmin, recipeHandler.ReindexEmbeddings)
 		}
 	}
 
 	logger.Info("Router setup complete")
 	return router
 }
+
+// ConfigureTrustedProxies sets the proxies whose forwarding headers gin uses to
+// determine the client IP. An empty list trusts no proxies.
+func ConfigureTrustedProxies(router *gin.Engine, proxies []string) error {
+	// gin trusts every proxy by default; a nil list disables forwarding headers entirely.
+	return router.SetTrustedProxies(proxies)
+}
+
+// newRedisClient connects to Redis using config.LoadRedisConfig.
+// It returns nil when Redis is not configured or unreachable so that
+// Redis-backed features degrade gracefully.
+func newRedisClient(logger *logging.Logger) *redis.Client {
+	client, err := db.NewRedisClient(config.LoadRedisConfig())
+	if err != nil {
+		if !errors.Is(err, db.ErrRedisNotConfigured) {
+			logger.Warn("Redis unavailable, continuing without it", zap.Error(err))
+		}
+		return nil
+	}
+	return client
+}
+
+// newImageStorage creates the storage for uploaded recipe photos from STORAGE_DRIVER and serves
+// the local driver's directory under its public URL. It returns nil, disabling uploads, when
+// the storage cannot be set up.
+func newImageStorage(router *gin.Engine, logger *logging.Logger) storage.Storage {
+	cfg := config.LoadStorageConfig()
+	store, err := storage.New(context.Background(), cfg)
+	if err != nil {
+		logger.Error("Failed to set up image storage; recipe photo uploads are disabled", zap.Error(err))
+		return nil
+	}
+	if local, ok := store.(*storage.LocalStorage); ok {
+		publicURL := cfg.PublicURL
+		if publicURL == "" {
+			publicURL = storage.DefaultLocalPublicURL
+		}
+		if strings.HasPrefix(publicURL, "/") {
+			router.Static(publicURL, local.Dir())
+		}
+	}
+	return store
+}
+
+// newPriceEstimator loads the ingredient price table from PRICE_TABLE_PATH,
+// falling back to pricing.DefaultPrices when it is unset or invalid.
+func newPriceEstimator(logger *logging.Logger) *pricing.Estimator {
+	path := os.Getenv("PRICE_TABLE_PATH")
+	if path == "" {
+		return pricing.NewEstimator(pricing.DefaultPrices)
+	}
+	prices, err := pricing.LoadPrices(path)
+	if err != nil {
+		logger.Warn("Invalid PRICE_TABLE_PATH, using default prices", zap.Error(err))
+		return pricing.NewEstimator(pricing.DefaultPrices)
+	}
+	return pricing.NewEstimator(prices)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+)
+
+// MaxFavoriteCheckIDs caps the number of recipe IDs accepted by a single favorite check.
+const MaxFavoriteCheckIDs = 100
+
+// ErrAlreadyFavorited is returned by AddFavorite when the user has already favorited the recipe.
+var ErrAlreadyFavorited = repositories.ErrAlreadyFavorited
+
+// FavoriteService handles business logic for recipe favorites
+type FavoriteService interface {
+	CheckFavorites(ctx context.Context, userID string, recipeIDs []string) (map[string]bool, error)
+	AddFavorite(ctx context.Context, userID, recipeID string) error
+	RemoveFavorite(ctx context.Context, userID, recipeID string) error
+	ListFavorites(ctx context.Context, userID string, page, limit int) ([]models.Recipe, error)
+}
+
+type DefaultFavoriteService struct {
+	repo repositories.FavoriteRepository
+}
+
+func NewFavoriteService(repo repositories.FavoriteRepository) FavoriteService {
+	return &DefaultFavoriteService{repo: repo}
+}
+
+// CheckFavorites reports, for each requested recipe ID, whether the user has favorited it.
+func (s *DefaultFavoriteService) CheckFavorites(ctx context.Context, userID string, recipeIDs []string) (map[string]bool, error) {
+	if len(recipeIDs) > MaxFavoriteCheckIDs {
+		return nil, fmt.Errorf("at most %d recipe IDs may be checked at once", MaxFavoriteCheckIDs)
+	}
+
+	result := make(map[string]bool, len(recipeIDs))
+	for _, id := range recipeIDs {
+		result[id] = false
+	}
+
+	favorited, err := s.repo.GetFavoritedRecipeIDs(ctx, userID, recipeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range favorited {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// AddFavorite marks the recipe as a favorite of the user.
+func (s *DefaultFavoriteService) AddFavorite(ctx context.Context, userID, recipeID string) error {
+	return s.repo.AddFavorite(ctx, userID, recipeID)
+}
+
+// RemoveFavorite removes the recipe from the user's favorites.
+func (s *DefaultFavoriteService) RemoveFavorite(ctx context.Context, userID, recipeID string) error {
+	return s.repo.RemoveFavorite(ctx, userID, recipeID)
+}
+
+// ListFavorites returns a page of the user's favorite recipes, most recently favorited first.
+func (s *DefaultFavoriteService) ListFavorites(ctx context.Context, userID string, page, limit int) ([]models.Recipe, error) {
+	return s.repo.ListFavorites(ctx, userID, page, limit)
+}
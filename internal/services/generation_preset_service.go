@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+)
+
+// ErrPresetForbidden is returned when a user asks for a preset that belongs to someone else.
+var ErrPresetForbidden = errors.New("preset belongs to another user")
+
+// GenerationPresetService manages users' named recipe generation presets.
+type GenerationPresetService interface {
+	CreatePreset(ctx context.Context, userID string, req dtos.GenerationPresetRequest) (*models.GenerationPreset, error)
+	ListPresets(ctx context.Context, userID string) ([]models.GenerationPreset, error)
+	GetPresetForUser(ctx context.Context, userID, presetID string) (*models.GenerationPreset, error)
+}
+
+type DefaultGenerationPresetService struct {
+	repo repositories.GenerationPresetRepository
+}
+
+func NewGenerationPresetService(repo repositories.GenerationPresetRepository) GenerationPresetService {
+	return &DefaultGenerationPresetService{repo: repo}
+}
+
+// CreatePreset stores a new preset owned by the user.
+func (s *DefaultGenerationPresetService) CreatePreset(ctx context.Context, userID string, req dtos.GenerationPresetRequest) (*models.GenerationPreset, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("preset name is required")
+	}
+	if req.MaxTimeMinutes < 0 || req.Servings < 0 {
+		return nil, errors.New("max_time_minutes and servings cannot be negative")
+	}
+
+	preset := &models.GenerationPreset{
+		UserID:         userID,
+		Name:           name,
+		Cuisine:        strings.TrimSpace(req.Cuisine),
+		Diet:           strings.TrimSpace(req.Diet),
+		MaxTimeMinutes: req.MaxTimeMinutes,
+		Servings:       req.Servings,
+	}
+	if err := s.repo.CreatePreset(ctx, preset); err != nil {
+		return nil, fmt.Errorf("failed to create preset: %w", err)
+	}
+	return preset, nil
+}
+
+// ListPresets returns the user's presets.
+func (s *DefaultGenerationPresetService) ListPresets(ctx context.Context, userID string) ([]models.GenerationPreset, error) {
+	return s.repo.ListPresets(ctx, userID)
+}
+
+// GetPresetForUser returns the preset if it belongs to the user. A missing preset yields
+// gorm.ErrRecordNotFound and another user's preset yields ErrPresetForbidden.
+func (s *DefaultGenerationPresetService) GetPresetForUser(ctx context.Context, userID, presetID string) (*models.GenerationPreset, error) {
+	preset, err := s.repo.GetPreset(ctx, presetID)
+	if err != nil {
+		return nil, err
+	}
+	if preset.UserID != userID {
+		return nil, ErrPresetForbidden
+	}
+	return preset, nil
+}
+
+// ApplyGenerationPreset merges the preset's constraints into a free-text generation query,
+// e.g. "pasta" becomes "pasta (cuisine: italian; ready in at most 30 minutes; serves 4)".
+func ApplyGenerationPreset(query string, preset *models.GenerationPreset) string {
+	query = strings.TrimSpace(query)
+	if preset == nil {
+		return query
+	}
+
+	var constraints []string
+	if preset.Cuisine != "" {
+		constraints = append(constraints, "cuisine: "+preset.Cuisine)
+	}
+	if preset.Diet != "" {
+		constraints = append(constraints, "diet: "+preset.Diet)
+	}
+	if preset.MaxTimeMinutes > 0 {
+		constraints = append(constraints, fmt.Sprintf("ready in at most %d minutes", preset.MaxTimeMinutes))
+	}
+	if preset.Servings > 0 {
+		constraints = append(constraints, fmt.Sprintf("serves %d", preset.Servings))
+	}
+	if len(constraints) == 0 {
+		return query
+	}
+	return query + " (" + strings.Join(constraints, "; ") + ")"
+}
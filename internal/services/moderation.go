@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"go.uber.org/zap"
+)
+
+// ErrQueryRejected is returned when a recipe query fails content moderation. Its message is
+// safe to show to the caller and does not reveal which rule matched.
+var ErrQueryRejected = errors.New("query was rejected by content moderation")
+
+// Moderator checks a free-text recipe query before it is sent to the model, returning
+// ErrQueryRejected when the query must not be used.
+type Moderator interface {
+	Check(ctx context.Context, query string) error
+}
+
+// DefaultModerationTerms are the phrases KeywordModerator always rejects: attempts to override
+// the prompt and clearly harmful requests. Terms are kept specific, since plain words like
+// "bomb" also name dishes.
+var DefaultModerationTerms = []string{
+	"ignore previous instructions", "ignore all previous instructions", "ignore the above",
+	"disregard previous instructions", "system prompt", "jailbreak", "developer mode",
+	"make a bomb", "build a bomb", "poison someone", "methamphetamine", "cook meth",
+}
+
+// KeywordModerator rejects queries containing any of its terms as whole words, ignoring case
+// and differences in whitespace.
+type KeywordModerator struct {
+	patterns []*regexp.Regexp
+}
+
+// NewKeywordModerator creates a KeywordModerator for DefaultModerationTerms plus extra.
+func NewKeywordModerator(extra ...string) *KeywordModerator {
+	m := &KeywordModerator{}
+	for _, term := range append(append([]string{}, DefaultModerationTerms...), extra...) {
+		words := strings.Fields(term)
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		m.patterns = append(m.patterns, regexp.MustCompile(`(?i)\b`+strings.Join(words, `\s+`)+`\b`))
+	}
+	return m
+}
+
+// Check implements Moderator.
+func (m *KeywordModerator) Check(ctx context.Context, query string) error {
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(query) {
+			return ErrQueryRejected
+		}
+	}
+	return nil
+}
+
+// OpenAIModerator runs the keyword filter, then asks the OpenAI moderation API. When the API
+// call fails the query is let through with a warning, so an outage does not stop generation.
+type OpenAIModerator struct {
+	keywords *KeywordModerator
+	// moderate calls the moderation API; replaced in tests.
+	moderate func(ctx context.Context, text string) (*integrations.ModerationResult, error)
+}
+
+// NewOpenAIModerator creates an OpenAIModerator whose keyword filter also rejects extra.
+func NewOpenAIModerator(extra ...string) *OpenAIModerator {
+	return &OpenAIModerator{keywords: NewKeywordModerator(extra...), moderate: integrations.ModerateText}
+}
+
+// Check implements Moderator.
+func (m *OpenAIModerator) Check(ctx context.Context, query string) error {
+	if err := m.keywords.Check(ctx, query); err != nil {
+		return err
+	}
+	result, err := m.moderate(ctx, query)
+	if err != nil {
+		zap.S().Warnw("Moderation API call failed, allowing query", "error", err)
+		return nil
+	}
+	if result.Flagged {
+		zap.S().Infow("Query flagged by the moderation API", "categories", result.Categories)
+		return ErrQueryRejected
+	}
+	return nil
+}
+
+// NewModerator builds the Moderator selected by cfg, or nil when moderation is off.
+func NewModerator(cfg config.ModerationConfig) Moderator {
+	switch cfg.Driver {
+	case "keywords":
+		return NewKeywordModerator(cfg.BlockedTerms...)
+	case "openai":
+		return NewOpenAIModerator(cfg.BlockedTerms...)
+	}
+	return nil
+}
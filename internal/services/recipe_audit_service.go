@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+)
+
+// DefaultRecipeAuditLimit is the number of audit entries returned when no limit is given.
+const DefaultRecipeAuditLimit = 100
+
+// RecipeFieldChange is the old and new value of a recipe field in an audit entry's diff.
+type RecipeFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// RecipeAuditService records who created, changed or deleted recipes.
+type RecipeAuditService interface {
+	// Record adds an entry for a change made by actorID, which may be empty. For updates the
+	// diff between before and after is stored; either may be nil for creations and deletions.
+	Record(ctx context.Context, actorID, action string, before, after *models.Recipe) error
+	// ListRecipeAuditLog returns up to limit entries for the recipe, newest first.
+	ListRecipeAuditLog(ctx context.Context, recipeID string, limit int) ([]models.RecipeAuditEntry, error)
+}
+
+type DefaultRecipeAuditService struct {
+	repo repositories.RecipeAuditRepository
+}
+
+func NewRecipeAuditService(repo repositories.RecipeAuditRepository) RecipeAuditService {
+	return &DefaultRecipeAuditService{repo: repo}
+}
+
+func (s *DefaultRecipeAuditService) Record(ctx context.Context, actorID, action string, before, after *models.Recipe) error {
+	entry := &models.RecipeAuditEntry{Action: action}
+	switch {
+	case after != nil:
+		entry.RecipeID = after.ID
+	case before != nil:
+		entry.RecipeID = before.ID
+	}
+	if entry.RecipeID == "" {
+		return fmt.Errorf("audit entry needs a recipe")
+	}
+	if actorID != "" {
+		entry.ActorID = &actorID
+	}
+
+	if before != nil && after != nil {
+		diff, err := RecipeDiff(before, after)
+		if err != nil {
+			return err
+		}
+		if len(diff) > 0 {
+			if entry.Diff, err = json.Marshal(diff); err != nil {
+				return err
+			}
+		}
+	}
+	return s.repo.CreateEntry(ctx, entry)
+}
+
+func (s *DefaultRecipeAuditService) ListRecipeAuditLog(ctx context.Context, recipeID string, limit int) ([]models.RecipeAuditEntry, error) {
+	if limit <= 0 {
+		limit = DefaultRecipeAuditLimit
+	}
+	return s.repo.ListEntries(ctx, recipeID, limit)
+}
+
+// unauditedRecipeFields are the JSON fields of a recipe left out of audit diffs: identifiers,
+// timestamps and values derived from other data.
+var unauditedRecipeFields = []string{"id", "created_at", "updated_at", "embedding", "embedding_updated_at", "average_rating", "rating_count"}
+
+// namedRecipeFields are associations compared by the names of their entries.
+var namedRecipeFields = []string{"cuisines", "diets", "appliances", "tags"}
+
+// RecipeDiff returns the fields whose JSON value differs between before and after, keyed by
+// their JSON name. Cuisines, diets, appliances and tags are compared as lists of names.
+func RecipeDiff(before, after *models.Recipe) (map[string]RecipeFieldChange, error) {
+	from, err := auditedRecipeFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := auditedRecipeFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]RecipeFieldChange)
+	for field, value := range to {
+		if !reflect.DeepEqual(from[field], value) {
+			diff[field] = RecipeFieldChange{From: from[field], To: value}
+		}
+	}
+	for field, value := range from {
+		if _, ok := to[field]; !ok {
+			diff[field] = RecipeFieldChange{From: value}
+		}
+	}
+	return diff, nil
+}
+
+func auditedRecipeFields(recipe *models.Recipe) (map[string]interface{}, error) {
+	data, err := json.Marshal(recipe)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range unauditedRecipeFields {
+		delete(fields, field)
+	}
+	for _, field := range namedRecipeFields {
+		entries, _ := fields[field].([]interface{})
+		names := []interface{}{}
+		for _, entry := range entries {
+			if object, ok := entry.(map[string]interface{}); ok {
+				names = append(names, object["name"])
+			}
+		}
+		fields[field] = names
+	}
+	return fields, nil
+}
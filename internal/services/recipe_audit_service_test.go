@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+)
+
+// recordingAuditRepository keeps the entries passed to CreateEntry.
+type recordingAuditRepository struct {
+	repositories.RecipeAuditRepository
+	entries []*models.RecipeAuditEntry
+}
+
+func (r *recordingAuditRepository) CreateEntry(ctx context.Context, entry *models.RecipeAuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestRecipeDiff(t *testing.T) {
+	before := &models.Recipe{ID: "1", Title: "Pancakes", Servings: 2, Tags: []models.Tag{{ID: "t1", Name: "breakfast"}}}
+	after := *before
+	after.Title = "Fluffy pancakes"
+	after.AverageRating = 4.5
+	after.Tags = []models.Tag{{Name: "breakfast"}}
+	if err := after.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "1", Unit: "cup"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := RecipeDiff(before, &after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 2 {
+		t.Fatalf("expected title and ingredients to change, got %v", diff)
+	}
+	if diff["title"].From != "Pancakes" || diff["title"].To != "Fluffy pancakes" {
+		t.Errorf("unexpected title change %+v", diff["title"])
+	}
+	if _, ok := diff["ingredients"]; !ok {
+		t.Errorf("expected ingredients in diff, got %v", diff)
+	}
+}
+
+func TestRecordRecipeAudit(t *testing.T) {
+	repo := &recordingAuditRepository{}
+	audit := NewRecipeAuditService(repo)
+	ctx := context.Background()
+	before := &models.Recipe{ID: "1", Title: "Soup"}
+	after := &models.Recipe{ID: "1", Title: "Stew"}
+
+	if err := audit.Record(ctx, "user-1", models.RecipeAuditUpdate, before, after); err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Record(ctx, "", models.RecipeAuditDelete, after, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Record(ctx, "user-1", models.RecipeAuditCreate, nil, &models.Recipe{}); err == nil {
+		t.Error("expected an error for a recipe without an ID")
+	}
+
+	if len(repo.entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(repo.entries))
+	}
+	update := repo.entries[0]
+	if update.RecipeID != "1" || update.ActorID == nil || *update.ActorID != "user-1" {
+		t.Errorf("unexpected update entry %+v", update)
+	}
+	var diff map[string]RecipeFieldChange
+	if err := json.Unmarshal(update.Diff, &diff); err != nil {
+		t.Fatal(err)
+	}
+	if diff["title"].To != "Stew" {
+		t.Errorf("unexpected diff %v", diff)
+	}
+	if deletion := repo.entries[1]; deletion.ActorID != nil || deletion.Diff != nil {
+		t.Errorf("unexpected delete entry %+v", deletion)
+	}
+}
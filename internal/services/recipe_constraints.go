@@ -0,0 +1,83 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/parsers"
+	"github.com/pageza/alchemorsel-v1/internal/units"
+)
+
+// nonIngredientWords are nouns the query parser reports as ingredients although they describe the
+// meal, the cooking method, the time or the nutrition target rather than something to cook with.
+var nonIngredientWords = map[string]bool{
+	// meals and courses
+	"breakfast": true, "brunch": true, "lunch": true, "dinner": true, "supper": true, "snack": true,
+	"dessert": true, "meal": true, "dish": true, "recipe": true, "side": true, "starter": true,
+	// appliances and cookware
+	"fryer": true, "oven": true, "cooker": true, "pot": true, "grill": true, "wok": true,
+	"smoker": true, "blender": true, "processor": true, "microwave": true, "pan": true,
+	"skillet": true, "stove": true,
+	// time
+	"minute": true, "minutes": true, "min": true, "mins": true, "hour": true, "hours": true,
+	"hr": true, "hrs": true, "time": true, "day": true, "days": true,
+	"week": true, "weeknight": true, "night": true, "weekend": true,
+	// nutrition targets and portions
+	"kcal": true, "calorie": true, "calories": true, "protein": true, "carbs": true,
+	"serving": true, "servings": true, "portion": true, "portions": true, "person": true, "people": true,
+	// amounts and filler
+	"lots": true, "plenty": true, "bit": true, "ready": true, "something": true, "idea": true, "ideas": true,
+}
+
+// queryConstraints turns the structured parts of a freeform query into explicit guidance for the
+// generation prompt. Constraints are hard rules, so that for example "without onions" reaches the
+// model as a requirement rather than a phrase it may overlook; preferences name the ingredients the
+// query mentions, which the model should feature where they suit the dish. Both are nil when the
+// query cannot be parsed, leaving the raw query as the only guidance.
+func queryConstraints(query string) (constraints, preferences []string) {
+	parsed, err := parsers.ParseRecipeQuery(query)
+	if err != nil {
+		return nil, nil
+	}
+
+	if parsed.Cuisine != "" && parsed.Cuisine != "unknown" {
+		constraints = append(constraints, fmt.Sprintf("The recipe must be %s cuisine.", parsed.Cuisine))
+	}
+	if parsed.DietaryRestrictions != "" && parsed.DietaryRestrictions != "none" {
+		constraints = append(constraints, fmt.Sprintf("The recipe must be strictly %s.", parsed.DietaryRestrictions))
+	}
+	for _, exclusion := range parsed.Exclusions {
+		constraints = append(constraints, fmt.Sprintf("Do NOT use %s or anything made from it, not even as a garnish or optional ingredient.", exclusion))
+	}
+	if len(parsed.Appliances) > 0 {
+		constraints = append(constraints, "Cook with: "+strings.Join(parsed.Appliances, ", ")+".")
+	}
+	if parsed.MaxTimeMinutes > 0 {
+		constraints = append(constraints, fmt.Sprintf("The total prep and cooking time must not exceed %d minutes.", parsed.MaxTimeMinutes))
+	}
+	if ingredients := mentionedIngredients(parsed); len(ingredients) > 0 {
+		preferences = append(preferences, "Feature these ingredients from the query: "+strings.Join(ingredients, ", ")+".")
+	}
+	return constraints, preferences
+}
+
+// mentionedIngredients returns the parsed ingredients without the words that name the meal, an
+// appliance, a time, a unit or a nutrition target, none of which the model should be asked to cook.
+func mentionedIngredients(parsed *parsers.ParsedQuery) []string {
+	skip := map[string]bool{parsed.Cuisine: true, parsed.DietaryRestrictions: true}
+	for _, appliance := range parsed.Appliances {
+		for _, word := range strings.Fields(appliance) {
+			skip[word] = true
+		}
+	}
+
+	var ingredients []string
+	for _, ingredient := range parsed.Ingredients {
+		word := strings.ToLower(ingredient)
+		if skip[word] || nonIngredientWords[word] || units.Convertible("1", word) {
+			continue
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultRecipeDifficulties are the allowed difficulty levels when RECIPE_DIFFICULTIES is not set.
+var DefaultRecipeDifficulties = []string{"easy", "medium", "hard"}
+
+// difficultySynonyms maps wording commonly produced by the model to a default difficulty level.
+var difficultySynonyms = map[string]string{
+	"beginner":     "easy",
+	"simple":       "easy",
+	"very easy":    "easy",
+	"moderate":     "medium",
+	"intermediate": "medium",
+	"average":      "medium",
+	"advanced":     "hard",
+	"difficult":    "hard",
+	"challenging":  "hard",
+	"expert":       "hard",
+}
+
+// RecipeDifficulties returns the allowed difficulty levels, read from the comma-separated
+// RECIPE_DIFFICULTIES variable, e.g. "easy,medium,hard,expert".
+func RecipeDifficulties() []string {
+	var levels []string
+	for _, level := range strings.Split(os.Getenv("RECIPE_DIFFICULTIES"), ",") {
+		if level = strings.ToLower(strings.TrimSpace(level)); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	if len(levels) == 0 {
+		return DefaultRecipeDifficulties
+	}
+	return levels
+}
+
+// ValidateRecipeDifficulty checks a client-supplied difficulty against RecipeDifficulties,
+// ignoring case and surrounding whitespace. An empty value is allowed and stays empty.
+func ValidateRecipeDifficulty(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	for _, level := range RecipeDifficulties() {
+		if value == level {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported difficulty: %s (allowed: %s)", value, strings.Join(RecipeDifficulties(), ", "))
+}
+
+// CoerceRecipeDifficulty maps loosely worded difficulties, such as model output like
+// "Intermediate", onto an allowed level. Values that cannot be mapped become empty.
+func CoerceRecipeDifficulty(value string) string {
+	if level, err := ValidateRecipeDifficulty(value); err == nil {
+		return level
+	}
+	if level, ok := difficultySynonyms[strings.ToLower(strings.TrimSpace(value))]; ok {
+		if allowed, err := ValidateRecipeDifficulty(level); err == nil {
+			return allowed
+		}
+	}
+	return ""
+}
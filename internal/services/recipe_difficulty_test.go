@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidateRecipeDifficulty(t *testing.T) {
+	if got, err := ValidateRecipeDifficulty(" Hard "); err != nil || got != "hard" {
+		t.Errorf("Expected hard, got %q (%v)", got, err)
+	}
+	if got, err := ValidateRecipeDifficulty(""); err != nil || got != "" {
+		t.Errorf("Expected empty difficulty to be allowed, got %q (%v)", got, err)
+	}
+	if _, err := ValidateRecipeDifficulty("intermediate"); err == nil {
+		t.Error("Expected client input outside the allowed set to be rejected")
+	}
+
+	t.Setenv("RECIPE_DIFFICULTIES", "easy,medium,hard,expert")
+	if got, err := ValidateRecipeDifficulty("Expert"); err != nil || got != "expert" {
+		t.Errorf("Expected configured level expert, got %q (%v)", got, err)
+	}
+}
+
+func TestSaveRecipeCoercesModelDifficulty(t *testing.T) {
+	s := &recipeService{
+		repo:            stubRecipeRepository{},
+		embeddingFields: DefaultEmbeddingFields,
+		embedder: embedderFunc(func(text string) ([]float64, error) {
+			return []float64{1}, nil
+		}),
+	}
+
+	cases := map[string]string{
+		"Intermediate": "medium",
+		"EASY":         "easy",
+		"Challenging":  "hard",
+		"galactic":     "",
+	}
+	for input, want := range cases {
+		recipe := embeddingTestRecipe()
+		recipe.Difficulty = input
+		if err := s.SaveRecipe(context.Background(), recipe); err != nil {
+			t.Fatalf("Unexpected error saving %q: %v", input, err)
+		}
+		if recipe.Difficulty != want {
+			t.Errorf("Expected %q to be coerced to %q, got %q", input, want, recipe.Difficulty)
+		}
+	}
+}
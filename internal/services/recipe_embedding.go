@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"go.uber.org/zap"
+)
+
+// Recipe fields that can be included in the text used to compute a recipe's embedding.
+const (
+	EmbeddingFieldTitle       = "title"
+	EmbeddingFieldDescription = "description"
+	EmbeddingFieldTags        = "tags"
+	EmbeddingFieldIngredients = "ingredients"
+	EmbeddingFieldSteps       = "steps"
+)
+
+// DefaultEmbeddingFields are embedded when EMBEDDING_TEXT_FIELDS is not set.
+var DefaultEmbeddingFields = []string{
+	EmbeddingFieldTitle,
+	EmbeddingFieldDescription,
+	EmbeddingFieldTags,
+	EmbeddingFieldIngredients,
+}
+
+var embeddingFields = map[string]bool{
+	EmbeddingFieldTitle:       true,
+	EmbeddingFieldDescription: true,
+	EmbeddingFieldTags:        true,
+	EmbeddingFieldIngredients: true,
+	EmbeddingFieldSteps:       true,
+}
+
+// ParseEmbeddingFields parses a comma-separated list of embedding fields, e.g.
+// "title,ingredients,steps". An empty value returns DefaultEmbeddingFields.
+func ParseEmbeddingFields(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultEmbeddingFields, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !embeddingFields[field] {
+			return nil, fmt.Errorf("unsupported embedding field: %s", field)
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return DefaultEmbeddingFields, nil
+	}
+	return fields, nil
+}
+
+// embeddingFieldsFromEnv reads EMBEDDING_TEXT_FIELDS, falling back to the defaults when it is invalid.
+func embeddingFieldsFromEnv() []string {
+	fields, err := ParseEmbeddingFields(os.Getenv("EMBEDDING_TEXT_FIELDS"))
+	if err != nil {
+		zap.S().Warnw("Invalid EMBEDDING_TEXT_FIELDS, using defaults", "error", err)
+		return DefaultEmbeddingFields
+	}
+	return fields
+}
+
+// buildEmbeddingText renders the given recipe fields, one per line, as the text to embed.
+// Every code path that computes a recipe embedding must use it so equal recipes embed equally.
+func buildEmbeddingText(recipe *models.Recipe, fields []string) string {
+	var lines []string
+	for _, field := range fields {
+		switch field {
+		case EmbeddingFieldTitle:
+			lines = append(lines, recipe.Title)
+		case EmbeddingFieldDescription:
+			lines = append(lines, recipe.Description)
+		case EmbeddingFieldTags:
+			tags := make([]string, len(recipe.Tags))
+			for i, tag := range recipe.Tags {
+				tags[i] = tag.Name
+			}
+			lines = append(lines, strings.Join(tags, ", "))
+		case EmbeddingFieldIngredients:
+			ingredients, _ := recipe.GetIngredients()
+			items := make([]string, len(ingredients))
+			for i, ing := range ingredients {
+				items[i] = strings.Join(strings.Fields(ing.Amount+" "+ing.Unit+" "+ing.Name), " ")
+			}
+			lines = append(lines, strings.Join(items, ", "))
+		case EmbeddingFieldSteps:
+			steps, _ := recipe.GetSteps()
+			descriptions := make([]string, len(steps))
+			for i, step := range steps {
+				descriptions[i] = step.Description
+			}
+			lines = append(lines, strings.Join(descriptions, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EmbeddingDimensionError is returned when the embedder produces a vector whose length does not
+// match EMBEDDING_DIMENSIONS, e.g. after the embedding model was changed. Such vectors are never
+// stored, as they cannot be compared with the existing ones.
+type EmbeddingDimensionError struct {
+	Got  int
+	Want int
+}
+
+func (e *EmbeddingDimensionError) Error() string {
+	return fmt.Sprintf("embedding has %d dimensions, expected %d", e.Got, e.Want)
+}
+
+// embed computes the recipe's embedding and checks its length against embeddingDimensions.
+func (s *recipeService) embed(ctx context.Context, recipe *models.Recipe) ([]float64, error) {
+	embedding, err := s.embedder.GenerateEmbedding(ctx, buildEmbeddingText(recipe, s.embeddingFields))
+	if err != nil {
+		return nil, err
+	}
+	if s.embeddingDimensions > 0 && len(embedding) != s.embeddingDimensions {
+		return nil, &EmbeddingDimensionError{Got: len(embedding), Want: s.embeddingDimensions}
+	}
+	return embedding, nil
+}
+
+// DefaultReindexBatchSize is used when ReindexEmbeddings is given a non-positive batch size.
+const DefaultReindexBatchSize = 100
+
+// MaxReindexBatchSize is the largest batch the reindex endpoint accepts, since the whole batch
+// is embedded within a single request's timeout.
+const MaxReindexBatchSize = 100
+
+// DefaultStaleEmbeddingsLimit is used when ListStaleEmbeddings is given a non-positive limit.
+const DefaultStaleEmbeddingsLimit = 100
+
+// ReindexResult reports the progress of a ReindexEmbeddings call.
+type ReindexResult struct {
+	// Processed is the number of recipes examined in this batch.
+	Processed int
+	// Changed is the number of recipes whose embedding differs from the stored one.
+	// In a dry run these are the rows that would be updated.
+	Changed int
+	// NextOffset is where the next call should start.
+	NextOffset int
+	// Done is set once the batch reached the end of the table.
+	Done bool
+}
+
+// ReindexEmbeddings recomputes the embeddings of one batch of up to batchSize recipes starting
+// at offset, using the same text as SaveRecipe and UpdateRecipe. A whole table does not fit in
+// one request, so callers repeat it from NextOffset until Done. Only changed embeddings are
+// written, and nothing is written in a dry run. The batch is written in one bulk transaction
+// (see RecipeRepository.WithBulkTransaction), so if embedding, the database or the context
+// fails part-way the batch is left untouched, NextOffset stays at offset and the error is
+// returned.
+func (s *recipeService) ReindexEmbeddings(ctx context.Context, offset, batchSize int, dryRun bool) (ReindexResult, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultReindexBatchSize
+	}
+	result := ReindexResult{NextOffset: offset}
+	recipes, err := s.repo.ListRecipesBatch(ctx, offset, batchSize)
+	if err != nil {
+		return result, err
+	}
+
+	changed := make(map[string]models.Float64Slice)
+	for i := range recipes {
+		recipe := &recipes[i]
+		embedding, err := s.embed(ctx, recipe)
+		if err != nil {
+			return result, fmt.Errorf("failed to embed recipe %s: %w", recipe.ID, err)
+		}
+		if !reflect.DeepEqual([]float64(recipe.Embedding), embedding) {
+			changed[recipe.ID] = embedding
+		}
+	}
+	if !dryRun && len(changed) > 0 {
+		err := s.repo.WithBulkTransaction(ctx, func(repo repositories.RecipeRepository) error {
+			for id, embedding := range changed {
+				if err := repo.UpdateRecipeEmbedding(ctx, id, embedding); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	return ReindexResult{
+		Processed:  len(recipes),
+		Changed:    len(changed),
+		NextOffset: offset + len(recipes),
+		Done:       len(recipes) < batchSize,
+	}, nil
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+)
+
+// stubRecipeRepository accepts saves and updates without a database.
+type stubRecipeRepository struct {
+	repositories.RecipeRepository
+}
+
+func (stubRecipeRepository) SaveRecipe(ctx context.Context, recipe *models.Recipe) error {
+	return nil
+}
+
+func (stubRecipeRepository) UpdateRecipe(ctx context.Context, recipe *models.Recipe) error {
+	return nil
+}
+
+// embedderFunc adapts a function to integrations.Embedder.
+type embedderFunc func(text string) ([]float64, error)
+
+func (f embedderFunc) GenerateEmbedding(ctx context.Context, text string) ([]float64, error) {
+	return f(text)
+}
+
+func embeddingTestRecipe() *models.Recipe {
+	recipe := &models.Recipe{
+		ID:          "recipe-1",
+		Title:       "Pancakes",
+		Description: "Fluffy breakfast pancakes.",
+		Tags:        []models.Tag{{ID: "tag-1", Name: "breakfast"}, {ID: "tag-2", Name: "sweet"}},
+	}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "2", Unit: "cups"}, {Name: "eggs", Amount: "2"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Whisk."}, {Order: 2, Description: "Fry."}})
+	return recipe
+}
+
+func TestBuildEmbeddingTextIsSharedBySaveAndUpdate(t *testing.T) {
+	var texts []string
+	s := &recipeService{
+		repo:            stubRecipeRepository{},
+		embeddingFields: DefaultEmbeddingFields,
+		embedder: embedderFunc(func(text string) ([]float64, error) {
+			texts = append(texts, text)
+			return []float64{1, 2, 3}, nil
+		}),
+	}
+
+	saved := embeddingTestRecipe()
+	if err := s.SaveRecipe(context.Background(), saved); err != nil {
+		t.Fatalf("Unexpected error saving: %v", err)
+	}
+	updated := embeddingTestRecipe()
+	if err := s.UpdateRecipe(context.Background(), updated); err != nil {
+		t.Fatalf("Unexpected error updating: %v", err)
+	}
+
+	if len(texts) != 2 {
+		t.Fatalf("Expected two embedding calls, got %d", len(texts))
+	}
+	if texts[0] != texts[1] {
+		t.Errorf("Expected identical embedding text, got:\n%q\n%q", texts[0], texts[1])
+	}
+	want := "Pancakes\nFluffy breakfast pancakes.\nbreakfast, sweet\n2 cups flour, 2 eggs"
+	if texts[0] != want {
+		t.Errorf("Expected embedding text %q, got %q", want, texts[0])
+	}
+	if len(saved.Embedding) != 3 || len(updated.Embedding) != 3 {
+		t.Error("Expected embeddings to be stored on the recipes")
+	}
+}
+
+func TestBuildEmbeddingTextWithSteps(t *testing.T) {
+	fields, err := ParseEmbeddingFields("title, steps")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := buildEmbeddingText(embeddingTestRecipe(), fields); got != "Pancakes\nWhisk. Fry." {
+		t.Errorf("Unexpected embedding text %q", got)
+	}
+}
+
+func TestParseEmbeddingFields(t *testing.T) {
+	if fields, err := ParseEmbeddingFields(""); err != nil || strings.Join(fields, ",") != "title,description,tags,ingredients" {
+		t.Errorf("Expected default fields, got %v, %v", fields, err)
+	}
+	if _, err := ParseEmbeddingFields("title,calories"); err == nil {
+		t.Error("Expected error for unsupported field, got nil")
+	}
+}
+
+func TestEmbeddingFailureDoesNotFailSave(t *testing.T) {
+	s := &recipeService{
+		repo:            stubRecipeRepository{},
+		embeddingFields: DefaultEmbeddingFields,
+		embedder:        embedderFunc(func(string) ([]float64, error) { return nil, errors.New("embedding service unavailable") }),
+	}
+	recipe := embeddingTestRecipe()
+	recipe.Embedding = models.Float64Slice{9}
+
+	if err := s.SaveRecipe(context.Background(), recipe); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if recipe.Embedding != nil {
+		t.Errorf("Expected stale embedding to be cleared, got %v", recipe.Embedding)
+	}
+}
+
+// batchRecipeRepository serves recipes in batches and records embedding updates.
+type batchRecipeRepository struct {
+	repositories.RecipeRepository
+	recipes    []models.Recipe
+	updated    map[string]models.Float64Slice
+	failUpdate string
+}
+
+func (r *batchRecipeRepository) ListRecipesBatch(ctx context.Context, offset, limit int) ([]models.Recipe, error) {
+	if offset >= len(r.recipes) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(r.recipes) {
+		end = len(r.recipes)
+	}
+	return append([]models.Recipe(nil), r.recipes[offset:end]...), nil
+}
+
+func (r *batchRecipeRepository) UpdateRecipeEmbedding(ctx context.Context, id string, embedding models.Float64Slice) error {
+	if id == r.failUpdate {
+		return errors.New("write failed")
+	}
+	r.updated[id] = embedding
+	return nil
+}
+
+// WithTransaction stages updates and only keeps them when fn succeeds.
+func (r *batchRecipeRepository) WithTransaction(ctx context.Context, fn func(repo repositories.RecipeRepository) error) error {
+	committed := r.updated
+	r.updated = make(map[string]models.Float64Slice)
+	for id, embedding := range committed {
+		r.updated[id] = embedding
+	}
+	if err := fn(r); err != nil {
+		r.updated = committed
+		return err
+	}
+	return nil
+}
+
+func (r *batchRecipeRepository) WithBulkTransaction(ctx context.Context, fn func(repo repositories.RecipeRepository) error) error {
+	return r.WithTransaction(ctx, fn)
+}
+
+func TestReindexEmbeddings(t *testing.T) {
+	newRepo := func() *batchRecipeRepository {
+		return &batchRecipeRepository{
+			recipes: []models.Recipe{
+				{ID: "a", Title: "Soup", Embedding: models.Float64Slice{9}},
+				{ID: "b", Title: "Stew", Embedding: models.Float64Slice{1}},
+				{ID: "c", Title: "Salad"},
+			},
+			updated: map[string]models.Float64Slice{},
+		}
+	}
+	// Only "Stew" already has an up-to-date embedding.
+	embedder := embedderFunc(func(text string) ([]float64, error) {
+		if text == "Stew" {
+			return []float64{1}, nil
+		}
+		return []float64{float64(len(text))}, nil
+	})
+	fields := []string{EmbeddingFieldTitle}
+
+	t.Run("processes one batch per call", func(t *testing.T) {
+		repo := newRepo()
+		s := &recipeService{repo: repo, embeddingFields: fields, embedder: embedder}
+
+		result, err := s.ReindexEmbeddings(context.Background(), 0, 2, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != (ReindexResult{Processed: 2, Changed: 1, NextOffset: 2}) {
+			t.Errorf("Unexpected result %+v", result)
+		}
+		if len(repo.updated) != 1 || repo.updated["a"][0] != 4 {
+			t.Errorf("Unexpected updates %v", repo.updated)
+		}
+
+		result, err = s.ReindexEmbeddings(context.Background(), result.NextOffset, 2, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != (ReindexResult{Processed: 1, Changed: 1, NextOffset: 3, Done: true}) {
+			t.Errorf("Unexpected result %+v", result)
+		}
+		if len(repo.updated) != 2 || repo.updated["c"][0] != 5 {
+			t.Errorf("Unexpected updates %v", repo.updated)
+		}
+	})
+
+	t.Run("dry run writes nothing and resumes from offset", func(t *testing.T) {
+		repo := newRepo()
+		s := &recipeService{repo: repo, embeddingFields: fields, embedder: embedder}
+
+		result, err := s.ReindexEmbeddings(context.Background(), 1, 2, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != (ReindexResult{Processed: 2, Changed: 1, NextOffset: 3}) {
+			t.Errorf("Unexpected result %+v", result)
+		}
+		if len(repo.updated) != 0 {
+			t.Errorf("Expected no updates in a dry run, got %v", repo.updated)
+		}
+	})
+
+	t.Run("embedding failure leaves the batch unwritten", func(t *testing.T) {
+		repo := newRepo()
+		failing := embedderFunc(func(text string) ([]float64, error) {
+			if text == "Salad" {
+				return nil, errors.New("rate limited")
+			}
+			return embedder(text)
+		})
+		s := &recipeService{repo: repo, embeddingFields: fields, embedder: failing}
+
+		result, err := s.ReindexEmbeddings(context.Background(), 2, 2, false)
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if result.NextOffset != 2 || len(repo.updated) != 0 {
+			t.Errorf("Expected nothing written and retry at offset 2, got %+v and %v", result, repo.updated)
+		}
+	})
+
+	t.Run("wrong embedding length is rejected", func(t *testing.T) {
+		repo := newRepo()
+		s := &recipeService{repo: repo, embeddingFields: fields, embedder: embedder, embeddingDimensions: 2}
+
+		_, err := s.ReindexEmbeddings(context.Background(), 0, 10, false)
+		var dimensionErr *EmbeddingDimensionError
+		if !errors.As(err, &dimensionErr) || dimensionErr.Got != 1 || dimensionErr.Want != 2 {
+			t.Fatalf("Expected EmbeddingDimensionError, got %v", err)
+		}
+		if len(repo.updated) != 0 {
+			t.Errorf("Expected no writes, got %v", repo.updated)
+		}
+	})
+
+	t.Run("write failure rolls back the batch", func(t *testing.T) {
+		repo := newRepo()
+		repo.failUpdate = "c"
+		s := &recipeService{repo: repo, embeddingFields: fields, embedder: embedder}
+
+		result, err := s.ReindexEmbeddings(context.Background(), 0, 10, false)
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if result.NextOffset != 0 || len(repo.updated) != 0 {
+			t.Errorf("Expected no writes and resume at offset 0, got %+v and %v", result, repo.updated)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// MaxRecipeImageBytes is the largest recipe photo accepted for upload.
+const MaxRecipeImageBytes = 5 << 20
+
+// Errors returned by ValidateRecipeImage.
+var (
+	ErrRecipeImageTooLarge  = fmt.Errorf("image must be at most %d MB", MaxRecipeImageBytes>>20)
+	ErrUnsupportedImageType = errors.New("image must be a JPEG, PNG or WebP file")
+)
+
+// recipeImageExtensions maps the accepted photo types to the extension they are stored with.
+var recipeImageExtensions = map[string]string{"image/jpeg": ".jpg", "image/png": ".png", "image/webp": ".webp"}
+
+// ValidateRecipeImage checks that data is a JPEG, PNG or WebP image of at most
+// MaxRecipeImageBytes and returns its content type and file extension. The type is detected
+// from the content, so a mislabelled upload cannot be stored as an image.
+func ValidateRecipeImage(data []byte) (contentType, ext string, err error) {
+	if len(data) > MaxRecipeImageBytes {
+		return "", "", ErrRecipeImageTooLarge
+	}
+	contentType = http.DetectContentType(data)
+	ext, ok := recipeImageExtensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedImageType
+	}
+	return contentType, ext, nil
+}
+
+// RecipeImageKey returns the storage key for a new photo of a recipe. Each upload gets a new
+// name so caches never serve a replaced photo.
+func RecipeImageKey(recipeID, ext string) string {
+	return "recipes/" + recipeID + "/" + uuid.New().String() + ext
+}
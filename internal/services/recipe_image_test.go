@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateRecipeImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		ext         string
+		err         error
+	}{
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "image/jpeg", ".jpg", nil},
+		{"png", []byte("\x89PNG\r\n\x1a\n"), "image/png", ".png", nil},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp", ".webp", nil},
+		{"gif", []byte("GIF89a"), "", "", ErrUnsupportedImageType},
+		{"text", []byte("<svg></svg>"), "", "", ErrUnsupportedImageType},
+		{"too large", make([]byte, MaxRecipeImageBytes+1), "", "", ErrRecipeImageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, ext, err := ValidateRecipeImage(tt.data)
+			if !errors.Is(err, tt.err) || contentType != tt.contentType || ext != tt.ext {
+				t.Errorf("Expected (%q, %q, %v), got (%q, %q, %v)", tt.contentType, tt.ext, tt.err, contentType, ext, err)
+			}
+		})
+	}
+}
+
+func TestRecipeImageKey(t *testing.T) {
+	key := RecipeImageKey("recipe-1", ".png")
+	if !strings.HasPrefix(key, "recipes/recipe-1/") || !strings.HasSuffix(key, ".png") {
+		t.Errorf("Unexpected key %q", key)
+	}
+	if key == RecipeImageKey("recipe-1", ".png") {
+		t.Error("Expected each upload to get a new key")
+	}
+}
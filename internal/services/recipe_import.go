@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/units"
+)
+
+// RecipeImportError reports why a pasted recipe was rejected.
+type RecipeImportError struct {
+	Problems []string
+}
+
+func (e *RecipeImportError) Error() string {
+	return "invalid recipe: " + strings.Join(e.Problems, "; ")
+}
+
+// importedRecipe is the accepted shape of a pasted recipe. Amounts may be numbers or strings.
+type importedRecipe struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Ingredients []struct {
+		Name   string      `json:"name"`
+		Amount interface{} `json:"amount"`
+		Unit   string      `json:"unit"`
+	} `json:"ingredients"`
+	Steps             []models.Step `json:"steps"`
+	NutritionalInfo   string        `json:"nutritional_info"`
+	AllergyDisclaimer string        `json:"allergy_disclaimer"`
+	Cuisines          []string      `json:"cuisines"`
+	Diets             []string      `json:"diets"`
+	Appliances        []string      `json:"appliances"`
+	Tags              []string      `json:"tags"`
+	Difficulty        string        `json:"difficulty"`
+	PrepTime          int           `json:"prep_time"`
+	CookTime          int           `json:"cooking_time"`
+	Servings          int           `json:"servings"`
+	Language          string        `json:"language"`
+}
+
+// ParseImportedRecipe decodes a recipe pasted as JSON and checks it with the rules applied to
+// AI output: RecipeSchema and validateGeneratedRecipe. It also requires a title, rejects unknown
+// fields and amounts that are not numbers, fractions or mixed numbers, and validates the
+// difficulty and language. Validation failures are returned as a *RecipeImportError. The
+// returned recipe is not approved.
+func ParseImportedRecipe(data []byte) (*models.Recipe, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, &RecipeImportError{Problems: []string{"body is not valid JSON: " + err.Error()}}
+	}
+	if err := validateModelRecipe(decoded); err != nil {
+		return nil, &RecipeImportError{Problems: schemaProblems(err)}
+	}
+
+	var in importedRecipe
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&in); err != nil {
+		return nil, &RecipeImportError{Problems: []string{err.Error()}}
+	}
+
+	var generated modelRecipe
+	if err := json.Unmarshal(data, &generated); err != nil {
+		return nil, &RecipeImportError{Problems: []string{err.Error()}}
+	}
+	var problems []string
+	if err := validateGeneratedRecipe(&generated); err != nil {
+		problems = append(problems, schemaProblems(err)...)
+	}
+	if strings.TrimSpace(in.Title) == "" {
+		problems = append(problems, "recipe.title is required")
+	}
+	for i, ing := range generated.ingredients() {
+		if ing.Amount == "" {
+			continue
+		}
+		if _, err := units.ParseAmount(ing.Amount); err != nil {
+			problems = append(problems, fmt.Sprintf("recipe.ingredients[%d].amount %q is not a number", i, ing.Amount))
+		}
+	}
+	difficulty, err := ValidateRecipeDifficulty(in.Difficulty)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	language, err := NormalizeRecipeLanguage(in.Language)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return nil, &RecipeImportError{Problems: problems}
+	}
+
+	recipe := &models.Recipe{
+		Title:             strings.TrimSpace(in.Title),
+		Description:       in.Description,
+		NutritionalInfo:   in.NutritionalInfo,
+		AllergyDisclaimer: in.AllergyDisclaimer,
+		Difficulty:        difficulty,
+		PrepTime:          in.PrepTime,
+		CookTime:          in.CookTime,
+		Servings:          in.Servings,
+		Language:          language,
+	}
+	if err := recipe.SetIngredients(generated.ingredients()); err != nil {
+		return nil, err
+	}
+	steps := in.Steps
+	for i := range steps {
+		if steps[i].Order == 0 {
+			steps[i].Order = i + 1
+		}
+	}
+	if err := recipe.SetSteps(steps); err != nil {
+		return nil, err
+	}
+	for _, name := range in.Cuisines {
+		recipe.Cuisines = append(recipe.Cuisines, models.Cuisine{Name: name})
+	}
+	for _, name := range in.Diets {
+		recipe.Diets = append(recipe.Diets, models.Diet{Name: name})
+	}
+	for _, name := range in.Appliances {
+		recipe.Appliances = append(recipe.Appliances, models.Appliance{Name: name})
+	}
+	for _, name := range in.Tags {
+		recipe.Tags = append(recipe.Tags, models.Tag{Name: name})
+	}
+	return recipe, nil
+}
+
+// schemaProblems returns the problems listed by a *ModelSchemaError in err's chain, or err's
+// message when it is some other error.
+func schemaProblems(err error) []string {
+	var schemaErr *ModelSchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr.Problems
+	}
+	return []string{err.Error()}
+}
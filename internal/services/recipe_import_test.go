@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImportedRecipe(t *testing.T) {
+	recipe, err := ParseImportedRecipe([]byte(`{
+		"title": "Pancakes",
+		"ingredients": [{"name": "flour", "amount": "1 1/2", "unit": "cups"}, {"name": "eggs", "amount": 2}],
+		"steps": [{"description": "Whisk."}, {"description": "Fry."}],
+		"difficulty": "Easy",
+		"tags": ["breakfast"],
+		"servings": 4
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if recipe.Title != "Pancakes" || recipe.Difficulty != "easy" || recipe.Servings != 4 || recipe.Approved {
+		t.Errorf("Unexpected recipe %+v", recipe)
+	}
+	ingredients, _ := recipe.GetIngredients()
+	if len(ingredients) != 2 || ingredients[1].Amount != "2" {
+		t.Errorf("Unexpected ingredients %+v", ingredients)
+	}
+	steps, _ := recipe.GetSteps()
+	if len(steps) != 2 || steps[1].Order != 2 {
+		t.Errorf("Expected step orders to be defaulted, got %+v", steps)
+	}
+	if len(recipe.Tags) != 1 || recipe.Tags[0].Name != "breakfast" {
+		t.Errorf("Unexpected tags %+v", recipe.Tags)
+	}
+}
+
+func TestParseImportedRecipeRejectsInvalidInput(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body, problem string
+	}{
+		"unknown field":     {`{"title": "T", "ingredients": [{"name": "a"}], "steps": [{"description": "b"}], "rating": 5}`, `unknown field "rating"`},
+		"malformed amount":  {`{"title": "T", "ingredients": [{"name": "a", "amount": "a handful"}], "steps": [{"description": "b"}]}`, "ingredients[0].amount"},
+		"negative amount":   {`{"title": "T", "ingredients": [{"name": "a", "amount": -1}], "steps": [{"description": "b"}]}`, "must not be negative"},
+		"missing title":     {`{"ingredients": [{"name": "a"}], "steps": [{"description": "b"}]}`, "recipe.title is required"},
+		"missing steps":     {`{"title": "T", "ingredients": [{"name": "a"}]}`, "recipe.steps is required"},
+		"wrong type":        {`{"title": "T", "ingredients": "flour", "steps": [{"description": "b"}]}`, "recipe.ingredients must be array"},
+		"bad difficulty":    {`{"title": "T", "ingredients": [{"name": "a"}], "steps": [{"description": "b"}], "difficulty": "impossible"}`, "unsupported difficulty"},
+		"not json":          {`title: T`, "not valid JSON"},
+		"approval flag set": {`{"title": "T", "ingredients": [{"name": "a"}], "steps": [{"description": "b"}], "approved": true}`, `unknown field "approved"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseImportedRecipe([]byte(tc.body))
+			var importErr *RecipeImportError
+			if !errors.As(err, &importErr) {
+				t.Fatalf("Expected a RecipeImportError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tc.problem) {
+				t.Errorf("Expected %q in %q", tc.problem, err.Error())
+			}
+		})
+	}
+}
+
+func TestSchemaProblems(t *testing.T) {
+	wrapped := fmt.Errorf("validating: %w", &ModelSchemaError{Problems: []string{"recipe.steps is required"}})
+	if got := schemaProblems(wrapped); !reflect.DeepEqual(got, []string{"recipe.steps is required"}) {
+		t.Errorf("Expected the wrapped schema problems, got %v", got)
+	}
+	if got := schemaProblems(errors.New("unexpected")); !reflect.DeepEqual(got, []string{"unexpected"}) {
+		t.Errorf("Expected the error message for other errors, got %v", got)
+	}
+}
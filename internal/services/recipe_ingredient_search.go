@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/parsers"
+)
+
+const (
+	// MaxSearchIngredients caps the number of ingredients accepted by a single ingredient search.
+	MaxSearchIngredients = 20
+	// ingredientSearchCandidates caps the recipes loaded from the database for ranking. The
+	// database returns those mentioning the most searched terms first.
+	ingredientSearchCandidates = 500
+	// missingIngredientPenalty weighs the share of a recipe's ingredients the user does not have
+	// against the share of their ingredients the recipe uses.
+	missingIngredientPenalty = 0.5
+)
+
+// IngredientMatch is a recipe found by an ingredient search together with how well it fits the
+// ingredients on hand.
+type IngredientMatch struct {
+	Recipe models.Recipe
+	// Matched lists the searched ingredients the recipe uses, as given in the search.
+	Matched []string
+	// Missing lists the recipe's ingredients that were not searched for, as named in the recipe.
+	Missing []string
+	// Score is the fraction of searched ingredients matched, less missingIngredientPenalty times
+	// the fraction of the recipe's ingredients missing.
+	Score float64
+}
+
+// SearchByIngredients finds recipes that use any of the given ingredients, best fit first. Names
+// are compared after NormalizeIngredient and in singular form, and an ingredient matches recipe
+// items that contain it as whole words, so "chicken" matches "chicken breast" and "eggs" "egg".
+func (s *recipeService) SearchByIngredients(ctx context.Context, ingredients []string, limit int) ([]IngredientMatch, error) {
+	searched := make([]string, 0, len(ingredients))
+	canonical := make([]string, 0, len(ingredients))
+	seen := make(map[string]bool)
+	for _, name := range ingredients {
+		name = strings.TrimSpace(name)
+		normalized := parsers.NormalizeIngredient(name)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		searched = append(searched, name)
+		canonical = append(canonical, normalized)
+	}
+	if len(canonical) == 0 {
+		return nil, fmt.Errorf("at least one ingredient is required")
+	}
+	if len(canonical) > MaxSearchIngredients {
+		return nil, fmt.Errorf("at most %d ingredients may be searched at once", MaxSearchIngredients)
+	}
+
+	// Look up both the given and canonical spellings so recipes naming a synonym are still
+	// found, in singular form so that "eggs" also finds "egg".
+	var terms []string
+	looked := make(map[string]bool)
+	for i := range canonical {
+		for _, term := range []string{singular(canonical[i]), singular(strings.ToLower(searched[i]))} {
+			if !looked[term] {
+				looked[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	candidates, err := s.repo.FindRecipesByIngredients(ctx, terms, ingredientSearchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []IngredientMatch
+	for _, recipe := range candidates {
+		match, ok := matchIngredients(recipe, searched, canonical)
+		if ok {
+			matches = append(matches, match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Recipe.Title < matches[j].Recipe.Title
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// matchIngredients scores a recipe against the searched ingredients. It reports false when the
+// recipe uses none of them, as the database match may have come from an amount or unit.
+func matchIngredients(recipe models.Recipe, searched, canonical []string) (IngredientMatch, bool) {
+	items, err := recipe.GetIngredients()
+	if err != nil || len(items) == 0 {
+		return IngredientMatch{}, false
+	}
+
+	names := make([]string, len(items))
+	for j, item := range items {
+		names[j] = singular(parsers.NormalizeIngredient(item.Name))
+	}
+
+	match := IngredientMatch{Recipe: recipe}
+	used := make([]bool, len(items))
+	for i, want := range canonical {
+		want = singular(want)
+		found := false
+		for j, name := range names {
+			if containsWord(name, want) {
+				used[j] = true
+				found = true
+			}
+		}
+		if found {
+			match.Matched = append(match.Matched, searched[i])
+		}
+	}
+	if len(match.Matched) == 0 {
+		return IngredientMatch{}, false
+	}
+	for j, item := range items {
+		if !used[j] {
+			match.Missing = append(match.Missing, item.Name)
+		}
+	}
+
+	match.Score = float64(len(match.Matched))/float64(len(canonical)) -
+		missingIngredientPenalty*float64(len(match.Missing))/float64(len(items))
+	return match, true
+}
+
+// singular naively strips plural endings from each word, so "tomatoes" and "tomato" compare equal.
+func singular(phrase string) string {
+	words := strings.Fields(phrase)
+	for i, word := range words {
+		switch {
+		case len(word) > 4 && strings.HasSuffix(word, "oes"):
+			words[i] = strings.TrimSuffix(word, "es")
+		case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// containsWord reports whether phrase contains word as a run of whole words.
+func containsWord(phrase, word string) bool {
+	return strings.Contains(" "+phrase+" ", " "+word+" ")
+}
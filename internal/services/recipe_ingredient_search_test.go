@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+)
+
+// ingredientRecipeRepository returns fixed candidates and records the search terms.
+type ingredientRecipeRepository struct {
+	repositories.RecipeRepository
+	recipes []models.Recipe
+	terms   []string
+}
+
+func (r *ingredientRecipeRepository) FindRecipesByIngredients(ctx context.Context, terms []string, limit int) ([]models.Recipe, error) {
+	r.terms = terms
+	return r.recipes, nil
+}
+
+func ingredientTestRecipe(title string, names ...string) models.Recipe {
+	recipe := models.Recipe{Title: title}
+	ingredients := make([]models.Ingredient, len(names))
+	for i, name := range names {
+		ingredients[i] = models.Ingredient{Name: name, Amount: "1"}
+	}
+	_ = recipe.SetIngredients(ingredients)
+	return recipe
+}
+
+func TestSearchByIngredients(t *testing.T) {
+	repo := &ingredientRecipeRepository{recipes: []models.Recipe{
+		ingredientTestRecipe("Stir Fry", "chicken breast", "scallions", "soy sauce", "rice"),
+		ingredientTestRecipe("Chicken and Rice", "chicken thighs", "rice"),
+		ingredientTestRecipe("Tomato Soup", "tomatoes", "cream"),
+		// Matched in the database only through an amount or unit.
+		ingredientTestRecipe("Pie", "flour", "butter"),
+	}}
+	service := &recipeService{repo: repo}
+
+	matches, err := service.SearchByIngredients(context.Background(), []string{"Chicken", "rice", "Green Onions", "chicken"}, 0)
+	if err != nil {
+		t.Fatalf("SearchByIngredients returned error: %v", err)
+	}
+
+	expectedTerms := []string{"chicken", "rice", "green onion"}
+	if !reflect.DeepEqual(repo.terms, expectedTerms) {
+		t.Errorf("Expected search terms %v, got %v", expectedTerms, repo.terms)
+	}
+
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	if matches[0].Recipe.Title != "Stir Fry" {
+		t.Errorf("Expected the recipe using every ingredient first, got %s", matches[0].Recipe.Title)
+	}
+	if !reflect.DeepEqual(matches[0].Matched, []string{"Chicken", "rice", "Green Onions"}) {
+		t.Errorf("Unexpected matched ingredients: %v", matches[0].Matched)
+	}
+	if !reflect.DeepEqual(matches[0].Missing, []string{"soy sauce"}) {
+		t.Errorf("Unexpected missing ingredients: %v", matches[0].Missing)
+	}
+	if want := 1 - missingIngredientPenalty/4; matches[0].Score != want {
+		t.Errorf("Expected score %v, got %v", want, matches[0].Score)
+	}
+	if matches[1].Recipe.Title != "Chicken and Rice" || len(matches[1].Missing) != 0 {
+		t.Errorf("Unexpected second match: %s missing %v", matches[1].Recipe.Title, matches[1].Missing)
+	}
+}
+
+func TestSearchByIngredientsMatchesPlurals(t *testing.T) {
+	repo := &ingredientRecipeRepository{recipes: []models.Recipe{ingredientTestRecipe("Tomato Soup", "tomatoes", "cream")}}
+	service := &recipeService{repo: repo}
+
+	matches, err := service.SearchByIngredients(context.Background(), []string{"tomato"}, 10)
+	if err != nil {
+		t.Fatalf("SearchByIngredients returned error: %v", err)
+	}
+	if len(matches) != 1 || !reflect.DeepEqual(matches[0].Missing, []string{"cream"}) {
+		t.Errorf("Expected tomatoes to match tomato, got %+v", matches)
+	}
+}
+
+func TestSearchByIngredientsLimit(t *testing.T) {
+	repo := &ingredientRecipeRepository{recipes: []models.Recipe{
+		ingredientTestRecipe("A", "egg"),
+		ingredientTestRecipe("B", "egg", "milk"),
+	}}
+	service := &recipeService{repo: repo}
+
+	matches, err := service.SearchByIngredients(context.Background(), []string{"eggs"}, 1)
+	if err != nil {
+		t.Fatalf("SearchByIngredients returned error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Recipe.Title != "A" {
+		t.Errorf("Expected only the best match, got %+v", matches)
+	}
+}
+
+func TestSearchByIngredientsRequiresIngredients(t *testing.T) {
+	service := &recipeService{repo: &ingredientRecipeRepository{}}
+	if _, err := service.SearchByIngredients(context.Background(), []string{" "}, 10); err == nil {
+		t.Error("Expected an error for a search without ingredients")
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRecipeLanguage is used when a request does not specify a language.
+const DefaultRecipeLanguage = "en"
+
+// SupportedRecipeLanguages maps the language codes recipes can be generated in to their display names.
+var SupportedRecipeLanguages = map[string]string{
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"ja": "Japanese",
+	"zh": "Chinese",
+}
+
+// NormalizeRecipeLanguage resolves a language code, locale (e.g. "es-MX"), Accept-Language
+// header value or English language name to a supported language code.
+// An empty value resolves to DefaultRecipeLanguage.
+func NormalizeRecipeLanguage(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return DefaultRecipeLanguage, nil
+	}
+
+	// Use the first preference of an Accept-Language header, ignoring quality values.
+	tag := strings.TrimSpace(strings.Split(strings.Split(value, ",")[0], ";")[0])
+	code := strings.ToLower(strings.SplitN(strings.ReplaceAll(tag, "_", "-"), "-", 2)[0])
+	if _, ok := SupportedRecipeLanguages[code]; ok {
+		return code, nil
+	}
+	for code, name := range SupportedRecipeLanguages {
+		if strings.EqualFold(name, tag) {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language: %s", value)
+}
+
+// languageInstruction returns the prompt instruction asking the model to respond in the given language.
+func languageInstruction(language string) string {
+	name, ok := SupportedRecipeLanguages[language]
+	if !ok {
+		name = SupportedRecipeLanguages[DefaultRecipeLanguage]
+	}
+	return "Respond in " + name + "."
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/errors"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/prompts"
+)
+
+// Nutrition is the per-serving nutrition computed by the external model. Calories are in kcal,
+// the macros in grams.
+type Nutrition struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Carbs    float64 `json:"carbs"`
+	Fat      float64 `json:"fat"`
+}
+
+// String formats the nutrition the way it is stored in models.Recipe.NutritionalInfo.
+func (n Nutrition) String() string {
+	return fmt.Sprintf("Per serving: %s kcal, %s g protein, %s g carbs, %s g fat",
+		formatMacro(n.Calories), formatMacro(n.Protein), formatMacro(n.Carbs), formatMacro(n.Fat))
+}
+
+// formatMacro rounds a value to one decimal place, dropping a trailing ".0".
+func formatMacro(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
+
+var (
+	// caloriesPattern matches "350 kcal", "350 calories" or "calories: 350".
+	caloriesPattern = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(?:kcal|calories|cal)\b|\bcalories\s*:?\s*(\d+(?:\.\d+)?)`)
+	// macroPatterns match "12 g protein", "12g of protein" or "protein: 12" for each macro.
+	macroPatterns = map[string]*regexp.Regexp{
+		"protein": macroPattern("protein"),
+		"carbs":   macroPattern("carb(?:s|ohydrates?)"),
+		"fat":     macroPattern("fats?"),
+	}
+)
+
+// macroPattern matches a gram amount written before or after the macro name.
+func macroPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*g\s+(?:of\s+)?` + name + `\b|\b` + name + `\s*:?\s*(\d+(?:\.\d+)?)`)
+}
+
+// ParseNutritionalInfo reads the per-serving nutrition from a recipe's NutritionalInfo, either as
+// written by Nutrition.String or in similar free text returned by the model. It reports false when
+// no calorie count is found; macros that are not mentioned are zero.
+func ParseNutritionalInfo(info string) (Nutrition, bool) {
+	calories, ok := firstNumber(caloriesPattern, info)
+	if !ok {
+		return Nutrition{}, false
+	}
+	protein, _ := firstNumber(macroPatterns["protein"], info)
+	carbs, _ := firstNumber(macroPatterns["carbs"], info)
+	fat, _ := firstNumber(macroPatterns["fat"], info)
+	return Nutrition{Calories: calories, Protein: protein, Carbs: carbs, Fat: fat}, true
+}
+
+// firstNumber returns the number captured by the first match of pattern, from whichever of its
+// alternatives matched.
+func firstNumber(pattern *regexp.Regexp, text string) (float64, bool) {
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	for _, group := range match[1:] {
+		if group != "" {
+			n, err := strconv.ParseFloat(group, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// nutritionMacros lists the keys the model must return, in the order problems are reported.
+var nutritionMacros = []string{"calories", "protein", "carbs", "fat"}
+
+// RecomputeNutrition asks the external model for the per-serving nutrition of the recipe's
+// current ingredients and servings.
+func (s *recipeResolutionService) RecomputeNutrition(ctx context.Context, recipe *models.Recipe) (*Nutrition, error) {
+	if recipe == nil {
+		return nil, errors.NewValidationError("recipe cannot be nil")
+	}
+	ingredients, err := recipe.GetIngredients()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipe ingredients: %w", err)
+	}
+	if len(ingredients) == 0 {
+		return nil, errors.NewValidationError("recipe has no ingredients")
+	}
+	current, err := json.Marshal(ingredients)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := prompts.RenderNutritionPrompt(recipe.Servings, current)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.generate(ctx, prompt, integrations.GenerationOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return parseNutrition(response)
+}
+
+// parseNutrition extracts the nutrition object from a model response. Each macro may be a
+// number or a numeric string with a unit, such as "12 g"; missing or negative values are
+// reported as a *ModelSchemaError.
+func parseNutrition(response string) (*Nutrition, error) {
+	raw, err := extractJSONObject(response)
+	if err != nil {
+		return nil, err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse model response: %w", err)
+	}
+
+	var problems []string
+	values := make(map[string]float64, len(nutritionMacros))
+	for _, key := range nutritionMacros {
+		value, ok := macroValue(decoded[key])
+		switch {
+		case decoded[key] == nil:
+			problems = append(problems, "nutrition."+key+" is required")
+		case !ok:
+			problems = append(problems, "nutrition."+key+" must be a number")
+		case value < 0:
+			problems = append(problems, "nutrition."+key+" must not be negative")
+		}
+		values[key] = value
+	}
+	if len(problems) > 0 {
+		return nil, &ModelSchemaError{Problems: problems}
+	}
+	return &Nutrition{Calories: values["calories"], Protein: values["protein"], Carbs: values["carbs"], Fat: values["fat"]}, nil
+}
+
+// macroValue reads a macro given as a JSON number or as a string such as "350 kcal" or "12g".
+func macroValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		number := strings.TrimRightFunc(strings.TrimSpace(v), func(r rune) bool {
+			return r == ' ' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		})
+		n, err := strconv.ParseFloat(number, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
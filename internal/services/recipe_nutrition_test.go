@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+)
+
+func TestRecomputeNutritionWithStubbedModel(t *testing.T) {
+	var prompt string
+	s := &recipeResolutionService{generate: func(_ context.Context, p string, _ integrations.GenerationOptions) (string, error) {
+		prompt = p
+		return "```json\n{\"calories\": 350, \"protein\": \"12 g\", \"carbs\": \"40.5g\", \"fat\": 0}\n```", nil
+	}}
+	recipe := &models.Recipe{Title: "Pancakes", Servings: 4}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "250", Unit: "g"}})
+
+	nutrition, err := s.RecomputeNutrition(context.Background(), recipe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *nutrition != (Nutrition{Calories: 350, Protein: 12, Carbs: 40.5, Fat: 0}) {
+		t.Errorf("Unexpected nutrition %+v", nutrition)
+	}
+	if got := nutrition.String(); got != "Per serving: 350 kcal, 12 g protein, 40.5 g carbs, 0 g fat" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+	if !strings.Contains(prompt, "4 servings") || !strings.Contains(prompt, `"name":"flour"`) {
+		t.Errorf("Expected the servings and ingredients in the prompt, got:\n%s", prompt)
+	}
+}
+
+func TestParseNutritionRejectsInvalidMacros(t *testing.T) {
+	_, err := parseNutrition(`{"calories": -10, "protein": "lots", "carbs": 5}`)
+	var schemaErr *ModelSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected *ModelSchemaError, got %v", err)
+	}
+	want := []string{"nutrition.calories must not be negative", "nutrition.protein must be a number", "nutrition.fat is required"}
+	if strings.Join(schemaErr.Problems, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Expected problems %v, got %v", want, schemaErr.Problems)
+	}
+}
+
+func TestRecomputeNutritionRequiresIngredients(t *testing.T) {
+	s := &recipeResolutionService{generate: func(context.Context, string, integrations.GenerationOptions) (string, error) {
+		t.Fatal("Did not expect a model call")
+		return "", nil
+	}}
+	recipe := &models.Recipe{Title: "Empty"}
+	_ = recipe.SetIngredients([]models.Ingredient{})
+	if _, err := s.RecomputeNutrition(context.Background(), recipe); err == nil {
+		t.Error("Expected an error for a recipe without ingredients")
+	}
+}
+
+func TestParseNutritionalInfo(t *testing.T) {
+	cases := map[string]Nutrition{
+		"Per serving: 350 kcal, 12 g protein, 40.5 g carbs, 0 g fat":       {Calories: 350, Protein: 12, Carbs: 40.5, Fat: 0},
+		"Calories: 520, Protein: 30g, Carbohydrates: 45g, Fat: 18g":        {Calories: 520, Protein: 30, Carbs: 45, Fat: 18},
+		"About 410 calories per serving with 22g of protein and 9 g fats.": {Calories: 410, Protein: 22, Fat: 9},
+	}
+	for info, want := range cases {
+		got, ok := ParseNutritionalInfo(info)
+		if !ok || got != want {
+			t.Errorf("ParseNutritionalInfo(%q) = %+v, %v; want %+v", info, got, ok, want)
+		}
+	}
+	if _, ok := ParseNutritionalInfo("High in protein"); ok {
+		t.Error("Expected no nutrition without a calorie count")
+	}
+}
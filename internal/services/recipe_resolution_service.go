@@ -3,13 +3,19 @@ package services
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
 	"github.com/pageza/alchemorsel-v1/internal/errors"
 	"github.com/pageza/alchemorsel-v1/internal/integrations"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"github.com/pageza/alchemorsel-v1/internal/parsers"
+	"github.com/pageza/alchemorsel-v1/internal/prompts"
+	"go.uber.org/zap"
 )
 
 // RecipeResolutionService defines the functions for the multi-step recipe resolution flow.
@@ -23,21 +29,52 @@ type RecipeResolutionService interface {
 	// FindCloseMatches should return a list of close matches from the recipe database based on the parsed query.
 	FindCloseMatches(ctx context.Context, parsedQuery *parsers.ParsedQuery) ([]string, error)
 	// BuildCompositePrompt creates a composite prompt using the user's query, prompt instructions,
-	// expected response format, and additional profile data (e.g., allergen and diet restrictions).
-	BuildCompositePrompt(query string, promptInstructions string, expectedResponseFormat string, profile map[string]interface{}) (string, error)
+	// expected response format, additional profile data (e.g., allergen and diet restrictions),
+	// and the language code the recipe should be written in.
+	BuildCompositePrompt(query string, promptInstructions string, expectedResponseFormat string, profile map[string]interface{}, language string) (string, error)
 	// ResolveRecipeByModel sends the composite prompt to the external model and returns
-	// a candidate recipe along with alternative proposals.
-	ResolveRecipeByModel(ctx context.Context, compositePrompt string) (string, []string, error)
+	// a candidate recipe along with alternative proposals. opts overrides the model parameters.
+	// A candidate that does not match RecipeSchema after one retry yields a *ModelSchemaError.
+	// The candidate is the model's message content, i.e. the recipe JSON the model wrote, not the
+	// chat completion around it.
+	ResolveRecipeByModel(ctx context.Context, compositePrompt string, opts integrations.GenerationOptions) (string, []string, error)
+	// SubstituteIngredient asks the external model to replace a single ingredient in the recipe,
+	// adjusting affected amounts and steps, and returns the modified (unsaved) recipe.
+	SubstituteIngredient(ctx context.Context, recipe *models.Recipe, ingredient string, reason string) (*models.Recipe, error)
+	// ExpandRecipe asks the external model to enrich the recipe with more detailed steps, tips and
+	// nutritional information, preserving the title and ingredients unless allowCoreChanges is set.
+	ExpandRecipe(ctx context.Context, recipe *models.Recipe, allowCoreChanges bool) (*models.Recipe, error)
+	// GenerateRecipe asks the external model for a new recipe matching query, using the default
+	// prompt instructions and response format, and returns it unsaved. Queries failing the
+	// configured Moderator return ErrQueryRejected without calling the model.
+	GenerateRecipe(ctx context.Context, query string) (*models.Recipe, error)
+	// RecomputeNutrition asks the external model for the per-serving nutrition of the recipe's
+	// current ingredients and servings.
+	RecomputeNutrition(ctx context.Context, recipe *models.Recipe) (*Nutrition, error)
+	// SuggestModelTags asks the external model for up to MaxModelTagSuggestions descriptive tags
+	// the recipe does not have yet.
+	SuggestModelTags(ctx context.Context, recipe *models.Recipe) ([]string, error)
 }
 
 // recipeResolutionService is a default implementation of RecipeResolutionService.
 // All methods are currently scaffolded with TODO comments.
 
-type recipeResolutionService struct{}
+type recipeResolutionService struct {
+	// generate sends a prompt to the external model; replaced in tests.
+	generate func(ctx context.Context, prompt string, opts integrations.GenerationOptions) (string, error)
+	// moderator checks queries in GenerateRecipe before any prompt is built; nil disables it.
+	moderator Moderator
+}
 
 // NewRecipeResolutionService creates a new instance of RecipeResolutionService.
 func NewRecipeResolutionService() RecipeResolutionService {
-	return &recipeResolutionService{}
+	return NewRecipeResolutionServiceWithModerator(nil)
+}
+
+// NewRecipeResolutionServiceWithModerator creates a RecipeResolutionService that rejects
+// GenerateRecipe queries failing moderator. A nil moderator lets every query through.
+func NewRecipeResolutionServiceWithModerator(moderator Moderator) RecipeResolutionService {
+	return &recipeResolutionService{generate: callExternalAPI, moderator: moderator}
 }
 
 func (s *recipeResolutionService) FindExactMatch(ctx context.Context, parsedQuery *parsers.ParsedQuery) (string, error) {
@@ -113,14 +150,16 @@ func (s *recipeResolutionService) FindCloseMatches(ctx context.Context, parsedQu
 	return nil, nil
 }
 
-// Define hardcoded defaults for prompt instructions and expected response format
-const (
-	DefaultExpectedResponseFormat = "{\"title\": string, \"description\": string, \"ingredients\": [{\"name\": string, \"amount\": number, \"unit\": string}], \"steps\": [{\"order\": number, \"description\": string}], \"nutritional_info\": string, \"allergy_disclaimer\": string, \"cuisines\": [string], \"diets\": [string], \"appliances\": [string], \"tags\": [string], \"images\": [string], \"difficulty\": string, \"prep_time\": number, \"cooking_time\": number, \"servings\": number, \"approved\": boolean}"
-	DefaultPromptInstructions     = "Act as a professional personal chef. Provide detailed, step-by-step recipes with clear instructions and precise measurements."
+// Defaults for prompt instructions and expected response format, loaded from the prompt templates.
+var (
+	DefaultExpectedResponseFormat = prompts.DefaultResponseFormat()
+	DefaultPromptInstructions     = prompts.DefaultInstructions()
 )
 
-// BuildCompositePrompt constructs the composite prompt using the user's query and profile details with hardcoded prompt instructions and expected response format.
-func (s *recipeResolutionService) BuildCompositePrompt(query string, promptInstructions string, expectedResponseFormat string, profile map[string]interface{}) (string, error) {
+// BuildCompositePrompt renders the generation prompt template with the user's query, the constraints
+// parsed from it and profile details, falling back to the default prompt instructions and expected
+// response format.
+func (s *recipeResolutionService) BuildCompositePrompt(query string, promptInstructions string, expectedResponseFormat string, profile map[string]interface{}, language string) (string, error) {
 	// Check if promptInstructions and expectedResponseFormat are provided; if not, use the defaults
 	if promptInstructions == "" {
 		promptInstructions = DefaultPromptInstructions
@@ -130,28 +169,280 @@ func (s *recipeResolutionService) BuildCompositePrompt(query string, promptInstr
 		expectedResponseFormat = DefaultExpectedResponseFormat
 		fmt.Println("ExpectedResponseFormat missing in request, using default expected response format")
 	}
-
-	compositePrompt := "=== Composite Prompt for Recipe Resolution ===\n\n"
-	compositePrompt += "User Query:\n" + query + "\n\n"
-	compositePrompt += "Prompt Instructions:\n" + promptInstructions + "\n\n"
-	compositePrompt += "Expected Response Format:\n" + expectedResponseFormat + "\n\n"
-	compositePrompt += "User Profile:\n"
-	for key, value := range profile {
-		compositePrompt += " - " + key + ": " + fmt.Sprintf("%v", value) + "\n"
+	language, err := NormalizeRecipeLanguage(language)
+	if err != nil {
+		return "", errors.NewValidationError(err.Error())
 	}
-	compositePrompt += "\n=== End of Prompt ==="
-	return compositePrompt, nil
+
+	constraints, preferences := queryConstraints(query)
+	return prompts.RenderGeneratePrompt(prompts.GenerateData{
+		Query:               query,
+		Constraints:         constraints,
+		Preferences:         preferences,
+		Instructions:        promptInstructions,
+		ResponseFormat:      expectedResponseFormat,
+		LanguageInstruction: languageInstruction(language),
+		Profile:             profile,
+	})
 }
 
-func (s *recipeResolutionService) ResolveRecipeByModel(ctx context.Context, compositePrompt string) (string, []string, error) {
-	response, err := callExternalAPI(compositePrompt)
+func (s *recipeResolutionService) ResolveRecipeByModel(ctx context.Context, compositePrompt string, opts integrations.GenerationOptions) (string, []string, error) {
+	response, _, err := s.generateModelRecipe(ctx, compositePrompt, opts)
 	if err != nil {
 		return "", nil, err
 	}
-	// For now, just return the raw response as the candidate and an empty slice for alternatives.
+	// For now, just return the model's recipe text as the candidate and an empty slice for alternatives.
 	return response, []string{}, nil
 }
 
+// GenerateRecipe renders the generation prompt for query and converts the validated model output
+// into a recipe. The title falls back to the query when the model leaves it out.
+func (s *recipeResolutionService) GenerateRecipe(ctx context.Context, query string) (*models.Recipe, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.NewValidationError("query cannot be empty")
+	}
+	if utf8.RuneCountInString(query) > dtos.MaxQueryLength {
+		return nil, errors.NewValidationError(fmt.Sprintf("query must not exceed %d characters", dtos.MaxQueryLength))
+	}
+	if s.moderator != nil {
+		if err := s.moderator.Check(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	constraints, preferences := queryConstraints(query)
+	prompt, err := prompts.RenderGeneratePrompt(prompts.GenerateData{
+		Query:               query,
+		Constraints:         constraints,
+		Preferences:         preferences,
+		LanguageInstruction: languageInstruction(DefaultRecipeLanguage),
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, generated, err := s.generateModelRecipe(ctx, prompt, integrations.GenerationOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	recipe := &models.Recipe{
+		Title:           strings.TrimSpace(generated.Title),
+		Description:     generated.Description,
+		NutritionalInfo: generated.NutritionalInfo,
+		Language:        DefaultRecipeLanguage,
+	}
+	if recipe.Title == "" {
+		recipe.Title = strings.TrimSpace(query)
+	}
+	if len(generated.Tips) > 0 {
+		recipe.Description = strings.TrimSpace(recipe.Description + "\n\nTips:\n- " + strings.Join(generated.Tips, "\n- "))
+	}
+	if err := recipe.SetIngredients(generated.ingredients()); err != nil {
+		return nil, err
+	}
+	if err := recipe.SetSteps(generated.Steps); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// SubstituteIngredient builds a focused modification prompt that swaps a single ingredient and
+// parses the model's JSON response into a copy of the recipe.
+func (s *recipeResolutionService) SubstituteIngredient(ctx context.Context, recipe *models.Recipe, ingredient string, reason string) (*models.Recipe, error) {
+	instructions := fmt.Sprintf("Replace only the ingredient %q in the recipe below", ingredient)
+	if reason != "" {
+		instructions += fmt.Sprintf(" so that it is %s", reason)
+	}
+	instructions += ". Adjust the amounts and any steps affected by the substitution and leave everything else unchanged."
+
+	modified, generated, err := s.modifyByModel(ctx, recipe, instructions)
+	if err != nil {
+		return nil, err
+	}
+	if generated.Title != "" {
+		modified.Title = generated.Title
+	}
+	if generated.Description != "" {
+		modified.Description = generated.Description
+	}
+	if err := modified.SetIngredients(generated.ingredients()); err != nil {
+		return nil, err
+	}
+	if err := modified.SetSteps(generated.Steps); err != nil {
+		return nil, err
+	}
+	return modified, nil
+}
+
+// ExpandRecipe asks the external model for more detailed steps, tips and nutritional information.
+// Unless allowCoreChanges is set, the original title and ingredients are kept as-is.
+func (s *recipeResolutionService) ExpandRecipe(ctx context.Context, recipe *models.Recipe, allowCoreChanges bool) (*models.Recipe, error) {
+	instructions := "Expand the recipe below: rewrite the steps with more detail, add practical cooking tips and provide nutritional information per serving."
+	if !allowCoreChanges {
+		instructions += " Keep the title and the ingredients exactly as they are."
+	}
+	instructions += " Include a \"tips\" array of strings and a \"nutritional_info\" string in the JSON."
+
+	expanded, generated, err := s.modifyByModel(ctx, recipe, instructions)
+	if err != nil {
+		return nil, err
+	}
+	if allowCoreChanges {
+		if generated.Title != "" {
+			expanded.Title = generated.Title
+		}
+		if err := expanded.SetIngredients(generated.ingredients()); err != nil {
+			return nil, err
+		}
+	}
+	if generated.Description != "" {
+		expanded.Description = generated.Description
+	}
+	if len(generated.Tips) > 0 {
+		expanded.Description = strings.TrimSpace(expanded.Description + "\n\nTips:\n- " + strings.Join(generated.Tips, "\n- "))
+	}
+	if generated.NutritionalInfo != "" {
+		expanded.NutritionalInfo = generated.NutritionalInfo
+	}
+	if err := expanded.SetSteps(generated.Steps); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// modifyByModel sends the recipe together with the modification instructions to the external model.
+// It returns an unsaved copy of the recipe alongside the parsed model output for the caller to merge.
+func (s *recipeResolutionService) modifyByModel(ctx context.Context, recipe *models.Recipe, instructions string) (*models.Recipe, *modelRecipe, error) {
+	if recipe == nil {
+		return nil, nil, errors.NewValidationError("recipe cannot be nil")
+	}
+	ingredients, err := recipe.GetIngredients()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read recipe ingredients: %w", err)
+	}
+	steps, err := recipe.GetSteps()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read recipe steps: %w", err)
+	}
+
+	current, err := json.Marshal(map[string]interface{}{
+		"title":            recipe.Title,
+		"description":      recipe.Description,
+		"ingredients":      ingredients,
+		"steps":            steps,
+		"nutritional_info": recipe.NutritionalInfo,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prompt, err := prompts.RenderModifyPrompt(instructions, current)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, generated, err := s.generateModelRecipe(ctx, prompt, integrations.GenerationOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modified := *recipe
+	modified.ID = ""
+	modified.Embedding = nil
+	return &modified, generated, nil
+}
+
+// generateModelRecipe sends prompt to the external model and validates the response against
+// RecipeSchema, returning the raw response alongside the parsed recipe. Malformed output is
+// usually a one-off, so the model is asked once more before a *ModelSchemaError is returned.
+func (s *recipeResolutionService) generateModelRecipe(ctx context.Context, prompt string, opts integrations.GenerationOptions) (string, *modelRecipe, error) {
+	for attempt := 1; ; attempt++ {
+		response, err := s.generate(ctx, prompt, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		generated, err := parseModelRecipe(response)
+		if err == nil {
+			return response, generated, nil
+		}
+		zap.L().Warn("Model response did not match the recipe schema",
+			zap.Int("attempt", attempt), zap.String("response", response), zap.Error(err))
+		var schemaErr *ModelSchemaError
+		if !stderrors.As(err, &schemaErr) || attempt == 2 {
+			return "", nil, err
+		}
+	}
+}
+
+// modelRecipe is the subset of the expected response format used when parsing model output.
+type modelRecipe struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	NutritionalInfo string   `json:"nutritional_info"`
+	Tips            []string `json:"tips"`
+	Ingredients     []struct {
+		Name   string      `json:"name"`
+		Amount interface{} `json:"amount"`
+		Unit   string      `json:"unit"`
+	} `json:"ingredients"`
+	Steps []models.Step `json:"steps"`
+}
+
+// ingredients converts the parsed ingredients, whose amounts may be numbers or strings, to models.
+func (r *modelRecipe) ingredients() []models.Ingredient {
+	ingredients := make([]models.Ingredient, 0, len(r.Ingredients))
+	for _, ing := range r.Ingredients {
+		amount := ""
+		if ing.Amount != nil {
+			amount = fmt.Sprintf("%v", ing.Amount)
+		}
+		ingredients = append(ingredients, models.Ingredient{Name: ing.Name, Amount: amount, Unit: ing.Unit})
+	}
+	return ingredients
+}
+
+// extractJSONObject returns the outermost JSON object in a model response, dropping any
+// surrounding text or code fences.
+func extractJSONObject(response string) ([]byte, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("model response did not contain a JSON object")
+	}
+	return []byte(response[start : end+1]), nil
+}
+
+// parseModelRecipe extracts the JSON recipe from a model response, tolerating surrounding text or code fences.
+// The recipe must conform to RecipeSchema and pass validateGeneratedRecipe; a *ModelSchemaError
+// describes any mismatch.
+func parseModelRecipe(response string) (*modelRecipe, error) {
+	raw, err := extractJSONObject(response)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse model response: %w", err)
+	}
+	if err := validateModelRecipe(decoded); err != nil {
+		return nil, err
+	}
+
+	var recipe modelRecipe
+	if err := json.Unmarshal(raw, &recipe); err != nil {
+		return nil, fmt.Errorf("failed to parse model response: %w", err)
+	}
+	if err := validateGeneratedRecipe(&recipe); err != nil {
+		return nil, err
+	}
+	// Default optional fields the schema allows the model to omit.
+	for i := range recipe.Steps {
+		if recipe.Steps[i].Order == 0 {
+			recipe.Steps[i].Order = i + 1
+		}
+	}
+	return &recipe, nil
+}
+
 // ResolveRecipe searches for a matching recipe; if not found, generates one using external APIs.
 func ResolveRecipe(query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error) {
 	// Construct a prompt by prefixing the user's request with instructions
@@ -168,7 +459,7 @@ func ResolveRecipe(query string, attributes map[string]interface{}) (*models.Rec
 	}
 
 	// Call the external API to generate the recipe
-	generatedResponse, err := callExternalAPI(prompt)
+	generatedResponse, err := callExternalAPI(context.Background(), prompt, integrations.GenerationOptions{})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -185,6 +476,6 @@ func ResolveRecipe(query string, attributes map[string]interface{}) (*models.Rec
 }
 
 // Consolidate DeepSeek integration: delegate the call to integrations.GenerateRecipe
-func callExternalAPI(prompt string) (string, error) {
-	return integrations.GenerateRecipe(prompt, make(map[string]interface{}))
+func callExternalAPI(ctx context.Context, prompt string, opts integrations.GenerationOptions) (string, error) {
+	return integrations.GenerateRecipeWithContext(ctx, prompt, make(map[string]interface{}), opts)
 }
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+)
+
+func TestBuildCompositePromptLanguageInstruction(t *testing.T) {
+	s := NewRecipeResolutionService()
+
+	prompt, err := s.BuildCompositePrompt("paella", "", "", nil, "es")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Respond in Spanish.") {
+		t.Errorf("Expected prompt to contain Spanish language instruction, got:\n%s", prompt)
+	}
+}
+
+func TestBuildCompositePromptDefaultsToEnglish(t *testing.T) {
+	s := NewRecipeResolutionService()
+
+	prompt, err := s.BuildCompositePrompt("pancakes", "", "", nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Respond in English.") {
+		t.Errorf("Expected prompt to default to English, got:\n%s", prompt)
+	}
+}
+
+func TestBuildCompositePromptRejectsUnsupportedLanguage(t *testing.T) {
+	s := NewRecipeResolutionService()
+
+	if _, err := s.BuildCompositePrompt("pancakes", "", "", nil, "klingon"); err == nil {
+		t.Error("Expected error for unsupported language, got nil")
+	}
+}
+
+func TestNormalizeRecipeLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":                        "en",
+		"FR":                      "fr",
+		"es-MX":                   "es",
+		"de-DE,de;q=0.9,en;q=0.8": "de",
+		"Italian":                 "it",
+	}
+	for input, expected := range cases {
+		got, err := NormalizeRecipeLanguage(input)
+		if err != nil {
+			t.Errorf("NormalizeRecipeLanguage(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("NormalizeRecipeLanguage(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestSubstituteIngredientWithStubbedModel(t *testing.T) {
+	var prompt string
+	s := &recipeResolutionService{generate: func(_ context.Context, p string, _ integrations.GenerationOptions) (string, error) {
+		prompt = p
+		return "```json\n" + `{"title": "Dairy-Free Pancakes", "ingredients": [{"name": "flour", "amount": 2, "unit": "cups"}, {"name": "olive oil", "amount": "3", "unit": "tbsp"}], "steps": [{"order": 1, "description": "Whisk flour with olive oil."}]}` + "\n```", nil
+	}}
+
+	recipe := &models.Recipe{ID: "recipe-1", Title: "Pancakes"}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "2", Unit: "cups"}, {Name: "butter", Amount: "3", Unit: "tbsp"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Whisk flour with melted butter."}})
+
+	modified, err := s.SubstituteIngredient(context.Background(), recipe, "butter", "dairy-free")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, `"butter"`) || !strings.Contains(prompt, "dairy-free") {
+		t.Errorf("Expected prompt to name the ingredient and reason, got:\n%s", prompt)
+	}
+	if modified.ID != "" {
+		t.Errorf("Expected modified recipe to be unsaved, got ID %q", modified.ID)
+	}
+	if modified.Title != "Dairy-Free Pancakes" {
+		t.Errorf("Expected title from model response, got %q", modified.Title)
+	}
+	ingredients, _ := modified.GetIngredients()
+	if len(ingredients) != 2 || ingredients[1].Name != "olive oil" || ingredients[0].Amount != "2" {
+		t.Errorf("Unexpected ingredients: %+v", ingredients)
+	}
+	if original, _ := recipe.GetIngredients(); original[1].Name != "butter" {
+		t.Error("Expected original recipe to be left unchanged")
+	}
+}
+
+// useDeepSeekServer points the real DeepSeek client at a local server that answers every call
+// with a chat completion whose message content is content, as the live API does.
+func useDeepSeekServer(t *testing.T, content string) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     "chatcmpl-1",
+			"object": "chat.completion",
+			"model":  "deepseek-chat",
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"message":       map[string]string{"role": "assistant", "content": content},
+				"finish_reason": "stop",
+			}},
+			"usage": map[string]int{"prompt_tokens": 120, "completion_tokens": 80, "total_tokens": 200},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("DEEPSEEK_API_KEY", "test-key")
+	t.Setenv("DEEPSEEK_API_URL", server.URL)
+	if err := integrations.LoadDeepSeekCredentials(); err != nil {
+		t.Fatalf("Failed to load DeepSeek credentials: %v", err)
+	}
+	t.Cleanup(func() { _ = integrations.LoadDeepSeekCredentials() })
+}
+
+func TestSubstituteIngredientWithDeepSeekResponse(t *testing.T) {
+	useDeepSeekServer(t, "```json\n"+`{"title": "Dairy-Free Pancakes", "ingredients": [{"name": "flour", "amount": 2, "unit": "cups"}, {"name": "olive oil", "amount": 3, "unit": "tbsp"}], "steps": [{"order": 1, "description": "Whisk flour with olive oil."}]}`+"\n```")
+	s := NewRecipeResolutionService()
+
+	recipe := &models.Recipe{ID: "recipe-1", Title: "Pancakes"}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "2", Unit: "cups"}, {Name: "butter", Amount: "3", Unit: "tbsp"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Whisk flour with melted butter."}})
+
+	modified, err := s.SubstituteIngredient(context.Background(), recipe, "butter", "dairy-free")
+	if err != nil {
+		t.Fatalf("Expected the chat completion to be unwrapped, got %v", err)
+	}
+	if modified.Title != "Dairy-Free Pancakes" {
+		t.Errorf("Expected title from the message content, got %q", modified.Title)
+	}
+	if ingredients, _ := modified.GetIngredients(); len(ingredients) != 2 || ingredients[1].Name != "olive oil" {
+		t.Errorf("Unexpected ingredients: %+v", ingredients)
+	}
+}
+
+func TestSubstituteIngredientRejectsInvalidModelResponse(t *testing.T) {
+	s := &recipeResolutionService{generate: func(context.Context, string, integrations.GenerationOptions) (string, error) {
+		return "Sorry, I cannot help with that.", nil
+	}}
+	recipe := &models.Recipe{Title: "Pancakes"}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "butter", Amount: "3", Unit: "tbsp"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Melt butter."}})
+
+	if _, err := s.SubstituteIngredient(context.Background(), recipe, "butter", ""); err == nil {
+		t.Error("Expected error for non-JSON model response, got nil")
+	}
+}
+
+func TestExpandRecipeWithStubbedModel(t *testing.T) {
+	response := `{"title": "Fancy Pancakes", "description": "Light and fluffy.", "nutritional_info": "350 kcal per serving", "tips": ["Rest the batter for 10 minutes."], "ingredients": [{"name": "flour", "amount": 250, "unit": "g"}, {"name": "sugar", "amount": 1, "unit": "tbsp"}], "steps": [{"order": 1, "description": "Whisk flour with milk until smooth."}, {"order": 2, "description": "Fry in a hot pan until golden."}]}`
+	s := &recipeResolutionService{generate: func(context.Context, string, integrations.GenerationOptions) (string, error) { return response, nil }}
+
+	recipe := &models.Recipe{ID: "recipe-1", Title: "Pancakes"}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "200", Unit: "g"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Mix and fry."}})
+
+	expanded, err := s.ExpandRecipe(context.Background(), recipe, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expanded.Title != "Pancakes" {
+		t.Errorf("Expected title to be preserved, got %q", expanded.Title)
+	}
+	ingredients, _ := expanded.GetIngredients()
+	if len(ingredients) != 1 || ingredients[0].Amount != "200" {
+		t.Errorf("Expected ingredients to be preserved, got %+v", ingredients)
+	}
+	steps, _ := expanded.GetSteps()
+	if len(steps) != 2 {
+		t.Errorf("Expected expanded steps, got %+v", steps)
+	}
+	if expanded.NutritionalInfo != "350 kcal per serving" {
+		t.Errorf("Expected nutritional info from model response, got %q", expanded.NutritionalInfo)
+	}
+	if !strings.Contains(expanded.Description, "Rest the batter for 10 minutes.") {
+		t.Errorf("Expected tips in description, got %q", expanded.Description)
+	}
+
+	expanded, err = s.ExpandRecipe(context.Background(), recipe, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ingredients, _ = expanded.GetIngredients()
+	if expanded.Title != "Fancy Pancakes" || len(ingredients) != 2 {
+		t.Errorf("Expected core changes to be applied, got title %q and ingredients %+v", expanded.Title, ingredients)
+	}
+}
+
+func TestParseModelRecipeConformingOutput(t *testing.T) {
+	recipe, err := parseModelRecipe(`Here you go: {"title": "Toast", "ingredients": [{"name": "bread", "amount": 2}], "steps": [{"description": "Toast the bread."}, {"order": 5, "description": "Serve."}]}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ingredients := recipe.ingredients()
+	if ingredients[0].Amount != "2" || ingredients[0].Unit != "" {
+		t.Errorf("Expected amount coerced to string and unit defaulted, got %+v", ingredients[0])
+	}
+	if recipe.Steps[0].Order != 1 || recipe.Steps[1].Order != 5 {
+		t.Errorf("Expected missing step order defaulted, got %+v", recipe.Steps)
+	}
+}
+
+func TestParseModelRecipeNonConformingOutput(t *testing.T) {
+	_, err := parseModelRecipe(`{"title": 42, "ingredients": [{"amount": "1"}], "steps": []}`)
+	var schemaErr *ModelSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected *ModelSchemaError, got %v", err)
+	}
+	expected := []string{
+		"recipe.ingredients[0].name is required",
+		"recipe.steps must have at least 1 item(s)",
+		"recipe.title must be string",
+	}
+	if strings.Join(schemaErr.Problems, "|") != strings.Join(expected, "|") {
+		t.Errorf("Unexpected problems %q", schemaErr.Problems)
+	}
+}
+
+func TestParseModelRecipeRejectsNonsenseValues(t *testing.T) {
+	tests := map[string]struct {
+		response string
+		problem  string
+	}{
+		"blank ingredient name": {
+			`{"ingredients": [{"name": "  "}], "steps": [{"description": "Stir."}]}`,
+			"recipe.ingredients[0].name must not be blank",
+		},
+		"negative numeric amount": {
+			`{"ingredients": [{"name": "flour", "amount": -2}], "steps": [{"description": "Stir."}]}`,
+			"recipe.ingredients[0].amount must not be negative",
+		},
+		"negative string amount": {
+			`{"ingredients": [{"name": "flour", "amount": "-0.5"}], "steps": [{"description": "Stir."}]}`,
+			"recipe.ingredients[0].amount must not be negative",
+		},
+		"empty instruction": {
+			`{"ingredients": [{"name": "flour"}], "steps": [{"order": 1, "description": ""}]}`,
+			"recipe.steps[0].description must not be blank",
+		},
+		"steps out of order": {
+			`{"ingredients": [{"name": "flour"}], "steps": [{"order": 2, "description": "Bake."}, {"order": 1, "description": "Mix."}]}`,
+			"recipe.steps[1].order must follow step 2",
+		},
+		"duplicate step order": {
+			`{"ingredients": [{"name": "flour"}], "steps": [{"order": 1, "description": "Mix."}, {"order": 1, "description": "Bake."}]}`,
+			"recipe.steps[1].order must follow step 1",
+		},
+		"negative step order": {
+			`{"ingredients": [{"name": "flour"}], "steps": [{"order": -1, "description": "Mix."}]}`,
+			"recipe.steps[0].order must not be negative",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseModelRecipe(tt.response)
+			var schemaErr *ModelSchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("Expected *ModelSchemaError, got %v", err)
+			}
+			if strings.Join(schemaErr.Problems, "|") != tt.problem {
+				t.Errorf("Expected problem %q, got %q", tt.problem, schemaErr.Problems)
+			}
+		})
+	}
+}
+
+func TestExpandRecipeRetriesMalformedModelOutputOnce(t *testing.T) {
+	responses := []string{
+		`{"ingredients": [{"name": "flour"}], "steps": [{"order": 1, "description": ""}]}`,
+		`{"ingredients": [{"name": "flour"}], "steps": [{"order": 1, "description": "Mix and fry."}]}`,
+	}
+	calls := 0
+	s := &recipeResolutionService{generate: func(context.Context, string, integrations.GenerationOptions) (string, error) {
+		response := responses[len(responses)-1]
+		if calls < len(responses) {
+			response = responses[calls]
+		}
+		calls++
+		return response, nil
+	}}
+	recipe := &models.Recipe{Title: "Pancakes"}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Mix."}})
+
+	if _, err := s.ExpandRecipe(context.Background(), recipe, false); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 model calls, got %d", calls)
+	}
+
+	calls = 0
+	responses = responses[:1]
+	_, err := s.ExpandRecipe(context.Background(), recipe, false)
+	var schemaErr *ModelSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected *ModelSchemaError after the retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected exactly one retry, got %d calls", calls)
+	}
+}
+
+func TestResolveRecipeByModelValidatesResponse(t *testing.T) {
+	valid := `{"title": "Pancakes", "ingredients": [{"name": "flour", "amount": 250, "unit": "g"}], "steps": [{"order": 1, "description": "Mix and fry."}]}`
+	responses := []string{`{"title": "Pancakes", "ingredients": "flour"}`, valid}
+	calls := 0
+	s := &recipeResolutionService{generate: func(context.Context, string, integrations.GenerationOptions) (string, error) {
+		response := responses[len(responses)-1]
+		if calls < len(responses) {
+			response = responses[calls]
+		}
+		calls++
+		return response, nil
+	}}
+
+	candidate, _, err := s.ResolveRecipeByModel(context.Background(), "prompt", integrations.GenerationOptions{})
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if candidate != valid || calls != 2 {
+		t.Errorf("Expected the valid candidate after 2 calls, got %q after %d", candidate, calls)
+	}
+
+	calls = 0
+	responses = responses[:1]
+	_, _, err = s.ResolveRecipeByModel(context.Background(), "prompt", integrations.GenerationOptions{})
+	var schemaErr *ModelSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected *ModelSchemaError, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected exactly one retry, got %d calls", calls)
+	}
+}
+
+func TestGenerateRecipeWithDeepSeekResponse(t *testing.T) {
+	useDeepSeekServer(t, `{"title": "Tomato Soup", "ingredients": [{"name": "tomatoes", "amount": 4, "unit": "whole"}], "steps": [{"order": 1, "description": "Simmer and blend."}]}`)
+	s := NewRecipeResolutionService()
+
+	recipe, err := s.GenerateRecipe(context.Background(), "tomato soup")
+	if err != nil {
+		t.Fatalf("Expected the chat completion to be unwrapped, got %v", err)
+	}
+	if recipe.Title != "Tomato Soup" {
+		t.Errorf("Expected title from the message content, got %q", recipe.Title)
+	}
+	if steps, _ := recipe.GetSteps(); len(steps) != 1 {
+		t.Errorf("Unexpected steps: %+v", steps)
+	}
+}
+
+func TestResolveRecipeByModelReturnsMessageContent(t *testing.T) {
+	content := `{"title": "Pancakes", "ingredients": [{"name": "flour", "amount": 250, "unit": "g"}], "steps": [{"order": 1, "description": "Mix and fry."}]}`
+	useDeepSeekServer(t, content)
+	s := NewRecipeResolutionService()
+
+	candidate, _, err := s.ResolveRecipeByModel(context.Background(), "prompt", integrations.GenerationOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if candidate != content {
+		t.Errorf("Expected the candidate to be the message content, got %q", candidate)
+	}
+}
+
+func TestGenerateRecipeWithStubbedModel(t *testing.T) {
+	var prompt string
+	s := &recipeResolutionService{generate: func(_ context.Context, p string, _ integrations.GenerationOptions) (string, error) {
+		prompt = p
+		return `{"description": "Weeknight dinner.", "tips": ["Rest the dough."], "ingredients": [{"name": "flour", "amount": 500, "unit": "g"}], "steps": [{"order": 1, "description": "Knead."}]}`, nil
+	}}
+
+	recipe, err := s.GenerateRecipe(context.Background(), " homemade pizza ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "homemade pizza") {
+		t.Errorf("Expected prompt to contain the query, got:\n%s", prompt)
+	}
+	if recipe.ID != "" || recipe.Title != "homemade pizza" || recipe.Language != DefaultRecipeLanguage {
+		t.Errorf("Unexpected recipe: %+v", recipe)
+	}
+	if !strings.Contains(recipe.Description, "- Rest the dough.") {
+		t.Errorf("Expected tips in the description, got %q", recipe.Description)
+	}
+	if ingredients, _ := recipe.GetIngredients(); len(ingredients) != 1 || ingredients[0].Amount != "500" {
+		t.Errorf("Unexpected ingredients: %+v", ingredients)
+	}
+
+	if _, err := s.GenerateRecipe(context.Background(), "  "); err == nil {
+		t.Error("Expected an empty query to be rejected")
+	}
+	prompt = ""
+	if _, err := s.GenerateRecipe(context.Background(), strings.Repeat("pizza ", 100)); err == nil || prompt != "" {
+		t.Errorf("Expected an oversized query to be rejected before the model is called, got %v", err)
+	}
+}
+
+func TestBuildCompositePromptAddsQueryConstraints(t *testing.T) {
+	s := &recipeResolutionService{}
+	prompt, err := s.BuildCompositePrompt("a quick Mexican vegan dish with tomatoes without onions", "", "", nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"Constraints (these must be followed exactly):\n",
+		" - Do NOT use onions or anything made from it",
+		" - The recipe must be mexican cuisine.",
+		" - The recipe must be strictly vegan.",
+		" - The total prep and cooking time must not exceed 30 minutes.",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+	if !strings.Contains(prompt, "Preferences (follow these where they suit the dish):\n - Feature these ingredients from the query: tomatoes.\n") {
+		t.Errorf("Expected tomatoes as a preference, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "dish, ") || strings.Contains(prompt, "onions, ") || strings.Contains(prompt, "onions.\n") {
+		t.Errorf("Expected onions only as an exclusion and no meal words as ingredients, got:\n%s", prompt)
+	}
+}
+
+func TestGenerateRecipePromptListsExclusions(t *testing.T) {
+	var prompt string
+	s := &recipeResolutionService{generate: func(_ context.Context, p string, _ integrations.GenerationOptions) (string, error) {
+		prompt = p
+		return `{"title": "Salsa", "ingredients": [{"name": "tomatoes", "amount": 3, "unit": ""}], "steps": [{"order": 1, "description": "Chop."}]}`, nil
+	}}
+
+	if _, err := s.GenerateRecipe(context.Background(), "salsa without onions"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, " - Do NOT use onions") {
+		t.Errorf("Expected onions as a negative constraint, got:\n%s", prompt)
+	}
+}
+
+func TestQueryConstraintsWithoutSignals(t *testing.T) {
+	if constraints, preferences := queryConstraints("   "); constraints != nil || preferences != nil {
+		t.Errorf("Expected no constraints for an unparseable query, got %v and %v", constraints, preferences)
+	}
+}
+
+func TestQueryConstraintsRealisticQueries(t *testing.T) {
+	tests := []struct {
+		query       string
+		constraints []string
+		preferences []string
+	}{
+		{
+			query: "a quick dinner in an air fryer using 2 chicken breasts",
+			constraints: []string{
+				"Cook with: air fryer.",
+				"The total prep and cooking time must not exceed 30 minutes.",
+			},
+			preferences: []string{"Feature these ingredients from the query: breasts."},
+		},
+		{
+			query: "vegetarian breakfast, italian, about 500 kcal per serving",
+			constraints: []string{
+				"The recipe must be italian cuisine.",
+				"The recipe must be strictly vegetarian.",
+			},
+		},
+		{
+			query: "vegan dinner, about 600 kcal per serving, day 2 of 7, a different dish from the other days",
+			constraints: []string{
+				"The recipe must be strictly vegan.",
+			},
+		},
+		{
+			query:       "high protein lunch with 200 g of salmon and a cup of rice",
+			preferences: []string{"Feature these ingredients from the query: salmon, rice."},
+		},
+		{
+			query: "slow cooker beef stew ready in 45 minutes for weeknight supper",
+			constraints: []string{
+				"Cook with: slow cooker.",
+				"The total prep and cooking time must not exceed 45 minutes.",
+			},
+			preferences: []string{"Feature these ingredients from the query: beef."},
+		},
+		{
+			query:       "pasta, no onions, and lots of garlic",
+			constraints: []string{"Do NOT use onions or anything made from it, not even as a garnish or optional ingredient."},
+			preferences: []string{"Feature these ingredients from the query: pasta, garlic."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			constraints, preferences := queryConstraints(tt.query)
+			if !reflect.DeepEqual(constraints, tt.constraints) {
+				t.Errorf("Expected constraints %q, got %q", tt.constraints, constraints)
+			}
+			if !reflect.DeepEqual(preferences, tt.preferences) {
+				t.Errorf("Expected preferences %q, got %q", tt.preferences, preferences)
+			}
+		})
+	}
+}
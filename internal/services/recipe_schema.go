@@ -0,0 +1,185 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RecipeSchemaVersion identifies the version of the generated recipe schema.
+const RecipeSchemaVersion = "v1"
+
+//go:embed schema/recipe.v1.json
+var recipeSchemaJSON []byte
+
+// RecipeSchema returns the JSON Schema that AI-generated recipes are validated against.
+func RecipeSchema() []byte {
+	return recipeSchemaJSON
+}
+
+// ModelSchemaError reports AI output that does not conform to the recipe schema.
+type ModelSchemaError struct {
+	Problems []string
+}
+
+func (e *ModelSchemaError) Error() string {
+	return "model response does not match recipe schema " + RecipeSchemaVersion + ": " + strings.Join(e.Problems, "; ")
+}
+
+// jsonSchema is the subset of JSON Schema used by the recipe schema:
+// type, required, properties, items and minItems.
+type jsonSchema struct {
+	Type       schemaTypes            `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*jsonSchema `json:"properties"`
+	Items      *jsonSchema            `json:"items"`
+	MinItems   int                    `json:"minItems"`
+}
+
+// schemaTypes accepts "type" as either a single type name or a list of names.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+var recipeSchema = mustParseSchema(recipeSchemaJSON)
+
+func mustParseSchema(data []byte) *jsonSchema {
+	var schema jsonSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		panic(fmt.Sprintf("invalid embedded recipe schema: %v", err))
+	}
+	return &schema
+}
+
+// validateModelRecipe checks decoded model output against the recipe schema.
+func validateModelRecipe(value interface{}) error {
+	var problems []string
+	recipeSchema.validate(value, "recipe", &problems)
+	if len(problems) > 0 {
+		return &ModelSchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// validateGeneratedRecipe checks the values in schema-conforming model output that the schema
+// cannot express: names and step descriptions must not be blank, amounts must not be negative
+// and step orders must be positive and increasing.
+func validateGeneratedRecipe(r *modelRecipe) error {
+	var problems []string
+	for i, ing := range r.Ingredients {
+		if strings.TrimSpace(ing.Name) == "" {
+			problems = append(problems, fmt.Sprintf("recipe.ingredients[%d].name must not be blank", i))
+		}
+		if negativeAmount(ing.Amount) {
+			problems = append(problems, fmt.Sprintf("recipe.ingredients[%d].amount must not be negative", i))
+		}
+	}
+	previous := 0
+	for i, step := range r.Steps {
+		if strings.TrimSpace(step.Description) == "" {
+			problems = append(problems, fmt.Sprintf("recipe.steps[%d].description must not be blank", i))
+		}
+		if step.Order < 0 {
+			problems = append(problems, fmt.Sprintf("recipe.steps[%d].order must not be negative", i))
+		} else if step.Order != 0 && step.Order <= previous {
+			problems = append(problems, fmt.Sprintf("recipe.steps[%d].order must follow step %d", i, previous))
+		}
+		if step.Order > previous {
+			previous = step.Order
+		}
+	}
+	if len(problems) > 0 {
+		return &ModelSchemaError{Problems: problems}
+	}
+	return nil
+}
+
+// negativeAmount reports whether an ingredient amount, given as a number or numeric string, is below zero.
+func negativeAmount(amount interface{}) bool {
+	switch v := amount.(type) {
+	case float64:
+		return v < 0
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return err == nil && n < 0
+	}
+	return false
+}
+
+func (s *jsonSchema) validate(value interface{}, path string, problems *[]string) {
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		*problems = append(*problems, fmt.Sprintf("%s must be %s", path, strings.Join(s.Type, " or ")))
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if field, ok := v[name]; !ok || field == nil {
+				*problems = append(*problems, fmt.Sprintf("%s.%s is required", path, name))
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if field, ok := v[name]; ok && field != nil {
+				s.Properties[name].validate(field, path+"."+name, problems)
+			}
+		}
+	case []interface{}:
+		if len(v) < s.MinItems {
+			*problems = append(*problems, fmt.Sprintf("%s must have at least %d item(s)", path, s.MinItems))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+func (t schemaTypes) matches(value interface{}) bool {
+	for _, name := range t {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if name == "object" {
+				return true
+			}
+		case []interface{}:
+			if name == "array" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case float64:
+			if name == "number" || (name == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
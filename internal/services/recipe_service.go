@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"github.com/pageza/alchemorsel-v1/internal/repositories"
 	"go.uber.org/zap"
@@ -25,20 +28,32 @@ type RecipeService interface {
 	// DeleteRecipe deletes a recipe by ID
 	DeleteRecipe(ctx context.Context, id string) error
 
-	// ListRecipes retrieves a list of recipes with pagination and sorting
-	ListRecipes(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error)
+	// ListRecipes retrieves a list of recipes with pagination, sorting and filtering
+	ListRecipes(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error)
+
+	// ListRecipesByUser retrieves a page of the recipes saved by a user
+	ListRecipesByUser(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error)
 
 	// SearchRecipes searches for recipes based on query parameters
 	SearchRecipes(ctx context.Context, query string, tags []string, difficulty string) ([]models.Recipe, error)
 
-	// RateRecipe adds a rating to a recipe
-	RateRecipe(ctx context.Context, recipeID string, rating float64) error
+	// SearchByIngredients finds up to limit recipes using the given ingredients, best fit first
+	SearchByIngredients(ctx context.Context, ingredients []string, limit int) ([]IngredientMatch, error)
+
+	// RateRecipe records a user's rating of a recipe, replacing their earlier rating if any
+	RateRecipe(ctx context.Context, recipeID, userID string, rating float64) error
 
-	// GetRecipeRatings retrieves all ratings for a recipe
-	GetRecipeRatings(ctx context.Context, recipeID string) ([]float64, error)
+	// GetRecipeRatings retrieves the rating count and average for a recipe
+	GetRecipeRatings(ctx context.Context, recipeID string) (*models.RatingSummary, error)
 
 	// ResolveRecipe resolves a recipe query with attributes
 	ResolveRecipe(ctx context.Context, query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error)
+
+	// ReindexEmbeddings regenerates the stored embeddings of one batch of recipes starting at offset
+	ReindexEmbeddings(ctx context.Context, offset, batchSize int, dryRun bool) (ReindexResult, error)
+
+	// ListStaleEmbeddings returns up to limit recipes whose embedding is missing or older than the recipe
+	ListStaleEmbeddings(ctx context.Context, limit int) ([]models.Recipe, error)
 }
 
 // recipeService is the implementation of RecipeService
@@ -48,22 +63,56 @@ type recipeService struct {
 	dietService      DietService
 	applianceService ApplianceService
 	tagService       TagService
+	// embeddingFields selects the recipe fields included in the embedding text.
+	embeddingFields []string
+	embedder        integrations.Embedder
+	// embeddingDimensions is the length every embedding must have before it is stored; zero
+	// disables the check.
+	embeddingDimensions int
 }
 
+// NewRecipeService creates a RecipeService that computes embeddings with OpenAI.
 func NewRecipeService(
 	repo repositories.RecipeRepository,
 	cuisineService CuisineService,
 	dietService DietService,
 	applianceService ApplianceService,
 	tagService TagService,
+) RecipeService {
+	return NewRecipeServiceWithEmbedder(repo, cuisineService, dietService, applianceService, tagService, integrations.OpenAIEmbedder{})
+}
+
+// NewRecipeServiceWithEmbedder creates a RecipeService that computes recipe embeddings with embedder.
+func NewRecipeServiceWithEmbedder(
+	repo repositories.RecipeRepository,
+	cuisineService CuisineService,
+	dietService DietService,
+	applianceService ApplianceService,
+	tagService TagService,
+	embedder integrations.Embedder,
 ) RecipeService {
 	return &recipeService{
-		repo:             repo,
-		cuisineService:   cuisineService,
-		dietService:      dietService,
-		applianceService: applianceService,
-		tagService:       tagService,
+		repo:                repo,
+		cuisineService:      cuisineService,
+		dietService:         dietService,
+		applianceService:    applianceService,
+		tagService:          tagService,
+		embeddingFields:     embeddingFieldsFromEnv(),
+		embedder:            embedder,
+		embeddingDimensions: config.LoadAIConfig().EmbeddingDimensions,
+	}
+}
+
+// refreshEmbedding recomputes the recipe's embedding from its current content.
+// Failures are logged and leave the recipe without an embedding rather than failing the save.
+func (s *recipeService) refreshEmbedding(ctx context.Context, recipe *models.Recipe) {
+	embedding, err := s.embed(ctx, recipe)
+	if err != nil {
+		zap.S().Warnw("Failed to generate recipe embedding", "id", recipe.ID, "error", err)
+		recipe.Embedding = nil
+		return
 	}
+	recipe.Embedding = embedding
 }
 
 func (s *recipeService) GetRecipe(ctx context.Context, id string) (*models.Recipe, error) {
@@ -80,6 +129,9 @@ func (s *recipeService) SaveRecipe(ctx context.Context, recipe *models.Recipe) e
 		return errors.New("recipe title is required")
 	}
 
+	// Recipes may come from model output, so map loose difficulty wording onto the allowed levels.
+	recipe.Difficulty = CoerceRecipeDifficulty(recipe.Difficulty)
+
 	// Ensure the recipe has a valid UUID.
 	if recipe.ID == "" {
 		recipe.ID = uuid.New().String()
@@ -91,61 +143,11 @@ func (s *recipeService) SaveRecipe(ctx context.Context, recipe *models.Recipe) e
 	}
 	recipe.UpdatedAt = time.Now()
 
-	// Handle cuisines
-	if len(recipe.Cuisines) > 0 {
-		for i, cuisine := range recipe.Cuisines {
-			if cuisine.ID == "" {
-				// Try to find existing cuisine by name or create a new one
-				existingCuisine, err := s.cuisineService.GetOrCreate(ctx, cuisine.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Cuisines[i] = *existingCuisine
-			}
-		}
+	if err := s.resolveRelatedEntities(ctx, recipe); err != nil {
+		return err
 	}
 
-	// Handle diets
-	if len(recipe.Diets) > 0 {
-		for i, diet := range recipe.Diets {
-			if diet.ID == "" {
-				// Try to find existing diet by name or create a new one
-				existingDiet, err := s.dietService.GetOrCreate(ctx, diet.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Diets[i] = *existingDiet
-			}
-		}
-	}
-
-	// Handle appliances
-	if len(recipe.Appliances) > 0 {
-		for i, appliance := range recipe.Appliances {
-			if appliance.ID == "" {
-				// Try to find existing appliance by name or create a new one
-				existingAppliance, err := s.applianceService.GetOrCreate(ctx, appliance.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Appliances[i] = *existingAppliance
-			}
-		}
-	}
-
-	// Handle tags
-	if len(recipe.Tags) > 0 {
-		for i, tag := range recipe.Tags {
-			if tag.ID == "" {
-				// Try to find existing tag by name or create a new one
-				existingTag, err := s.tagService.GetOrCreate(ctx, tag.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Tags[i] = *existingTag
-			}
-		}
-	}
+	s.refreshEmbedding(ctx, recipe)
 
 	// Log the operation
 	zap.S().Infow("Saving recipe to the database",
@@ -160,8 +162,19 @@ func (s *recipeService) SaveRecipe(ctx context.Context, recipe *models.Recipe) e
 	return s.repo.SaveRecipe(ctx, recipe)
 }
 
-func (s *recipeService) ListRecipes(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error) {
-	return s.repo.ListRecipes(ctx, page, limit, sort, order)
+func (s *recipeService) ListStaleEmbeddings(ctx context.Context, limit int) ([]models.Recipe, error) {
+	if limit <= 0 {
+		limit = DefaultStaleEmbeddingsLimit
+	}
+	return s.repo.ListStaleEmbeddings(ctx, limit)
+}
+
+func (s *recipeService) ListRecipes(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error) {
+	return s.repo.ListRecipes(ctx, page, limit, sort, order, filter)
+}
+
+func (s *recipeService) ListRecipesByUser(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error) {
+	return s.repo.ListRecipesByUser(ctx, userID, page, limit, sort, order)
 }
 
 func (s *recipeService) UpdateRecipe(ctx context.Context, recipe *models.Recipe) error {
@@ -174,61 +187,14 @@ func (s *recipeService) UpdateRecipe(ctx context.Context, recipe *models.Recipe)
 		return errors.New("recipe title is required")
 	}
 
-	// Handle cuisines
-	if len(recipe.Cuisines) > 0 {
-		for i, cuisine := range recipe.Cuisines {
-			if cuisine.ID == "" {
-				// Try to find existing cuisine by name or create a new one
-				existingCuisine, err := s.cuisineService.GetOrCreate(ctx, cuisine.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Cuisines[i] = *existingCuisine
-			}
-		}
-	}
+	// Recipes may come from model output, so map loose difficulty wording onto the allowed levels.
+	recipe.Difficulty = CoerceRecipeDifficulty(recipe.Difficulty)
 
-	// Handle diets
-	if len(recipe.Diets) > 0 {
-		for i, diet := range recipe.Diets {
-			if diet.ID == "" {
-				// Try to find existing diet by name or create a new one
-				existingDiet, err := s.dietService.GetOrCreate(ctx, diet.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Diets[i] = *existingDiet
-			}
-		}
+	if err := s.resolveRelatedEntities(ctx, recipe); err != nil {
+		return err
 	}
 
-	// Handle appliances
-	if len(recipe.Appliances) > 0 {
-		for i, appliance := range recipe.Appliances {
-			if appliance.ID == "" {
-				// Try to find existing appliance by name or create a new one
-				existingAppliance, err := s.applianceService.GetOrCreate(ctx, appliance.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Appliances[i] = *existingAppliance
-			}
-		}
-	}
-
-	// Handle tags
-	if len(recipe.Tags) > 0 {
-		for i, tag := range recipe.Tags {
-			if tag.ID == "" {
-				// Try to find existing tag by name or create a new one
-				existingTag, err := s.tagService.GetOrCreate(ctx, tag.Name)
-				if err != nil {
-					return err
-				}
-				recipe.Tags[i] = *existingTag
-			}
-		}
-	}
+	s.refreshEmbedding(ctx, recipe)
 
 	// Log the operation
 	zap.S().Infow("Updating recipe in the database",
@@ -243,6 +209,57 @@ func (s *recipeService) UpdateRecipe(ctx context.Context, recipe *models.Recipe)
 	return s.repo.UpdateRecipe(ctx, recipe)
 }
 
+// resolveRelatedEntities replaces the recipe's cuisines, diets, appliances and tags that have no
+// ID with the stored rows of the same name, creating missing ones with one batch per kind.
+func (s *recipeService) resolveRelatedEntities(ctx context.Context, recipe *models.Recipe) error {
+	if err := resolveByName(recipe.Cuisines, func(c *models.Cuisine) (string, string) { return c.ID, c.Name },
+		func(names []string) ([]*models.Cuisine, error) { return s.cuisineService.GetOrCreateBatch(ctx, names) }); err != nil {
+		return err
+	}
+	if err := resolveByName(recipe.Diets, func(d *models.Diet) (string, string) { return d.ID, d.Name },
+		func(names []string) ([]*models.Diet, error) { return s.dietService.GetOrCreateBatch(ctx, names) }); err != nil {
+		return err
+	}
+	if err := resolveByName(recipe.Appliances, func(a *models.Appliance) (string, string) { return a.ID, a.Name },
+		func(names []string) ([]*models.Appliance, error) {
+			return s.applianceService.GetOrCreateBatch(ctx, names)
+		}); err != nil {
+		return err
+	}
+	return resolveByName(recipe.Tags, func(t *models.Tag) (string, string) { return t.ID, t.Name },
+		func(names []string) ([]*models.Tag, error) { return s.tagService.GetOrCreateBatch(ctx, names) })
+}
+
+// resolveByName replaces the items without an ID by the rows getOrCreate returns for their
+// names, matched case-insensitively. getOrCreate is only called when there is at least one such item.
+func resolveByName[T any](items []T, key func(*T) (id, name string), getOrCreate func(names []string) ([]*T, error)) error {
+	var names []string
+	for i := range items {
+		if id, name := key(&items[i]); id == "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	rows, err := getOrCreate(names)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*T, len(rows))
+	for _, row := range rows {
+		_, name := key(row)
+		byName[strings.ToLower(NormalizeEntityName(name))] = row
+	}
+	for i := range items {
+		id, name := key(&items[i])
+		if row := byName[strings.ToLower(NormalizeEntityName(name))]; id == "" && row != nil {
+			items[i] = *row
+		}
+	}
+	return nil
+}
+
 func (s *recipeService) DeleteRecipe(ctx context.Context, id string) error {
 	return s.repo.DeleteRecipe(ctx, id)
 }
@@ -251,11 +268,11 @@ func (s *recipeService) SearchRecipes(ctx context.Context, query string, tags []
 	return s.repo.SearchRecipes(ctx, query, tags, difficulty)
 }
 
-func (s *recipeService) RateRecipe(ctx context.Context, recipeID string, rating float64) error {
-	return s.repo.RateRecipe(ctx, recipeID, rating)
+func (s *recipeService) RateRecipe(ctx context.Context, recipeID, userID string, rating float64) error {
+	return s.repo.RateRecipe(ctx, recipeID, userID, rating)
 }
 
-func (s *recipeService) GetRecipeRatings(ctx context.Context, recipeID string) ([]float64, error) {
+func (s *recipeService) GetRecipeRatings(ctx context.Context, recipeID string) (*models.RatingSummary, error) {
 	return s.repo.GetRecipeRatings(ctx, recipeID)
 }
 
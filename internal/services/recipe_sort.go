@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultRecipeSort is the column recipes are listed by when no sort is requested.
+	DefaultRecipeSort = "created_at"
+	// DefaultRecipeSortOrder is the direction used when no order is requested.
+	DefaultRecipeSortOrder = "desc"
+)
+
+// RecipeSortFields lists the fields recipes may be sorted by. total_time_minutes is the
+// sum of the prep and cook times.
+var RecipeSortFields = map[string]bool{
+	"created_at":         true,
+	"updated_at":         true,
+	"title":              true,
+	"average_rating":     true,
+	"total_time_minutes": true,
+}
+
+// NormalizeRecipeSort validates a requested sort column and order against the allowlist.
+// Empty values resolve to DefaultRecipeSort and DefaultRecipeSortOrder.
+func NormalizeRecipeSort(sort, order string) (string, string, error) {
+	sort = strings.ToLower(strings.TrimSpace(sort))
+	if sort == "" {
+		sort = DefaultRecipeSort
+	}
+	if !RecipeSortFields[sort] {
+		return "", "", fmt.Errorf("unsupported sort field: %s", sort)
+	}
+
+	order = strings.ToLower(strings.TrimSpace(order))
+	switch order {
+	case "":
+		order = DefaultRecipeSortOrder
+	case "asc", "desc":
+	default:
+		return "", "", fmt.Errorf("unsupported sort order: %s", order)
+	}
+	return sort, order, nil
+}
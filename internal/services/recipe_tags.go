@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/errors"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/parsers"
+	"github.com/pageza/alchemorsel-v1/internal/prompts"
+)
+
+// MaxModelTagSuggestions caps the tags taken from a model suggestion.
+const MaxModelTagSuggestions = 5
+
+// TagRule suggests Tag for recipes with an ingredient whose name contains one of Words.
+type TagRule struct {
+	Tag   string
+	Words []string
+}
+
+// DefaultTagRules are the ingredient rules used by SuggestTags, in the order their tags are suggested.
+var DefaultTagRules = []TagRule{
+	{Tag: "chicken", Words: []string{"chicken"}},
+	{Tag: "beef", Words: []string{"beef", "steak", "brisket"}},
+	{Tag: "pork", Words: []string{"pork", "bacon", "ham", "prosciutto", "chorizo"}},
+	{Tag: "lamb", Words: []string{"lamb"}},
+	{Tag: "seafood", Words: []string{"fish", "salmon", "tuna", "cod", "shrimp", "prawn", "crab", "lobster", "mussel", "clam", "scallop"}},
+	{Tag: "tofu", Words: []string{"tofu", "tempeh"}},
+	{Tag: "pasta", Words: []string{"pasta", "spaghetti", "penne", "linguine", "fettuccine", "macaroni", "lasagna", "noodle"}},
+	{Tag: "rice", Words: []string{"rice", "risotto"}},
+	{Tag: "chocolate", Words: []string{"chocolate", "cocoa"}},
+	{Tag: "spicy", Words: []string{"chili", "chilli", "jalapeno", "jalapeño", "habanero", "cayenne", "sriracha"}},
+	{Tag: "cheese", Words: []string{"cheese", "parmesan", "mozzarella", "cheddar", "feta", "ricotta"}},
+}
+
+// Time-based tags added by SuggestTags from the recipe's prep and cooking time.
+const (
+	QuickTagMinutes = 30
+	SlowTagMinutes  = 180
+)
+
+// SuggestTags derives candidate tags from the recipe's cuisines, diets, difficulty, total time
+// and notable ingredients, following DefaultTagRules. Tags the recipe already has are left out,
+// compared case-insensitively, and suggestions are lower case without duplicates.
+func SuggestTags(recipe *models.Recipe) []string {
+	suggestions := newTagSet(recipe)
+	for _, cuisine := range recipe.Cuisines {
+		suggestions.add(cuisine.Name)
+	}
+	for _, diet := range recipe.Diets {
+		suggestions.add(diet.Name)
+	}
+	if recipe.Difficulty != "" {
+		suggestions.add(recipe.Difficulty)
+	}
+	if total := recipe.PrepTime + recipe.CookTime; total > 0 && total <= QuickTagMinutes {
+		suggestions.add("quick")
+	} else if total >= SlowTagMinutes {
+		suggestions.add("slow-cooked")
+	}
+
+	ingredients, _ := recipe.GetIngredients()
+	for _, rule := range DefaultTagRules {
+		if ingredientsMatch(ingredients, rule.Words) {
+			suggestions.add(rule.Tag)
+		}
+	}
+	return suggestions.tags
+}
+
+// MergeTagSuggestions appends the model's suggestions to the rule-based ones, skipping tags the
+// recipe or the earlier suggestions already have.
+func MergeTagSuggestions(recipe *models.Recipe, suggested, extra []string) []string {
+	suggestions := newTagSet(recipe)
+	for _, tag := range append(append([]string{}, suggested...), extra...) {
+		suggestions.add(tag)
+	}
+	return suggestions.tags
+}
+
+func ingredientsMatch(ingredients []models.Ingredient, words []string) bool {
+	for _, ing := range ingredients {
+		name := parsers.NormalizeIngredient(ing.Name)
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagSet collects suggestions in order, skipping blanks, duplicates and the recipe's own tags.
+type tagSet struct {
+	seen map[string]bool
+	tags []string
+}
+
+func newTagSet(recipe *models.Recipe) *tagSet {
+	set := &tagSet{seen: make(map[string]bool), tags: []string{}}
+	for _, tag := range recipe.Tags {
+		set.seen[strings.ToLower(strings.TrimSpace(tag.Name))] = true
+	}
+	return set
+}
+
+func (s *tagSet) add(tag string) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || s.seen[tag] {
+		return
+	}
+	s.seen[tag] = true
+	s.tags = append(s.tags, tag)
+}
+
+// SuggestModelTags asks the external model for up to MaxModelTagSuggestions short descriptive tags
+// that the recipe does not have yet, such as "comfort food" or "meal prep".
+func (s *recipeResolutionService) SuggestModelTags(ctx context.Context, recipe *models.Recipe) ([]string, error) {
+	if recipe == nil {
+		return nil, errors.NewValidationError("recipe cannot be nil")
+	}
+	ingredients, err := recipe.GetIngredients()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recipe ingredients: %w", err)
+	}
+	names := make([]string, len(ingredients))
+	for i, ing := range ingredients {
+		names[i] = ing.Name
+	}
+	tags := make([]string, len(recipe.Tags))
+	for i, tag := range recipe.Tags {
+		tags[i] = tag.Name
+	}
+	current, err := json.Marshal(map[string]interface{}{
+		"title":       recipe.Title,
+		"description": recipe.Description,
+		"ingredients": names,
+		"tags":        tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := prompts.RenderTagsPrompt(MaxModelTagSuggestions, current)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.generate(ctx, prompt, integrations.GenerationOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return parseTagSuggestions(response)
+}
+
+// parseTagSuggestions reads the tags array from a model response, keeping at most
+// MaxModelTagSuggestions non-empty tags of up to 30 characters.
+func parseTagSuggestions(response string) ([]string, error) {
+	raw, err := extractJSONObject(response)
+	if err != nil {
+		return nil, err
+	}
+	var decoded struct {
+		Tags []interface{} `json:"tags"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to parse model response: %w", err)
+	}
+	if decoded.Tags == nil {
+		return nil, &ModelSchemaError{Problems: []string{"tags is required"}}
+	}
+	tags := []string{}
+	for _, value := range decoded.Tags {
+		tag, ok := value.(string)
+		tag = strings.TrimSpace(tag)
+		if !ok || tag == "" || len(tag) > 30 {
+			continue
+		}
+		tags = append(tags, tag)
+		if len(tags) == MaxModelTagSuggestions {
+			break
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+)
+
+func TestSuggestTags(t *testing.T) {
+	recipe := &models.Recipe{
+		Title:      "Spicy Shrimp Pasta",
+		Difficulty: "Easy",
+		PrepTime:   10,
+		CookTime:   15,
+		Cuisines:   []models.Cuisine{{Name: "Italian"}},
+		Diets:      []models.Diet{{Name: "Pescatarian"}},
+		Tags:       []models.Tag{{Name: "ITALIAN"}, {Name: "pasta"}},
+	}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "Spaghetti", Amount: "200", Unit: "g"},
+		{Name: "Large shrimp, peeled", Amount: "300", Unit: "g"},
+		{Name: "Red chili flakes", Amount: "1", Unit: "tsp"},
+	})
+
+	want := []string{"pescatarian", "easy", "quick", "seafood", "spicy"}
+	if got := SuggestTags(recipe); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestSuggestTagsSlowCooked(t *testing.T) {
+	recipe := &models.Recipe{PrepTime: 30, CookTime: 240}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "Beef brisket"}})
+
+	want := []string{"slow-cooked", "beef"}
+	if got := SuggestTags(recipe); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestMergeTagSuggestions(t *testing.T) {
+	recipe := &models.Recipe{Tags: []models.Tag{{Name: "Dinner"}}}
+	got := MergeTagSuggestions(recipe, []string{"chicken"}, []string{" Comfort Food ", "CHICKEN", "dinner", ""})
+	want := []string{"chicken", "comfort food"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestSuggestModelTagsWithStubbedModel(t *testing.T) {
+	var prompt string
+	s := &recipeResolutionService{generate: func(_ context.Context, p string, _ integrations.GenerationOptions) (string, error) {
+		prompt = p
+		return "```json\n{\"tags\": [\"weeknight dinner\", \"\", 42, \"meal prep\", \"" + strings.Repeat("x", 31) + "\"]}\n```", nil
+	}}
+	recipe := &models.Recipe{Title: "Chicken Curry", Tags: []models.Tag{{Name: "indian"}}}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "chicken"}})
+
+	tags, err := s.SuggestModelTags(context.Background(), recipe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"weeknight dinner", "meal prep"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("Expected %v, got %v", want, tags)
+	}
+	if !strings.Contains(prompt, `"title":"Chicken Curry"`) || !strings.Contains(prompt, `"tags":["indian"]`) {
+		t.Errorf("Expected the recipe in the prompt, got:\n%s", prompt)
+	}
+}
+
+func TestParseTagSuggestionsRequiresTags(t *testing.T) {
+	_, err := parseTagSuggestions(`{"labels": ["dinner"]}`)
+	var schemaErr *ModelSchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected *ModelSchemaError, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RefreshTokenStore tracks issued refresh tokens by ID so they can be rotated and revoked.
+type RefreshTokenStore interface {
+	Save(ctx context.Context, tokenID, userID string, ttl time.Duration) error
+	// Consume removes an active token, reporting whether it was active.
+	Consume(ctx context.Context, tokenID, userID string) (bool, error)
+	Revoke(ctx context.Context, tokenID string) error
+}
+
+// DefaultRefreshTokenStore keeps active refresh token IDs in Redis. When no Redis client is
+// configured, tokens are not tracked: every signed token is treated as active and cannot be revoked.
+type DefaultRefreshTokenStore struct {
+	redis *redis.Client
+}
+
+// NewRefreshTokenStore creates a new RefreshTokenStore backed by the given Redis client.
+func NewRefreshTokenStore(redisClient *redis.Client) RefreshTokenStore {
+	return &DefaultRefreshTokenStore{redis: redisClient}
+}
+
+func refreshTokenKey(tokenID string) string {
+	return fmt.Sprintf("refresh_token:%s", tokenID)
+}
+
+// Save records the token as active for ttl.
+func (s *DefaultRefreshTokenStore) Save(ctx context.Context, tokenID, userID string, ttl time.Duration) error {
+	if s.redis == nil {
+		return nil
+	}
+	if err := s.redis.Set(ctx, refreshTokenKey(tokenID), userID, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+// Consume deletes the token and reports whether it was active for userID, so each refresh
+// token can be exchanged only once.
+func (s *DefaultRefreshTokenStore) Consume(ctx context.Context, tokenID, userID string) (bool, error) {
+	if s.redis == nil {
+		return true, nil
+	}
+	owner, err := s.redis.GetDel(ctx, refreshTokenKey(tokenID)).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check refresh token: %w", err)
+	}
+	return owner == userID, nil
+}
+
+// Revoke deletes the token so it can no longer be used.
+func (s *DefaultRefreshTokenStore) Revoke(ctx context.Context, tokenID string) error {
+	if s.redis == nil {
+		return nil
+	}
+	if err := s.redis.Del(ctx, refreshTokenKey(tokenID)).Err(); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
@@ -3,11 +3,30 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/pageza/alchemorsel-v1/internal/errors"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"github.com/pageza/alchemorsel-v1/internal/repositories"
 )
 
+// NormalizeEntityName trims a cuisine, diet, appliance or tag name and collapses its inner
+// whitespace. Names are otherwise kept as given; they are unique regardless of case.
+func NormalizeEntityName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// normalizeEntityNames normalizes names, failing on blank ones.
+func normalizeEntityNames(names []string) ([]string, error) {
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		if normalized[i] = NormalizeEntityName(name); normalized[i] == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+	}
+	return normalized, nil
+}
+
 // CuisineService handles business logic for cuisines
 type CuisineService interface {
 	GetByID(ctx context.Context, id string) (*models.Cuisine, error)
@@ -16,6 +35,9 @@ type CuisineService interface {
 	List(ctx context.Context) ([]*models.Cuisine, error)
 	Delete(ctx context.Context, id string) error
 	GetOrCreate(ctx context.Context, name string) (*models.Cuisine, error)
+	// GetOrCreateBatch resolves all names with one lookup and creates the missing cuisines in a
+	// single transaction, so concurrent saves do not create duplicates.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Cuisine, error)
 }
 
 type DefaultCuisineService struct {
@@ -31,10 +53,12 @@ func (s *DefaultCuisineService) GetByID(ctx context.Context, id string) (*models
 }
 
 func (s *DefaultCuisineService) GetByName(ctx context.Context, name string) (*models.Cuisine, error) {
-	return s.repo.GetByName(ctx, name)
+	return s.repo.GetByName(ctx, NormalizeEntityName(name))
 }
 
+// Create stores cuisine, or loads the existing cuisine with the same name into it.
 func (s *DefaultCuisineService) Create(ctx context.Context, cuisine *models.Cuisine) error {
+	cuisine.Name = NormalizeEntityName(cuisine.Name)
 	if cuisine.Name == "" {
 		return fmt.Errorf("cuisine name is required")
 	}
@@ -50,6 +74,7 @@ func (s *DefaultCuisineService) Delete(ctx context.Context, id string) error {
 }
 
 func (s *DefaultCuisineService) GetOrCreate(ctx context.Context, name string) (*models.Cuisine, error) {
+	name = NormalizeEntityName(name)
 	cuisine, err := s.repo.GetByName(ctx, name)
 	if err == nil {
 		return cuisine, nil
@@ -63,6 +88,14 @@ func (s *DefaultCuisineService) GetOrCreate(ctx context.Context, name string) (*
 	return cuisine, nil
 }
 
+func (s *DefaultCuisineService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Cuisine, error) {
+	normalized, err := normalizeEntityNames(names)
+	if err != nil {
+		return nil, fmt.Errorf("cuisine %w", err)
+	}
+	return s.repo.GetOrCreateBatch(ctx, normalized)
+}
+
 // DietService handles business logic for diets
 type DietService interface {
 	GetByID(ctx context.Context, id string) (*models.Diet, error)
@@ -71,6 +104,9 @@ type DietService interface {
 	List(ctx context.Context) ([]*models.Diet, error)
 	Delete(ctx context.Context, id string) error
 	GetOrCreate(ctx context.Context, name string) (*models.Diet, error)
+	// GetOrCreateBatch resolves all names with one lookup and creates the missing diets in a
+	// single transaction, so concurrent saves do not create duplicates.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Diet, error)
 }
 
 type DefaultDietService struct {
@@ -86,10 +122,12 @@ func (s *DefaultDietService) GetByID(ctx context.Context, id string) (*models.Di
 }
 
 func (s *DefaultDietService) GetByName(ctx context.Context, name string) (*models.Diet, error) {
-	return s.repo.GetByName(ctx, name)
+	return s.repo.GetByName(ctx, NormalizeEntityName(name))
 }
 
+// Create stores diet, or loads the existing diet with the same name into it.
 func (s *DefaultDietService) Create(ctx context.Context, diet *models.Diet) error {
+	diet.Name = NormalizeEntityName(diet.Name)
 	if diet.Name == "" {
 		return fmt.Errorf("diet name is required")
 	}
@@ -105,6 +143,7 @@ func (s *DefaultDietService) Delete(ctx context.Context, id string) error {
 }
 
 func (s *DefaultDietService) GetOrCreate(ctx context.Context, name string) (*models.Diet, error) {
+	name = NormalizeEntityName(name)
 	diet, err := s.repo.GetByName(ctx, name)
 	if err == nil {
 		return diet, nil
@@ -118,6 +157,14 @@ func (s *DefaultDietService) GetOrCreate(ctx context.Context, name string) (*mod
 	return diet, nil
 }
 
+func (s *DefaultDietService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Diet, error) {
+	normalized, err := normalizeEntityNames(names)
+	if err != nil {
+		return nil, fmt.Errorf("diet %w", err)
+	}
+	return s.repo.GetOrCreateBatch(ctx, normalized)
+}
+
 // ApplianceService handles business logic for appliances
 type ApplianceService interface {
 	GetByID(ctx context.Context, id string) (*models.Appliance, error)
@@ -126,6 +173,9 @@ type ApplianceService interface {
 	List(ctx context.Context) ([]*models.Appliance, error)
 	Delete(ctx context.Context, id string) error
 	GetOrCreate(ctx context.Context, name string) (*models.Appliance, error)
+	// GetOrCreateBatch resolves all names with one lookup and creates the missing appliances in a
+	// single transaction, so concurrent saves do not create duplicates.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Appliance, error)
 }
 
 type DefaultApplianceService struct {
@@ -141,10 +191,12 @@ func (s *DefaultApplianceService) GetByID(ctx context.Context, id string) (*mode
 }
 
 func (s *DefaultApplianceService) GetByName(ctx context.Context, name string) (*models.Appliance, error) {
-	return s.repo.GetByName(ctx, name)
+	return s.repo.GetByName(ctx, NormalizeEntityName(name))
 }
 
+// Create stores appliance, or loads the existing appliance with the same name into it.
 func (s *DefaultApplianceService) Create(ctx context.Context, appliance *models.Appliance) error {
+	appliance.Name = NormalizeEntityName(appliance.Name)
 	if appliance.Name == "" {
 		return fmt.Errorf("appliance name is required")
 	}
@@ -160,6 +212,7 @@ func (s *DefaultApplianceService) Delete(ctx context.Context, id string) error {
 }
 
 func (s *DefaultApplianceService) GetOrCreate(ctx context.Context, name string) (*models.Appliance, error) {
+	name = NormalizeEntityName(name)
 	appliance, err := s.repo.GetByName(ctx, name)
 	if err == nil {
 		return appliance, nil
@@ -173,6 +226,14 @@ func (s *DefaultApplianceService) GetOrCreate(ctx context.Context, name string)
 	return appliance, nil
 }
 
+func (s *DefaultApplianceService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Appliance, error) {
+	normalized, err := normalizeEntityNames(names)
+	if err != nil {
+		return nil, fmt.Errorf("appliance %w", err)
+	}
+	return s.repo.GetOrCreateBatch(ctx, normalized)
+}
+
 // TagService handles business logic for tags
 type TagService interface {
 	GetByID(ctx context.Context, id string) (*models.Tag, error)
@@ -181,6 +242,14 @@ type TagService interface {
 	List(ctx context.Context) ([]*models.Tag, error)
 	Delete(ctx context.Context, id string) error
 	GetOrCreate(ctx context.Context, name string) (*models.Tag, error)
+	// GetOrCreateBatch resolves all names with one lookup and creates the missing tags in a
+	// single transaction, so concurrent saves do not create duplicates.
+	GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Tag, error)
+	// Merge moves every recipe of the source tags to the target tag and deletes the sources, in
+	// one transaction. A missing target yields gorm.ErrRecordNotFound.
+	Merge(ctx context.Context, targetID string, sourceIDs []string) (*repositories.TagMergeResult, error)
+	// DeleteUnused deletes the tags without recipes and returns how many were deleted.
+	DeleteUnused(ctx context.Context) (int64, error)
 }
 
 type DefaultTagService struct {
@@ -196,10 +265,12 @@ func (s *DefaultTagService) GetByID(ctx context.Context, id string) (*models.Tag
 }
 
 func (s *DefaultTagService) GetByName(ctx context.Context, name string) (*models.Tag, error) {
-	return s.repo.GetByName(ctx, name)
+	return s.repo.GetByName(ctx, NormalizeEntityName(name))
 }
 
+// Create stores tag, or loads the existing tag with the same name into it.
 func (s *DefaultTagService) Create(ctx context.Context, tag *models.Tag) error {
+	tag.Name = NormalizeEntityName(tag.Name)
 	if tag.Name == "" {
 		return fmt.Errorf("tag name is required")
 	}
@@ -215,6 +286,7 @@ func (s *DefaultTagService) Delete(ctx context.Context, id string) error {
 }
 
 func (s *DefaultTagService) GetOrCreate(ctx context.Context, name string) (*models.Tag, error) {
+	name = NormalizeEntityName(name)
 	tag, err := s.repo.GetByName(ctx, name)
 	if err == nil {
 		return tag, nil
@@ -227,3 +299,30 @@ func (s *DefaultTagService) GetOrCreate(ctx context.Context, name string) (*mode
 	}
 	return tag, nil
 }
+
+func (s *DefaultTagService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Tag, error) {
+	normalized, err := normalizeEntityNames(names)
+	if err != nil {
+		return nil, fmt.Errorf("tag %w", err)
+	}
+	return s.repo.GetOrCreateBatch(ctx, normalized)
+}
+
+func (s *DefaultTagService) Merge(ctx context.Context, targetID string, sourceIDs []string) (*repositories.TagMergeResult, error) {
+	if targetID == "" {
+		return nil, errors.NewValidationError("target tag is required")
+	}
+	if len(sourceIDs) == 0 {
+		return nil, errors.NewValidationError("at least one source tag is required")
+	}
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, errors.NewValidationError("the target tag cannot also be a source")
+		}
+	}
+	return s.repo.Merge(ctx, targetID, sourceIDs)
+}
+
+func (s *DefaultTagService) DeleteUnused(ctx context.Context) (int64, error) {
+	return s.repo.DeleteUnused(ctx)
+}
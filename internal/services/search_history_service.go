@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultSearchHistoryLimit caps the number of searches kept per user.
+const DefaultSearchHistoryLimit = 50
+
+// SearchHistoryService records and retrieves a user's recent recipe searches.
+type SearchHistoryService interface {
+	RecordSearch(ctx context.Context, userID, query string, resultCount int) error
+	GetSearchHistory(ctx context.Context, userID string, limit int) ([]dtos.SearchHistoryEntry, error)
+	ClearSearchHistory(ctx context.Context, userID string) error
+}
+
+// DefaultSearchHistoryService stores search history as a capped Redis list per user.
+// When no Redis client is configured, recording is a no-op and history is empty.
+type DefaultSearchHistoryService struct {
+	redis      *redis.Client
+	maxEntries int
+}
+
+// NewSearchHistoryService creates a new SearchHistoryService backed by the given Redis client.
+func NewSearchHistoryService(redisClient *redis.Client, maxEntries int) SearchHistoryService {
+	if maxEntries <= 0 {
+		maxEntries = DefaultSearchHistoryLimit
+	}
+	return &DefaultSearchHistoryService{redis: redisClient, maxEntries: maxEntries}
+}
+
+func searchHistoryKey(userID string) string {
+	return fmt.Sprintf("search_history:%s", userID)
+}
+
+// RecordSearch prepends a search to the user's history and trims it to the configured cap.
+// Empty queries are ignored.
+func (s *DefaultSearchHistoryService) RecordSearch(ctx context.Context, userID, query string, resultCount int) error {
+	query = strings.TrimSpace(query)
+	if s.redis == nil || userID == "" || query == "" {
+		return nil
+	}
+
+	entry, err := json.Marshal(dtos.SearchHistoryEntry{
+		Query:       query,
+		ResultCount: resultCount,
+		SearchedAt:  dtos.NewTimestamp(time.Now()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode search history entry: %w", err)
+	}
+
+	key := searchHistoryKey(userID)
+	pipe := s.redis.TxPipeline()
+	pipe.LPush(ctx, key, entry)
+	pipe.LTrim(ctx, key, 0, int64(s.maxEntries-1))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record search history: %w", err)
+	}
+	return nil
+}
+
+// GetSearchHistory returns up to limit of the user's most recent searches, newest first.
+func (s *DefaultSearchHistoryService) GetSearchHistory(ctx context.Context, userID string, limit int) ([]dtos.SearchHistoryEntry, error) {
+	entries := []dtos.SearchHistoryEntry{}
+	if s.redis == nil {
+		return entries, nil
+	}
+	if limit <= 0 || limit > s.maxEntries {
+		limit = s.maxEntries
+	}
+
+	values, err := s.redis.LRange(ctx, searchHistoryKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search history: %w", err)
+	}
+
+	for _, value := range values {
+		var entry dtos.SearchHistoryEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ClearSearchHistory removes all recorded searches for the user.
+func (s *DefaultSearchHistoryService) ClearSearchHistory(ctx context.Context, userID string) error {
+	if s.redis == nil {
+		return nil
+	}
+	if err := s.redis.Del(ctx, searchHistoryKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear search history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist records access tokens that were revoked before they expired, e.g. on logout.
+type TokenDenylist interface {
+	Deny(ctx context.Context, token string, until time.Time) error
+	IsDenied(ctx context.Context, token string) (bool, error)
+}
+
+// DefaultTokenDenylist stores revoked tokens in Redis, keyed by a hash of the token and kept
+// until the token would have expired anyway. Without a Redis client nothing is denied.
+type DefaultTokenDenylist struct {
+	redis *redis.Client
+}
+
+// NewTokenDenylist creates a new TokenDenylist backed by the given Redis client.
+func NewTokenDenylist(redisClient *redis.Client) TokenDenylist {
+	return &DefaultTokenDenylist{redis: redisClient}
+}
+
+func deniedTokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("denied_token:%s", hex.EncodeToString(sum[:]))
+}
+
+// Deny rejects the token until the given time. Tokens that have already expired are ignored.
+func (d *DefaultTokenDenylist) Deny(ctx context.Context, token string, until time.Time) error {
+	ttl := time.Until(until)
+	if d.redis == nil || ttl <= 0 {
+		return nil
+	}
+	if err := d.redis.Set(ctx, deniedTokenKey(token), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to deny token: %w", err)
+	}
+	return nil
+}
+
+// IsDenied reports whether the token has been revoked.
+func (d *DefaultTokenDenylist) IsDenied(ctx context.Context, token string) (bool, error) {
+	if d.redis == nil {
+		return false, nil
+	}
+	n, err := d.redis.Exists(ctx, deniedTokenKey(token)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token denylist: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,11 @@
+package services
+
+import "errors"
+
+var (
+	// ErrUserNotFound is returned when the user to change does not exist.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrEmailTaken is returned, wrapped with the email, when the email already belongs to
+	// another account.
+	ErrEmailTaken = errors.New("user already exists")
+)
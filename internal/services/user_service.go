@@ -2,14 +2,19 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"math/rand"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pageza/alchemorsel-v1/internal/email"
+	"github.com/pageza/alchemorsel-v1/internal/errors"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/pageza/alchemorsel-v1/internal/units"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -24,13 +29,38 @@ type UserServiceInterface interface {
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token string, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
 	PatchUser(ctx context.Context, id string, updates map[string]interface{}) error
 	GetAllUsers(ctx context.Context) ([]*models.User, error)
+	ListUsers(ctx context.Context, page, limit int, query string) (*UserList, error)
+}
+
+const (
+	// DefaultUserListLimit is the page size used when ListUsers is given none.
+	DefaultUserListLimit = 20
+	// MaxUserListLimit caps the page size of ListUsers.
+	MaxUserListLimit = 100
+)
+
+// UserList is one page of users with the total number matching the search.
+type UserList struct {
+	Users []*models.User
+	Total int64
+	// Page and Limit are the values applied after defaults and the MaxUserListLimit cap.
+	Page  int
+	Limit int
 }
 
 // UserService is the implementation of UserServiceInterface.
 type UserService struct {
 	repo repositories.UserRepository
+	// Mailer delivers password reset and verification links; when nil they are only logged.
+	Mailer email.Mailer
+	// PasswordResetURL is the page the reset link points at, with the token appended as ?token=.
+	PasswordResetURL string
+	// EmailVerificationURL is the endpoint the verification link points at, with the token
+	// appended as a path segment.
+	EmailVerificationURL string
 }
 
 func NewUserService(repo repositories.UserRepository) *UserService {
@@ -80,7 +110,7 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) error {
 		return err
 	}
 	if existingUser != nil {
-		return fmt.Errorf("user with email %s already exists", user.Email)
+		return fmt.Errorf("%w: %s", ErrEmailTaken, user.Email)
 	}
 
 	// Assign a UUID if not provided
@@ -95,18 +125,72 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) error {
 	}
 	user.Password = string(hashedPassword)
 
-	return s.repo.CreateUser(ctx, user)
+	// The account stays unverified until the emailed token is redeemed.
+	issueEmailVerification(user)
+	if err := s.repo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	s.sendVerificationEmail(ctx, user)
+	return nil
 }
 
 func (s *UserService) GetUser(ctx context.Context, id string) (*models.User, error) {
 	return s.repo.GetUser(ctx, id)
 }
 
+// UpdateUser replaces the name and email of user id with those in user. The password is
+// re-hashed only when user.Password is set; otherwise the stored hash is kept. A new email
+// must be verified again, so changing it clears EmailVerified and issues a new verification
+// token. Other account fields, such as admin status, are left untouched. It returns
+// ErrUserNotFound for an unknown id and ErrEmailTaken when the email belongs to another user.
 func (s *UserService) UpdateUser(ctx context.Context, id string, user *models.User) error {
-	if err := s.validateUser(user); err != nil {
+	if user == nil {
+		return fmt.Errorf("user cannot be nil")
+	}
+	if user.Email == "" {
+		return fmt.Errorf("email is required")
+	}
+	if user.Password != "" && len(user.Password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+
+	existing, err := s.repo.GetUser(ctx, id)
+	if err != nil {
 		return err
 	}
-	return s.repo.UpdateUser(ctx, user)
+	if existing == nil {
+		return ErrUserNotFound
+	}
+
+	// The email may stay the same, but must not belong to another account.
+	owner, err := s.repo.GetUserByEmail(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.ID != id {
+		return fmt.Errorf("%w: %s", ErrEmailTaken, user.Email)
+	}
+
+	emailChanged := !strings.EqualFold(existing.Email, user.Email)
+	if emailChanged {
+		issueEmailVerification(existing)
+	}
+	existing.Name = user.Name
+	existing.Email = user.Email
+	if user.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		existing.Password = string(hashedPassword)
+	}
+	if err := s.repo.UpdateUser(ctx, existing); err != nil {
+		return err
+	}
+	if emailChanged {
+		s.sendVerificationEmail(ctx, existing)
+	}
+	return nil
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
@@ -129,12 +213,48 @@ func (s *UserService) ForgotPassword(ctx context.Context, email string) error {
 	}
 
 	// Generate reset token
-	token := generateResetToken()
+	token := generateToken()
 	user.ResetPasswordToken = token
 	expiry := time.Now().Add(24 * time.Hour)
 	user.ResetPasswordExpires = &expiry
 
-	return s.repo.UpdateUser(ctx, user)
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	// The email is sent in the background and a failed send is only logged, so neither the
+	// response nor its timing reveals whether the email exists.
+	msg := passwordResetMessage(user.Email, s.PasswordResetURL, token)
+	go func(ctx context.Context, userID string) {
+		if err := s.mailer().Send(ctx, msg); err != nil {
+			zap.L().Error("Failed to send password reset email", zap.String("user_id", userID), zap.Error(err))
+		}
+	}(context.WithoutCancel(ctx), user.ID)
+	return nil
+}
+
+// mailer returns the configured Mailer, or one that only logs when none is set.
+func (s *UserService) mailer() email.Mailer {
+	if s.Mailer == nil {
+		return email.LogMailer{}
+	}
+	return s.Mailer
+}
+
+// passwordResetMessage builds the email carrying the reset link for token.
+func passwordResetMessage(to, resetURL, token string) email.Message {
+	link := resetURL
+	if strings.Contains(link, "?") {
+		link += "&token=" + url.QueryEscape(token)
+	} else {
+		link += "?token=" + url.QueryEscape(token)
+	}
+	return email.Message{
+		To:      to,
+		Subject: "Reset your Alchemorsel password",
+		Body: "We received a request to reset your password. Use the link below within 24 hours to choose a new one:\n\n" +
+			link + "\n\nIf you did not ask for this, you can ignore this email.",
+	}
 }
 
 // ResetPassword completes the password reset process
@@ -173,9 +293,10 @@ func (s *UserService) PatchUser(ctx context.Context, id string, updates map[stri
 		return err
 	}
 	if user == nil {
-		return fmt.Errorf("user not found")
+		return ErrUserNotFound
 	}
 	zap.S().Debugw("PatchUser: original user retrieved", "user", user)
+	emailChanged := false
 
 	// Update fields
 	for field, value := range updates {
@@ -183,6 +304,10 @@ func (s *UserService) PatchUser(ctx context.Context, id string, updates map[stri
 		switch field {
 		case "email":
 			if email, ok := value.(string); ok {
+				if !strings.EqualFold(user.Email, email) {
+					issueEmailVerification(user)
+					emailChanged = true
+				}
 				user.Email = email
 				zap.S().Debugw("PatchUser: updated email", "email", email)
 			}
@@ -201,6 +326,14 @@ func (s *UserService) PatchUser(ctx context.Context, id string, updates map[stri
 				user.Password = string(hashedPassword)
 				zap.S().Debug("PatchUser: updated password")
 			}
+		case "preferred_units":
+			value, _ := value.(string)
+			system, err := units.ParseSystem(value)
+			if err != nil {
+				return errors.NewValidationError(err.Error())
+			}
+			user.PreferredUnits = string(system)
+			zap.S().Debugw("PatchUser: updated preferred units", "preferred_units", user.PreferredUnits)
 		default:
 			zap.S().Warnw("PatchUser: unrecognized field, skipping update", "field", field)
 		}
@@ -211,6 +344,9 @@ func (s *UserService) PatchUser(ctx context.Context, id string, updates map[stri
 		return err
 	}
 	zap.S().Debugw("PatchUser: repository updated user successfully", "userID", user.ID)
+	if emailChanged {
+		s.sendVerificationEmail(ctx, user)
+	}
 	return nil
 }
 
@@ -219,8 +355,29 @@ func (s *UserService) GetAllUsers(ctx context.Context) ([]*models.User, error) {
 	return s.repo.GetAllUsers(ctx)
 }
 
-// Helper function to generate reset token
-func generateResetToken() string {
+// ListUsers retrieves a page of users whose name or email contains query, or all users when
+// query is empty. Pages start at 1; limit defaults to DefaultUserListLimit and is capped at
+// MaxUserListLimit.
+func (s *UserService) ListUsers(ctx context.Context, page, limit int, query string) (*UserList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultUserListLimit
+	}
+	if limit > MaxUserListLimit {
+		limit = MaxUserListLimit
+	}
+	users, total, err := s.repo.ListUsers(ctx, page, limit, strings.TrimSpace(query))
+	if err != nil {
+		return nil, err
+	}
+	return &UserList{Users: users, Total: total, Page: page, Limit: limit}, nil
+}
+
+// generateToken returns 32 random bytes from crypto/rand, hex encoded, for the password reset
+// and email verification tokens.
+func generateToken() string {
 	b := make([]byte, 32)
 	rand.Read(b)
 	return hex.EncodeToString(b)
@@ -0,0 +1,226 @@
+package shopping
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/parsers"
+	"github.com/pageza/alchemorsel-v1/internal/units"
+)
+
+// Categories a shopping list is grouped by, in the order they are listed.
+const (
+	CategoryProduce = "produce"
+	CategoryMeat    = "meat & seafood"
+	CategoryDairy   = "dairy & eggs"
+	CategoryBakery  = "bakery"
+	CategorySpices  = "spices & seasonings"
+	CategoryPantry  = "pantry"
+	CategoryOther   = "other"
+)
+
+var categoryOrder = []string{CategoryProduce, CategoryMeat, CategoryDairy, CategoryBakery, CategorySpices, CategoryPantry, CategoryOther}
+
+// categoryWords maps words found in ingredient names to their category. Names are matched word
+// by word from the last one, so "chicken stock" is pantry and "garlic powder" a spice.
+var categoryWords = map[string]string{
+	"apple": CategoryProduce, "avocado": CategoryProduce, "banana": CategoryProduce, "basil": CategoryProduce,
+	"bean sprout": CategoryProduce, "berry": CategoryProduce, "broccoli": CategoryProduce, "cabbage": CategoryProduce,
+	"carrot": CategoryProduce, "celery": CategoryProduce, "cilantro": CategoryProduce, "cucumber": CategoryProduce,
+	"garlic": CategoryProduce, "ginger": CategoryProduce, "kale": CategoryProduce, "lemon": CategoryProduce,
+	"lettuce": CategoryProduce, "lime": CategoryProduce, "mushroom": CategoryProduce, "onion": CategoryProduce,
+	"parsley": CategoryProduce, "pepper": CategoryProduce, "potato": CategoryProduce, "spinach": CategoryProduce,
+	"tomato": CategoryProduce, "zucchini": CategoryProduce,
+
+	"bacon": CategoryMeat, "beef": CategoryMeat, "chicken": CategoryMeat, "fish": CategoryMeat,
+	"ham": CategoryMeat, "lamb": CategoryMeat, "pork": CategoryMeat, "salmon": CategoryMeat,
+	"sausage": CategoryMeat, "shrimp": CategoryMeat, "tuna": CategoryMeat, "turkey": CategoryMeat,
+
+	"butter": CategoryDairy, "cheese": CategoryDairy, "cream": CategoryDairy, "egg": CategoryDairy,
+	"milk": CategoryDairy, "mozzarella": CategoryDairy, "parmesan": CategoryDairy, "yogurt": CategoryDairy,
+
+	"bread": CategoryBakery, "bun": CategoryBakery, "tortilla": CategoryBakery, "baguette": CategoryBakery,
+
+	"cinnamon": CategorySpices, "cumin": CategorySpices, "oregano": CategorySpices, "paprika": CategorySpices,
+	"powder": CategorySpices, "salt": CategorySpices, "seasoning": CategorySpices, "thyme": CategorySpices,
+	"black pepper": CategorySpices,
+
+	"broth": CategoryPantry, "flour": CategoryPantry, "honey": CategoryPantry, "noodle": CategoryPantry,
+	"oil": CategoryPantry, "pasta": CategoryPantry, "rice": CategoryPantry, "sauce": CategoryPantry,
+	"stock": CategoryPantry, "sugar": CategoryPantry, "vinegar": CategoryPantry,
+}
+
+// Category guesses the shopping category of an ingredient from the words in its name, falling
+// back to CategoryOther.
+func Category(name string) string {
+	words := strings.Fields(singular(parsers.NormalizeIngredient(name)))
+	for i := len(words) - 1; i >= 0; i-- {
+		if i > 0 {
+			if category, ok := categoryWords[words[i-1]+" "+words[i]]; ok {
+				return category
+			}
+		}
+		if category, ok := categoryWords[words[i]]; ok {
+			return category
+		}
+	}
+	return CategoryOther
+}
+
+// Item is one line of a shopping list. Amount and Unit are empty when the recipes list the
+// ingredient without a quantity.
+type Item struct {
+	Name      string
+	Amount    string
+	Unit      string
+	Category  string
+	RecipeIDs []string
+}
+
+// List is a consolidated shopping list. Items are sorted by category, then name. Incompatible
+// holds the ingredients needed in amounts that cannot be added up, such as "200 g" and "2 cups"
+// of flour, with one item per amount.
+type List struct {
+	Items        []Item
+	Incompatible []Item
+}
+
+// Builder merges the ingredients of several recipes into a List.
+type Builder struct {
+	system  units.System
+	entries map[string]*entry
+}
+
+// entry collects the quantities of one ingredient, keyed by what they can be added up in.
+type entry struct {
+	name    string
+	buckets map[string]*bucket
+	order   []string
+}
+
+type bucket struct {
+	value     float64
+	amount    string
+	unit      string
+	summable  bool
+	recipeIDs []string
+}
+
+// NewBuilder creates a Builder that expresses merged masses and volumes in the given system.
+func NewBuilder(system units.System) *Builder {
+	return &Builder{system: system, entries: make(map[string]*entry)}
+}
+
+// Add adds the ingredients of a recipe. Ingredients are merged by their name after
+// parsers.NormalizeIngredient, in singular form. Masses and volumes are summed across units via
+// units.Normalize; other units are summed only when they are the same.
+func (b *Builder) Add(recipeID string, ingredients []models.Ingredient) {
+	for _, ing := range ingredients {
+		name := parsers.NormalizeIngredient(ing.Name)
+		if name == "" {
+			continue
+		}
+		e, ok := b.entries[singular(name)]
+		if !ok {
+			e = &entry{name: name, buckets: make(map[string]*bucket)}
+			b.entries[singular(name)] = e
+		}
+
+		key, unit, quantity, summable := quantityOf(ing)
+		bk, ok := e.buckets[key]
+		if !ok {
+			bk = &bucket{amount: strings.TrimSpace(ing.Amount), unit: unit, summable: summable}
+			e.buckets[key] = bk
+			e.order = append(e.order, key)
+		}
+		bk.value += quantity
+		if !containsString(bk.recipeIDs, recipeID) {
+			bk.recipeIDs = append(bk.recipeIDs, recipeID)
+		}
+	}
+}
+
+// quantityOf returns the key of the quantities ing can be added to, the unit they are summed in
+// and the quantity of ing in it. summable is false when the amount is not a number, such as
+// "to taste"; such amounts are only merged with identical ones.
+func quantityOf(ing models.Ingredient) (key, unit string, quantity float64, summable bool) {
+	if value, base, ok := units.Normalize(ing.Amount, ing.Unit); ok {
+		return "base:" + base, base, value, true
+	}
+	unit = strings.TrimSpace(ing.Unit)
+	if value, err := units.ParseAmount(ing.Amount); err == nil {
+		return "unit:" + strings.ToLower(unit), unit, value, true
+	}
+	return "text:" + strings.ToLower(strings.TrimSpace(ing.Amount)) + "|" + strings.ToLower(unit), unit, 0, false
+}
+
+// List returns the merged shopping list.
+func (b *Builder) List() List {
+	list := List{Items: []Item{}, Incompatible: []Item{}}
+	for _, e := range b.entries {
+		category := Category(e.name)
+		for _, key := range e.order {
+			item := b.item(e.name, category, e.buckets[key])
+			if len(e.order) == 1 {
+				list.Items = append(list.Items, item)
+			} else {
+				list.Incompatible = append(list.Incompatible, item)
+			}
+		}
+	}
+	sortItems(list.Items)
+	sortItems(list.Incompatible)
+	return list
+}
+
+func (b *Builder) item(name, category string, bk *bucket) Item {
+	item := Item{Name: name, Amount: bk.amount, Unit: bk.unit, Category: category, RecipeIDs: bk.recipeIDs}
+	if bk.summable {
+		item.Amount, item.Unit = units.Express(bk.value, bk.unit, b.system)
+	}
+	return item
+}
+
+// sortItems orders items by category, then name, unit and amount.
+func sortItems(items []Item) {
+	rank := make(map[string]int, len(categoryOrder))
+	for i, category := range categoryOrder {
+		rank[category] = i
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Category != items[j].Category {
+			return rank[items[i].Category] < rank[items[j].Category]
+		}
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		if items[i].Unit != items[j].Unit {
+			return items[i].Unit < items[j].Unit
+		}
+		return items[i].Amount < items[j].Amount
+	})
+}
+
+// singular naively strips plural endings from each word, so "tomatoes" and "tomato" merge.
+func singular(phrase string) string {
+	words := strings.Fields(phrase)
+	for i, word := range words {
+		switch {
+		case len(word) > 4 && strings.HasSuffix(word, "oes"):
+			words[i] = strings.TrimSuffix(word, "es")
+		case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package shopping
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/units"
+)
+
+func TestBuilderMergesCompatibleAmounts(t *testing.T) {
+	b := NewBuilder(units.Metric)
+	b.Add("soup", []models.Ingredient{
+		{Name: "Tomatoes", Amount: "4", Unit: ""},
+		{Name: "scallions", Amount: "2", Unit: ""},
+		{Name: "butter", Amount: "1/2", Unit: "kg"},
+		{Name: "salt", Amount: "to taste"},
+	})
+	b.Add("pasta", []models.Ingredient{
+		{Name: "tomato", Amount: "2", Unit: ""},
+		{Name: "Butter", Amount: "250", Unit: "g"},
+		{Name: "salt", Amount: "To taste"},
+	})
+
+	list := b.List()
+	want := []Item{
+		{Name: "green onions", Amount: "2", Category: CategoryProduce, RecipeIDs: []string{"soup"}},
+		{Name: "tomatoes", Amount: "6", Category: CategoryProduce, RecipeIDs: []string{"soup", "pasta"}},
+		{Name: "butter", Amount: "750", Unit: "g", Category: CategoryDairy, RecipeIDs: []string{"soup", "pasta"}},
+		{Name: "salt", Amount: "to taste", Category: CategorySpices, RecipeIDs: []string{"soup", "pasta"}},
+	}
+	if !reflect.DeepEqual(list.Items, want) {
+		t.Errorf("Unexpected items:\n got %+v\nwant %+v", list.Items, want)
+	}
+	if len(list.Incompatible) != 0 {
+		t.Errorf("Expected no incompatible items, got %+v", list.Incompatible)
+	}
+}
+
+func TestBuilderListsIncompatibleUnitsSeparately(t *testing.T) {
+	b := NewBuilder(units.Imperial)
+	b.Add("bread", []models.Ingredient{{Name: "flour", Amount: "500", Unit: "g"}})
+	b.Add("cake", []models.Ingredient{{Name: "flour", Amount: "2", Unit: "cups"}, {Name: "flour", Amount: "1", Unit: "cup"}})
+
+	list := b.List()
+	if len(list.Items) != 0 {
+		t.Errorf("Expected no merged items, got %+v", list.Items)
+	}
+	want := []Item{
+		{Name: "flour", Amount: "3", Unit: "cup", Category: CategoryPantry, RecipeIDs: []string{"cake"}},
+		{Name: "flour", Amount: "1.1", Unit: "lb", Category: CategoryPantry, RecipeIDs: []string{"bread"}},
+	}
+	if !reflect.DeepEqual(list.Incompatible, want) {
+		t.Errorf("Unexpected incompatible items:\n got %+v\nwant %+v", list.Incompatible, want)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	for name, want := range map[string]string{
+		"garlic":        CategoryProduce,
+		"garlic powder": CategorySpices,
+		"black pepper":  CategorySpices,
+		"red pepper":    CategoryProduce,
+		"chicken stock": CategoryPantry,
+		"Eggs":          CategoryDairy,
+		"saffron":       CategoryOther,
+	} {
+		if got := Category(name); got != want {
+			t.Errorf("Category(%q) = %q; want %q", name, got, want)
+		}
+	}
+}
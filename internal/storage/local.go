@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage writes files below a directory on the local disk, which the router serves
+// under its public URL.
+type LocalStorage struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStorage creates a LocalStorage rooted at dir. An empty publicURL defaults to
+// DefaultLocalPublicURL.
+func NewLocalStorage(dir, publicURL string) *LocalStorage {
+	if publicURL == "" {
+		publicURL = DefaultLocalPublicURL
+	}
+	return &LocalStorage{dir: dir, publicURL: publicURL}
+}
+
+// Dir returns the directory files are written to.
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// Put implements Storage. The file is written to a temporary name and renamed into place, so
+// readers never see a partial file.
+func (s *LocalStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return objectURL(s.publicURL, key), nil
+}
+
+// Delete implements Storage.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"sync"
+)
+
+// Object is a file held by MemoryStorage.
+type Object struct {
+	ContentType string
+	Data        []byte
+}
+
+// MemoryStorage keeps files in memory, for tests.
+type MemoryStorage struct {
+	publicURL string
+
+	mu      sync.Mutex
+	objects map[string]Object
+}
+
+// NewMemoryStorage creates an empty MemoryStorage whose URLs start with publicURL.
+func NewMemoryStorage(publicURL string) *MemoryStorage {
+	return &MemoryStorage{publicURL: publicURL, objects: make(map[string]Object)}
+}
+
+// Put implements Storage.
+func (s *MemoryStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = Object{ContentType: contentType, Data: append([]byte(nil), data...)}
+	return objectURL(s.publicURL, key), nil
+}
+
+// Delete implements Storage.
+func (s *MemoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+// Get returns the file stored under key.
+func (s *MemoryStorage) Get(key string) (Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	return object, ok
+}
+
+// Len returns the number of stored files.
+func (s *MemoryStorage) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.objects)
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+)
+
+// S3Storage stores files in a bucket of an S3-compatible object store, such as AWS S3 or
+// MinIO, with SigV4-signed requests addressed path-style as endpoint/bucket/key.
+type S3Storage struct {
+	endpoint    string
+	bucket      string
+	region      string
+	publicURL   string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+// NewS3Storage creates an S3Storage for the bucket in cfg, using credentials from the default
+// AWS credential chain. An empty cfg.PublicURL defaults to the bucket's URL.
+func NewS3Storage(ctx context.Context, cfg config.StorageConfig) (*S3Storage, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 storage driver")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.S3Region))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+	}
+	return newS3Storage(cfg, awsCfg.Credentials), nil
+}
+
+func newS3Storage(cfg config.StorageConfig, credentials aws.CredentialsProvider) *S3Storage {
+	s := &S3Storage{
+		endpoint:    strings.TrimRight(cfg.S3Endpoint, "/"),
+		bucket:      cfg.S3Bucket,
+		region:      cfg.S3Region,
+		publicURL:   cfg.PublicURL,
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.publicURL == "" {
+		s.publicURL = s.endpoint + "/" + s.bucket
+	}
+	return s
+}
+
+// Put implements Storage.
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if err := s.do(ctx, http.MethodPut, key, header, data); err != nil {
+		return "", err
+	}
+	return objectURL(s.publicURL, key), nil
+}
+
+// Delete implements Storage.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	return s.do(ctx, http.MethodDelete, key, http.Header{}, nil)
+}
+
+// do sends a signed request for the object under key. S3 answers deletes of missing objects
+// with 204, so any 2xx response is a success.
+func (s *S3Storage) do(ctx context.Context, method, key string, header http.Header, body []byte) error {
+	target := s.endpoint + "/" + url.PathEscape(s.bucket) + "/" + escapeKey(key)
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load S3 credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, credentials, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign S3 request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("S3 %s %s failed with status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// escapeKey escapes each segment of a slash-separated key for use in a URL path.
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
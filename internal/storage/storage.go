@@ -0,0 +1,50 @@
+// Package storage keeps uploaded files, such as recipe photos, on local disk, in an
+// S3-compatible object store or, in tests, in memory.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+)
+
+// DefaultLocalPublicURL is the path the local driver's files are served under.
+const DefaultLocalPublicURL = "/uploads"
+
+// Storage stores files under slash-separated keys, such as "recipes/<id>/<name>.webp". It lets
+// callers substitute MemoryStorage in tests.
+type Storage interface {
+	// Put stores data under key, replacing any existing file, and returns the URL it is served from.
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete removes the file stored under key. Deleting a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the storage selected by cfg.Driver: "s3" stores files in the configured bucket,
+// anything else writes them to cfg.LocalDir.
+func New(ctx context.Context, cfg config.StorageConfig) (Storage, error) {
+	if cfg.Driver == "s3" {
+		return NewS3Storage(ctx, cfg)
+	}
+	return NewLocalStorage(cfg.LocalDir, cfg.PublicURL), nil
+}
+
+// validateKey rejects keys that are empty or could escape the storage root.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// objectURL joins the public base URL and a key.
+func objectURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+)
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"recipes/1/photo.webp", "photo.png"} {
+		if err := validateKey(key); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "/etc/passwd", "../secret", "recipes/../../secret", "recipes//photo.png", `recipes\photo.png`} {
+		if err := validateKey(key); err == nil {
+			t.Errorf("Expected %q to be rejected", key)
+		}
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "")
+	ctx := context.Background()
+
+	url, err := s.Put(ctx, "recipes/1/photo.webp", "image/webp", []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "/uploads/recipes/1/photo.webp" {
+		t.Errorf("Unexpected URL %q", url)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "recipes", "1", "photo.webp"))
+	if err != nil || string(data) != "data" {
+		t.Fatalf("Expected the file to be written, got %q, %v", data, err)
+	}
+
+	if err := s.Delete(ctx, "recipes/1/photo.webp"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "recipes", "1", "photo.webp")); !os.IsNotExist(err) {
+		t.Errorf("Expected the file to be removed, got %v", err)
+	}
+	if err := s.Delete(ctx, "recipes/1/photo.webp"); err != nil {
+		t.Errorf("Expected deleting a missing file to succeed, got %v", err)
+	}
+	if _, err := s.Put(ctx, "../photo.webp", "image/webp", []byte("data")); err == nil {
+		t.Error("Expected a key outside the directory to be rejected")
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	type request struct {
+		method, path, contentType, auth, body string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.URL.EscapedPath(), r.Header.Get("Content-Type"), r.Header.Get("Authorization"), string(body)})
+		if strings.Contains(r.URL.Path, "denied") {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	credentials := aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	}))
+	s := newS3Storage(config.StorageConfig{S3Endpoint: server.URL + "/", S3Bucket: "photos", S3Region: "eu-west-1"}, credentials)
+	ctx := context.Background()
+
+	url, err := s.Put(ctx, "recipes/1/my photo.png", "image/png", []byte("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := server.URL + "/photos/recipes/1/my photo.png"; url != want {
+		t.Errorf("Expected URL %q, got %q", want, url)
+	}
+	if err := s.Delete(ctx, "recipes/1/my photo.png"); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	put := requests[0]
+	if put.method != http.MethodPut || put.path != "/photos/recipes/1/my%20photo.png" || put.contentType != "image/png" || put.body != "png" {
+		t.Errorf("Unexpected upload %+v", put)
+	}
+	if !strings.HasPrefix(put.auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(put.auth, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("Expected a SigV4 signature, got %q", put.auth)
+	}
+	if requests[1].method != http.MethodDelete {
+		t.Errorf("Expected a delete, got %s", requests[1].method)
+	}
+
+	if _, err := s.Put(ctx, "denied.png", "image/png", []byte("png")); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Expected the S3 error to be reported, got %v", err)
+	}
+}
+
+func TestNewS3StorageRequiresBucket(t *testing.T) {
+	if _, err := New(context.Background(), config.StorageConfig{Driver: "s3"}); err == nil {
+		t.Error("Expected an error without S3_ENDPOINT and S3_BUCKET")
+	}
+}
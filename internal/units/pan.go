@@ -0,0 +1,73 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Pan shapes supported by PanArea.
+const (
+	RoundPan       = "round"
+	RectangularPan = "rectangular"
+)
+
+// Pan describes a baking pan. Round pans use Diameter; rectangular pans use Width and Length.
+// Dimensions can be in any unit as long as pans being compared use the same one.
+type Pan struct {
+	Shape    string
+	Diameter float64
+	Width    float64
+	Length   float64
+}
+
+// PanArea returns the base area of the pan.
+func PanArea(p Pan) (float64, error) {
+	switch strings.ToLower(strings.TrimSpace(p.Shape)) {
+	case RoundPan:
+		if p.Diameter <= 0 {
+			return 0, fmt.Errorf("round pan diameter must be positive")
+		}
+		return math.Pi * (p.Diameter / 2) * (p.Diameter / 2), nil
+	case RectangularPan:
+		if p.Width <= 0 || p.Length <= 0 {
+			return 0, fmt.Errorf("rectangular pan width and length must be positive")
+		}
+		return p.Width * p.Length, nil
+	}
+	return 0, fmt.Errorf("unsupported pan shape: %s (expected round or rectangular)", p.Shape)
+}
+
+// PanScaleRatio returns the factor ingredient amounts are multiplied by to move a
+// recipe from the source pan to the target pan.
+func PanScaleRatio(source, target Pan) (float64, error) {
+	sourceArea, err := PanArea(source)
+	if err != nil {
+		return 0, fmt.Errorf("source pan: %w", err)
+	}
+	targetArea, err := PanArea(target)
+	if err != nil {
+		return 0, fmt.Errorf("target pan: %w", err)
+	}
+	return targetArea / sourceArea, nil
+}
+
+// ScaleBakeTime adjusts a bake time in minutes for a pan scaled by ratio. Scaling amounts by
+// area keeps the batter depth the same, so only the time for heat to reach the centre changes;
+// it is approximated as growing with the square root of the area ratio.
+func ScaleBakeTime(minutes int, ratio float64) int {
+	if minutes <= 0 || ratio <= 0 {
+		return minutes
+	}
+	return int(math.Round(float64(minutes) * math.Sqrt(ratio)))
+}
+
+// ScaleAmount multiplies a parsed amount by factor, returning ok=false when the amount
+// cannot be parsed.
+func ScaleAmount(amount string, factor float64) (string, bool) {
+	value, err := ParseAmount(amount)
+	if err != nil {
+		return amount, false
+	}
+	return FormatAmount(value * factor), true
+}
@@ -0,0 +1,60 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPanScaleRatio(t *testing.T) {
+	cases := []struct {
+		name           string
+		source, target Pan
+		want           float64
+	}{
+		{"round to round", Pan{Shape: "round", Diameter: 8}, Pan{Shape: "round", Diameter: 10}, 1.5625},
+		{"rect to rect", Pan{Shape: "rectangular", Width: 8, Length: 8}, Pan{Shape: "rectangular", Width: 9, Length: 13}, 1.828125},
+		{"round to rect", Pan{Shape: "round", Diameter: 9}, Pan{Shape: "Rectangular", Width: 9, Length: 13}, 117 / (math.Pi * 4.5 * 4.5)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PanScaleRatio(tc.source, tc.target)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("Expected ratio %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPanScaleRatioRejectsInvalidDimensions(t *testing.T) {
+	invalid := []Pan{
+		{Shape: "round", Diameter: 0},
+		{Shape: "rectangular", Width: 9, Length: -1},
+		{Shape: "hexagonal", Diameter: 9},
+	}
+	for _, pan := range invalid {
+		if _, err := PanScaleRatio(Pan{Shape: "round", Diameter: 8}, pan); err == nil {
+			t.Errorf("Expected error for pan %+v, got nil", pan)
+		}
+	}
+}
+
+func TestScaleBakeTime(t *testing.T) {
+	if got := ScaleBakeTime(30, 1.5625); got != 38 {
+		t.Errorf("Expected 38 minutes, got %d", got)
+	}
+	if got := ScaleBakeTime(0, 2); got != 0 {
+		t.Errorf("Expected unset bake time to stay 0, got %d", got)
+	}
+}
+
+func TestScaleAmount(t *testing.T) {
+	if got, ok := ScaleAmount("1 1/2", 2); !ok || got != "3" {
+		t.Errorf("Expected 3, got %q (ok=%v)", got, ok)
+	}
+	if got, ok := ScaleAmount("to taste", 2); ok || got != "to taste" {
+		t.Errorf("Expected unparseable amount unchanged, got %q (ok=%v)", got, ok)
+	}
+}
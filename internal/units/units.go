@@ -0,0 +1,201 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// System identifies a measurement system ingredient amounts can be expressed in.
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// DefaultSystem is used when neither the request nor the user specifies a system.
+const DefaultSystem = Imperial
+
+// ParseSystem validates a measurement system name. Matching is case-insensitive.
+func ParseSystem(value string) (System, error) {
+	switch System(strings.ToLower(strings.TrimSpace(value))) {
+	case Metric:
+		return Metric, nil
+	case Imperial:
+		return Imperial, nil
+	}
+	return "", fmt.Errorf("unsupported measurement units: %s (expected metric or imperial)", value)
+}
+
+type dimension int
+
+const (
+	mass dimension = iota
+	volume
+)
+
+type unitInfo struct {
+	dimension dimension
+	system    System
+	// factor converts one of this unit into the base unit (grams or millilitres).
+	factor float64
+}
+
+var knownUnits = map[string]unitInfo{
+	"g":      {mass, Metric, 1},
+	"gram":   {mass, Metric, 1},
+	"grams":  {mass, Metric, 1},
+	"kg":     {mass, Metric, 1000},
+	"oz":     {mass, Imperial, 28.3495},
+	"ounce":  {mass, Imperial, 28.3495},
+	"ounces": {mass, Imperial, 28.3495},
+	"lb":     {mass, Imperial, 453.592},
+	"lbs":    {mass, Imperial, 453.592},
+	"pound":  {mass, Imperial, 453.592},
+	"pounds": {mass, Imperial, 453.592},
+
+	"ml":          {volume, Metric, 1},
+	"milliliter":  {volume, Metric, 1},
+	"milliliters": {volume, Metric, 1},
+	"l":           {volume, Metric, 1000},
+	"liter":       {volume, Metric, 1000},
+	"liters":      {volume, Metric, 1000},
+	"tsp":         {volume, Imperial, 4.92892},
+	"teaspoon":    {volume, Imperial, 4.92892},
+	"teaspoons":   {volume, Imperial, 4.92892},
+	"tbsp":        {volume, Imperial, 14.7868},
+	"tablespoon":  {volume, Imperial, 14.7868},
+	"tablespoons": {volume, Imperial, 14.7868},
+	"fl oz":       {volume, Imperial, 29.5735},
+	"cup":         {volume, Imperial, 236.588},
+	"cups":        {volume, Imperial, 236.588},
+	"pint":        {volume, Imperial, 473.176},
+	"pints":       {volume, Imperial, 473.176},
+	"quart":       {volume, Imperial, 946.353},
+	"quarts":      {volume, Imperial, 946.353},
+	"gallon":      {volume, Imperial, 3785.41},
+	"gallons":     {volume, Imperial, 3785.41},
+}
+
+// target describes a unit a converted amount may be expressed in.
+// Targets are ordered from largest to smallest; the first one the amount reaches is used.
+type target struct {
+	unit   string
+	factor float64
+}
+
+var targets = map[System]map[dimension][]target{
+	Metric: {
+		mass:   {{"kg", 1000}, {"g", 1}},
+		volume: {{"l", 1000}, {"ml", 1}},
+	},
+	Imperial: {
+		mass:   {{"lb", 453.592}, {"oz", 28.3495}},
+		volume: {{"cup", 236.588}, {"tbsp", 14.7868}, {"tsp", 4.92892}},
+	},
+}
+
+// Convert expresses amount of unit in the given system. Amounts may be whole numbers,
+// decimals, fractions ("1/2") or mixed numbers ("1 1/2"). Amounts that cannot be parsed,
+// units without a known conversion (e.g. "pinch") and units already in the target system
+// are returned unchanged.
+func Convert(amount, unit string, to System) (string, string) {
+	info, ok := knownUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !ok || info.system == to {
+		return amount, unit
+	}
+	value, err := ParseAmount(amount)
+	if err != nil {
+		return amount, unit
+	}
+
+	return express(value*info.factor, info.dimension, to)
+}
+
+// Convertible reports whether Convert can change amount of unit into another system: the unit
+// has a known conversion and the amount can be parsed.
+func Convertible(amount, unit string) bool {
+	if _, ok := knownUnits[strings.ToLower(strings.TrimSpace(unit))]; !ok {
+		return false
+	}
+	_, err := ParseAmount(amount)
+	return err == nil
+}
+
+// Normalize converts amount of unit into its base unit, returning the value and "g" for
+// masses or "ml" for volumes. ok is false when the amount or unit is not recognised.
+func Normalize(amount, unit string) (value float64, base string, ok bool) {
+	info, known := knownUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !known {
+		return 0, "", false
+	}
+	parsed, err := ParseAmount(amount)
+	if err != nil {
+		return 0, "", false
+	}
+	if info.dimension == mass {
+		return parsed * info.factor, "g", true
+	}
+	return parsed * info.factor, "ml", true
+}
+
+// ParseAmount parses whole numbers, decimals, fractions ("1/2") and mixed numbers ("1 1/2").
+func ParseAmount(amount string) (float64, error) {
+	var total float64
+	fields := strings.Fields(amount)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty amount")
+	}
+	for _, field := range fields {
+		if num, den, found := strings.Cut(field, "/"); found {
+			n, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, err
+			}
+			d, err := strconv.ParseFloat(den, 64)
+			if err != nil || d == 0 {
+				return 0, fmt.Errorf("invalid fraction: %s", field)
+			}
+			total += n / d
+			continue
+		}
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, err
+		}
+		total += v
+	}
+	return total, nil
+}
+
+// Express formats a value in a base unit returned by Normalize ("g" or "ml") in the largest unit
+// of the given system it reaches, e.g. 1500 g as "1.5" "kg". Other base units are returned as is.
+func Express(value float64, base string, to System) (string, string) {
+	switch base {
+	case "g":
+		return express(value, mass, to)
+	case "ml":
+		return express(value, volume, to)
+	}
+	return FormatAmount(value), base
+}
+
+// express formats a value in the base unit of dim using the largest target of the system it reaches.
+func express(value float64, dim dimension, to System) (string, string) {
+	candidates := targets[to][dim]
+	chosen := candidates[len(candidates)-1]
+	for _, t := range candidates {
+		if value >= t.factor {
+			chosen = t
+			break
+		}
+	}
+	return FormatAmount(value / chosen.factor), chosen.unit
+}
+
+// FormatAmount rounds to two decimal places and drops trailing zeros.
+func FormatAmount(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,82 @@
+package units
+
+import "testing"
+
+func TestParseSystem(t *testing.T) {
+	for input, want := range map[string]System{"metric": Metric, "Imperial": Imperial, " METRIC ": Metric} {
+		got, err := ParseSystem(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSystem(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSystem("cubits"); err == nil {
+		t.Error("Expected error for unsupported system, got nil")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		amount, unit string
+		to           System
+		wantAmount   string
+		wantUnit     string
+	}{
+		{"1", "cup", Metric, "236.59", "ml"},
+		{"1 1/2", "lb", Metric, "680.39", "g"},
+		{"3", "pounds", Metric, "1.36", "kg"},
+		{"1/2", "tsp", Metric, "2.46", "ml"},
+		{"200", "g", Imperial, "7.05", "oz"},
+		{"1", "kg", Imperial, "2.2", "lb"},
+		{"500", "ml", Imperial, "2.11", "cup"},
+		{"30", "ml", Imperial, "2.03", "tbsp"},
+		{"5", "ml", Imperial, "1.01", "tsp"},
+		// Already in the target system.
+		{"2", "cups", Imperial, "2", "cups"},
+		// No known conversion.
+		{"1", "pinch", Metric, "1", "pinch"},
+		// Unparseable amount.
+		{"a few", "cups", Metric, "a few", "cups"},
+	}
+	for _, tc := range cases {
+		amount, unit := Convert(tc.amount, tc.unit, tc.to)
+		if amount != tc.wantAmount || unit != tc.wantUnit {
+			t.Errorf("Convert(%q, %q, %s) = %q %q; want %q %q", tc.amount, tc.unit, tc.to, amount, unit, tc.wantAmount, tc.wantUnit)
+		}
+	}
+}
+
+func TestConvertible(t *testing.T) {
+	for _, tc := range []struct {
+		amount, unit string
+		want         bool
+	}{
+		{"1", "cup", true},
+		{"1 1/2", " Grams ", true},
+		{"1", "pinch", false},
+		{"a few", "cups", false},
+		{"", "g", false},
+	} {
+		if got := Convertible(tc.amount, tc.unit); got != tc.want {
+			t.Errorf("Convertible(%q, %q) = %v; want %v", tc.amount, tc.unit, got, tc.want)
+		}
+	}
+}
+
+func TestExpress(t *testing.T) {
+	for _, tc := range []struct {
+		value                float64
+		base                 string
+		to                   System
+		wantAmount, wantUnit string
+	}{
+		{1500, "g", Metric, "1.5", "kg"},
+		{250, "ml", Metric, "250", "ml"},
+		{473.176, "ml", Imperial, "2", "cup"},
+		{3, "each", Metric, "3", "each"},
+	} {
+		amount, unit := Express(tc.value, tc.base, tc.to)
+		if amount != tc.wantAmount || unit != tc.wantUnit {
+			t.Errorf("Express(%v, %q, %s) = %q %q; want %q %q", tc.value, tc.base, tc.to, amount, unit, tc.wantAmount, tc.wantUnit)
+		}
+	}
+}
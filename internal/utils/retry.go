@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -19,3 +22,54 @@ func Retry(maxAttempts int, delay time.Duration, fn func() error) error {
 	}
 	return err
 }
+
+// PermanentError marks an error that RetryWithBackoff must not retry.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps err so that RetryWithBackoff returns it without further attempts.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// RetryWithBackoff calls fn up to maxAttempts times, waiting baseDelay after the first failure
+// and doubling the wait after each subsequent one. An error that implements
+// RetryAfter() time.Duration with a positive value overrides the computed wait. Retrying stops
+// early when fn returns a Permanent error or ctx is done. It returns the number of attempts
+// made and the last error, unwrapped from Permanent.
+func RetryWithBackoff(ctx context.Context, maxAttempts int, baseDelay time.Duration, fn func() error) (int, error) {
+	var err error
+	delay := baseDelay
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return attempt, nil
+		}
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			return attempt, permanent.Err
+		}
+		if attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		wait := delay
+		var hinted interface{ RetryAfter() time.Duration }
+		if errors.As(err, &hinted) && hinted.RetryAfter() > 0 {
+			wait = hinted.RetryAfter()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
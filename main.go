@@ -1,16 +0,0 @@
-package main
-
-import (
-	"go.uber.org/zap"
-)
-
-func main() {
-	logger, err := zap.NewDevelopment()
-	if err != nil {
-		panic(err)
-	}
-	zap.ReplaceGlobals(logger)
-	logger.Info("Application started")
-
-	// Application code that uses the logger
-}
@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRedisConfig(t *testing.T) {
+	t.Run("defaults without redis", func(t *testing.T) {
+		t.Setenv("REDIS_ADDR", "")
+		t.Setenv("REDIS_HOST", "")
+
+		cfg := config.LoadRedisConfig()
+
+		assert.Equal(t, "", cfg.Addr)
+		assert.Equal(t, 0, cfg.DB)
+		assert.Equal(t, 10, cfg.PoolSize)
+		assert.Equal(t, 5*time.Second, cfg.DialTimeout)
+	})
+
+	t.Run("host and port", func(t *testing.T) {
+		t.Setenv("REDIS_ADDR", "")
+		t.Setenv("REDIS_HOST", "cache")
+		t.Setenv("REDIS_PORT", "")
+
+		assert.Equal(t, "cache:6379", config.LoadRedisConfig().Addr)
+	})
+
+	t.Run("explicit settings", func(t *testing.T) {
+		t.Setenv("REDIS_ADDR", "redis.internal:6380")
+		t.Setenv("REDIS_HOST", "ignored")
+		t.Setenv("REDIS_PASSWORD", "secret")
+		t.Setenv("REDIS_DB", "2")
+		t.Setenv("REDIS_POOL_SIZE", "25")
+		t.Setenv("REDIS_MIN_IDLE_CONNS", "5")
+		t.Setenv("REDIS_DIAL_TIMEOUT", "750ms")
+
+		assert.Equal(t, config.RedisConfig{
+			Addr:         "redis.internal:6380",
+			Password:     "secret",
+			DB:           2,
+			PoolSize:     25,
+			MinIdleConns: 5,
+			DialTimeout:  750 * time.Millisecond,
+		}, config.LoadRedisConfig())
+	})
+
+	t.Run("invalid values fall back", func(t *testing.T) {
+		t.Setenv("REDIS_DB", "-1")
+		t.Setenv("REDIS_POOL_SIZE", "0")
+		t.Setenv("REDIS_DIAL_TIMEOUT", "soon")
+
+		cfg := config.LoadRedisConfig()
+
+		assert.Equal(t, 0, cfg.DB)
+		assert.Equal(t, 10, cfg.PoolSize)
+		assert.Equal(t, 5*time.Second, cfg.DialTimeout)
+	})
+}
@@ -0,0 +1,49 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/db"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// pooledBulkLimit configures a database the way InitDB does and returns the bulk limit the
+// recipe repository would derive from it.
+func pooledBulkLimit(t *testing.T) int {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := database.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db.ConfigurePool(sqlDB, db.NewConfig())
+	return repositories.NewBulkLimiterForDB(database).Limit()
+}
+
+func TestConfiguredPoolCapsBulkOperations(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("POSTGRES_MAX_OPEN_CONNS", "")
+		t.Setenv("BULK_DB_CONN_RESERVE", "")
+
+		assert.Equal(t, db.DefaultMaxOpenConns-repositories.DefaultBulkConnReserve, pooledBulkLimit(t))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		t.Setenv("POSTGRES_MAX_OPEN_CONNS", "40")
+		t.Setenv("BULK_DB_CONN_RESERVE", "12")
+
+		assert.Equal(t, 28, pooledBulkLimit(t))
+	})
+
+	t.Run("invalid pool size falls back to the default", func(t *testing.T) {
+		t.Setenv("POSTGRES_MAX_OPEN_CONNS", "-1")
+		t.Setenv("BULK_DB_CONN_RESERVE", "5")
+
+		assert.Equal(t, db.DefaultMaxOpenConns-5, pooledBulkLimit(t))
+	})
+}
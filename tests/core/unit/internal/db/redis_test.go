@@ -0,0 +1,36 @@
+package db_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisClientNotConfigured(t *testing.T) {
+	client, err := db.NewRedisClient(config.RedisConfig{})
+
+	assert.Nil(t, client)
+	assert.ErrorIs(t, err, db.ErrRedisNotConfigured)
+}
+
+func TestNewRedisClientFailsFastWhenUnreachable(t *testing.T) {
+	// Reserve a free port and close it so nothing is listening there.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	start := time.Now()
+	client, err := db.NewRedisClient(config.RedisConfig{Addr: addr, PoolSize: 1, DialTimeout: 200 * time.Millisecond})
+
+	assert.Nil(t, client)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, db.ErrRedisNotConfigured)
+	assert.Contains(t, err.Error(), addr)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
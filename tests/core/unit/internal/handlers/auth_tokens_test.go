@@ -0,0 +1,220 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryRefreshTokenStore is an in-memory RefreshTokenStore standing in for Redis.
+type memoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func (s *memoryRefreshTokenStore) Save(_ context.Context, tokenID, userID string, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenID] = userID
+	return nil
+}
+
+func (s *memoryRefreshTokenStore) Consume(_ context.Context, tokenID, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner, ok := s.tokens[tokenID]
+	delete(s.tokens, tokenID)
+	return ok && owner == userID, nil
+}
+
+func (s *memoryRefreshTokenStore) Revoke(_ context.Context, tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, tokenID)
+	return nil
+}
+
+// memoryTokenDenylist is an in-memory TokenDenylist standing in for Redis.
+type memoryTokenDenylist struct {
+	mu     sync.Mutex
+	denied map[string]time.Time
+}
+
+func (d *memoryTokenDenylist) Deny(_ context.Context, token string, until time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.denied[token] = until
+	return nil
+}
+
+func (d *memoryTokenDenylist) IsDenied(_ context.Context, token string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	until, ok := d.denied[token]
+	return ok && time.Now().Before(until), nil
+}
+
+func TestRefreshToken(t *testing.T) {
+	handler, router, mockService := setupUserTest()
+	handler.RefreshTokens = &memoryRefreshTokenStore{tokens: map[string]string{}}
+	handler.Denylist = &memoryTokenDenylist{denied: map[string]time.Time{}}
+	middleware.UseTokenDenylist(handler.Denylist)
+	t.Cleanup(func() { middleware.UseTokenDenylist(nil) })
+	router.POST("/users/login", handler.LoginUser)
+	router.POST("/users/refresh", handler.RefreshToken)
+	router.POST("/users/logout", middleware.AuthMiddleware(), handler.Logout)
+	router.GET("/users/me", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	mockService.On("Authenticate", mock.Anything, "test@example.com", "password123").Return(&models.User{ID: "1"}, nil)
+
+	send := func(method, path, bearer string, body interface{}) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		return send("POST", path, "", body)
+	}
+	login := func(t *testing.T) dtos.TokenResponse {
+		w := post("/users/login", map[string]string{"email": "test@example.com", "password": "password123"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		var tokens dtos.TokenResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tokens))
+		assert.NotEmpty(t, tokens.Token)
+		assert.NotEmpty(t, tokens.RefreshToken)
+		return tokens
+	}
+	refresh := func(token string) *httptest.ResponseRecorder {
+		return post("/users/refresh", dtos.RefreshTokenRequest{RefreshToken: token})
+	}
+	signRefresh := func(expires time.Time) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "1", "jti": "manual", "typ": "refresh", "exp": expires.Unix(),
+		}).SignedString([]byte("test-secret"))
+		return token
+	}
+
+	t.Run("refresh token is exchanged once for a new pair", func(t *testing.T) {
+		tokens := login(t)
+
+		w := refresh(tokens.RefreshToken)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var renewed dtos.TokenResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &renewed))
+		assert.NotEmpty(t, renewed.Token)
+		assert.NotEqual(t, tokens.RefreshToken, renewed.RefreshToken)
+
+		assert.Equal(t, http.StatusUnauthorized, refresh(tokens.RefreshToken).Code)
+	})
+
+	t.Run("refresh token cannot be used as an access token", func(t *testing.T) {
+		tokens := login(t)
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/users/me", tokens.RefreshToken, nil).Code)
+	})
+
+	t.Run("access token is not accepted as a refresh token", func(t *testing.T) {
+		tokens := login(t)
+		assert.Equal(t, http.StatusUnauthorized, refresh(tokens.Token).Code)
+	})
+
+	t.Run("logout denylists the access token and revokes the refresh token", func(t *testing.T) {
+		tokens := login(t)
+		assert.Equal(t, http.StatusOK, send("GET", "/users/me", tokens.Token, nil).Code)
+
+		w := send("POST", "/users/logout", tokens.Token, dtos.LogoutRequest{RefreshToken: tokens.RefreshToken})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/users/me", tokens.Token, nil).Code)
+		assert.Equal(t, http.StatusUnauthorized, refresh(tokens.RefreshToken).Code)
+	})
+
+	t.Run("logout without a body still revokes the access token", func(t *testing.T) {
+		tokens := login(t)
+		assert.Equal(t, http.StatusOK, send("POST", "/users/logout", tokens.Token, nil).Code)
+		assert.Equal(t, http.StatusUnauthorized, send("GET", "/users/me", tokens.Token, nil).Code)
+	})
+
+	t.Run("logout rejects an invalid refresh token", func(t *testing.T) {
+		tokens := login(t)
+
+		w := send("POST", "/users/logout", tokens.Token, dtos.LogoutRequest{RefreshToken: "not-a-token"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusOK, send("GET", "/users/me", tokens.Token, nil).Code)
+	})
+
+	t.Run("logout does not revoke another user's refresh token", func(t *testing.T) {
+		tokens := login(t)
+		handler.RefreshTokens.Save(context.Background(), "other", "2", time.Hour)
+		otherToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "2", "jti": "other", "typ": "refresh", "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("test-secret"))
+
+		w := send("POST", "/users/logout", tokens.Token, dtos.LogoutRequest{RefreshToken: otherToken})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		active, _ := handler.RefreshTokens.Consume(context.Background(), "other", "2")
+		assert.True(t, active)
+	})
+
+	t.Run("recently expired token is accepted within the grace period", func(t *testing.T) {
+		handler.RefreshTokens.Save(context.Background(), "manual", "1", time.Hour)
+		assert.Equal(t, http.StatusOK, refresh(signRefresh(time.Now().Add(-time.Minute))).Code)
+	})
+
+	t.Run("token expired beyond the grace period is rejected", func(t *testing.T) {
+		handler.RefreshTokens.Save(context.Background(), "manual", "1", time.Hour)
+		assert.Equal(t, http.StatusUnauthorized, refresh(signRefresh(time.Now().Add(-time.Hour))).Code)
+	})
+}
+
+// failingTokenDenylist fails every call, as Redis does when it is unreachable.
+type failingTokenDenylist struct{}
+
+func (failingTokenDenylist) Deny(context.Context, string, time.Time) error {
+	return errors.New("dial tcp 10.0.0.5:6379: connection refused")
+}
+
+func (failingTokenDenylist) IsDenied(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func TestLogoutHidesStoreErrors(t *testing.T) {
+	handler, router, _ := setupUserTest()
+	handler.Denylist = failingTokenDenylist{}
+	router.POST("/users/logout", middleware.AuthMiddleware(), handler.Logout)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/users/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response dtos.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Failed to log out", response.Message)
+	assert.NotContains(t, w.Body.String(), "10.0.0.5")
+}
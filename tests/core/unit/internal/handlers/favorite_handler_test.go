@@ -0,0 +1,192 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockFavoriteRepository is a mock implementation of the FavoriteRepository interface
+type MockFavoriteRepository struct {
+	mock.Mock
+}
+
+func (m *MockFavoriteRepository) GetFavoritedRecipeIDs(ctx context.Context, userID string, recipeIDs []string) ([]string, error) {
+	args := m.Called(ctx, userID, recipeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFavoriteRepository) AddFavorite(ctx context.Context, userID, recipeID string) error {
+	args := m.Called(ctx, userID, recipeID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepository) RemoveFavorite(ctx context.Context, userID, recipeID string) error {
+	args := m.Called(ctx, userID, recipeID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepository) ListFavorites(ctx context.Context, userID string, page, limit int) ([]models.Recipe, error) {
+	args := m.Called(ctx, userID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
+
+func setupFavoriteTest() (*gin.Engine, *MockFavoriteRepository) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockFavoriteRepository)
+	handler := handlers.NewFavoriteHandler(services.NewFavoriteService(mockRepo))
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.POST("/users/me/favorites/check", handler.CheckFavorites)
+	router.GET("/users/me/favorites", handler.ListFavorites)
+	router.POST("/recipes/:id/favorite", handler.AddFavorite)
+	router.DELETE("/recipes/:id/favorite", handler.RemoveFavorite)
+	return router, mockRepo
+}
+
+func serveFavoriteRequest(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func postFavoriteCheck(router *gin.Engine, ids []string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(dtos.FavoriteCheckRequest{RecipeIDs: ids})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/users/me/favorites/check", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCheckFavorites(t *testing.T) {
+	t.Run("mix of favorited and non-favorited", func(t *testing.T) {
+		router, mockRepo := setupFavoriteTest()
+		ids := []string{"r1", "r2", "r3"}
+		mockRepo.On("GetFavoritedRecipeIDs", mock.Anything, "test-user", ids).Return([]string{"r1", "r3"}, nil).Once()
+
+		w := postFavoriteCheck(router, ids)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.FavoriteCheckResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, map[string]bool{"r1": true, "r2": false, "r3": true}, response.Favorites)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("too many ids", func(t *testing.T) {
+		router, mockRepo := setupFavoriteTest()
+		ids := make([]string, services.MaxFavoriteCheckIDs+1)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("r%d", i)
+		}
+
+		w := postFavoriteCheck(router, ids)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRepo.AssertNotCalled(t, "GetFavoritedRecipeIDs", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing recipe_ids", func(t *testing.T) {
+		router, _ := setupFavoriteTest()
+		w := postFavoriteCheck(router, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAddFavorite(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"added", nil, http.StatusCreated},
+		{"already a favorite", services.ErrAlreadyFavorited, http.StatusConflict},
+		{"recipe not found", gorm.ErrRecordNotFound, http.StatusNotFound},
+		{"repository error", fmt.Errorf("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockRepo := setupFavoriteTest()
+			mockRepo.On("AddFavorite", mock.Anything, "test-user", "r1").Return(tt.err).Once()
+
+			w := serveFavoriteRequest(router, "POST", "/recipes/r1/favorite")
+
+			assert.Equal(t, tt.status, w.Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		router, mockRepo := setupFavoriteTest()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/r1/favorite", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockRepo.AssertNotCalled(t, "AddFavorite", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRemoveFavorite(t *testing.T) {
+	t.Run("removed", func(t *testing.T) {
+		router, mockRepo := setupFavoriteTest()
+		mockRepo.On("RemoveFavorite", mock.Anything, "test-user", "r1").Return(nil).Once()
+
+		w := serveFavoriteRequest(router, "DELETE", "/recipes/r1/favorite")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not a favorite", func(t *testing.T) {
+		router, mockRepo := setupFavoriteTest()
+		mockRepo.On("RemoveFavorite", mock.Anything, "test-user", "r1").Return(gorm.ErrRecordNotFound).Once()
+
+		w := serveFavoriteRequest(router, "DELETE", "/recipes/r1/favorite")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestListFavorites(t *testing.T) {
+	router, mockRepo := setupFavoriteTest()
+	recipes := []models.Recipe{{ID: "r2", Title: "Stew"}, {ID: "r1", Title: "Soup"}}
+	mockRepo.On("ListFavorites", mock.Anything, "test-user", 2, 5).Return(recipes, nil).Once()
+
+	w := serveFavoriteRequest(router, "GET", "/users/me/favorites?page=2&limit=5")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dtos.RecipeListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Recipes, 2)
+	assert.Equal(t, "r2", response.Recipes[0].ID)
+	assert.Equal(t, 2, response.Meta.Page)
+	assert.Equal(t, 5, response.Meta.Limit)
+	mockRepo.AssertExpectations(t)
+}
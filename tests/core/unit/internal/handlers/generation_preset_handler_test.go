@@ -0,0 +1,162 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockGenerationPresetRepository is a mock implementation of the GenerationPresetRepository interface
+type MockGenerationPresetRepository struct {
+	mock.Mock
+}
+
+func (m *MockGenerationPresetRepository) CreatePreset(ctx context.Context, preset *models.GenerationPreset) error {
+	args := m.Called(ctx, preset)
+	return args.Error(0)
+}
+
+func (m *MockGenerationPresetRepository) GetPreset(ctx context.Context, id string) (*models.GenerationPreset, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GenerationPreset), args.Error(1)
+}
+
+func (m *MockGenerationPresetRepository) ListPresets(ctx context.Context, userID string) ([]models.GenerationPreset, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.GenerationPreset), args.Error(1)
+}
+
+func authorizedJSONRequest(method, path string, body interface{}) *http.Request {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	return req
+}
+
+func TestCreatePreset(t *testing.T) {
+	setup := func() (*gin.Engine, *MockGenerationPresetRepository) {
+		gin.SetMode(gin.TestMode)
+		repo := new(MockGenerationPresetRepository)
+		handler := handlers.NewGenerationPresetHandler(services.NewGenerationPresetService(repo))
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/users/me/presets", handler.CreatePreset)
+		return router, repo
+	}
+
+	t.Run("preset is stored for the caller", func(t *testing.T) {
+		router, repo := setup()
+		repo.On("CreatePreset", mock.Anything, mock.MatchedBy(func(p *models.GenerationPreset) bool {
+			return p.UserID == "test-user" && p.Name == "Weeknight dinner" && p.MaxTimeMinutes == 30 && p.Servings == 4
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedJSONRequest("POST", "/users/me/presets", dtos.GenerationPresetRequest{
+			Name: " Weeknight dinner ", Diet: "family-friendly", MaxTimeMinutes: 30, Servings: 4,
+		}))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response models.GenerationPreset
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Weeknight dinner", response.Name)
+		assert.Equal(t, "family-friendly", response.Diet)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("name is required", func(t *testing.T) {
+		router, repo := setup()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedJSONRequest("POST", "/users/me/presets", map[string]interface{}{"servings": 4}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		repo.AssertNotCalled(t, "CreatePreset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("negative max time is rejected", func(t *testing.T) {
+		router, repo := setup()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedJSONRequest("POST", "/users/me/presets", map[string]interface{}{"name": "Quick", "max_time_minutes": -5}))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		repo.AssertNotCalled(t, "CreatePreset", mock.Anything, mock.Anything)
+	})
+}
+
+func TestQueryRecipeWithPreset(t *testing.T) {
+	setup := func() (*gin.Engine, *MockRecipeResolutionService, *MockGenerationPresetRepository) {
+		gin.SetMode(gin.TestMode)
+		resolution := new(MockRecipeResolutionService)
+		repo := new(MockGenerationPresetRepository)
+		handler := handlers.NewRecipeMultistepResolutionHandler(resolution)
+		handler.Presets = services.NewGenerationPresetService(repo)
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/recipes/resolve/query", handler.QueryRecipe)
+		return router, resolution, repo
+	}
+	body := map[string]interface{}{
+		"query":                  "chicken pasta",
+		"promptInstructions":     "Create a recipe",
+		"expectedResponseFormat": "JSON",
+		"preset_id":              "preset-1",
+	}
+
+	t.Run("preset constraints are merged into the query", func(t *testing.T) {
+		router, resolution, repo := setup()
+		repo.On("GetPreset", mock.Anything, "preset-1").Return(&models.GenerationPreset{
+			ID: "preset-1", UserID: "test-user", Cuisine: "italian", MaxTimeMinutes: 30, Servings: 4,
+		}, nil)
+		merged := "chicken pasta (cuisine: italian; ready in at most 30 minutes; serves 4)"
+		resolution.On("FindCloseMatches", mock.Anything, mock.Anything).Return([]string{}, nil)
+		resolution.On("BuildCompositePrompt", merged, mock.Anything, mock.Anything, mock.Anything, "en").Return("prompt", nil)
+		resolution.On("ResolveRecipeByModel", mock.Anything, "prompt", mock.Anything).Return("candidate", []string{}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedJSONRequest("POST", "/recipes/resolve/query", body))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resolution.AssertExpectations(t)
+	})
+
+	t.Run("another user's preset is forbidden", func(t *testing.T) {
+		router, resolution, repo := setup()
+		repo.On("GetPreset", mock.Anything, "preset-1").Return(&models.GenerationPreset{ID: "preset-1", UserID: "someone-else"}, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedJSONRequest("POST", "/recipes/resolve/query", body))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		resolution.AssertNotCalled(t, "ResolveRecipeByModel", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown preset", func(t *testing.T) {
+		router, _, repo := setup()
+		repo.On("GetPreset", mock.Anything, "preset-1").Return(nil, gorm.ErrRecordNotFound)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authorizedJSONRequest("POST", "/recipes/resolve/query", body))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+// MockRecipeAuditService is a mock implementation of services.RecipeAuditService.
+type MockRecipeAuditService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeAuditService) Record(ctx context.Context, actorID, action string, before, after *models.Recipe) error {
+	args := m.Called(ctx, actorID, action, before, after)
+	return args.Error(0)
+}
+
+func (m *MockRecipeAuditService) ListRecipeAuditLog(ctx context.Context, recipeID string, limit int) ([]models.RecipeAuditEntry, error) {
+	args := m.Called(ctx, recipeID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecipeAuditEntry), args.Error(1)
+}
+
+func TestDeleteRecipeRecordsAuditEntry(t *testing.T) {
+	handler, router, mockService := setupTest()
+	audit := new(MockRecipeAuditService)
+	handler.Audit = audit
+	router.DELETE("/recipes/:id", handler.DeleteRecipe)
+
+	owner := "test-user"
+	recipe := &models.Recipe{ID: "1", UserID: &owner}
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+	mockService.On("DeleteRecipe", mock.Anything, "1").Return(nil)
+	audit.On("Record", mock.Anything, "test-user", models.RecipeAuditDelete, recipe, (*models.Recipe)(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/recipes/1", nil)
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	audit.AssertExpectations(t)
+}
+
+func TestUpdateRecipeAuditFailureIsNotFatal(t *testing.T) {
+	handler, router, mockService := setupTest()
+	audit := new(MockRecipeAuditService)
+	handler.Audit = audit
+	router.PUT("/recipes/:id", handler.UpdateRecipe)
+
+	owner := "test-user"
+	mockService.On("GetRecipe", mock.Anything, "1").Return(&models.Recipe{ID: "1", Title: "Old", UserID: &owner}, nil)
+	mockService.On("UpdateRecipe", mock.Anything, mock.Anything).Return(nil)
+	audit.On("Record", mock.Anything, "test-user", models.RecipeAuditUpdate, mock.Anything, mock.Anything).
+		Return(errors.New("audit table missing"))
+
+	body, _ := json.Marshal(dtos.RecipeRequest{
+		Title:       "New",
+		Ingredients: []dtos.Ingredient{{Name: "flour", Amount: "1", Unit: "cup"}},
+		Steps:       []dtos.Step{{Order: 1, Description: "Mix"}},
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/recipes/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	audit.AssertExpectations(t)
+	before := audit.Calls[0].Arguments.Get(3).(*models.Recipe)
+	after := audit.Calls[0].Arguments.Get(4).(*models.Recipe)
+	assert.Equal(t, "Old", before.Title)
+	assert.Equal(t, "New", after.Title)
+}
+
+func TestGetRecipeAuditLog(t *testing.T) {
+	handler, router, _ := setupTest()
+	audit := new(MockRecipeAuditService)
+	handler.Audit = audit
+	router.GET("/admin/recipes/:id/audit-log", handler.GetRecipeAuditLog)
+
+	actor := "test-user"
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	audit.On("ListRecipeAuditLog", mock.Anything, "1", 100).Return([]models.RecipeAuditEntry{
+		{ID: "e2", RecipeID: "1", ActorID: &actor, Action: models.RecipeAuditUpdate, Diff: datatypes.JSON(`{"title":{"from":"Old","to":"New"}}`), CreatedAt: createdAt},
+		{ID: "e1", RecipeID: "1", Action: models.RecipeAuditCreate, CreatedAt: createdAt.Add(-time.Hour)},
+	}, nil)
+
+	t.Run("lists entries", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/recipes/1/audit-log", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			RecipeID string `json:"recipe_id"`
+			Count    int    `json:"count"`
+			Entries  []struct {
+				ActorID   *string                      `json:"actor_id"`
+				Action    string                       `json:"action"`
+				Diff      map[string]map[string]string `json:"diff"`
+				CreatedAt string                       `json:"created_at"`
+			} `json:"entries"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "1", response.RecipeID)
+		assert.Equal(t, 2, response.Count)
+		assert.Equal(t, "test-user", *response.Entries[0].ActorID)
+		assert.Equal(t, "New", response.Entries[0].Diff["title"]["to"])
+		assert.Equal(t, "2024-05-01T12:00:00Z", response.Entries[0].CreatedAt)
+		assert.Nil(t, response.Entries[1].ActorID)
+		assert.Nil(t, response.Entries[1].Diff)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/recipes/1/audit-log?limit=0", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,174 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupBatchTest() (*gin.Engine, *MockRecipeService, *MockRecipeResolutionService) {
+	gin.SetMode(gin.TestMode)
+	recipes := new(MockRecipeService)
+	resolution := new(MockRecipeResolutionService)
+	handler := handlers.NewRecipeBatchHandler(recipes, resolution)
+	handler.Concurrency = 2
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.POST("/recipes/generate/batch", handler.GenerateRecipesBatch)
+	return router, recipes, resolution
+}
+
+func postBatch(router *gin.Engine, body interface{}, authenticated bool) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipes/generate/batch", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if authenticated {
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGenerateRecipesBatch(t *testing.T) {
+	t.Run("failed queries do not abort the batch", func(t *testing.T) {
+		router, recipes, resolution := setupBatchTest()
+		resolution.On("GenerateRecipe", mock.Anything, "pizza").Return(&models.Recipe{Title: "Pizza"}, nil)
+		resolution.On("GenerateRecipe", mock.Anything, "soup").Return(nil, integrations.ErrDeepSeekRateLimited)
+		resolution.On("GenerateRecipe", mock.Anything, "salad").Return(&models.Recipe{Title: "Salad"}, nil)
+		recipes.On("SaveRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool { return r.Title == "Pizza" })).
+			Run(func(args mock.Arguments) { args.Get(1).(*models.Recipe).ID = "recipe-pizza" }).Return(nil)
+		recipes.On("SaveRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool { return r.Title == "Salad" })).
+			Return(errors.New("database unavailable"))
+
+		w := postBatch(router, dtos.BatchGenerationRequest{Queries: []string{"pizza", "soup", "salad"}}, true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.BatchGenerationResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Results, 3) {
+			assert.Equal(t, "pizza", response.Results[0].Query)
+			assert.Equal(t, "recipe-pizza", response.Results[0].RecipeID)
+			assert.Nil(t, response.Results[0].Error)
+			if assert.NotNil(t, response.Results[0].Recipe) {
+				assert.Equal(t, "Pizza", response.Results[0].Recipe.Title)
+			}
+			assert.Equal(t, "soup", response.Results[1].Query)
+			if assert.NotNil(t, response.Results[1].Error) {
+				assert.Equal(t, "AI_RATE_LIMITED", response.Results[1].Error.Code)
+			}
+			if assert.NotNil(t, response.Results[2].Error) {
+				assert.Equal(t, "INTERNAL_ERROR", response.Results[2].Error.Code)
+			}
+		}
+		resolution.AssertExpectations(t)
+		recipes.AssertExpectations(t)
+	})
+
+	t.Run("too many queries", func(t *testing.T) {
+		router, _, resolution := setupBatchTest()
+		queries := make([]string, dtos.MaxBatchGenerationQueries+1)
+		for i := range queries {
+			queries[i] = "pizza"
+		}
+
+		w := postBatch(router, dtos.BatchGenerationRequest{Queries: queries}, true)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		resolution.AssertNotCalled(t, "GenerateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty query list", func(t *testing.T) {
+		router, _, _ := setupBatchTest()
+
+		w := postBatch(router, dtos.BatchGenerationRequest{Queries: []string{}}, true)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		router, _, resolution := setupBatchTest()
+
+		w := postBatch(router, dtos.BatchGenerationRequest{Queries: []string{"pizza"}}, false)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		resolution.AssertNotCalled(t, "GenerateRecipe", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenerateRecipesBatchCancelled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recipes := new(MockRecipeService)
+	resolution := new(MockRecipeResolutionService)
+	handler := handlers.NewRecipeBatchHandler(recipes, resolution)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	payload, _ := json.Marshal(dtos.BatchGenerationRequest{Queries: []string{"pizza", "soup"}})
+	c.Request, _ = http.NewRequestWithContext(ctx, "POST", "/recipes/generate/batch", bytes.NewBuffer(payload))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("currentUser", "test-user-id")
+
+	handler.GenerateRecipesBatch(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dtos.BatchGenerationResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	for _, result := range response.Results {
+		if assert.NotNil(t, result.Error) {
+			assert.Equal(t, "CANCELLED", result.Error.Code)
+		}
+	}
+	resolution.AssertNotCalled(t, "GenerateRecipe", mock.Anything, mock.Anything)
+}
+
+func TestGenerateRecipesBatchDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recipes := new(MockRecipeService)
+	resolution := new(MockRecipeResolutionService)
+	handler := handlers.NewRecipeBatchHandler(recipes, resolution)
+	handler.Concurrency = 2
+	handler.Deadline = 50 * time.Millisecond
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.POST("/recipes/generate/batch", middleware.Timeout(time.Second), handler.GenerateRecipesBatch)
+
+	resolution.On("GenerateRecipe", mock.Anything, "pizza").Return(&models.Recipe{Title: "Pizza"}, nil)
+	resolution.On("GenerateRecipe", mock.Anything, "stew").
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(nil, integrations.ErrDeepSeekTimeout)
+	recipes.On("SaveRecipe", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.Recipe).ID = "recipe-pizza" }).Return(nil)
+
+	w := postBatch(router, dtos.BatchGenerationRequest{Queries: []string{"pizza", "stew"}}, true)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dtos.BatchGenerationResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Results, 2) {
+		assert.Equal(t, "recipe-pizza", response.Results[0].RecipeID)
+		assert.Nil(t, response.Results[0].Error)
+		if assert.NotNil(t, response.Results[1].Error) {
+			assert.Equal(t, "CANCELLED", response.Results[1].Error.Code)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func postClone(router http.Handler, recipeID string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipes/"+recipeID+"/clone", nil)
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCloneRecipe(t *testing.T) {
+	author := "someone-else"
+
+	t.Run("saves a copy owned by the current user", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.POST("/recipes/:id/clone", handler.CloneRecipe)
+
+		original := &models.Recipe{
+			ID:            "1",
+			UserID:        &author,
+			Title:         "Shakshuka",
+			Tags:          []models.Tag{{ID: "tag-1", Name: "brunch"}},
+			Approved:      true,
+			AverageRating: 4.5,
+			RatingCount:   2,
+			Embedding:     models.Float64Slice{0.1, 0.2},
+		}
+		require.NoError(t, original.SetIngredients([]models.Ingredient{{Name: "eggs", Amount: "4", Unit: "whole"}}))
+		mockService.On("GetRecipe", mock.Anything, "1").Return(original, nil)
+		mockService.On("SaveRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return r.ID == "" && r.UserID != nil && *r.UserID == "test-user" &&
+				r.ClonedFrom != nil && *r.ClonedFrom == "1" &&
+				r.Title == "Shakshuka" && len(r.Tags) == 1 && !r.Approved && r.Embedding == nil
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Recipe).ID = "2"
+		}).Return(nil)
+
+		w := postClone(router, "1")
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var response dtos.RecipeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "2", response.ID)
+		assert.Equal(t, "1", response.ClonedFrom)
+		assert.Equal(t, []string{"brunch"}, response.Tags)
+		assert.Len(t, response.Ingredients, 1)
+		assert.Zero(t, response.RatingCount)
+		assert.Equal(t, "1", original.ID, "the original is left untouched")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.POST("/recipes/:id/clone", handler.CloneRecipe)
+		mockService.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		w := postClone(router, "missing")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertNotCalled(t, "SaveRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("save fails", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.POST("/recipes/:id/clone", handler.CloneRecipe)
+		mockService.On("GetRecipe", mock.Anything, "1").Return(&models.Recipe{ID: "1", Title: "Soup"}, nil)
+		mockService.On("SaveRecipe", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		w := postClone(router, "1")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.POST("/recipes/:id/clone", handler.CloneRecipe)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/1/clone", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertNotCalled(t, "GetRecipe", mock.Anything, mock.Anything)
+	})
+}
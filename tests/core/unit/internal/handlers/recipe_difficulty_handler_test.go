@@ -0,0 +1,110 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecipeDifficultyValidation(t *testing.T) {
+	t.Run("save normalizes an allowed difficulty", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.POST("/recipes", handler.SaveRecipe)
+
+		mockService.On("SaveRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return r.Difficulty == "medium"
+		})).Return(nil)
+
+		body, _ := json.Marshal(dtos.RecipeRequest{
+			Title:       "New Recipe",
+			Difficulty:  " Medium ",
+			Ingredients: []dtos.Ingredient{{Name: "Ingredient 1", Amount: "1", Unit: "cup"}},
+			Steps:       []dtos.Step{{Order: 1, Description: "Step 1"}},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("save rejects an unknown difficulty", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.POST("/recipes", handler.SaveRecipe)
+
+		body, _ := json.Marshal(dtos.RecipeRequest{
+			Title:       "New Recipe",
+			Difficulty:  "esy",
+			Ingredients: []dtos.Ingredient{{Name: "Ingredient 1", Amount: "1", Unit: "cup"}},
+			Steps:       []dtos.Step{{Order: 1, Description: "Step 1"}},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "BAD_REQUEST", response.Code)
+		assert.Contains(t, response.Message, "unsupported difficulty: esy")
+		mockService.AssertNotCalled(t, "SaveRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("search rejects an unknown difficulty", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		router.GET("/recipes/search", handler.SearchRecipes)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/search?difficulty=esy", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "BAD_REQUEST", response.Code)
+		mockService.AssertNotCalled(t, "SearchRecipes", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestListDifficulties(t *testing.T) {
+	handler, router, _ := setupTest()
+	router.GET("/recipes/difficulties", handler.ListDifficulties)
+
+	t.Run("defaults", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/difficulties", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RecipeDifficultiesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"easy", "medium", "hard"}, response.Difficulties)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		t.Setenv("RECIPE_DIFFICULTIES", "easy, hard ,Expert")
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/difficulties", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		var response dtos.RecipeDifficultiesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{"easy", "hard", "expert"}, response.Difficulties)
+	})
+}
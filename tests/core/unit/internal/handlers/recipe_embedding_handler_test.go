@@ -0,0 +1,247 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEmbedder is a mock implementation of integrations.Embedder.
+type MockEmbedder struct {
+	mock.Mock
+}
+
+func (m *MockEmbedder) GenerateEmbedding(ctx context.Context, text string) ([]float64, error) {
+	args := m.Called(ctx, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]float64), args.Error(1)
+}
+
+// savingRecipeRepository records the recipe passed to SaveRecipe.
+type savingRecipeRepository struct {
+	repositories.RecipeRepository
+	saved *models.Recipe
+}
+
+func (r *savingRecipeRepository) SaveRecipe(ctx context.Context, recipe *models.Recipe) error {
+	r.saved = recipe
+	return nil
+}
+
+func TestSaveRecipeUsesInjectedEmbedder(t *testing.T) {
+	t.Setenv("EMBEDDING_DIMENSIONS", "2")
+	post := func(embedder *MockEmbedder) (*httptest.ResponseRecorder, *savingRecipeRepository) {
+		gin.SetMode(gin.TestMode)
+		repo := &savingRecipeRepository{}
+		handler := handlers.NewRecipeHandler(services.NewRecipeServiceWithEmbedder(repo, nil, nil, nil, nil, embedder))
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/recipes", handler.SaveRecipe)
+
+		body, _ := json.Marshal(dtos.RecipeRequest{
+			Title:       "Tomato Soup",
+			Ingredients: []dtos.Ingredient{{Name: "tomatoes", Amount: "4", Unit: "whole"}},
+			Steps:       []dtos.Step{{Order: 1, Description: "Simmer."}},
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w, repo
+	}
+
+	t.Run("embedding stored with the recipe", func(t *testing.T) {
+		embedder := new(MockEmbedder)
+		embedder.On("GenerateEmbedding", mock.Anything, mock.MatchedBy(func(text string) bool {
+			return strings.Contains(text, "Tomato Soup") && strings.Contains(text, "4 whole tomatoes")
+		})).Return([]float64{0.25, 0.75}, nil).Once()
+
+		w, repo := post(embedder)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		embedder.AssertExpectations(t)
+		if assert.NotNil(t, repo.saved) {
+			assert.Equal(t, models.Float64Slice{0.25, 0.75}, repo.saved.Embedding)
+		}
+	})
+
+	t.Run("embedding failure does not fail the save", func(t *testing.T) {
+		embedder := new(MockEmbedder)
+		embedder.On("GenerateEmbedding", mock.Anything, mock.Anything).Return(nil, errors.New("rate limited")).Once()
+
+		w, repo := post(embedder)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		embedder.AssertExpectations(t)
+		if assert.NotNil(t, repo.saved) {
+			assert.Nil(t, repo.saved.Embedding)
+		}
+	})
+
+	t.Run("embedding of the wrong length is not stored", func(t *testing.T) {
+		embedder := new(MockEmbedder)
+		embedder.On("GenerateEmbedding", mock.Anything, mock.Anything).Return([]float64{0.25, 0.5, 0.75}, nil).Once()
+
+		w, repo := post(embedder)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		if assert.NotNil(t, repo.saved) {
+			assert.Nil(t, repo.saved.Embedding)
+		}
+	})
+}
+
+func TestReindexEmbeddings(t *testing.T) {
+	setup := func(isAdmin bool) (*gin.Engine, *MockRecipeService) {
+		handler, router, mockService := setupTest()
+		users := new(MockUserService)
+		users.On("GetUser", mock.Anything, "test-user").Return(&models.User{ID: "test-user", IsAdmin: isAdmin}, nil)
+		router.POST("/admin/recipes/reindex-embeddings", middleware.RequireAdmin(users), handler.ReindexEmbeddings)
+		return router, mockService
+	}
+	post := func(router *gin.Engine, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/recipes/reindex-embeddings"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("dry run resumes from offset", func(t *testing.T) {
+		router, mockService := setup(true)
+		mockService.On("ReindexEmbeddings", mock.Anything, 200, 50, true).
+			Return(services.ReindexResult{Processed: 30, Changed: 12, NextOffset: 230, Done: true}, nil)
+
+		w := post(router, "?dry_run=true&offset=200&batch_size=50")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.ReindexEmbeddingsResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dtos.ReindexEmbeddingsResponse{DryRun: true, Processed: 30, Changed: 12, NextOffset: 230, Done: true}, response)
+	})
+
+	t.Run("failure reports resume offset", func(t *testing.T) {
+		router, mockService := setup(true)
+		mockService.On("ReindexEmbeddings", mock.Anything, 0, services.DefaultReindexBatchSize, false).
+			Return(services.ReindexResult{NextOffset: 0}, errors.New("rate limited"))
+
+		w := post(router, "")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Contains(t, response.Message, "offset=0")
+	})
+
+	t.Run("wrong embedding length", func(t *testing.T) {
+		router, mockService := setup(true)
+		mockService.On("ReindexEmbeddings", mock.Anything, 0, services.DefaultReindexBatchSize, false).
+			Return(services.ReindexResult{NextOffset: 0}, fmt.Errorf("failed to embed recipe 1: %w", &services.EmbeddingDimensionError{Got: 3072, Want: 1536}))
+
+		w := post(router, "")
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "EMBEDDING_ERROR", response.Code)
+		assert.Contains(t, response.Message, "3072 dimensions, expected 1536")
+	})
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		router, mockService := setup(false)
+
+		w := post(router, "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "ReindexEmbeddings", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	for name, query := range map[string]string{
+		"negative offset":    "?offset=-1",
+		"batch size too big": "?batch_size=101",
+	} {
+		t.Run(name, func(t *testing.T) {
+			router, _ := setup(true)
+			assert.Equal(t, http.StatusBadRequest, post(router, query).Code)
+		})
+	}
+}
+
+func TestListStaleEmbeddings(t *testing.T) {
+	setup := func(isAdmin bool) (*gin.Engine, *MockRecipeService) {
+		handler, router, mockService := setupTest()
+		users := new(MockUserService)
+		users.On("GetUser", mock.Anything, "test-user").Return(&models.User{ID: "test-user", IsAdmin: isAdmin}, nil)
+		router.GET("/admin/recipes/stale-embeddings", middleware.RequireAdmin(users), handler.ListStaleEmbeddings)
+		return router, mockService
+	}
+	get := func(router *gin.Engine, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/recipes/stale-embeddings"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("reports missing and outdated embeddings", func(t *testing.T) {
+		router, mockService := setup(true)
+		embeddedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockService.On("ListStaleEmbeddings", mock.Anything, 20).Return([]models.Recipe{
+			{ID: "1", Title: "Soup", UpdatedAt: embeddedAt.Add(time.Hour), EmbeddingUpdatedAt: &embeddedAt},
+			{ID: "2", Title: "Bread", UpdatedAt: embeddedAt},
+		}, nil)
+
+		w := get(router, "?limit=20")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Count   int `json:"count"`
+			Recipes []struct {
+				ID                 string  `json:"id"`
+				EmbeddingUpdatedAt *string `json:"embedding_updated_at"`
+				Reason             string  `json:"reason"`
+			} `json:"recipes"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Count)
+		assert.Equal(t, "outdated", response.Recipes[0].Reason)
+		if assert.NotNil(t, response.Recipes[0].EmbeddingUpdatedAt) {
+			assert.Equal(t, "2024-01-01T00:00:00Z", *response.Recipes[0].EmbeddingUpdatedAt)
+		}
+		assert.Equal(t, "missing", response.Recipes[1].Reason)
+		assert.Nil(t, response.Recipes[1].EmbeddingUpdatedAt)
+	})
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		router, mockService := setup(false)
+
+		assert.Equal(t, http.StatusForbidden, get(router, "").Code)
+		mockService.AssertNotCalled(t, "ListStaleEmbeddings", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		router, _ := setup(true)
+		assert.Equal(t, http.StatusBadRequest, get(router, "?limit=0").Code)
+	})
+}
@@ -10,12 +10,16 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	apperrors "github.com/pageza/alchemorsel-v1/internal/errors"
 	"github.com/pageza/alchemorsel-v1/internal/handlers"
 	"github.com/pageza/alchemorsel-v1/internal/middleware"
 	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/pricing"
+	"github.com/pageza/alchemorsel-v1/internal/services"
 	testhelpers "github.com/pageza/alchemorsel-v1/tests"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,8 +35,16 @@ type MockRecipeService struct {
 	mock.Mock
 }
 
-func (m *MockRecipeService) ListRecipes(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error) {
-	args := m.Called(ctx, page, limit, sort, order)
+func (m *MockRecipeService) ListRecipes(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error) {
+	args := m.Called(ctx, page, limit, sort, order, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeService) ListRecipesByUser(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error) {
+	args := m.Called(ctx, userID, page, limit, sort, order)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -70,17 +82,17 @@ func (m *MockRecipeService) SearchRecipes(ctx context.Context, query string, tag
 	return args.Get(0).([]models.Recipe), args.Error(1)
 }
 
-func (m *MockRecipeService) RateRecipe(ctx context.Context, recipeID string, rating float64) error {
-	args := m.Called(ctx, recipeID, rating)
+func (m *MockRecipeService) RateRecipe(ctx context.Context, recipeID, userID string, rating float64) error {
+	args := m.Called(ctx, recipeID, userID, rating)
 	return args.Error(0)
 }
 
-func (m *MockRecipeService) GetRecipeRatings(ctx context.Context, recipeID string) ([]float64, error) {
+func (m *MockRecipeService) GetRecipeRatings(ctx context.Context, recipeID string) (*models.RatingSummary, error) {
 	args := m.Called(ctx, recipeID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]float64), args.Error(1)
+	return args.Get(0).(*models.RatingSummary), args.Error(1)
 }
 
 func (m *MockRecipeService) ResolveRecipe(ctx context.Context, query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error) {
@@ -88,6 +100,27 @@ func (m *MockRecipeService) ResolveRecipe(ctx context.Context, query string, att
 	return args.Get(0).(*models.Recipe), args.Get(1).([]*models.Recipe), args.Error(2)
 }
 
+func (m *MockRecipeService) ReindexEmbeddings(ctx context.Context, offset, batchSize int, dryRun bool) (services.ReindexResult, error) {
+	args := m.Called(ctx, offset, batchSize, dryRun)
+	return args.Get(0).(services.ReindexResult), args.Error(1)
+}
+
+func (m *MockRecipeService) SearchByIngredients(ctx context.Context, ingredients []string, limit int) ([]services.IngredientMatch, error) {
+	args := m.Called(ctx, ingredients, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.IngredientMatch), args.Error(1)
+}
+
+func (m *MockRecipeService) ListStaleEmbeddings(ctx context.Context, limit int) ([]models.Recipe, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Recipe), args.Error(1)
+}
+
 func setupTest() (*handlers.RecipeHandler, *gin.Engine, *MockRecipeService) {
 	gin.SetMode(gin.TestMode)
 	mockService := new(MockRecipeService)
@@ -105,7 +138,7 @@ func TestListRecipes(t *testing.T) {
 	router.GET("/recipes", handler.ListRecipes)
 
 	t.Run("error listing recipes", func(t *testing.T) {
-		mockService.On("ListRecipes", mock.Anything, 1, 10, "created_at", "desc").
+		mockService.On("ListRecipes", mock.Anything, 1, 10, "created_at", "desc", models.RecipeFilter{}).
 			Return(nil, errors.New("database error"))
 
 		w := httptest.NewRecorder()
@@ -121,6 +154,46 @@ func TestListRecipes(t *testing.T) {
 		assert.Equal(t, "database error", response.Message)
 	})
 
+	for _, sort := range []string{"created_at", "updated_at", "title", "average_rating", "total_time_minutes"} {
+		t.Run("sort by "+sort, func(t *testing.T) {
+			mockService.On("ListRecipes", mock.Anything, 1, 10, sort, "asc", models.RecipeFilter{}).
+				Return([]models.Recipe{{ID: "1", Title: "Test Recipe"}}, nil)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/recipes?sort="+sort+"&order=asc", nil)
+			req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response dtos.RecipeListResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Len(t, response.Recipes, 1)
+			assert.Equal(t, sort, response.Meta.Sort)
+			assert.Equal(t, "asc", response.Meta.Order)
+		})
+	}
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes?sort=password_hash", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "ListRecipes", mock.Anything, mock.Anything, mock.Anything, "password_hash", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid sort order", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes?sort=title&order=sideways", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("unauthorized access", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/recipes?page=1&limit=10", nil)
@@ -136,6 +209,92 @@ func TestListRecipes(t *testing.T) {
 	})
 }
 
+func TestListRecipesFilters(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/recipes", handler.ListRecipes)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("difficulty and tags must all match by default", func(t *testing.T) {
+		filter := models.RecipeFilter{Difficulty: "easy", Tags: []string{"vegan", "quick"}}
+		mockService.On("ListRecipes", mock.Anything, 1, 10, "created_at", "desc", filter).
+			Return([]models.Recipe{{ID: "1", Title: "Salad"}}, nil).Once()
+
+		w := get("/recipes?difficulty=Easy&tag=vegan&tag=quick")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("tag_match=any", func(t *testing.T) {
+		filter := models.RecipeFilter{Tags: []string{"vegan", "quick"}, MatchAnyTag: true}
+		mockService.On("ListRecipes", mock.Anything, 1, 10, "created_at", "desc", filter).
+			Return([]models.Recipe{}, nil).Once()
+
+		w := get("/recipes?tag=vegan&tag=quick&tag_match=any")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid difficulty", func(t *testing.T) {
+		w := get("/recipes?difficulty=impossible")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid tag_match", func(t *testing.T) {
+		w := get("/recipes?tag=vegan&tag_match=some")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListMyRecipes(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/users/me/recipes", handler.ListMyRecipes)
+
+	t.Run("lists the current user's recipes", func(t *testing.T) {
+		mockService.On("ListRecipesByUser", mock.Anything, "test-user", 2, 5, "title", "asc").
+			Return([]models.Recipe{{ID: "1", Title: "My Recipe"}}, nil).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me/recipes?page=2&limit=5&sort=title&order=asc", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dtos.RecipeListResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Recipes, 1)
+		assert.Equal(t, dtos.RecipeListMeta{Page: 2, Limit: 5, Sort: "title", Order: "asc"}, response.Meta)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.On("ListRecipesByUser", mock.Anything, "test-user", 1, 10, "created_at", "desc").
+			Return(nil, errors.New("database error")).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me/recipes", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me/recipes", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
 func TestGetRecipe(t *testing.T) {
 	handler, router, mockService := setupTest()
 	router.GET("/recipes/:id", handler.GetRecipe)
@@ -162,6 +321,32 @@ func TestGetRecipe(t *testing.T) {
 		assert.Equal(t, "Test Recipe", response.Title)
 	})
 
+	t.Run("timestamps are RFC3339 UTC", func(t *testing.T) {
+		zone := time.FixedZone("UTC-5", -5*60*60)
+		mockRecipe := &models.Recipe{
+			ID:        "2",
+			Title:     "Timestamped Recipe",
+			CreatedAt: time.Date(2024, 3, 1, 7, 30, 15, 123456789, zone),
+			UpdatedAt: time.Date(2024, 3, 2, 22, 0, 0, 0, zone),
+		}
+
+		mockService.On("GetRecipe", mock.Anything, "2").
+			Return(mockRecipe, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/2", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var raw map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &raw)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-03-01T12:30:15Z", raw["created_at"])
+		assert.Equal(t, "2024-03-03T03:00:00Z", raw["updated_at"])
+	})
+
 	t.Run("recipe not found", func(t *testing.T) {
 		mockService.On("GetRecipe", mock.Anything, "999").
 			Return(nil, gorm.ErrRecordNotFound)
@@ -195,6 +380,522 @@ func TestGetRecipe(t *testing.T) {
 	})
 }
 
+func TestGetRecipeMeasurementUnits(t *testing.T) {
+	recipe := &models.Recipe{ID: "1", Title: "Test Recipe"}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "Flour", Amount: "2", Unit: "cups"},
+		{Name: "Salt", Amount: "1", Unit: "pinch"},
+	})
+
+	setup := func(preferred string) (*gin.Engine, *MockUserService) {
+		handler, router, mockService := setupTest()
+		users := new(MockUserService)
+		handler.Users = users
+		router.GET("/recipes/:id", handler.GetRecipe)
+		mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+		users.On("GetUser", mock.Anything, "test-user").Return(&models.User{ID: "test-user", PreferredUnits: preferred}, nil)
+		return router, users
+	}
+
+	get := func(router *gin.Engine, url string) (*httptest.ResponseRecorder, dtos.RecipeResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		var response dtos.RecipeResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	t.Run("metric preference converts amounts", func(t *testing.T) {
+		router, _ := setup("metric")
+		w, response := get(router, "/recipes/1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "metric", response.Units)
+		assert.Equal(t, "473.18", response.Ingredients[0].Amount)
+		assert.Equal(t, "ml", response.Ingredients[0].Unit)
+		assert.Equal(t, "pinch", response.Ingredients[1].Unit)
+	})
+
+	t.Run("unset preference defaults to imperial", func(t *testing.T) {
+		router, _ := setup("")
+		w, response := get(router, "/recipes/1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "imperial", response.Units)
+		assert.Equal(t, "2", response.Ingredients[0].Amount)
+		assert.Equal(t, "cups", response.Ingredients[0].Unit)
+	})
+
+	t.Run("query parameter overrides preference", func(t *testing.T) {
+		router, users := setup("metric")
+		w, response := get(router, "/recipes/1?units=imperial")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "imperial", response.Units)
+		assert.Equal(t, "cups", response.Ingredients[0].Unit)
+		users.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid units parameter", func(t *testing.T) {
+		router, _ := setup("metric")
+		w, _ := get(router, "/recipes/1?units=cubits")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetRecipeEstimatedCost(t *testing.T) {
+	handler, router, mockService := setupTest()
+	handler.Pricing = pricing.NewEstimator(map[string]pricing.Price{
+		"flour": {Cost: 2, Per: "kg"},
+		"egg":   {Cost: 0.5, Per: "each"},
+	})
+	router.GET("/recipes/:id", handler.GetRecipe)
+
+	recipe := &models.Recipe{ID: "1", Title: "Test Recipe"}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "Flour", Amount: "500", Unit: "g"},
+		{Name: "Eggs", Amount: "2", Unit: ""},
+		{Name: "Saffron", Amount: "1", Unit: "pinch"},
+	})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+
+	get := func(url string) dtos.RecipeResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RecipeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("estimate included when requested", func(t *testing.T) {
+		response := get("/recipes/1?estimate_cost=true")
+		if assert.NotNil(t, response.EstimatedCost) {
+			assert.Equal(t, 2.0, *response.EstimatedCost)
+		}
+		assert.Equal(t, []string{"Saffron"}, response.UnpricedIngredients)
+	})
+
+	t.Run("estimate omitted by default", func(t *testing.T) {
+		response := get("/recipes/1")
+		assert.Nil(t, response.EstimatedCost)
+		assert.Empty(t, response.UnpricedIngredients)
+	})
+}
+
+func TestScaleRecipe(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.POST("/recipes/:id/scale", handler.ScaleRecipe)
+
+	recipe := &models.Recipe{ID: "1", Title: "Pancakes", Servings: 4}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "flour", Amount: "1 1/2", Unit: "cups"},
+		{Name: "eggs", Amount: "2"},
+		{Name: "salt", Amount: "to taste"},
+	})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+	mockService.On("GetRecipe", mock.Anything, "no-servings").Return(&models.Recipe{ID: "no-servings", Title: "Stew"}, nil)
+
+	post := func(id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/"+id+"/scale", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("scales amounts and servings", func(t *testing.T) {
+		w := post("1", `{"target_servings": 6}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.ServingsScaleResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1.5, response.Ratio)
+		assert.Equal(t, 6, response.Recipe.Servings)
+		assert.Equal(t, "2.25", response.Recipe.Ingredients[0].Amount)
+		assert.Equal(t, "cups", response.Recipe.Ingredients[0].Unit)
+		assert.Equal(t, "3", response.Recipe.Ingredients[1].Amount)
+		assert.Equal(t, "to taste", response.Recipe.Ingredients[2].Amount)
+		assert.Equal(t, []string{"salt"}, response.UnscaledIngredients)
+		mockService.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fractional result is rounded", func(t *testing.T) {
+		w := post("1", `{"target_servings": 3}`)
+
+		var response dtos.ServingsScaleResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "1.13", response.Recipe.Ingredients[0].Amount)
+	})
+
+	t.Run("recipe without servings", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post("no-servings", `{"target_servings": 2}`).Code)
+	})
+
+	for name, body := range map[string]string{
+		"missing target":  `{}`,
+		"zero target":     `{"target_servings": 0}`,
+		"non-numeric":     `{"target_servings": "six"}`,
+		"negative target": `{"target_servings": -2}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, post("1", body).Code)
+		})
+	}
+}
+
+func TestScalePan(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.POST("/recipes/:id/scale-pan", handler.ScalePan)
+
+	recipe := &models.Recipe{ID: "1", Title: "Sponge Cake", CookTime: 30}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "Flour", Amount: "2", Unit: "cups"},
+		{Name: "Salt", Amount: "a pinch", Unit: ""},
+	})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+
+	post := func(body dtos.PanScaleRequest) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/1/scale-pan", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("round to larger round pan", func(t *testing.T) {
+		w := post(dtos.PanScaleRequest{
+			Source: dtos.PanDimensions{Shape: "round", Diameter: 8},
+			Target: dtos.PanDimensions{Shape: "round", Diameter: 10},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.PanScaleResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.InDelta(t, 1.5625, response.Ratio, 1e-9)
+		assert.Equal(t, "3.13", response.Recipe.Ingredients[0].Amount)
+		assert.Equal(t, "a pinch", response.Recipe.Ingredients[1].Amount)
+		assert.Equal(t, 38, response.Recipe.CookTime)
+	})
+
+	t.Run("non-positive dimensions", func(t *testing.T) {
+		w := post(dtos.PanScaleRequest{
+			Source: dtos.PanDimensions{Shape: "rectangular", Width: 9, Length: 0},
+			Target: dtos.PanDimensions{Shape: "round", Diameter: 10},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported shape", func(t *testing.T) {
+		w := post(dtos.PanScaleRequest{
+			Source: dtos.PanDimensions{Shape: "hexagonal", Diameter: 9},
+			Target: dtos.PanDimensions{Shape: "round", Diameter: 10},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExportShoppingList(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/recipes/:id/shopping-list", handler.ExportShoppingList)
+
+	recipe := &models.Recipe{ID: "1", Title: "Pancakes", Servings: 2}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "flour", Amount: "1 1/2", Unit: "cups"},
+		{Name: "eggs", Amount: "2", Unit: ""},
+	})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/1/shopping-list"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("text scaled to servings", func(t *testing.T) {
+		w := get("?servings=4")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="shopping-list-1.txt"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "Shopping list: Pancakes (serves 4)\n\n- [ ] 3 cups flour\n- [ ] 4 eggs\n", w.Body.String())
+	})
+
+	t.Run("json scaled to servings", func(t *testing.T) {
+		w := get("?format=json&servings=1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="shopping-list-1.json"`, w.Header().Get("Content-Disposition"))
+		var list dtos.ShoppingList
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, "1", list.RecipeID)
+		assert.Equal(t, 1, list.Servings)
+		assert.Equal(t, []dtos.ShoppingListItem{
+			{Name: "flour", Amount: "0.75", Unit: "cups"},
+			{Name: "eggs", Amount: "1"},
+		}, list.Items)
+	})
+
+	t.Run("json converted to metric", func(t *testing.T) {
+		w := get("?format=json&units=metric")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var list dtos.ShoppingList
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, "metric", list.Units)
+		assert.Equal(t, "ml", list.Items[0].Unit)
+	})
+
+	for name, query := range map[string]string{
+		"unsupported format": "?format=pdf",
+		"invalid servings":   "?servings=0",
+		"invalid units":      "?units=cubits",
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, get(query).Code)
+		})
+	}
+}
+
+func TestGenerateShoppingList(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.POST("/recipes/shopping-list", handler.GenerateShoppingList)
+
+	pancakes := &models.Recipe{ID: "1", Title: "Pancakes"}
+	_ = pancakes.SetIngredients([]models.Ingredient{
+		{Name: "flour", Amount: "200", Unit: "g"},
+		{Name: "eggs", Amount: "2", Unit: ""},
+		{Name: "milk", Amount: "1", Unit: "cup"},
+	})
+	bread := &models.Recipe{ID: "2", Title: "Bread"}
+	_ = bread.SetIngredients([]models.Ingredient{
+		{Name: "Flour", Amount: "1/2", Unit: "kg"},
+		{Name: "egg", Amount: "1", Unit: ""},
+		{Name: "milk", Amount: "a splash", Unit: ""},
+	})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(pancakes, nil)
+	mockService.On("GetRecipe", mock.Anything, "2").Return(bread, nil)
+	mockService.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	post := func(query string, body interface{}) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/shopping-list"+query, bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("merges recipes and reports missing ones", func(t *testing.T) {
+		w := post("?units=metric", dtos.ShoppingListRequest{RecipeIDs: []string{"1", "missing", "2", "1"}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var list dtos.CombinedShoppingList
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, []string{"1", "2"}, list.RecipeIDs)
+		assert.Equal(t, []dtos.ShoppingListCategory{
+			{Category: "dairy & eggs", Items: []dtos.ShoppingListItem{
+				{Name: "eggs", Amount: "3", RecipeIDs: []string{"1", "2"}},
+			}},
+			{Category: "pantry", Items: []dtos.ShoppingListItem{
+				{Name: "flour", Amount: "700", Unit: "g", RecipeIDs: []string{"1", "2"}},
+			}},
+		}, list.Categories)
+		assert.Equal(t, []dtos.ShoppingListItem{
+			{Name: "milk", Amount: "a splash", RecipeIDs: []string{"2"}},
+			{Name: "milk", Amount: "236.59", Unit: "ml", RecipeIDs: []string{"1"}},
+		}, list.Incompatible)
+		if assert.Len(t, list.Errors, 1) {
+			assert.Equal(t, "missing", list.Errors[0].RecipeID)
+			assert.Equal(t, "NOT_FOUND", list.Errors[0].Error.Code)
+		}
+	})
+
+	t.Run("no recipe IDs", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post("", dtos.ShoppingListRequest{}).Code)
+	})
+
+	t.Run("invalid units", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post("?units=cubits", dtos.ShoppingListRequest{RecipeIDs: []string{"1"}}).Code)
+	})
+}
+
+func TestExportRecipe(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/recipes/:id/export", handler.ExportRecipe)
+
+	recipe := &models.Recipe{ID: "1", Title: "Pancakes", Servings: 2, PrepTime: 5}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "flour", Amount: "1", Unit: "cup"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Whisk and fry."}})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+	mockService.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("card", func(t *testing.T) {
+		w := get("/recipes/1/export?format=card")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="recipe-1-card.html"`, w.Header().Get("Content-Disposition"))
+		assert.Contains(t, w.Body.String(), "<h1>Pancakes</h1>")
+		assert.Contains(t, w.Body.String(), "<li>1 cup flour</li>")
+		assert.Contains(t, w.Body.String(), "<li>Whisk and fry.</li>")
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		w := get("/recipes/1/export?format=markdown")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="pancakes.md"`, w.Header().Get("Content-Disposition"))
+		assert.Contains(t, w.Body.String(), "# Pancakes\n")
+		assert.Contains(t, w.Body.String(), "- 1 cup flour\n")
+		assert.Contains(t, w.Body.String(), "1. Whisk and fry.\n")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("/recipes/1/export?format=pdf").Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/recipes/missing/export").Code)
+	})
+}
+
+func TestRecipeHandlersRejectMalformedCurrentUser(t *testing.T) {
+	for name, value := range map[string]interface{}{
+		"non-string value": 42,
+		"empty string":     "",
+		"map without id":   map[string]interface{}{"name": "Test User"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mockService := new(MockRecipeService)
+			handler := handlers.NewRecipeHandler(mockService)
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set("currentUser", value) })
+			router.Use(middleware.AuthMiddleware())
+			router.DELETE("/recipes/:id", handler.DeleteRecipe)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("DELETE", "/recipes/1", nil)
+			assert.NotPanics(t, func() { router.ServeHTTP(w, req) })
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			var response dtos.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "UNAUTHORIZED", response.Code)
+			mockService.AssertNotCalled(t, "GetRecipe", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConvertRecipeUnits(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/recipes/:id/convert", handler.ConvertRecipeUnits)
+
+	recipe := &models.Recipe{ID: "1", Title: "Bread"}
+	_ = recipe.SetIngredients([]models.Ingredient{
+		{Name: "flour", Amount: "500", Unit: "g"},
+		{Name: "salt", Amount: "1", Unit: "pinch"},
+		{Name: "water", Amount: "1", Unit: "cup"},
+	})
+	mockService.On("GetRecipe", mock.Anything, "1").Return(recipe, nil)
+	mockService.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("converts to imperial and reports skipped ingredients", func(t *testing.T) {
+		w := get("/recipes/1/convert?units=imperial")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RecipeConversionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "imperial", response.Recipe.Units)
+		assert.Equal(t, dtos.Ingredient{Name: "flour", Amount: "1.1", Unit: "lb"}, response.Recipe.Ingredients[0])
+		assert.Equal(t, dtos.Ingredient{Name: "salt", Amount: "1", Unit: "pinch"}, response.Recipe.Ingredients[1])
+		assert.Equal(t, dtos.Ingredient{Name: "water", Amount: "1", Unit: "cup"}, response.Recipe.Ingredients[2])
+		assert.Equal(t, []string{"salt"}, response.Skipped)
+	})
+
+	t.Run("invalid units", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("/recipes/1/convert?units=cubits").Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, get("/recipes/missing/convert?units=metric").Code)
+	})
+}
+
+func TestImportRecipe(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.POST("/recipes/import", handler.ImportRecipe)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/import", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("imports as pending", func(t *testing.T) {
+		mockService.On("SaveRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return r.Title == "Imported" && !r.Approved && r.UserID != nil && *r.UserID == "test-user"
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Recipe).ID = "imported-1"
+		}).Return(nil).Once()
+
+		w := post(`{"title": "Imported", "ingredients": [{"name": "flour", "amount": "1/2", "unit": "cup"}], "steps": [{"description": "Mix."}]}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response dtos.RecipeImportResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dtos.RecipeImportResponse{RecipeID: "imported-1", Status: "pending_approval"}, response)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		w := post(`{"title": "Imported", "ingredients": [{"name": "flour"}], "steps": [{"description": "Mix."}], "source": "other-app"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown field")
+	})
+
+	t.Run("rejects malformed amounts", func(t *testing.T) {
+		w := post(`{"title": "Imported", "ingredients": [{"name": "flour", "amount": "lots"}], "steps": [{"description": "Mix."}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "amount")
+	})
+
+	mockService.AssertExpectations(t)
+}
+
 func TestSaveRecipe(t *testing.T) {
 	handler, router, mockService := setupTest()
 	router.POST("/recipes", handler.SaveRecipe)
@@ -206,8 +907,9 @@ func TestSaveRecipe(t *testing.T) {
 			Steps:       []dtos.Step{{Order: 1, Description: "Step 1"}},
 		}
 
-		mockService.On("SaveRecipe", mock.Anything, mock.AnythingOfType("*models.Recipe")).
-			Return(nil)
+		mockService.On("SaveRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return r.UserID != nil && *r.UserID == "test-user"
+		})).Return(nil)
 
 		body, _ := json.Marshal(recipeReq)
 		w := httptest.NewRecorder()
@@ -304,11 +1006,98 @@ func TestSaveRecipe_missing_required_fields(t *testing.T) {
 	assert.Contains(t, response.Message, "Steps' Error:Field validation for 'Steps' failed on the 'required' tag")
 }
 
+func TestUpdateRecipeRelatedLists(t *testing.T) {
+	put := func(body string) *models.Recipe {
+		handler, router, mockService := setupTest()
+		router.PUT("/recipes/:id", handler.UpdateRecipe)
+		owner := "test-user"
+		existing := &models.Recipe{ID: "1", Title: "Chili", UserID: &owner, Tags: []models.Tag{{ID: "tag-1", Name: "quick"}}}
+		mockService.On("GetRecipe", mock.Anything, "1").Return(existing, nil)
+		var updated *models.Recipe
+		mockService.On("UpdateRecipe", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*models.Recipe)
+		}).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/recipes/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		return updated
+	}
+	base := `"title": "Chili", "ingredients": [{"name": "beans", "amount": "1", "unit": "can"}], "steps": [{"order": 1, "description": "Simmer."}]`
+
+	t.Run("omitted keeps current tags", func(t *testing.T) {
+		updated := put("{" + base + "}")
+		if assert.NotNil(t, updated) {
+			assert.Equal(t, []models.Tag{{ID: "tag-1", Name: "quick"}}, updated.Tags)
+		}
+	})
+
+	t.Run("null keeps current tags", func(t *testing.T) {
+		updated := put("{" + base + `, "tags": null}`)
+		if assert.NotNil(t, updated) {
+			assert.Len(t, updated.Tags, 1)
+		}
+	})
+
+	t.Run("empty list clears tags", func(t *testing.T) {
+		updated := put("{" + base + `, "tags": []}`)
+		if assert.NotNil(t, updated) {
+			assert.NotNil(t, updated.Tags)
+			assert.Empty(t, updated.Tags)
+		}
+	})
+
+	t.Run("values replace tags", func(t *testing.T) {
+		updated := put("{" + base + `, "tags": ["vegan", "spicy"]}`)
+		if assert.NotNil(t, updated) {
+			assert.Equal(t, []models.Tag{{Name: "vegan"}, {Name: "spicy"}}, updated.Tags)
+		}
+	})
+}
+
+func TestUpdateRecipeOwnership(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.PUT("/recipes/:id", handler.UpdateRecipe)
+
+	other := "someone-else"
+	mockService.On("GetRecipe", mock.Anything, "1").
+		Return(&models.Recipe{ID: "1", Title: "Chili", UserID: &other}, nil)
+	mockService.On("GetRecipe", mock.Anything, "2").
+		Return(&models.Recipe{ID: "2", Title: "Stew"}, nil)
+	body := `{"title": "Hijacked", "ingredients": [{"name": "beans", "amount": "1", "unit": "can"}], "steps": [{"order": 1, "description": "Simmer."}]}`
+
+	for name, id := range map[string]string{"recipe owned by another user": "1", "recipe without owner": "2"} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PUT", "/recipes/"+id, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+
+			var response dtos.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "FORBIDDEN", response.Code)
+		})
+	}
+	mockService.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+}
+
 func TestDeleteRecipe(t *testing.T) {
 	handler, router, mockService := setupTest()
 	router.DELETE("/recipes/:id", handler.DeleteRecipe)
 
+	owner := "test-user"
+	other := "someone-else"
+
 	t.Run("successful delete recipe", func(t *testing.T) {
+		mockService.On("GetRecipe", mock.Anything, "1").
+			Return(&models.Recipe{ID: "1", UserID: &owner}, nil)
 		mockService.On("DeleteRecipe", mock.Anything, "1").
 			Return(nil)
 
@@ -320,9 +1109,40 @@ func TestDeleteRecipe(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, w.Code)
 	})
 
+	t.Run("recipe owned by another user", func(t *testing.T) {
+		mockService.On("GetRecipe", mock.Anything, "2").
+			Return(&models.Recipe{ID: "2", UserID: &other}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/recipes/2", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response dtos.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "FORBIDDEN", response.Code)
+		mockService.AssertNotCalled(t, "DeleteRecipe", mock.Anything, "2")
+	})
+
+	t.Run("recipe without owner", func(t *testing.T) {
+		mockService.On("GetRecipe", mock.Anything, "3").
+			Return(&models.Recipe{ID: "3"}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/recipes/3", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "DeleteRecipe", mock.Anything, "3")
+	})
+
 	t.Run("recipe not found", func(t *testing.T) {
-		mockService.On("DeleteRecipe", mock.Anything, "999").
-			Return(gorm.ErrRecordNotFound)
+		mockService.On("GetRecipe", mock.Anything, "999").
+			Return(nil, gorm.ErrRecordNotFound)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("DELETE", "/recipes/999", nil)
@@ -354,24 +1174,66 @@ func TestDeleteRecipe(t *testing.T) {
 }
 
 func TestRateRecipe(t *testing.T) {
-	t.Skip("Temporarily disabled - rating functionality not implemented yet")
 	handler, router, mockService := setupTest()
 	router.POST("/recipes/:id/rate", handler.RateRecipe)
 
 	t.Run("successful rate recipe", func(t *testing.T) {
-		mockService.On("RateRecipe", mock.Anything, "1", 5.0).
+		mockService.On("RateRecipe", mock.Anything, "1", "test-user", 5.0).
 			Return(nil)
 		mockService.On("GetRecipe", mock.Anything, "1").
-			Return(&models.Recipe{ID: "1"}, nil)
+			Return(&models.Recipe{ID: "1", AverageRating: 4.5, RatingCount: 2}, nil)
 
 		w := httptest.NewRecorder()
-		body := `5.0`
+		body := `{"rating": 5.0}`
 		req, _ := http.NewRequest("POST", "/recipes/1/rate", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dtos.RecipeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 4.5, response.AverageRating)
+		assert.Equal(t, 2, response.RatingCount)
+	})
+
+	t.Run("zero is a valid rating", func(t *testing.T) {
+		mockService.On("RateRecipe", mock.Anything, "2", "test-user", 0.0).
+			Return(nil)
+		mockService.On("GetRecipe", mock.Anything, "2").
+			Return(&models.Recipe{ID: "2"}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/2/rate", strings.NewReader(`{"rating": 0}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("missing rating", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/1/rate", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		mockService.On("RateRecipe", mock.Anything, "missing", "test-user", 3.0).
+			Return(apperrors.NewNotFoundError("recipe not found"))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/missing/rate", strings.NewReader(`{"rating": 3}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 
 	t.Run("invalid rating value - too high", func(t *testing.T) {
@@ -441,3 +1303,88 @@ func TestRateRecipe(t *testing.T) {
 		assert.Equal(t, "Missing or invalid authorization token", response.Message)
 	})
 }
+
+func TestGetRecipeRatings(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/recipes/:id/ratings", handler.GetRecipeRatings)
+
+	t.Run("count and average", func(t *testing.T) {
+		mockService.On("GetRecipeRatings", mock.Anything, "1").
+			Return(&models.RatingSummary{Count: 3, Average: 4}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/1/ratings", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RecipeRatingsResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dtos.RecipeRatingsResponse{RecipeID: "1", Count: 3, Average: 4}, response)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		mockService.On("GetRecipeRatings", mock.Anything, "missing").
+			Return(nil, apperrors.NewNotFoundError("recipe not found"))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/missing/ratings", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSearchByIngredients(t *testing.T) {
+	handler, router, mockService := setupTest()
+	router.GET("/recipes/search/ingredients", handler.SearchByIngredients)
+
+	search := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/search/ingredients"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("ranked results", func(t *testing.T) {
+		matches := []services.IngredientMatch{{
+			Recipe:  models.Recipe{ID: "1", Title: "Fried Rice"},
+			Matched: []string{"egg", "rice"},
+			Missing: []string{"soy sauce"},
+			Score:   0.83,
+		}}
+		mockService.On("SearchByIngredients", mock.Anything, []string{"egg", "rice"}, 5).Return(matches, nil).Once()
+
+		w := search("?ingredients=egg&ingredients=rice&ingredients=+&limit=5")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.IngredientSearchResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Results, 1)
+		assert.Equal(t, "1", response.Results[0].Recipe.ID)
+		assert.Equal(t, []string{"egg", "rice"}, response.Results[0].MatchedIngredients)
+		assert.Equal(t, []string{"soy sauce"}, response.Results[0].MissingIngredients)
+		assert.Equal(t, 0.83, response.Results[0].Score)
+	})
+
+	t.Run("missing ingredients", func(t *testing.T) {
+		w := search("")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("too many ingredients", func(t *testing.T) {
+		query := "?ingredients=a"
+		for i := 0; i < services.MaxSearchIngredients; i++ {
+			query += "&ingredients=a"
+		}
+		w := search(query)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		w := search("?ingredients=egg&limit=0")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"github.com/pageza/alchemorsel-v1/internal/storage"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// postImage uploads data as the named form field of a multipart request.
+func postImage(router http.Handler, recipeID, field string, data []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile(field, "photo")
+	part.Write(data)
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipes/"+recipeID+"/image", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUploadRecipeImage(t *testing.T) {
+	owner := "test-user"
+	other := "someone-else"
+
+	t.Run("stores the image and records its URL", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		images := storage.NewMemoryStorage("https://cdn.example.com")
+		handler.Images = images
+		router.POST("/recipes/:id/image", handler.UploadRecipeImage)
+
+		mockService.On("GetRecipe", mock.Anything, "1").Return(&models.Recipe{ID: "1", UserID: &owner}, nil)
+		mockService.On("UpdateRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return strings.HasPrefix(r.ImageURL, "https://cdn.example.com/recipes/1/") && strings.HasSuffix(r.ImageURL, ".png")
+		})).Return(nil)
+
+		w := postImage(router, "1", "image", append(pngHeader, "pixels"...))
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dtos.RecipeImageResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "1", response.RecipeID)
+		key := strings.TrimPrefix(response.ImageURL, "https://cdn.example.com/")
+		stored, ok := images.Get(key)
+		require.True(t, ok)
+		assert.Equal(t, "image/png", stored.ContentType)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("removes the image when the recipe cannot be saved", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		images := storage.NewMemoryStorage("/uploads")
+		handler.Images = images
+		router.POST("/recipes/:id/image", handler.UploadRecipeImage)
+
+		mockService.On("GetRecipe", mock.Anything, "1").Return(&models.Recipe{ID: "1", UserID: &owner}, nil)
+		mockService.On("UpdateRecipe", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		w := postImage(router, "1", "image", pngHeader)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Zero(t, images.Len())
+	})
+
+	tests := []struct {
+		name   string
+		field  string
+		data   []byte
+		status int
+		code   string
+	}{
+		{"missing file", "photo", pngHeader, http.StatusBadRequest, "BAD_REQUEST"},
+		{"not an image", "image", []byte("just some text"), http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"too large", "image", append(pngHeader, make([]byte, services.MaxRecipeImageBytes)...), http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, router, mockService := setupTest()
+			images := storage.NewMemoryStorage("/uploads")
+			handler.Images = images
+			router.POST("/recipes/:id/image", handler.UploadRecipeImage)
+			mockService.On("GetRecipe", mock.Anything, "1").Return(&models.Recipe{ID: "1", UserID: &owner}, nil)
+
+			w := postImage(router, "1", tt.field, tt.data)
+
+			assert.Equal(t, tt.status, w.Code)
+			var response dtos.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+			assert.Zero(t, images.Len())
+			mockService.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("recipe owned by another user", func(t *testing.T) {
+		handler, router, mockService := setupTest()
+		handler.Images = storage.NewMemoryStorage("/uploads")
+		router.POST("/recipes/:id/image", handler.UploadRecipeImage)
+		mockService.On("GetRecipe", mock.Anything, "2").Return(&models.Recipe{ID: "2", UserID: &other}, nil)
+
+		w := postImage(router, "2", "image", pngHeader)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,168 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupMealPlanTest() (*gin.Engine, *MockRecipeService, *MockRecipeResolutionService) {
+	gin.SetMode(gin.TestMode)
+	recipes := new(MockRecipeService)
+	resolution := new(MockRecipeResolutionService)
+	handler := handlers.NewRecipeBatchHandler(recipes, resolution)
+	handler.Concurrency = 3
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.POST("/recipes/generate/meal-plan", handler.GenerateMealPlan)
+	return router, recipes, resolution
+}
+
+func postMealPlan(router *gin.Engine, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipes/generate/meal-plan", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGenerateMealPlan(t *testing.T) {
+	t.Run("groups recipes by day and meal with a nutrition summary", func(t *testing.T) {
+		router, recipes, resolution := setupMealPlanTest()
+		for day, title := range map[int]string{1: "Risotto", 2: "Gnocchi"} {
+			resolution.On("GenerateRecipe", mock.Anything, fmt.Sprintf("vegetarian lunch, italian, about 900 kcal per serving, day %d of 2, a different dish from the other days", day)).
+				Return(&models.Recipe{Title: title, NutritionalInfo: "Per serving: 800 kcal, 20 g protein, 100 g carbs, 30 g fat"}, nil).Once()
+		}
+		resolution.On("GenerateRecipe", mock.Anything, "vegetarian dinner, italian, about 900 kcal per serving, day 1 of 2, a different dish from the other days").
+			Return(nil, integrations.ErrDeepSeekTimeout).Once()
+		resolution.On("GenerateRecipe", mock.Anything, "vegetarian dinner, italian, about 900 kcal per serving, day 2 of 2, a different dish from the other days").
+			Return(&models.Recipe{Title: "Lasagne", NutritionalInfo: "Calories: 1000"}, nil).Once()
+		recipes.On("SaveRecipe", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				recipe := args.Get(1).(*models.Recipe)
+				recipe.ID = strings.ToLower(recipe.Title)
+			}).Return(nil)
+
+		w := postMealPlan(router, dtos.MealPlanRequest{
+			Days:                2,
+			MealsPerDay:         2,
+			DietaryRestrictions: []string{"vegetarian"},
+			CalorieTarget:       1800,
+			Preferences:         "italian",
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.MealPlanResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Days, 2) {
+			for i, day := range response.Days {
+				assert.Equal(t, i+1, day.Day)
+				if assert.Len(t, day.Meals, 2) {
+					assert.Equal(t, "lunch", day.Meals[0].Meal)
+					assert.Equal(t, "dinner", day.Meals[1].Meal)
+				}
+			}
+			first, second := response.Days[0].Meals, response.Days[1].Meals
+			assert.Equal(t, "risotto", first[0].RecipeID)
+			assert.Equal(t, "gnocchi", second[0].RecipeID)
+			assert.NotEqual(t, first[0].Query, second[0].Query, "each day asks for its own lunch")
+			if assert.NotNil(t, first[1].Error) {
+				assert.Equal(t, "AI_TIMEOUT", first[1].Error.Code)
+			}
+			assert.Equal(t, "lasagne", second[1].RecipeID)
+		}
+		assert.Equal(t, dtos.MealPlanNutrition{
+			Calories:             2600,
+			Protein:              40,
+			Carbs:                200,
+			Fat:                  60,
+			AverageDailyCalories: 1300,
+			CalorieTarget:        1800,
+			RecipesCounted:       3,
+		}, response.Nutrition)
+		resolution.AssertNumberOfCalls(t, "GenerateRecipe", 4)
+	})
+
+	t.Run("single day plan leaves the day out", func(t *testing.T) {
+		router, recipes, resolution := setupMealPlanTest()
+		resolution.On("GenerateRecipe", mock.Anything, "dinner").Return(&models.Recipe{Title: "Curry"}, nil).Once()
+		recipes.On("SaveRecipe", mock.Anything, mock.Anything).Return(nil)
+
+		w := postMealPlan(router, dtos.MealPlanRequest{Days: 1, MealsPerDay: 1})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resolution.AssertExpectations(t)
+	})
+
+	t.Run("too many recipes", func(t *testing.T) {
+		router, _, resolution := setupMealPlanTest()
+
+		w := postMealPlan(router, dtos.MealPlanRequest{Days: 7, MealsPerDay: 4})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		resolution.AssertNotCalled(t, "GenerateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing days", func(t *testing.T) {
+		router, _, _ := setupMealPlanTest()
+
+		w := postMealPlan(router, dtos.MealPlanRequest{MealsPerDay: 3})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGenerateMealPlanDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recipes := new(MockRecipeService)
+	resolution := new(MockRecipeResolutionService)
+	handler := handlers.NewRecipeBatchHandler(recipes, resolution)
+	handler.Concurrency = 1
+	handler.Deadline = 50 * time.Millisecond
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.POST("/recipes/generate/meal-plan", middleware.Timeout(time.Second), handler.GenerateMealPlan)
+
+	resolution.On("GenerateRecipe", mock.Anything, "dinner, day 1 of 3, a different dish from the other days").
+		Return(&models.Recipe{Title: "Soup"}, nil).Once()
+	resolution.On("GenerateRecipe", mock.Anything, "dinner, day 2 of 3, a different dish from the other days").
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(nil, integrations.ErrDeepSeekTimeout).Once()
+	recipes.On("SaveRecipe", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.Recipe).ID = "recipe-soup" }).Return(nil)
+
+	w := postMealPlan(router, dtos.MealPlanRequest{Days: 3, MealsPerDay: 1})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dtos.MealPlanResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Days, 3) {
+		assert.Equal(t, "recipe-soup", response.Days[0].Meals[0].RecipeID)
+		for _, day := range response.Days[1:] {
+			if assert.NotNil(t, day.Meals[0].Error) {
+				assert.Equal(t, "CANCELLED", day.Meals[0].Error.Code)
+			}
+		}
+	}
+	resolution.AssertNotCalled(t, "GenerateRecipe", mock.Anything, "dinner, day 3 of 3, a different dish from the other days")
+}
@@ -0,0 +1,384 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/parsers"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockRecipeResolutionService is a mock implementation of the RecipeResolutionService interface
+type MockRecipeResolutionService struct {
+	mock.Mock
+}
+
+func (m *MockRecipeResolutionService) FindExactMatch(ctx context.Context, parsedQuery *parsers.ParsedQuery) (string, error) {
+	args := m.Called(ctx, parsedQuery)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) FindCloseMatches(ctx context.Context, parsedQuery *parsers.ParsedQuery) ([]string, error) {
+	args := m.Called(ctx, parsedQuery)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) BuildCompositePrompt(query string, promptInstructions string, expectedResponseFormat string, profile map[string]interface{}, language string) (string, error) {
+	args := m.Called(query, promptInstructions, expectedResponseFormat, profile, language)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) ResolveRecipeByModel(ctx context.Context, compositePrompt string, opts integrations.GenerationOptions) (string, []string, error) {
+	args := m.Called(ctx, compositePrompt, opts)
+	return args.String(0), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockRecipeResolutionService) SubstituteIngredient(ctx context.Context, recipe *models.Recipe, ingredient string, reason string) (*models.Recipe, error) {
+	args := m.Called(ctx, recipe, ingredient, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) ExpandRecipe(ctx context.Context, recipe *models.Recipe, allowCoreChanges bool) (*models.Recipe, error) {
+	args := m.Called(ctx, recipe, allowCoreChanges)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) GenerateRecipe(ctx context.Context, query string) (*models.Recipe, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Recipe), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) RecomputeNutrition(ctx context.Context, recipe *models.Recipe) (*services.Nutrition, error) {
+	args := m.Called(ctx, recipe)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Nutrition), args.Error(1)
+}
+
+func (m *MockRecipeResolutionService) SuggestModelTags(ctx context.Context, recipe *models.Recipe) ([]string, error) {
+	args := m.Called(ctx, recipe)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func setupModificationTest() (*gin.Engine, *MockRecipeService, *MockRecipeResolutionService) {
+	gin.SetMode(gin.TestMode)
+	recipes := new(MockRecipeService)
+	resolution := new(MockRecipeResolutionService)
+	handler := handlers.NewRecipeModificationHandler(recipes, resolution)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.POST("/recipes/:id/substitute", handler.SubstituteIngredient)
+	router.POST("/recipes/:id/expand", handler.ExpandRecipe)
+	router.POST("/recipes/:id/nutrition", handler.RecomputeNutrition)
+	router.POST("/recipes/:id/suggest-tags", handler.SuggestTags)
+	return router, recipes, resolution
+}
+
+func postSubstitution(router *gin.Engine, body dtos.IngredientSubstitutionRequest) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/recipes/recipe-1/substitute", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubstituteIngredient(t *testing.T) {
+	recipe := &models.Recipe{ID: "recipe-1", Title: "Pancakes"}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "Butter", Amount: "3", Unit: "tbsp"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Melt butter."}})
+
+	t.Run("successful substitution", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		modified := &models.Recipe{Title: "Dairy-Free Pancakes"}
+		_ = modified.SetIngredients([]models.Ingredient{{Name: "olive oil", Amount: "3", Unit: "tbsp"}})
+		_ = modified.SetSteps([]models.Step{{Order: 1, Description: "Warm olive oil."}})
+
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("SubstituteIngredient", mock.Anything, recipe, "butter", "dairy-free").Return(modified, nil)
+
+		w := postSubstitution(router, dtos.IngredientSubstitutionRequest{Ingredient: "butter", Reason: "dairy-free"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RecipeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Dairy-Free Pancakes", response.Title)
+		assert.Equal(t, "olive oil", response.Ingredients[0].Name)
+	})
+
+	t.Run("ingredient not in recipe", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+
+		w := postSubstitution(router, dtos.IngredientSubstitutionRequest{Ingredient: "eggs"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		resolution.AssertNotCalled(t, "SubstituteIngredient", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing ingredient", func(t *testing.T) {
+		router, _, _ := setupModificationTest()
+		w := postSubstitution(router, dtos.IngredientSubstitutionRequest{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExpandRecipe(t *testing.T) {
+	owner := "test-user"
+	recipe := &models.Recipe{ID: "recipe-1", Title: "Pancakes", UserID: &owner}
+	_ = recipe.SetIngredients([]models.Ingredient{{Name: "Flour", Amount: "200", Unit: "g"}})
+	_ = recipe.SetSteps([]models.Step{{Order: 1, Description: "Mix and fry."}})
+
+	postExpansion := func(router *gin.Engine, id string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/"+id+"/expand", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("successful expansion is saved", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		expanded := &models.Recipe{Title: "Pancakes", NutritionalInfo: "350 kcal per serving"}
+		_ = expanded.SetIngredients([]models.Ingredient{{Name: "Flour", Amount: "200", Unit: "g"}})
+		_ = expanded.SetSteps([]models.Step{
+			{Order: 1, Description: "Whisk the flour with milk until smooth."},
+			{Order: 2, Description: "Fry in a hot buttered pan until golden."},
+		})
+
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("ExpandRecipe", mock.Anything, recipe, false).Return(expanded, nil)
+		recipes.On("UpdateRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return r.ID == "recipe-1"
+		})).Return(nil)
+
+		w := postExpansion(router, "recipe-1", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.RecipeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "recipe-1", response.ID)
+		assert.Equal(t, "Pancakes", response.Title)
+		assert.Equal(t, "350 kcal per serving", response.NutritionalInfo)
+		assert.Len(t, response.Steps, 2)
+		recipes.AssertExpectations(t)
+	})
+
+	t.Run("allow core changes is forwarded", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("ExpandRecipe", mock.Anything, recipe, true).Return(&models.Recipe{Title: "Fluffy Pancakes"}, nil)
+		recipes.On("UpdateRecipe", mock.Anything, mock.Anything).Return(nil)
+
+		w := postExpansion(router, "recipe-1", `{"allow_core_changes": true}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		resolution.AssertExpectations(t)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		w := postExpansion(router, "missing", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		resolution.AssertNotCalled(t, "ExpandRecipe", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("recipe owned by another user", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		other := "someone-else"
+		recipes.On("GetRecipe", mock.Anything, "recipe-2").Return(&models.Recipe{ID: "recipe-2", Title: "Waffles", UserID: &other}, nil)
+
+		w := postExpansion(router, "recipe-2", "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "FORBIDDEN", response.Code)
+		resolution.AssertNotCalled(t, "ExpandRecipe", mock.Anything, mock.Anything, mock.Anything)
+		recipes.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("model output not matching schema", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("ExpandRecipe", mock.Anything, recipe, false).
+			Return(nil, &services.ModelSchemaError{Problems: []string{"recipe.steps is required"}})
+
+		w := postExpansion(router, "recipe-1", "")
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "AI_SCHEMA_ERROR", response.Code)
+		assert.Contains(t, response.Message, "recipe.steps is required")
+		recipes.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("DeepSeek errors map to gateway statuses", func(t *testing.T) {
+		tests := []struct {
+			kind   error
+			status int
+			code   string
+		}{
+			{integrations.ErrDeepSeekRateLimited, http.StatusTooManyRequests, "AI_RATE_LIMITED"},
+			{integrations.ErrDeepSeekBadResponse, http.StatusBadGateway, "AI_BAD_RESPONSE"},
+			{integrations.ErrDeepSeekTimeout, http.StatusGatewayTimeout, "AI_TIMEOUT"},
+			{integrations.ErrDeepSeekUnavailable, http.StatusServiceUnavailable, "AI_UNAVAILABLE"},
+		}
+		for _, tt := range tests {
+			router, recipes, resolution := setupModificationTest()
+			recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+			resolution.On("ExpandRecipe", mock.Anything, recipe, false).
+				Return(nil, &integrations.DeepSeekError{Kind: tt.kind, RequestID: "req-42"})
+
+			w := postExpansion(router, "recipe-1", "")
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "req-42", w.Header().Get("X-Upstream-Request-Id"))
+			var response dtos.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.code, response.Code)
+		}
+	})
+}
+
+func TestGetRecipeSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/recipes/schema", handlers.GetRecipeSchema)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/recipes/schema", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, services.RecipeSchemaVersion, w.Header().Get("X-Schema-Version"))
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &schema))
+	assert.ElementsMatch(t, []interface{}{"ingredients", "steps"}, schema["required"])
+}
+
+func TestRecomputeNutrition(t *testing.T) {
+	postNutrition := func(router *gin.Engine, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/"+id+"/nutrition", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	owner := "test-user"
+	newRecipe := func() *models.Recipe {
+		recipe := &models.Recipe{ID: "recipe-1", Title: "Pancakes", Servings: 4, NutritionalInfo: "500 kcal", UserID: &owner}
+		_ = recipe.SetIngredients([]models.Ingredient{{Name: "Flour", Amount: "200", Unit: "g"}})
+		return recipe
+	}
+
+	t.Run("nutrition is recomputed and saved", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipe := newRecipe()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("RecomputeNutrition", mock.Anything, recipe).
+			Return(&services.Nutrition{Calories: 350, Protein: 12, Carbs: 40.25, Fat: 10}, nil)
+		recipes.On("UpdateRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return r.ID == "recipe-1" && r.Title == "Pancakes" && r.NutritionalInfo == "Per serving: 350 kcal, 12 g protein, 40.3 g carbs, 10 g fat"
+		})).Return(nil)
+
+		w := postNutrition(router, "recipe-1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.NutritionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "recipe-1", response.RecipeID)
+		assert.Equal(t, 350.0, response.Calories)
+		assert.Equal(t, "Per serving: 350 kcal, 12 g protein, 40.3 g carbs, 10 g fat", response.NutritionalInfo)
+		recipes.AssertExpectations(t)
+	})
+
+	t.Run("recipe without ingredients", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		empty := &models.Recipe{ID: "recipe-1", UserID: &owner}
+		_ = empty.SetIngredients([]models.Ingredient{})
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(empty, nil)
+
+		w := postNutrition(router, "recipe-1")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		resolution.AssertNotCalled(t, "RecomputeNutrition", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid model output is not saved", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipe := newRecipe()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("RecomputeNutrition", mock.Anything, recipe).
+			Return(nil, &services.ModelSchemaError{Problems: []string{"nutrition.fat must not be negative"}})
+
+		w := postNutrition(router, "recipe-1")
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		recipes.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		router, recipes, _ := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		w := postNutrition(router, "missing")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("recipe owned by another user", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		other := "someone-else"
+		recipe := newRecipe()
+		recipe.UserID = &other
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+
+		w := postNutrition(router, "recipe-1")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "FORBIDDEN", response.Code)
+		resolution.AssertNotCalled(t, "RecomputeNutrition", mock.Anything, mock.Anything)
+		recipes.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+}
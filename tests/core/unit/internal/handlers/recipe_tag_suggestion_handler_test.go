@@ -0,0 +1,122 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/integrations"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSuggestTags(t *testing.T) {
+	postSuggestTags := func(router *gin.Engine, id string, body *dtos.TagSuggestionRequest) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/"+id+"/suggest-tags", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	newRecipe := func() *models.Recipe {
+		owner := "test-user"
+		recipe := &models.Recipe{
+			ID:       "recipe-1",
+			Title:    "Chicken Curry",
+			UserID:   &owner,
+			Cuisines: []models.Cuisine{{Name: "Indian"}},
+			Tags:     []models.Tag{{ID: "tag-1", Name: "Indian"}, {ID: "tag-2", Name: "dinner"}},
+		}
+		_ = recipe.SetIngredients([]models.Ingredient{{Name: "Chicken thighs", Amount: "500", Unit: "g"}})
+		return recipe
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) dtos.TagSuggestionResponse {
+		var response dtos.TagSuggestionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("suggestions are returned without saving", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(newRecipe(), nil)
+
+		w := postSuggestTags(router, "recipe-1", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := decode(t, w)
+		assert.Equal(t, []string{"chicken"}, response.Suggestions)
+		assert.False(t, response.Applied)
+		assert.Equal(t, []string{"Indian", "dinner"}, response.Tags)
+		recipes.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+		resolution.AssertNotCalled(t, "SuggestModelTags", mock.Anything, mock.Anything)
+	})
+
+	t.Run("model suggestions are merged and applied", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipe := newRecipe()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("SuggestModelTags", mock.Anything, recipe).Return([]string{"Comfort Food", "Chicken", "DINNER"}, nil)
+		recipes.On("UpdateRecipe", mock.Anything, mock.MatchedBy(func(r *models.Recipe) bool {
+			return len(r.Tags) == 4 && r.Tags[2].Name == "chicken" && r.Tags[3].Name == "comfort food"
+		})).Return(nil)
+
+		w := postSuggestTags(router, "recipe-1", &dtos.TagSuggestionRequest{UseModel: true, Apply: true})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := decode(t, w)
+		assert.Equal(t, []string{"chicken", "comfort food"}, response.Suggestions)
+		assert.True(t, response.Applied)
+		assert.Equal(t, []string{"Indian", "dinner", "chicken", "comfort food"}, response.Tags)
+		recipes.AssertExpectations(t)
+	})
+
+	t.Run("model failure keeps the rule-based suggestions", func(t *testing.T) {
+		router, recipes, resolution := setupModificationTest()
+		recipe := newRecipe()
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+		resolution.On("SuggestModelTags", mock.Anything, recipe).Return(nil, integrations.ErrDeepSeekUnavailable)
+
+		w := postSuggestTags(router, "recipe-1", &dtos.TagSuggestionRequest{UseModel: true})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := decode(t, w)
+		assert.Equal(t, []string{"chicken"}, response.Suggestions)
+		if assert.NotNil(t, response.ModelError) {
+			assert.Equal(t, "AI_UNAVAILABLE", response.ModelError.Code)
+		}
+	})
+
+	t.Run("only the owner can apply suggestions", func(t *testing.T) {
+		router, recipes, _ := setupModificationTest()
+		recipe := newRecipe()
+		other := "other-user"
+		recipe.UserID = &other
+		recipes.On("GetRecipe", mock.Anything, "recipe-1").Return(recipe, nil)
+
+		w := postSuggestTags(router, "recipe-1", &dtos.TagSuggestionRequest{Apply: true})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		recipes.AssertNotCalled(t, "UpdateRecipe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("recipe not found", func(t *testing.T) {
+		router, recipes, _ := setupModificationTest()
+		recipes.On("GetRecipe", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+		w := postSuggestTags(router, "missing", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
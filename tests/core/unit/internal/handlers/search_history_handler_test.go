@@ -0,0 +1,208 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchHistoryService is a mock implementation of the SearchHistoryService interface
+type MockSearchHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockSearchHistoryService) RecordSearch(ctx context.Context, userID, query string, resultCount int) error {
+	args := m.Called(ctx, userID, query, resultCount)
+	return args.Error(0)
+}
+
+func (m *MockSearchHistoryService) GetSearchHistory(ctx context.Context, userID string, limit int) ([]dtos.SearchHistoryEntry, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.SearchHistoryEntry), args.Error(1)
+}
+
+func (m *MockSearchHistoryService) ClearSearchHistory(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func setupSearchHistoryTest(history services.SearchHistoryService) (*gin.Engine, *MockRecipeService) {
+	gin.SetMode(gin.TestMode)
+	mockRecipes := new(MockRecipeService)
+	recipeHandler := handlers.NewRecipeHandler(mockRecipes)
+	recipeHandler.History = history
+	historyHandler := handlers.NewSearchHistoryHandler(history)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.GET("/recipes/search", recipeHandler.SearchRecipes)
+	router.GET("/users/me/search-history", historyHandler.GetSearchHistory)
+	router.DELETE("/users/me/search-history", historyHandler.ClearSearchHistory)
+	return router, mockRecipes
+}
+
+func TestSearchRecipes_RecordsHistory(t *testing.T) {
+	t.Run("records non-empty query with result count", func(t *testing.T) {
+		history := new(MockSearchHistoryService)
+		router, mockRecipes := setupSearchHistoryTest(history)
+
+		recipes := []models.Recipe{{ID: "1", Title: "Pasta"}, {ID: "2", Title: "Pesto Pasta"}}
+		mockRecipes.On("SearchRecipes", mock.Anything, "pasta", mock.Anything, "").Return(recipes, nil)
+		history.On("RecordSearch", mock.Anything, "test-user", "pasta", 2).Return(nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/search?q=pasta", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		history.AssertExpectations(t)
+	})
+
+	t.Run("history failure does not fail the search", func(t *testing.T) {
+		history := new(MockSearchHistoryService)
+		router, mockRecipes := setupSearchHistoryTest(history)
+
+		mockRecipes.On("SearchRecipes", mock.Anything, "soup", mock.Anything, "").Return([]models.Recipe{}, nil)
+		history.On("RecordSearch", mock.Anything, "test-user", "soup", 0).Return(errors.New("redis down"))
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/search?q=soup", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestGetSearchHistory(t *testing.T) {
+	t.Run("returns entries from the service", func(t *testing.T) {
+		history := new(MockSearchHistoryService)
+		router, _ := setupSearchHistoryTest(history)
+
+		entries := []dtos.SearchHistoryEntry{
+			{Query: "tacos", ResultCount: 3, SearchedAt: dtos.NewTimestamp(time.Now())},
+			{Query: "pasta", ResultCount: 2, SearchedAt: dtos.NewTimestamp(time.Now().Add(-time.Minute))},
+		}
+		history.On("GetSearchHistory", mock.Anything, "test-user", 5).Return(entries, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me/search-history?limit=5", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.SearchHistoryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Searches, 2)
+		assert.Equal(t, "tacos", response.Searches[0].Query)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		history := new(MockSearchHistoryService)
+		router, _ := setupSearchHistoryTest(history)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me/search-history?limit=abc", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		history.AssertNotCalled(t, "GetSearchHistory", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestClearSearchHistory(t *testing.T) {
+	history := new(MockSearchHistoryService)
+	router, _ := setupSearchHistoryTest(history)
+	history.On("ClearSearchHistory", mock.Anything, "test-user").Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/users/me/search-history", nil)
+	req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	history.AssertExpectations(t)
+}
+
+func TestSearchHistory_Redis(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+		DB:   1, // Use a different DB for testing
+	})
+	defer redisClient.Close()
+
+	ctx := context.Background()
+	if _, err := redisClient.Ping(ctx).Result(); err != nil {
+		t.Skip("Redis not available, skipping search history test")
+	}
+	redisClient.FlushDB(ctx)
+
+	history := services.NewSearchHistoryService(redisClient, 3)
+	router, mockRecipes := setupSearchHistoryTest(history)
+	mockRecipes.On("SearchRecipes", mock.Anything, mock.Anything, mock.Anything, "").Return([]models.Recipe{}, nil)
+	token := testhelpers.GenerateTestToken(nil)
+
+	search := func(q string) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes/search?q="+q, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	getHistory := func() dtos.SearchHistoryResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me/search-history", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.SearchHistoryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("empty queries are not recorded", func(t *testing.T) {
+		search("")
+		assert.Empty(t, getHistory().Searches)
+	})
+
+	t.Run("newest first and capped", func(t *testing.T) {
+		for _, q := range []string{"soup", "salad", "pasta", "tacos"} {
+			search(q)
+		}
+		searches := getHistory().Searches
+		if assert.Len(t, searches, 3) {
+			assert.Equal(t, "tacos", searches[0].Query)
+			assert.Equal(t, "pasta", searches[1].Query)
+			assert.Equal(t, "salad", searches[2].Query)
+		}
+	})
+
+	t.Run("clear removes history", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/users/me/search-history", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, getHistory().Searches)
+	})
+}
@@ -0,0 +1,160 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	apperrors "github.com/pageza/alchemorsel-v1/internal/errors"
+	"github.com/pageza/alchemorsel-v1/internal/handlers"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockTagService is a mock implementation of services.TagService.
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) GetByID(ctx context.Context, id string) (*models.Tag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Create(ctx context.Context, tag *models.Tag) error {
+	return m.Called(ctx, tag).Error(0)
+}
+
+func (m *MockTagService) List(ctx context.Context) ([]*models.Tag, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockTagService) GetOrCreate(ctx context.Context, name string) (*models.Tag, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Tag, error) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Tag), args.Error(1)
+}
+
+func (m *MockTagService) Merge(ctx context.Context, targetID string, sourceIDs []string) (*repositories.TagMergeResult, error) {
+	args := m.Called(ctx, targetID, sourceIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.TagMergeResult), args.Error(1)
+}
+
+func (m *MockTagService) DeleteUnused(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupTagAdminTest() (*gin.Engine, *MockTagService) {
+	gin.SetMode(gin.TestMode)
+	service := new(MockTagService)
+	handler := handlers.NewTagHandler(service)
+	router := gin.New()
+	router.POST("/admin/tags/merge", handler.MergeTags)
+	router.DELETE("/admin/tags/unused", handler.DeleteUnusedTags)
+	return router, service
+}
+
+func postTagMerge(router *gin.Engine, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/tags/merge", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMergeTags(t *testing.T) {
+	t.Run("returns the counts", func(t *testing.T) {
+		router, service := setupTagAdminTest()
+		service.On("Merge", mock.Anything, "tag-quick", []string{"tag-fast", "tag-speedy"}).
+			Return(&repositories.TagMergeResult{RecipesAffected: 3, TagsDeleted: 2}, nil)
+
+		w := postTagMerge(router, dtos.TagMergeRequest{TargetID: "tag-quick", SourceIDs: []string{"tag-fast", "tag-speedy"}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.TagMergeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, dtos.TagMergeResponse{TargetID: "tag-quick", RecipesAffected: 3, TagsDeleted: 2}, response)
+	})
+
+	t.Run("sources are required", func(t *testing.T) {
+		router, service := setupTagAdminTest()
+
+		w := postTagMerge(router, map[string]interface{}{"target_id": "tag-quick", "source_ids": []string{}})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		service.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("target among the sources", func(t *testing.T) {
+		router, service := setupTagAdminTest()
+		service.On("Merge", mock.Anything, "tag-quick", []string{"tag-quick"}).
+			Return(nil, apperrors.NewValidationError("the target tag cannot also be a source"))
+
+		w := postTagMerge(router, dtos.TagMergeRequest{TargetID: "tag-quick", SourceIDs: []string{"tag-quick"}})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		router, service := setupTagAdminTest()
+		service.On("Merge", mock.Anything, "missing", []string{"tag-fast"}).Return(nil, gorm.ErrRecordNotFound)
+
+		w := postTagMerge(router, dtos.TagMergeRequest{TargetID: "missing", SourceIDs: []string{"tag-fast"}})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestDeleteUnusedTags(t *testing.T) {
+	router, service := setupTagAdminTest()
+	service.On("DeleteUnused", mock.Anything).Return(int64(4), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/admin/tags/unused", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"tags_deleted": 4}`, w.Body.String())
+}
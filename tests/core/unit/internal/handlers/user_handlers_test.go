@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"github.com/pageza/alchemorsel-v1/internal/dtos"
 	"github.com/pageza/alchemorsel-v1/internal/handlers"
 	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -51,12 +53,12 @@ func (m *MockUserService) DeleteUser(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
-func (m *MockUserService) ListUsers(ctx context.Context, page, limit int) ([]models.User, error) {
-	args := m.Called(ctx, page, limit)
+func (m *MockUserService) ListUsers(ctx context.Context, page, limit int, query string) (*services.UserList, error) {
+	args := m.Called(ctx, page, limit, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]models.User), args.Error(1)
+	return args.Get(0).(*services.UserList), args.Error(1)
 }
 
 func (m *MockUserService) VerifyPassword(ctx context.Context, email, password string) error {
@@ -87,6 +89,11 @@ func (m *MockUserService) ResetPassword(ctx context.Context, token string, newPa
 	return args.Error(0)
 }
 
+func (m *MockUserService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
 func (m *MockUserService) PatchUser(ctx context.Context, id string, updates map[string]interface{}) error {
 	args := m.Called(ctx, id, updates)
 	return args.Error(0)
@@ -406,6 +413,7 @@ func TestGetCurrentUser(t *testing.T) {
 			ID:            "1",
 			Name:          "Test User",
 			Email:         "test@example.com",
+			Password:      "$2a$10$hashedpasswordvalue",
 			EmailVerified: true,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
@@ -428,6 +436,11 @@ func TestGetCurrentUser(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "Test User", response.Name)
 		assert.Equal(t, "test@example.com", response.Email)
+
+		var fields map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &fields))
+		assert.NotContains(t, fields, "password", "the password hash must not be returned")
+		assert.NotContains(t, w.Body.String(), mockUser.Password)
 	})
 
 	t.Run("unauthorized", func(t *testing.T) {
@@ -545,6 +558,72 @@ func TestUpdateCurrentUser(t *testing.T) {
 	})
 }
 
+func TestUpdateCurrentUserValidation(t *testing.T) {
+	put := func(handler *handlers.UserHandler, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/users/me", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("currentUser", "1")
+		handler.UpdateCurrentUser(c)
+		return w
+	}
+
+	t.Run("email already in use", func(t *testing.T) {
+		handler, _, mockService := setupUserTest()
+		mockService.On("UpdateUser", mock.Anything, "1", mock.AnythingOfType("*models.User")).
+			Return(fmt.Errorf("%w: taken@example.com", services.ErrEmailTaken))
+
+		w := put(handler, `{"name": "User", "email": "taken@example.com"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "CONFLICT", response.Code)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		handler, _, mockService := setupUserTest()
+		mockService.On("UpdateUser", mock.Anything, "1", mock.AnythingOfType("*models.User")).
+			Return(services.ErrUserNotFound)
+
+		w := put(handler, `{"name": "User", "email": "user@example.com"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "NOT_FOUND", response.Code)
+	})
+
+	t.Run("password is passed only when provided", func(t *testing.T) {
+		handler, _, mockService := setupUserTest()
+		mockService.On("UpdateUser", mock.Anything, "1", mock.MatchedBy(func(u *models.User) bool {
+			return u.Name == "User" && u.Email == "user@example.com" && u.Password == "NewPassword1"
+		})).Return(nil)
+		mockService.On("GetUser", mock.Anything, "1").Return(&models.User{ID: "1", Name: "User", Email: "user@example.com"}, nil)
+
+		w := put(handler, `{"name": " User ", "email": "user@example.com", "password": "NewPassword1"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	invalid := map[string]string{
+		"missing name":       `{"email": "user@example.com"}`,
+		"invalid email":      `{"name": "User", "email": "not-an-email"}`,
+		"short new password": `{"name": "User", "email": "user@example.com", "password": "short"}`,
+	}
+	for name, body := range invalid {
+		t.Run(name, func(t *testing.T) {
+			handler, _, mockService := setupUserTest()
+			w := put(handler, body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestPatchCurrentUser(t *testing.T) {
 	handler, router, mockService := setupUserTest()
 	router.PATCH("/users/me", handler.PatchCurrentUser)
@@ -577,11 +656,14 @@ func TestPatchCurrentUser(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response map[string]interface{}
+		var response dtos.UserResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		user := response["user"].(map[string]interface{})
-		assert.Equal(t, "Patched User", user["name"])
+		assert.Equal(t, "1", response.ID)
+		assert.Equal(t, "Patched User", response.Name)
+		assert.Equal(t, "test@example.com", response.Email)
+		assert.Contains(t, w.Body.String(), `"created_at"`)
+		assert.Contains(t, w.Body.String(), `"updated_at"`)
 	})
 
 	t.Run("unauthorized", func(t *testing.T) {
@@ -606,6 +688,27 @@ func TestPatchCurrentUser(t *testing.T) {
 		assert.Equal(t, "Unauthorized", response.Message)
 	})
 
+	t.Run("invalid preferred units", func(t *testing.T) {
+		handler, _, mockService := setupUserTest()
+		body, _ := json.Marshal(map[string]interface{}{"preferred_units": "cubits"})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/users/me", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("currentUser", "1")
+		handler.PatchCurrentUser(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dtos.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "BAD_REQUEST", response.Code)
+		assert.Contains(t, response.Message, "cubits")
+		mockService.AssertNotCalled(t, "PatchUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("invalid request body", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("PATCH", "/users/me", bytes.NewBufferString("invalid json"))
@@ -826,7 +929,7 @@ func TestResetPassword(t *testing.T) {
 }
 
 func TestVerifyEmail(t *testing.T) {
-	handler, router, _ := setupUserTest()
+	handler, router, mockService := setupUserTest()
 	router.GET("/verify-email/:token", handler.VerifyEmail)
 
 	t.Run("successful email verification", func(t *testing.T) {
@@ -835,6 +938,7 @@ func TestVerifyEmail(t *testing.T) {
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
 		c.Params = []gin.Param{{Key: "token", Value: "valid-token"}}
+		mockService.On("VerifyEmail", mock.Anything, "valid-token").Return(nil).Once()
 		handler.VerifyEmail(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -845,6 +949,36 @@ func TestVerifyEmail(t *testing.T) {
 		assert.Equal(t, "email verified successfully", response["message"])
 	})
 
+	t.Run("expired token", func(t *testing.T) {
+		mockService.On("VerifyEmail", mock.Anything, "stale-token").Return(services.ErrVerificationTokenExpired).Once()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/verify-email/stale-token", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dtos.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "BAD_REQUEST", response.Code)
+		assert.Equal(t, "Verification token has expired", response.Message)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockService.On("VerifyEmail", mock.Anything, "unknown-token").Return(services.ErrInvalidVerificationToken).Once()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/verify-email/unknown-token", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response dtos.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "BAD_REQUEST", response.Code)
+		assert.Equal(t, "Invalid verification token", response.Message)
+	})
+
 	t.Run("missing token", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/verify-email/", nil)
@@ -886,8 +1020,8 @@ func TestGetAllUsers(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetAllUsers", mock.Anything).
-			Return(mockUsers, nil)
+		mockService.On("ListUsers", mock.Anything, 1, services.DefaultUserListLimit, "").
+			Return(&services.UserList{Users: mockUsers, Total: 2, Page: 1, Limit: services.DefaultUserListLimit}, nil)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/users", nil)
@@ -910,7 +1044,7 @@ func TestGetAllUsers(t *testing.T) {
 	t.Run("service error", func(t *testing.T) {
 		// Reset the mock expectations to avoid interference from previous subtests
 		mockService.ExpectedCalls = nil
-		mockService.On("GetAllUsers", mock.Anything).
+		mockService.On("ListUsers", mock.Anything, 1, services.DefaultUserListLimit, "").
 			Return(nil, assert.AnError)
 
 		w := httptest.NewRecorder()
@@ -927,4 +1061,25 @@ func TestGetAllUsers(t *testing.T) {
 		assert.Equal(t, "INTERNAL_ERROR", response.Code)
 		assert.Contains(t, response.Message, "Failed to get users")
 	})
+
+	t.Run("page and search", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.On("ListUsers", mock.Anything, 3, 500, "smith").
+			Return(&services.UserList{
+				Users: []*models.User{{ID: "7", Name: "Ann Smith", Email: "ann@example.com"}},
+				Total: 41,
+				Page:  3,
+				Limit: services.MaxUserListLimit,
+			}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users?page=3&limit=500&q=smith", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dtos.UserListResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Users, 1)
+		assert.Equal(t, dtos.UserListMeta{Page: 3, Limit: services.MaxUserListLimit, Total: 41, Query: "smith"}, response.Meta)
+	})
 }
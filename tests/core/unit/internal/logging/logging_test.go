@@ -0,0 +1,55 @@
+package logging_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLoggingRotates(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := logging.NewLogger(logging.LogConfig{LogFormat: "json", LogDir: dir, EnableFile: true, MaxBackups: 2})
+	require.NoError(t, err)
+
+	logger.Info("written to the log file")
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "written to the log file")
+
+	require.NoError(t, logger.RotateLogs())
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestFileLoggingDisabled(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := logging.NewLogger(logging.LogConfig{LogFormat: "json", LogDir: dir})
+	require.NoError(t, err)
+
+	logger.Info("console only")
+	require.NoError(t, logger.RotateLogs())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLoadLogConfig(t *testing.T) {
+	t.Setenv("LOG_FILE_ENABLED", "true")
+	t.Setenv("LOG_DIR", "/var/log/alchemorsel")
+	t.Setenv("LOG_MAX_SIZE_MB", "50")
+	t.Setenv("LOG_COMPRESS", "true")
+
+	cfg := logging.LoadLogConfig()
+	assert.True(t, cfg.EnableFile)
+	assert.True(t, cfg.EnableConsole)
+	assert.Equal(t, "/var/log/alchemorsel", cfg.LogDir)
+	assert.Equal(t, 50, cfg.MaxSizeMB)
+	assert.Equal(t, logging.DefaultLogMaxBackups, cfg.MaxBackups)
+	assert.Equal(t, logging.DefaultLogMaxAgeDays, cfg.MaxAgeDays)
+	assert.True(t, cfg.Compress)
+}
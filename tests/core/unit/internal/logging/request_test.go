@@ -0,0 +1,65 @@
+package logging_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger, err := logging.NewLogger(logging.LogConfig{LogFormat: "json"})
+	require.NoError(t, err)
+
+	var seen string
+	var scoped *zap.Logger
+	router := gin.New()
+	router.Use(logger.RequestIDMiddleware())
+	router.GET("/ping", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+		scoped = logging.FromGin(c)
+		c.Status(http.StatusNoContent)
+	})
+	get := func(requestID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ping", nil)
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("generates an ID", func(t *testing.T) {
+		w := get("")
+		id := w.Header().Get("X-Request-ID")
+		assert.NotEmpty(t, id)
+		assert.Equal(t, id, seen)
+		assert.NotSame(t, zap.L(), scoped)
+	})
+
+	t.Run("keeps a client ID", func(t *testing.T) {
+		w := get("client-42.a")
+		assert.Equal(t, "client-42.a", w.Header().Get("X-Request-ID"))
+		assert.Equal(t, "client-42.a", seen)
+	})
+
+	t.Run("replaces an unsafe client ID", func(t *testing.T) {
+		w := get("bad id\nforged")
+		id := w.Header().Get("X-Request-ID")
+		assert.NotEqual(t, "bad id\nforged", id)
+		assert.Equal(t, id, seen)
+	})
+}
+
+func TestFromContextOutsideRequest(t *testing.T) {
+	assert.Same(t, zap.L(), logging.FromContext(context.Background()))
+	assert.Empty(t, logging.RequestID(context.Background()))
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// userLookupFunc adapts a function to middleware.UserLookup.
+type userLookupFunc func(id string) (*models.User, error)
+
+func (f userLookupFunc) GetUser(ctx context.Context, id string) (*models.User, error) {
+	return f(id)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := userLookupFunc(func(id string) (*models.User, error) {
+		switch id {
+		case "admin":
+			return &models.User{ID: id, IsAdmin: true}, nil
+		case "member":
+			return &models.User{ID: id}, nil
+		}
+		return nil, errors.New("user not found")
+	})
+
+	serve := func(currentUser interface{}) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			if currentUser != nil {
+				c.Set("currentUser", currentUser)
+			}
+		})
+		router.GET("/admin/users", middleware.RequireAdmin(users), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/users", nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name        string
+		currentUser interface{}
+		status      int
+	}{
+		{"admin", "admin", http.StatusOK},
+		{"admin with auth bypass", map[string]interface{}{"id": "admin"}, http.StatusOK},
+		{"non-admin", "member", http.StatusForbidden},
+		{"unknown user", "ghost", http.StatusForbidden},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, serve(tt.currentUser).Code)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+// unreachableDenylist fails every lookup, like a denylist whose Redis is down.
+type unreachableDenylist struct{}
+
+func (unreachableDenylist) IsDenied(context.Context, string) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestAuthMiddlewareDenylistUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("DISABLE_AUTH", "")
+	t.Setenv("INTEGRATION_TEST", "")
+	middleware.UseTokenDenylist(unreachableDenylist{})
+	t.Cleanup(func() { middleware.UseTokenDenylist(nil) })
+
+	serve := func() int {
+		router := gin.New()
+		router.GET("/users/me", middleware.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/users/me", nil)
+		req.Header.Set("Authorization", "Bearer "+testhelpers.GenerateTestToken(nil))
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("fails closed by default", func(t *testing.T) {
+		assert.Equal(t, http.StatusServiceUnavailable, serve())
+	})
+
+	t.Run("fails open when configured", func(t *testing.T) {
+		t.Setenv("TOKEN_DENYLIST_FAIL_OPEN", "true")
+		assert.Equal(t, http.StatusOK, serve())
+	})
+}
@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var received string
+	router := gin.New()
+	router.Use(middleware.BodyLimit(16))
+	router.POST("/generate", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = string(body)
+		c.Status(http.StatusNoContent)
+	})
+
+	send := func(body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/generate", body)
+		req.ContentLength = contentLength
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("body within the limit reaches the handler", func(t *testing.T) {
+		w := send(strings.NewReader(`{"query":"soup"}`), 16)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, `{"query":"soup"}`, received)
+	})
+
+	t.Run("oversized content length", func(t *testing.T) {
+		received = ""
+		w := send(strings.NewReader(`{"query":"lentil soup"}`), 23)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "PAYLOAD_TOO_LARGE", response.Code)
+		assert.Empty(t, received, "the handler does not run")
+	})
+
+	t.Run("oversized body of unknown length", func(t *testing.T) {
+		w := send(io.MultiReader(strings.NewReader(`{"query":"`), strings.NewReader(strings.Repeat("a", 1<<20))), -1)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("requests without a body pass", func(t *testing.T) {
+		w := send(http.NoBody, 0)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
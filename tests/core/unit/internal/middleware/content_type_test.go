@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+	router := gin.New()
+	router.Use(middleware.RequireJSON("/uploads"))
+	router.POST("/recipes", ok)
+	router.PATCH("/recipes/:id", ok)
+	router.POST("/uploads", ok)
+	router.POST("/reindex", ok)
+
+	send := func(method, path, contentType, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("rejects a form-encoded body", func(t *testing.T) {
+		w := send("POST", "/recipes", "application/x-www-form-urlencoded", "title=Soup")
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "UNSUPPORTED_MEDIA_TYPE", response.Code)
+	})
+
+	t.Run("rejects a body without a content type", func(t *testing.T) {
+		w := send("PATCH", "/recipes/1", "", `{"title":"Soup"}`)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("accepts JSON with parameters", func(t *testing.T) {
+		w := send("POST", "/recipes", "application/json; charset=utf-8", `{"title":"Soup"}`)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("accepts a request without a body", func(t *testing.T) {
+		w := send("POST", "/reindex", "", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("skips exempt routes", func(t *testing.T) {
+		w := send("POST", "/uploads", "multipart/form-data; boundary=x", "--x--")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireScrapeToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(token, authorization string) int {
+		router := gin.New()
+		router.GET("/metrics", middleware.RequireScrapeToken(token), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/metrics", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		status        int
+	}{
+		{"matching token", "secret", "Bearer secret", http.StatusOK},
+		{"wrong token", "secret", "Bearer guess", http.StatusUnauthorized},
+		{"token without bearer prefix", "secret", "secret", http.StatusUnauthorized},
+		{"missing header", "secret", "", http.StatusUnauthorized},
+		{"no token configured", "", "Bearer ", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, serve(tt.token, tt.authorization))
+		})
+	}
+}
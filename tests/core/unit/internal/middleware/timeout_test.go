@@ -0,0 +1,160 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/dtos"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowHandler waits for the given delay unless the request context is cancelled first.
+func slowHandler(delay time.Duration, cancelled *bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		select {
+		case <-time.After(delay):
+			c.JSON(http.StatusOK, gin.H{"message": "done"})
+		case <-c.Request.Context().Done():
+			*cancelled = true
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var fastCancelled, aiCancelled bool
+
+	router := gin.New()
+	crud := router.Group("", middleware.Timeout(20*time.Millisecond))
+	crud.GET("/recipes", slowHandler(200*time.Millisecond, &fastCancelled))
+	ai := router.Group("", middleware.Timeout(time.Second))
+	ai.POST("/recipes/resolve", slowHandler(50*time.Millisecond, &aiCancelled))
+
+	t.Run("fast route times out on slow handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/recipes", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		var response dtos.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "TIMEOUT", response.Code)
+		assert.True(t, fastCancelled, "expected downstream context to be cancelled")
+	})
+
+	t.Run("AI route does not time out", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/resolve", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message":"done"}`, w.Body.String())
+		assert.False(t, aiCancelled)
+	})
+
+	t.Run("no content responses pass through", func(t *testing.T) {
+		r := gin.New()
+		r.DELETE("/recipes/1", middleware.Timeout(time.Second), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/recipes/1", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
+
+func TestTimeoutDoesNotWaitForHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Header("X-Request-ID", "req-1")
+		c.Next()
+	})
+	router.GET("/slow", middleware.Timeout(20*time.Millisecond), func(c *gin.Context) {
+		defer close(finished)
+		// Ignores the request context entirely.
+		c.Header("Cache-Control", "max-age=60")
+		c.Header("Content-Type", "text/csv")
+		<-release
+		c.String(http.StatusOK, "late")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/slow")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	elapsed := time.Since(start)
+	close(release)
+	<-finished
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.Less(t, elapsed, time.Second, "504 should not wait for the handler")
+	var response dtos.ErrorResponse
+	assert.NoError(t, json.Unmarshal(body, &response))
+	assert.Equal(t, "TIMEOUT", response.Code)
+	assert.Equal(t, "req-1", resp.Header.Get("X-Request-ID"))
+	assert.Empty(t, resp.Header.Get("Cache-Control"))
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+}
+
+func TestTimeoutPassesHandlerHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/export", middleware.Timeout(time.Second), func(c *gin.Context) {
+		c.Header("Cache-Control", "max-age=60")
+		c.Data(http.StatusOK, "text/csv", []byte("a,b"))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/export", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "max-age=60", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "a,b", w.Body.String())
+}
+
+func TestTimeoutPropagatesPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/boom", middleware.Timeout(time.Second), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/boom", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestLoadTimeoutConfig(t *testing.T) {
+	t.Setenv("REQUEST_TIMEOUT", "3s")
+	t.Setenv("AI_REQUEST_TIMEOUT", "")
+	t.Setenv("UPLOAD_REQUEST_TIMEOUT", "")
+
+	cfg := middleware.LoadTimeoutConfig()
+	assert.Equal(t, 3*time.Second, cfg.Default)
+	assert.Equal(t, 90*time.Second, cfg.AI)
+	assert.Equal(t, 60*time.Second, cfg.Upload)
+
+	t.Setenv("UPLOAD_REQUEST_TIMEOUT", "2m")
+	assert.Equal(t, 2*time.Minute, middleware.LoadTimeoutConfig().Upload)
+}
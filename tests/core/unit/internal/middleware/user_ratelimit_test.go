@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitPerUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	middleware.ResetLimiters()
+	t.Cleanup(middleware.ResetLimiters)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("currentUser", user)
+		}
+		c.Next()
+	})
+	router.Use(middleware.RateLimit(nil, config.RateLimitConfig{RequestsPerSecond: 0.5, Burst: 2, ExpirationTTL: time.Minute}))
+	router.POST("/recipes/resolve", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(user, ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/recipes/resolve", nil)
+		req.RemoteAddr = ip + ":1234"
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("burst is allowed then limited with Retry-After", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("alice", "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, send("alice", "10.0.0.2").Code)
+
+		w := send("alice", "10.0.0.3")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("users have separate buckets", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("bob", "10.0.0.1").Code)
+	})
+
+	t.Run("anonymous requests are limited by IP", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send("", "10.0.0.9").Code)
+		assert.Equal(t, http.StatusOK, send("", "10.0.0.9").Code)
+		assert.Equal(t, http.StatusTooManyRequests, send("", "10.0.0.9").Code)
+		assert.Equal(t, http.StatusOK, send("", "10.0.0.10").Code)
+	})
+}
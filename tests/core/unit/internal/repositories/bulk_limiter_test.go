@@ -0,0 +1,77 @@
+package repositories_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBulkLimiterKeepsReserve(t *testing.T) {
+	assert.Equal(t, 90, repositories.NewBulkLimiter(100, 10).Limit())
+	assert.Equal(t, 1, repositories.NewBulkLimiter(5, 10).Limit(), "at least one bulk operation may run")
+	assert.Equal(t, 0, repositories.NewBulkLimiter(0, 10).Limit(), "an unbounded pool is not capped")
+}
+
+func TestNewBulkLimiterForDBReadsPoolSize(t *testing.T) {
+	db := setupSearchDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(8)
+	t.Setenv("BULK_DB_CONN_RESERVE", "3")
+
+	assert.Equal(t, 5, repositories.NewBulkLimiterForDB(db).Limit())
+}
+
+func TestWithBulkTransactionCapsConcurrency(t *testing.T) {
+	repo := repositories.NewRecipeRepositoryWithLimiter(setupSearchDB(t), repositories.NewBulkLimiter(4, 2))
+
+	var running, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.WithBulkTransaction(context.Background(), func(tx repositories.RecipeRepository) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak, int32(2))
+	assert.Greater(t, peak, int32(0))
+}
+
+func TestBulkLimiterStopsWaitingWhenContextIsDone(t *testing.T) {
+	limiter := repositories.NewBulkLimiter(1, 0)
+	release := make(chan struct{})
+	go func() {
+		_ = limiter.Do(context.Background(), func() error {
+			<-release
+			return nil
+		})
+	}()
+	defer close(release)
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := limiter.Do(ctx, func() error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
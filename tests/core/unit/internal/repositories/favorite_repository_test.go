@@ -0,0 +1,73 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupFavoriteDB(t *testing.T) (*gorm.DB, repositories.FavoriteRepository) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}, &models.RecipeFavorite{}))
+	return db, repositories.NewFavoriteRepository(db)
+}
+
+func TestAddFavorite(t *testing.T) {
+	db, repo := setupFavoriteDB(t)
+	ctx := context.Background()
+	recipe := &models.Recipe{Title: "Pancakes"}
+	require.NoError(t, db.Create(recipe).Error)
+
+	require.NoError(t, repo.AddFavorite(ctx, "user-1", recipe.ID))
+	assert.ErrorIs(t, repo.AddFavorite(ctx, "user-1", recipe.ID), repositories.ErrAlreadyFavorited)
+	assert.ErrorIs(t, repo.AddFavorite(ctx, "user-1", "missing"), gorm.ErrRecordNotFound)
+
+	ids, err := repo.GetFavoritedRecipeIDs(ctx, "user-1", []string{recipe.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{recipe.ID}, ids)
+}
+
+func TestRemoveFavorite(t *testing.T) {
+	db, repo := setupFavoriteDB(t)
+	ctx := context.Background()
+	recipe := &models.Recipe{Title: "Pancakes"}
+	require.NoError(t, db.Create(recipe).Error)
+	require.NoError(t, repo.AddFavorite(ctx, "user-1", recipe.ID))
+
+	require.NoError(t, repo.RemoveFavorite(ctx, "user-1", recipe.ID))
+	assert.ErrorIs(t, repo.RemoveFavorite(ctx, "user-1", recipe.ID), gorm.ErrRecordNotFound)
+}
+
+func TestListFavorites(t *testing.T) {
+	db, repo := setupFavoriteDB(t)
+	ctx := context.Background()
+
+	titles := []string{"Soup", "Salad", "Stew"}
+	start := time.Now()
+	for i, title := range titles {
+		recipe := &models.Recipe{Title: title, Embedding: models.Float64Slice{1, 2, 3}}
+		require.NoError(t, db.Create(recipe).Error)
+		require.NoError(t, db.Create(&models.RecipeFavorite{UserID: "user-1", RecipeID: recipe.ID, CreatedAt: start.Add(time.Duration(i) * time.Minute)}).Error)
+	}
+	other := &models.Recipe{Title: "Someone else's"}
+	require.NoError(t, db.Create(other).Error)
+	require.NoError(t, repo.AddFavorite(ctx, "user-2", other.ID))
+
+	recipes, err := repo.ListFavorites(ctx, "user-1", 1, 2)
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+	assert.Equal(t, "Stew", recipes[0].Title)
+	assert.Equal(t, "Salad", recipes[1].Title)
+	assert.Empty(t, recipes[0].Embedding)
+
+	recipes, err = repo.ListFavorites(ctx, "user-1", 2, 2)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "Soup", recipes[0].Title)
+}
@@ -0,0 +1,39 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeAuditEntries(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.RecipeAuditEntry{}))
+	repo := repositories.NewRecipeAuditRepository(db)
+	ctx := context.Background()
+
+	actor := "11111111-1111-1111-1111-111111111111"
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, action := range []string{models.RecipeAuditCreate, models.RecipeAuditUpdate, models.RecipeAuditDelete} {
+		entry := &models.RecipeAuditEntry{RecipeID: "recipe-1", ActorID: &actor, Action: action, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, repo.CreateEntry(ctx, entry))
+		assert.NotEmpty(t, entry.ID)
+	}
+	require.NoError(t, repo.CreateEntry(ctx, &models.RecipeAuditEntry{RecipeID: "recipe-2", Action: models.RecipeAuditCreate}))
+
+	entries, err := repo.ListEntries(ctx, "recipe-1", 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.RecipeAuditDelete, entries[0].Action)
+	assert.Equal(t, models.RecipeAuditUpdate, entries[1].Action)
+	assert.Equal(t, actor, *entries[0].ActorID)
+
+	entries, err = repo.ListEntries(ctx, "missing", 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,55 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClonedRecipeIsEditedIndependently(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	author := "author"
+	original := &models.Recipe{
+		UserID: &author,
+		Title:  "Dal",
+		Tags:   []models.Tag{{ID: "tag-lentils", Name: "lentils"}, {ID: "tag-vegan", Name: "vegan"}},
+	}
+	require.NoError(t, original.SetIngredients([]models.Ingredient{{Name: "red lentils", Amount: "200", Unit: "g"}}))
+	require.NoError(t, repo.SaveRecipe(ctx, original))
+
+	loaded, err := repo.GetRecipe(ctx, original.ID)
+	require.NoError(t, err)
+	clone := loaded.Clone("cook")
+	require.NoError(t, repo.SaveRecipe(ctx, clone))
+	require.NotEqual(t, original.ID, clone.ID)
+
+	edited, err := repo.GetRecipe(ctx, clone.ID)
+	require.NoError(t, err)
+	require.NotNil(t, edited.ClonedFrom)
+	assert.Equal(t, original.ID, *edited.ClonedFrom)
+	assert.Equal(t, "cook", *edited.UserID)
+	assert.ElementsMatch(t, []string{"lentils", "vegan"}, tagNames(edited))
+
+	edited.Title = "Spicy Dal"
+	edited.Tags = []models.Tag{{ID: "tag-lentils", Name: "lentils"}}
+	require.NoError(t, edited.SetIngredients([]models.Ingredient{{Name: "yellow lentils", Amount: "250", Unit: "g"}}))
+	require.NoError(t, repo.UpdateRecipe(ctx, edited))
+
+	unchanged, err := repo.GetRecipe(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Dal", unchanged.Title)
+	assert.Equal(t, "author", *unchanged.UserID)
+	assert.Nil(t, unchanged.ClonedFrom)
+	assert.ElementsMatch(t, []string{"lentils", "vegan"}, tagNames(unchanged))
+	ingredients, err := unchanged.GetIngredients()
+	require.NoError(t, err)
+	assert.Equal(t, []models.Ingredient{{Name: "red lentils", Amount: "200", Unit: "g"}}, ingredients)
+}
@@ -0,0 +1,63 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestReindexRepositoryMethods(t *testing.T) {
+	db := setupSearchDB(t)
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	first, err := repo.ListRecipesBatch(ctx, 0, 3)
+	require.NoError(t, err)
+	rest, err := repo.ListRecipesBatch(ctx, 3, 3)
+	require.NoError(t, err)
+	assert.Len(t, first, 3)
+	assert.Len(t, rest, 2)
+	assert.NotEqual(t, first[0].ID, rest[0].ID)
+
+	require.NoError(t, repo.UpdateRecipeEmbedding(ctx, first[0].ID, models.Float64Slice{0.5, 0.25}))
+	var stored models.Recipe
+	require.NoError(t, db.First(&stored, "id = ?", first[0].ID).Error)
+	assert.Equal(t, models.Float64Slice{0.5, 0.25}, stored.Embedding)
+	assert.Equal(t, first[0].Title, stored.Title)
+
+	assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateRecipeEmbedding(ctx, "missing", models.Float64Slice{1}))
+}
+
+func TestWithTransactionRollsBackOnError(t *testing.T) {
+	db := setupSearchDB(t)
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+	recipes, err := repo.ListRecipesBatch(ctx, 0, 2)
+	require.NoError(t, err)
+
+	failure := errors.New("second step failed")
+	err = repo.WithTransaction(ctx, func(tx repositories.RecipeRepository) error {
+		if err := tx.UpdateRecipeEmbedding(ctx, recipes[0].ID, models.Float64Slice{1}); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.Equal(t, failure, err)
+
+	var stored models.Recipe
+	require.NoError(t, db.First(&stored, "id = ?", recipes[0].ID).Error)
+	assert.Nil(t, stored.Embedding)
+
+	require.NoError(t, repo.WithTransaction(ctx, func(tx repositories.RecipeRepository) error {
+		return tx.UpdateRecipeEmbedding(ctx, recipes[1].ID, models.Float64Slice{2})
+	}))
+	var committed models.Recipe
+	require.NoError(t, db.First(&committed, "id = ?", recipes[1].ID).Error)
+	assert.Equal(t, models.Float64Slice{2}, committed.Embedding)
+}
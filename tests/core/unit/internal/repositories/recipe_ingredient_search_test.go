@@ -0,0 +1,66 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRecipesByIngredients(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	for title, names := range map[string][]string{
+		"Omelette":   {"Eggs", "Butter"},
+		"Fried Rice": {"rice", "egg", "soy_sauce"},
+		"Salad":      {"lettuce", "tomato"},
+	} {
+		recipe := &models.Recipe{Title: title, Embedding: models.Float64Slice{1, 2}}
+		ingredients := make([]models.Ingredient, len(names))
+		for i, name := range names {
+			ingredients[i] = models.Ingredient{Name: name}
+		}
+		require.NoError(t, recipe.SetIngredients(ingredients))
+		require.NoError(t, db.Create(recipe).Error)
+	}
+
+	recipes, err := repo.FindRecipesByIngredients(ctx, []string{"egg", "tomato"}, 0)
+	require.NoError(t, err)
+	titles := make([]string, 0, len(recipes))
+	for _, recipe := range recipes {
+		titles = append(titles, recipe.Title)
+		assert.Empty(t, recipe.Embedding)
+	}
+	assert.ElementsMatch(t, []string{"Omelette", "Fried Rice", "Salad"}, titles)
+
+	// LIKE wildcards in a term are matched literally.
+	recipes, err = repo.FindRecipesByIngredients(ctx, []string{"soy_"}, 0)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "Fried Rice", recipes[0].Title)
+
+	recipes, err = repo.FindRecipesByIngredients(ctx, []string{"egg"}, 1)
+	require.NoError(t, err)
+	assert.Len(t, recipes, 1)
+
+	// Recipes mentioning the most terms come first, so a limit keeps the best candidates.
+	recipes, err = repo.FindRecipesByIngredients(ctx, []string{"rice", "soy", "egg"}, 1)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "Fried Rice", recipes[0].Title)
+
+	recipes, err = repo.FindRecipesByIngredients(ctx, []string{"butter", "egg"}, 1)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "Omelette", recipes[0].Title)
+
+	recipes, err = repo.FindRecipesByIngredients(ctx, nil, 0)
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+}
@@ -0,0 +1,150 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// countQueries registers a callback that counts SELECT statements issued through db.
+func countQueries(t *testing.T, db *gorm.DB) *int {
+	t.Helper()
+	count := 0
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		count++
+	}))
+	return &count
+}
+
+func TestListRecipesPreloadsRelationsInBatches(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	italian := models.Cuisine{Name: "Italian"}
+	vegan := models.Diet{Name: "Vegan"}
+	oven := models.Appliance{ID: "appliance-oven", Name: "Oven"}
+	require.NoError(t, db.Create(&italian).Error)
+	require.NoError(t, db.Create(&vegan).Error)
+	require.NoError(t, db.Create(&oven).Error)
+
+	var recipes []models.Recipe
+	require.NoError(t, db.Find(&recipes).Error)
+	for i := range recipes {
+		recipe := &recipes[i]
+		tag := models.Tag{ID: fmt.Sprintf("tag-%d", i), Name: fmt.Sprintf("tag %d", i)}
+		require.NoError(t, db.Model(recipe).Association("Cuisines").Append(&italian))
+		require.NoError(t, db.Model(recipe).Association("Diets").Append(&vegan))
+		require.NoError(t, db.Model(recipe).Association("Appliances").Append(&oven))
+		require.NoError(t, db.Model(recipe).Association("Tags").Append(&tag))
+	}
+
+	repo := repositories.NewRecipeRepository(db)
+	queries := countQueries(t, db)
+
+	one, err := repo.ListRecipes(context.Background(), 1, 1, "", "", models.RecipeFilter{})
+	require.NoError(t, err)
+	require.Len(t, one, 1)
+	perPageOfOne := *queries
+
+	*queries = 0
+	all, err := repo.ListRecipes(context.Background(), 1, len(recipes), "", "", models.RecipeFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, len(recipes))
+	assert.Equal(t, perPageOfOne, *queries, "query count should not grow with the number of recipes")
+
+	for _, recipe := range all {
+		require.Len(t, recipe.Cuisines, 1)
+		require.Len(t, recipe.Diets, 1)
+		require.Len(t, recipe.Appliances, 1)
+		require.Len(t, recipe.Tags, 1)
+		assert.Equal(t, "Italian", recipe.Cuisines[0].Name)
+		assert.Equal(t, "Vegan", recipe.Diets[0].Name)
+		assert.Equal(t, "Oven", recipe.Appliances[0].Name)
+	}
+}
+
+func TestListRecipesByUser(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	owner, other := "user-1", "user-2"
+	for _, recipe := range []*models.Recipe{
+		{Title: "Mine B", UserID: &owner, Embedding: models.Float64Slice{0.5}},
+		{Title: "Mine A", UserID: &owner},
+		{Title: "Mine C", UserID: &owner},
+		{Title: "Theirs", UserID: &other},
+	} {
+		require.NoError(t, db.Create(recipe).Error)
+	}
+	repo := repositories.NewRecipeRepository(db)
+
+	first, err := repo.ListRecipesByUser(context.Background(), owner, 1, 2, "title", "asc")
+	require.NoError(t, err)
+	second, err := repo.ListRecipesByUser(context.Background(), owner, 2, 2, "title", "asc")
+	require.NoError(t, err)
+
+	var titles []string
+	for _, recipe := range append(first, second...) {
+		titles = append(titles, recipe.Title)
+		assert.Nil(t, recipe.Embedding, "embedding should not be loaded")
+	}
+	assert.Equal(t, []string{"Mine A", "Mine B", "Mine C"}, titles)
+}
+
+func TestListRecipesSortsByTotalTime(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.Exec("DELETE FROM recipes").Error)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	for _, recipe := range []*models.Recipe{
+		{Title: "Stew", PrepTime: 20, CookTime: 120},
+		{Title: "Salad", PrepTime: 10},
+		{Title: "Pasta", PrepTime: 5, CookTime: 15},
+	} {
+		require.NoError(t, db.Create(recipe).Error)
+	}
+	repo := repositories.NewRecipeRepository(db)
+
+	recipes, err := repo.ListRecipes(context.Background(), 1, 10, "total_time_minutes", "asc", models.RecipeFilter{})
+	require.NoError(t, err)
+	var titles []string
+	for _, recipe := range recipes {
+		titles = append(titles, recipe.Title)
+	}
+	assert.Equal(t, []string{"Salad", "Pasta", "Stew"}, titles)
+}
+
+func TestListRecipesFilters(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.Exec("DELETE FROM recipes").Error)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	vegan := models.Tag{ID: "tag-vegan", Name: "vegan"}
+	quick := models.Tag{ID: "tag-quick", Name: "quick"}
+	for _, recipe := range []*models.Recipe{
+		{Title: "Salad", Difficulty: "easy", Tags: []models.Tag{vegan, quick}},
+		{Title: "Curry", Difficulty: "medium", Tags: []models.Tag{vegan}},
+		{Title: "Omelette", Difficulty: "easy", Tags: []models.Tag{quick}},
+		{Title: "Roast", Difficulty: "hard"},
+	} {
+		require.NoError(t, db.Create(recipe).Error)
+	}
+	repo := repositories.NewRecipeRepository(db)
+
+	titles := func(filter models.RecipeFilter) []string {
+		recipes, err := repo.ListRecipes(context.Background(), 1, 10, "title", "asc", filter)
+		require.NoError(t, err)
+		var titles []string
+		for _, recipe := range recipes {
+			titles = append(titles, recipe.Title)
+		}
+		return titles
+	}
+
+	assert.Equal(t, []string{"Omelette", "Salad"}, titles(models.RecipeFilter{Difficulty: "easy"}))
+	assert.Equal(t, []string{"Salad"}, titles(models.RecipeFilter{Tags: []string{"vegan", "quick"}}))
+	assert.Equal(t, []string{"Curry", "Omelette", "Salad"}, titles(models.RecipeFilter{Tags: []string{"vegan", "quick"}, MatchAnyTag: true}))
+	assert.Equal(t, []string{"Omelette", "Salad"}, titles(models.RecipeFilter{Difficulty: "easy", Tags: []string{"quick"}}))
+}
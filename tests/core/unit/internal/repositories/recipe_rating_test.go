@@ -0,0 +1,98 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateRecipeFirstRating(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}, &models.RecipeRating{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	recipe := &models.Recipe{Title: "Pancakes"}
+	require.NoError(t, repo.SaveRecipe(ctx, recipe))
+	require.NoError(t, repo.RateRecipe(ctx, recipe.ID, "user-1", 4))
+
+	var rows []models.RecipeRating
+	require.NoError(t, db.Find(&rows, "recipe_id = ?", recipe.ID).Error)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "user-1", rows[0].UserID)
+	assert.Equal(t, 4.0, rows[0].Rating)
+
+	stored, err := repo.GetRecipe(ctx, recipe.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.RatingCount)
+	assert.Equal(t, 4.0, stored.AverageRating)
+}
+
+func TestRateRecipeKeepsOneRatingPerUser(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}, &models.RecipeRating{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	recipe := &models.Recipe{Title: "Chili"}
+	require.NoError(t, repo.SaveRecipe(ctx, recipe))
+
+	require.NoError(t, repo.RateRecipe(ctx, recipe.ID, "user-1", 5))
+	require.NoError(t, repo.RateRecipe(ctx, recipe.ID, "user-2", 2))
+	// Rating again replaces the user's earlier rating instead of counting twice.
+	require.NoError(t, repo.RateRecipe(ctx, recipe.ID, "user-1", 3))
+
+	var count int64
+	require.NoError(t, db.Model(&models.RecipeRating{}).Where("recipe_id = ?", recipe.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	summary, err := repo.GetRecipeRatings(ctx, recipe.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RatingSummary{Count: 2, Average: 2.5}, summary)
+
+	stored, err := repo.GetRecipe(ctx, recipe.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.RatingCount)
+	assert.Equal(t, 2.5, stored.AverageRating)
+}
+
+func TestRecipeRatingsAverageDoesNotDrift(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}, &models.RecipeRating{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	recipe := &models.Recipe{Title: "Soup"}
+	require.NoError(t, repo.SaveRecipe(ctx, recipe))
+
+	users := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for i, user := range users {
+		require.NoError(t, repo.RateRecipe(ctx, recipe.ID, user, float64(i%3)+0.1))
+	}
+
+	summary, err := repo.GetRecipeRatings(ctx, recipe.ID)
+	require.NoError(t, err)
+	assert.Equal(t, len(users), summary.Count)
+	assert.InDelta(t, (0.1+1.1+2.1+0.1+1.1+2.1+0.1)/7, summary.Average, 1e-12)
+}
+
+func TestRecipeRatingsMissingRecipe(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.RecipeRating{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	assert.Error(t, repo.RateRecipe(ctx, "missing", "user-1", 4))
+	_, err := repo.GetRecipeRatings(ctx, "missing")
+	assert.Error(t, err)
+
+	recipe := &models.Recipe{Title: "Unrated"}
+	require.NoError(t, repo.SaveRecipe(ctx, recipe))
+	summary, err := repo.GetRecipeRatings(ctx, recipe.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.RatingSummary{}, summary)
+}
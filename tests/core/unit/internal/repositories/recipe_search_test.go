@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSearchDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Recipe{}))
+
+	for _, title := range []string{"100% Rye Bread", "Whole Wheat Bread", "Chicken_Curry", "Chicken Curry", `Back\slash Buns`} {
+		recipe := &models.Recipe{Title: title}
+		require.NoError(t, db.Create(recipe).Error)
+	}
+	return db
+}
+
+func searchTitles(t *testing.T, repo repositories.RecipeRepository, query string) []string {
+	recipes, err := repo.SearchRecipes(context.Background(), query, nil, "")
+	require.NoError(t, err)
+	titles := make([]string, 0, len(recipes))
+	for _, r := range recipes {
+		titles = append(titles, r.Title)
+	}
+	return titles
+}
+
+func TestSearchRecipesEscapesWildcards(t *testing.T) {
+	repo := repositories.NewRecipeRepository(setupSearchDB(t))
+
+	t.Run("percent is matched literally", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"100% Rye Bread"}, searchTitles(t, repo, "%"))
+	})
+
+	t.Run("percent inside a term", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"100% Rye Bread"}, searchTitles(t, repo, "0% R"))
+	})
+
+	t.Run("underscore is matched literally", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"Chicken_Curry"}, searchTitles(t, repo, "n_C"))
+	})
+
+	t.Run("backslash is matched literally", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{`Back\slash Buns`}, searchTitles(t, repo, `k\s`))
+	})
+
+	t.Run("plain terms still match", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"100% Rye Bread", "Whole Wheat Bread"}, searchTitles(t, repo, "Bread"))
+	})
+}
@@ -0,0 +1,56 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tagNames(recipe *models.Recipe) []string {
+	names := make([]string, 0, len(recipe.Tags))
+	for _, tag := range recipe.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestUpdateRecipeReplacesRelatedEntities(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	quick := models.Tag{ID: "tag-quick", Name: "quick"}
+	vegan := models.Tag{ID: "tag-vegan", Name: "vegan"}
+	spicy := models.Tag{ID: "tag-spicy", Name: "spicy"}
+	recipe := &models.Recipe{Title: "Chili", Tags: []models.Tag{quick, vegan}, Cuisines: []models.Cuisine{{Name: "Mexican"}}}
+	require.NoError(t, repo.SaveRecipe(ctx, recipe))
+
+	t.Run("set values", func(t *testing.T) {
+		loaded, err := repo.GetRecipe(ctx, recipe.ID)
+		require.NoError(t, err)
+		loaded.Tags = []models.Tag{vegan, spicy}
+		require.NoError(t, repo.UpdateRecipe(ctx, loaded))
+
+		updated, err := repo.GetRecipe(ctx, recipe.ID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"vegan", "spicy"}, tagNames(updated))
+		assert.Len(t, updated.Cuisines, 1, "untouched relations are kept")
+	})
+
+	t.Run("set empty", func(t *testing.T) {
+		loaded, err := repo.GetRecipe(ctx, recipe.ID)
+		require.NoError(t, err)
+		loaded.Tags = []models.Tag{}
+		require.NoError(t, repo.UpdateRecipe(ctx, loaded))
+
+		updated, err := repo.GetRecipe(ctx, recipe.ID)
+		require.NoError(t, err)
+		assert.Empty(t, updated.Tags)
+		assert.Len(t, updated.Cuisines, 1)
+	})
+}
@@ -0,0 +1,193 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/pageza/alchemorsel-v1/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestTagGetOrCreateBatch(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Tag{}))
+	require.NoError(t, db.Create(&models.Tag{ID: "tag-quick", Name: "quick"}).Error)
+	repo := repositories.NewTagRepository(db)
+
+	tags, err := repo.GetOrCreateBatch(context.Background(), []string{"vegan", "quick", "vegan", "spicy"})
+	require.NoError(t, err)
+	require.Len(t, tags, 3)
+	assert.Equal(t, "vegan", tags[0].Name)
+	assert.Equal(t, "tag-quick", tags[1].ID, "existing tags are reused")
+	assert.Equal(t, "spicy", tags[2].Name)
+	assert.NotEmpty(t, tags[0].ID)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Tag{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestGetOrCreateBatchConcurrentCallsDoNotCreateDuplicates(t *testing.T) {
+	// A file database, since concurrent transactions need separate connections. Immediate
+	// transactions make writers wait for each other instead of failing to upgrade their lock.
+	dsn := filepath.Join(t.TempDir(), "entities.db") + "?_txlock=immediate&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}))
+	repo := repositories.NewCuisineRepository(db)
+
+	names := []string{"Italian", "Mexican", "Thai", "Indian"}
+	const callers = 8
+	results := make([][]*models.Cuisine, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Rotate the names so callers insert them in different orders.
+			rotated := append(append([]string{}, names[i%len(names):]...), names[:i%len(names)]...)
+			results[i], errs[i] = repo.GetOrCreateBatch(context.Background(), rotated)
+		}(i)
+	}
+	wg.Wait()
+
+	ids := make(map[string]string)
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i], fmt.Sprintf("caller %d", i))
+		require.Len(t, results[i], len(names))
+		for _, cuisine := range results[i] {
+			if id, ok := ids[cuisine.Name]; ok {
+				assert.Equal(t, id, cuisine.ID, "every caller gets the same %s row", cuisine.Name)
+			}
+			ids[cuisine.Name] = cuisine.ID
+		}
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.Cuisine{}).Count(&count).Error)
+	assert.Equal(t, int64(len(names)), count)
+}
+
+func TestCuisineNamesAreUniqueRegardlessOfCase(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}))
+	service := services.NewCuisineService(repositories.NewCuisineRepository(db))
+	ctx := context.Background()
+
+	italian := &models.Cuisine{Name: "Italian"}
+	require.NoError(t, service.Create(ctx, italian))
+
+	again := &models.Cuisine{Name: "  italian "}
+	require.NoError(t, service.Create(ctx, again), "creating an existing name returns it")
+	assert.Equal(t, italian.ID, again.ID)
+	assert.Equal(t, "Italian", again.Name)
+
+	found, err := service.GetByName(ctx, "ITALIAN")
+	require.NoError(t, err)
+	assert.Equal(t, italian.ID, found.ID)
+
+	resolved, err := service.GetOrCreate(ctx, "italian")
+	require.NoError(t, err)
+	assert.Equal(t, italian.ID, resolved.ID)
+
+	batch, err := service.GetOrCreateBatch(ctx, []string{"italian", "Thai", "thai "})
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.Equal(t, italian.ID, batch[0].ID)
+	assert.Equal(t, "Thai", batch[1].Name)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Cuisine{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	t.Run("the index rejects names differing only in case", func(t *testing.T) {
+		err := db.Create(&models.Cuisine{ID: "raw-insert", Name: "ITALIAN"}).Error
+		assert.Error(t, err)
+	})
+}
+
+func TestTagMerge(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	recipes := repositories.NewRecipeRepository(db)
+	tags := repositories.NewTagRepository(db)
+	ctx := context.Background()
+
+	quick := models.Tag{ID: "tag-quick", Name: "quick"}
+	fast := models.Tag{ID: "tag-fast", Name: "fast"}
+	speedy := models.Tag{ID: "tag-speedy", Name: "speedy"}
+	vegan := models.Tag{ID: "tag-vegan", Name: "vegan"}
+	// Both tagged "quick" and "fast": merging must not add a second "quick" association.
+	both := &models.Recipe{Title: "Salad", Tags: []models.Tag{quick, fast}, Embedding: models.Float64Slice{0.1}}
+	onlyFast := &models.Recipe{Title: "Toast", Tags: []models.Tag{fast, vegan}, Embedding: models.Float64Slice{0.2}}
+	onlySpeedy := &models.Recipe{Title: "Noodles", Tags: []models.Tag{speedy}, Embedding: models.Float64Slice{0.3}}
+	untouched := &models.Recipe{Title: "Stew", Tags: []models.Tag{vegan}, Embedding: models.Float64Slice{0.4}}
+	require.NoError(t, db.Exec("DELETE FROM recipes").Error)
+	for _, recipe := range []*models.Recipe{both, onlyFast, onlySpeedy, untouched} {
+		require.NoError(t, recipes.SaveRecipe(ctx, recipe))
+	}
+	require.Empty(t, staleIDs(t, recipes))
+
+	result, err := tags.Merge(ctx, quick.ID, []string{fast.ID, speedy.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.RecipesAffected)
+	assert.Equal(t, int64(2), result.TagsDeleted)
+
+	for _, recipe := range []*models.Recipe{both, onlyFast, onlySpeedy} {
+		loaded, err := recipes.GetRecipe(ctx, recipe.ID)
+		require.NoError(t, err)
+		assert.Contains(t, tagNames(loaded), "quick", recipe.Title)
+		assert.NotContains(t, tagNames(loaded), "fast", recipe.Title)
+		assert.NotContains(t, tagNames(loaded), "speedy", recipe.Title)
+	}
+	var associations int64
+	require.NoError(t, db.Table("recipe_tags").Where("recipe_id = ? AND tag_id = ?", both.ID, quick.ID).Count(&associations).Error)
+	assert.Equal(t, int64(1), associations)
+	loaded, err := recipes.GetRecipe(ctx, untouched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vegan"}, tagNames(loaded))
+
+	// The moved recipes' embeddings were computed with the old tags.
+	assert.ElementsMatch(t, []string{both.ID, onlyFast.ID, onlySpeedy.ID}, staleIDs(t, recipes))
+
+	_, err = tags.GetByID(ctx, fast.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	t.Run("missing target", func(t *testing.T) {
+		_, err := tags.Merge(ctx, "missing", []string{vegan.ID})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = tags.GetByID(ctx, vegan.ID)
+		assert.NoError(t, err, "nothing is deleted")
+	})
+}
+
+func TestTagDeleteUnused(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Cuisine{}, &models.Diet{}, &models.Appliance{}, &models.Tag{}))
+	tags := repositories.NewTagRepository(db)
+	ctx := context.Background()
+
+	used := models.Tag{ID: "tag-used", Name: "used"}
+	require.NoError(t, repositories.NewRecipeRepository(db).SaveRecipe(ctx, &models.Recipe{Title: "Soup", Tags: []models.Tag{used}}))
+	for _, name := range []string{"orphan", "stray"} {
+		require.NoError(t, tags.Create(ctx, &models.Tag{Name: name}))
+	}
+
+	deleted, err := tags.DeleteUnused(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	remaining, err := tags.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "used", remaining[0].Name)
+}
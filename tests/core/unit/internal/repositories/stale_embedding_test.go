@@ -0,0 +1,85 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func staleIDs(t *testing.T, repo repositories.RecipeRepository) []string {
+	t.Helper()
+	recipes, err := repo.ListStaleEmbeddings(context.Background(), 100)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(recipes))
+	for _, recipe := range recipes {
+		ids = append(ids, recipe.ID)
+	}
+	return ids
+}
+
+func TestListStaleEmbeddings(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.Exec("DELETE FROM recipes").Error)
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	fresh := &models.Recipe{Title: "Fresh", Embedding: models.Float64Slice{0.1}}
+	missing := &models.Recipe{Title: "Missing"}
+	edited := &models.Recipe{Title: "Edited", Embedding: models.Float64Slice{0.2}}
+	for _, recipe := range []*models.Recipe{fresh, missing, edited} {
+		require.NoError(t, repo.SaveRecipe(ctx, recipe))
+	}
+	assert.ElementsMatch(t, []string{missing.ID}, staleIDs(t, repo))
+
+	// An update that carries a regenerated embedding stays fresh.
+	fresh.Title = "Still Fresh"
+	require.NoError(t, repo.UpdateRecipe(ctx, fresh))
+	assert.NotContains(t, staleIDs(t, repo), fresh.ID)
+
+	// A recipe edited after its embedding was written has an outdated embedding.
+	require.NoError(t, db.Model(&models.Recipe{}).Where("id = ?", edited.ID).
+		UpdateColumn("embedding_updated_at", time.Now().Add(-time.Minute)).Error)
+	stale, err := repo.ListStaleEmbeddings(ctx, 100)
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+	for _, recipe := range stale {
+		if recipe.ID == edited.ID {
+			assert.NotNil(t, recipe.EmbeddingUpdatedAt)
+		} else {
+			assert.Equal(t, missing.ID, recipe.ID)
+			assert.Nil(t, recipe.EmbeddingUpdatedAt)
+		}
+	}
+
+	// Reindexing refreshes both.
+	require.NoError(t, repo.UpdateRecipeEmbedding(ctx, edited.ID, models.Float64Slice{0.3}))
+	require.NoError(t, repo.UpdateRecipeEmbedding(ctx, missing.ID, models.Float64Slice{0.4}))
+	assert.Empty(t, staleIDs(t, repo))
+}
+
+func TestRatingDoesNotMakeEmbeddingStale(t *testing.T) {
+	db := setupSearchDB(t)
+	require.NoError(t, db.AutoMigrate(&models.RecipeRating{}))
+	require.NoError(t, db.Exec("DELETE FROM recipes").Error)
+	repo := repositories.NewRecipeRepository(db)
+	ctx := context.Background()
+
+	recipe := &models.Recipe{Title: "Pancakes", Embedding: models.Float64Slice{0.1}}
+	require.NoError(t, repo.SaveRecipe(ctx, recipe))
+	stored, err := repo.GetRecipe(ctx, recipe.ID)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, repo.RateRecipe(ctx, recipe.ID, "user-1", 4))
+
+	rated, err := repo.GetRecipe(ctx, recipe.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rated.RatingCount)
+	assert.True(t, stored.UpdatedAt.Equal(rated.UpdatedAt), "rating must not touch updated_at")
+	assert.Empty(t, staleIDs(t, repo))
+}
@@ -0,0 +1,86 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupUserListDB(t *testing.T) repositories.UserRepository {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"Ann Smith", "Bob Jones", "Cara Smithson", "Dan Brown", "Eve Stone"} {
+		user := &models.User{
+			ID:        fmt.Sprintf("user-%d", i),
+			Name:      name,
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		}
+		require.NoError(t, db.Create(user).Error)
+	}
+	require.NoError(t, db.Create(&models.User{ID: "user-5", Name: "Fay", Email: "fay.SMITH@example.com", CreatedAt: start.Add(5 * time.Hour)}).Error)
+	return repositories.NewUserRepository(db)
+}
+
+func userNames(users []*models.User) []string {
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.Name
+	}
+	return names
+}
+
+func TestListUsersPages(t *testing.T) {
+	repo := setupUserListDB(t)
+	ctx := context.Background()
+
+	users, total, err := repo.ListUsers(ctx, 1, 4, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	assert.Equal(t, []string{"Ann Smith", "Bob Jones", "Cara Smithson", "Dan Brown"}, userNames(users))
+
+	users, total, err = repo.ListUsers(ctx, 2, 4, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	assert.Equal(t, []string{"Eve Stone", "Fay"}, userNames(users))
+
+	users, _, err = repo.ListUsers(ctx, 3, 4, "")
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestListUsersSearch(t *testing.T) {
+	repo := setupUserListDB(t)
+	ctx := context.Background()
+
+	// Matches names and emails regardless of case.
+	users, total, err := repo.ListUsers(ctx, 1, 2, "SMITH")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []string{"Ann Smith", "Cara Smithson"}, userNames(users))
+
+	users, _, err = repo.ListUsers(ctx, 2, 2, "smith")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Fay"}, userNames(users))
+
+	users, total, err = repo.ListUsers(ctx, 1, 10, "user3@")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"Dan Brown"}, userNames(users))
+
+	// LIKE wildcards are matched literally.
+	_, total, err = repo.ListUsers(ctx, 1, 10, "%")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
@@ -0,0 +1,61 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/routes"
+	testhelpers "github.com/pageza/alchemorsel-v1/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupMetricsRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("JWT_SECRET", "test-secret")
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	require.NoError(t, db.Create(&models.User{ID: "admin", Name: "Admin", Email: "admin@example.com", Password: "x", IsAdmin: true}).Error)
+	require.NoError(t, db.Create(&models.User{ID: "test-user", Name: "Member", Email: "member@example.com", Password: "x"}).Error)
+	logger, err := logging.NewLogger(logging.LogConfig{LogLevel: "error"})
+	require.NoError(t, err)
+	return routes.SetupRouterWithRedis(db, nil, logger)
+}
+
+func getMetricsWithAuth(router *gin.Engine, authorization string) int {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestMetricsRequiresAdmin(t *testing.T) {
+	router := setupMetricsRouter(t)
+
+	assert.Equal(t, http.StatusUnauthorized, getMetricsWithAuth(router, ""))
+	assert.Equal(t, http.StatusForbidden, getMetricsWithAuth(router, "Bearer "+testhelpers.GenerateTestToken(nil)))
+	admin := testhelpers.GenerateTestToken(jwt.MapClaims{"sub": "admin", "exp": time.Now().Add(time.Hour).Unix()})
+	assert.Equal(t, http.StatusOK, getMetricsWithAuth(router, "Bearer "+admin))
+}
+
+func TestMetricsScrapeToken(t *testing.T) {
+	t.Setenv("METRICS_SCRAPE_TOKEN", "scrape-secret")
+	router := setupMetricsRouter(t)
+
+	assert.Equal(t, http.StatusOK, getMetricsWithAuth(router, "Bearer scrape-secret"))
+	assert.Equal(t, http.StatusUnauthorized, getMetricsWithAuth(router, "Bearer wrong"))
+	assert.Equal(t, http.StatusUnauthorized, getMetricsWithAuth(router, ""))
+}
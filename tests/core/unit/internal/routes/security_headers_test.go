@@ -0,0 +1,72 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/logging"
+	"github.com/pageza/alchemorsel-v1/internal/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSecurityRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("METRICS_SCRAPE_TOKEN", "scrape-secret")
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	logger, err := logging.NewLogger(logging.LogConfig{LogLevel: "error"})
+	require.NoError(t, err)
+	return routes.SetupRouterWithRedis(db, nil, logger)
+}
+
+func getMetrics(router *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-secret")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSetupRouterSecurityHeaders(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		w := getMetrics(setupSecurityRouter(t))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		expected := map[string]string{
+			"X-Frame-Options":           "DENY",
+			"X-Content-Type-Options":    "nosniff",
+			"X-XSS-Protection":          "1; mode=block",
+			"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+			"Content-Security-Policy":   "default-src 'self'",
+			"Referrer-Policy":           "strict-origin-when-cross-origin",
+			"Permissions-Policy":        "geolocation=(), microphone=(), camera=()",
+		}
+		for header, value := range expected {
+			assert.Equal(t, value, w.Header().Get(header), header)
+		}
+	})
+
+	t.Run("configured CSP and HSTS", func(t *testing.T) {
+		t.Setenv("CONTENT_SECURITY_POLICY", "default-src 'none'")
+		t.Setenv("STRICT_TRANSPORT_SECURITY", "max-age=600")
+
+		w := getMetrics(setupSecurityRouter(t))
+
+		assert.Equal(t, "default-src 'none'", w.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "max-age=600", w.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("DISABLE_SECURITY_HEADERS", "true")
+
+		w := getMetrics(setupSecurityRouter(t))
+
+		assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	})
+}
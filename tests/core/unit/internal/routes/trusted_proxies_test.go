@@ -0,0 +1,53 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pageza/alchemorsel-v1/internal/config"
+	"github.com/pageza/alchemorsel-v1/internal/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func clientIPFor(t *testing.T, trustedProxies string) string {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	err := routes.ConfigureTrustedProxies(router, config.ParseTrustedProxies(trustedProxies))
+	assert.NoError(t, err)
+	router.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/ip", nil)
+	req.RemoteAddr = "10.0.0.5:12345"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	router.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestConfigureTrustedProxies(t *testing.T) {
+	t.Run("untrusted by default", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.5", clientIPFor(t, ""))
+	})
+
+	t.Run("trusted proxy honours X-Forwarded-For", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIPFor(t, "10.0.0.0/8"))
+	})
+
+	t.Run("other proxy is not trusted", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.5", clientIPFor(t, "192.168.1.1"))
+	})
+
+	t.Run("invalid proxy is rejected", func(t *testing.T) {
+		router := gin.New()
+		assert.Error(t, routes.ConfigureTrustedProxies(router, []string{"not-an-ip"}))
+	})
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	assert.Nil(t, config.ParseTrustedProxies(""))
+	assert.Equal(t, []string{"10.0.0.1", "172.16.0.0/12"}, config.ParseTrustedProxies(" 10.0.0.1, ,172.16.0.0/12 "))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/pageza/alchemorsel-v1/internal/models"
+	"github.com/pageza/alchemorsel-v1/internal/repositories"
 	"github.com/pageza/alchemorsel-v1/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -34,6 +35,14 @@ func (m *MockCuisineService) GetOrCreate(ctx context.Context, name string) (*mod
 	return &models.Cuisine{ID: "test-id", Name: name}, nil
 }
 
+func (m *MockCuisineService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Cuisine, error) {
+	rows := make([]*models.Cuisine, len(names))
+	for i, name := range names {
+		rows[i] = &models.Cuisine{ID: "test-id", Name: name}
+	}
+	return rows, nil
+}
+
 type MockDietService struct{}
 
 func (m *MockDietService) GetByID(ctx context.Context, id string) (*models.Diet, error) {
@@ -55,6 +64,14 @@ func (m *MockDietService) GetOrCreate(ctx context.Context, name string) (*models
 	return &models.Diet{ID: "test-id", Name: name}, nil
 }
 
+func (m *MockDietService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Diet, error) {
+	rows := make([]*models.Diet, len(names))
+	for i, name := range names {
+		rows[i] = &models.Diet{ID: "test-id", Name: name}
+	}
+	return rows, nil
+}
+
 type MockApplianceService struct{}
 
 func (m *MockApplianceService) GetByID(ctx context.Context, id string) (*models.Appliance, error) {
@@ -76,6 +93,14 @@ func (m *MockApplianceService) GetOrCreate(ctx context.Context, name string) (*m
 	return &models.Appliance{ID: "test-id", Name: name}, nil
 }
 
+func (m *MockApplianceService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Appliance, error) {
+	rows := make([]*models.Appliance, len(names))
+	for i, name := range names {
+		rows[i] = &models.Appliance{ID: "test-id", Name: name}
+	}
+	return rows, nil
+}
+
 type MockTagService struct{}
 
 func (m *MockTagService) GetByID(ctx context.Context, id string) (*models.Tag, error) {
@@ -97,6 +122,22 @@ func (m *MockTagService) GetOrCreate(ctx context.Context, name string) (*models.
 	return &models.Tag{ID: "test-id", Name: name}, nil
 }
 
+func (m *MockTagService) GetOrCreateBatch(ctx context.Context, names []string) ([]*models.Tag, error) {
+	rows := make([]*models.Tag, len(names))
+	for i, name := range names {
+		rows[i] = &models.Tag{ID: "test-id", Name: name}
+	}
+	return rows, nil
+}
+
+func (m *MockTagService) Merge(ctx context.Context, targetID string, sourceIDs []string) (*repositories.TagMergeResult, error) {
+	return &repositories.TagMergeResult{}, nil
+}
+
+func (m *MockTagService) DeleteUnused(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
 // func TestListRecipes(t *testing.T) {
 // 	recipes, err := services.ListRecipes()
 // 	if err == nil {
@@ -109,15 +150,30 @@ func (m *MockTagService) GetOrCreate(ctx context.Context, name string) (*models.
 
 // MockRecipeRepository is a mock implementation of RecipeRepository for testing.
 type MockRecipeRepository struct {
-	GetRecipeFunc        func(ctx context.Context, id string) (*models.Recipe, error)
-	SaveRecipeFunc       func(ctx context.Context, recipe *models.Recipe) error
-	ListRecipesFunc      func(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error)
-	UpdateRecipeFunc     func(ctx context.Context, recipe *models.Recipe) error
-	DeleteRecipeFunc     func(ctx context.Context, id string) error
-	SearchRecipesFunc    func(ctx context.Context, query string, tags []string, difficulty string) ([]models.Recipe, error)
-	RateRecipeFunc       func(ctx context.Context, recipeID string, rating float64) error
-	GetRecipeRatingsFunc func(ctx context.Context, recipeID string) ([]float64, error)
-	ResolveRecipeFunc    func(ctx context.Context, query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error)
+	GetRecipeFunc         func(ctx context.Context, id string) (*models.Recipe, error)
+	SaveRecipeFunc        func(ctx context.Context, recipe *models.Recipe) error
+	ListRecipesFunc       func(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error)
+	ListByUserFunc        func(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error)
+	UpdateRecipeFunc      func(ctx context.Context, recipe *models.Recipe) error
+	DeleteRecipeFunc      func(ctx context.Context, id string) error
+	SearchRecipesFunc     func(ctx context.Context, query string, tags []string, difficulty string) ([]models.Recipe, error)
+	RateRecipeFunc        func(ctx context.Context, recipeID, userID string, rating float64) error
+	GetRecipeRatingsFunc  func(ctx context.Context, recipeID string) (*models.RatingSummary, error)
+	ResolveRecipeFunc     func(ctx context.Context, query string, attributes map[string]interface{}) (*models.Recipe, []*models.Recipe, error)
+	ListRecipesBatchFunc  func(ctx context.Context, offset, limit int) ([]models.Recipe, error)
+	UpdateEmbeddingFunc   func(ctx context.Context, id string, embedding models.Float64Slice) error
+	ListStaleFunc         func(ctx context.Context, limit int) ([]models.Recipe, error)
+	FindByIngredientsFunc func(ctx context.Context, terms []string, limit int) ([]models.Recipe, error)
+}
+
+// WithTransaction runs fn against the mock itself; the mock has no transactional state.
+func (m *MockRecipeRepository) WithTransaction(ctx context.Context, fn func(repo repositories.RecipeRepository) error) error {
+	return fn(m)
+}
+
+// WithBulkTransaction runs fn against the mock itself, like WithTransaction.
+func (m *MockRecipeRepository) WithBulkTransaction(ctx context.Context, fn func(repo repositories.RecipeRepository) error) error {
+	return fn(m)
 }
 
 func (m *MockRecipeRepository) GetRecipe(ctx context.Context, id string) (*models.Recipe, error) {
@@ -134,9 +190,16 @@ func (m *MockRecipeRepository) SaveRecipe(ctx context.Context, recipe *models.Re
 	return nil
 }
 
-func (m *MockRecipeRepository) ListRecipes(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error) {
+func (m *MockRecipeRepository) ListRecipes(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error) {
 	if m.ListRecipesFunc != nil {
-		return m.ListRecipesFunc(ctx, page, limit, sort, order)
+		return m.ListRecipesFunc(ctx, page, limit, sort, order, filter)
+	}
+	return nil, nil
+}
+
+func (m *MockRecipeRepository) ListRecipesByUser(ctx context.Context, userID string, page, limit int, sort, order string) ([]models.Recipe, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID, page, limit, sort, order)
 	}
 	return nil, nil
 }
@@ -162,14 +225,14 @@ func (m *MockRecipeRepository) SearchRecipes(ctx context.Context, query string,
 	return nil, nil
 }
 
-func (m *MockRecipeRepository) RateRecipe(ctx context.Context, recipeID string, rating float64) error {
+func (m *MockRecipeRepository) RateRecipe(ctx context.Context, recipeID, userID string, rating float64) error {
 	if m.RateRecipeFunc != nil {
-		return m.RateRecipeFunc(ctx, recipeID, rating)
+		return m.RateRecipeFunc(ctx, recipeID, userID, rating)
 	}
 	return nil
 }
 
-func (m *MockRecipeRepository) GetRecipeRatings(ctx context.Context, recipeID string) ([]float64, error) {
+func (m *MockRecipeRepository) GetRecipeRatings(ctx context.Context, recipeID string) (*models.RatingSummary, error) {
 	if m.GetRecipeRatingsFunc != nil {
 		return m.GetRecipeRatingsFunc(ctx, recipeID)
 	}
@@ -183,6 +246,34 @@ func (m *MockRecipeRepository) ResolveRecipe(ctx context.Context, query string,
 	return nil, nil, nil
 }
 
+func (m *MockRecipeRepository) ListRecipesBatch(ctx context.Context, offset, limit int) ([]models.Recipe, error) {
+	if m.ListRecipesBatchFunc != nil {
+		return m.ListRecipesBatchFunc(ctx, offset, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockRecipeRepository) UpdateRecipeEmbedding(ctx context.Context, id string, embedding models.Float64Slice) error {
+	if m.UpdateEmbeddingFunc != nil {
+		return m.UpdateEmbeddingFunc(ctx, id, embedding)
+	}
+	return nil
+}
+
+func (m *MockRecipeRepository) ListStaleEmbeddings(ctx context.Context, limit int) ([]models.Recipe, error) {
+	if m.ListStaleFunc != nil {
+		return m.ListStaleFunc(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockRecipeRepository) FindRecipesByIngredients(ctx context.Context, terms []string, limit int) ([]models.Recipe, error) {
+	if m.FindByIngredientsFunc != nil {
+		return m.FindByIngredientsFunc(ctx, terms, limit)
+	}
+	return nil, nil
+}
+
 func TestSaveRecipeSuccess(t *testing.T) {
 	// Create a mock repository that simulates a successful save.
 	mockRepo := &MockRecipeRepository{
@@ -358,7 +449,7 @@ func TestListRecipes(t *testing.T) {
 	}
 
 	mockRepo := &MockRecipeRepository{
-		ListRecipesFunc: func(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error) {
+		ListRecipesFunc: func(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error) {
 			return mockRecipes, nil
 		},
 	}
@@ -370,14 +461,14 @@ func TestListRecipes(t *testing.T) {
 
 	service := services.NewRecipeService(mockRepo, mockCuisineService, mockDietService, mockApplianceService, mockTagService)
 
-	recipes, err := service.ListRecipes(context.Background(), 1, 10, "created_at", "desc")
+	recipes, err := service.ListRecipes(context.Background(), 1, 10, "created_at", "desc", models.RecipeFilter{})
 	assert.NoError(t, err)
 	assert.Equal(t, mockRecipes, recipes)
 }
 
 func TestListRecipesError(t *testing.T) {
 	mockRepo := &MockRecipeRepository{
-		ListRecipesFunc: func(ctx context.Context, page, limit int, sort, order string) ([]models.Recipe, error) {
+		ListRecipesFunc: func(ctx context.Context, page, limit int, sort, order string, filter models.RecipeFilter) ([]models.Recipe, error) {
 			return nil, assert.AnError
 		},
 	}
@@ -389,7 +480,7 @@ func TestListRecipesError(t *testing.T) {
 
 	service := services.NewRecipeService(mockRepo, mockCuisineService, mockDietService, mockApplianceService, mockTagService)
 
-	recipes, err := service.ListRecipes(context.Background(), 1, 10, "created_at", "desc")
+	recipes, err := service.ListRecipes(context.Background(), 1, 10, "created_at", "desc", models.RecipeFilter{})
 	assert.Error(t, err)
 	assert.Nil(t, recipes)
 }
@@ -2,6 +2,7 @@ package unit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/docker/go-connections/nat"
 	_ "github.com/lib/pq"
 	"github.com/pageza/alchemorsel-v1/internal/db"
+	"github.com/pageza/alchemorsel-v1/internal/email"
 	"github.com/pageza/alchemorsel-v1/internal/models"
 	"github.com/pageza/alchemorsel-v1/internal/repositories"
 	"github.com/pageza/alchemorsel-v1/internal/services"
@@ -21,6 +23,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // TestMain sets up a PostgreSQL container for unit tests.
@@ -143,6 +146,14 @@ func (m *MockUserRepository) GetAllUsers(ctx context.Context) ([]*models.User, e
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListUsers(ctx context.Context, page, limit int, query string) ([]*models.User, int64, error) {
+	args := m.Called(ctx, page, limit, query)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockUserRepository) GetUserByResetPasswordToken(ctx context.Context, token string) (*models.User, error) {
 	args := m.Called(ctx, token)
 	if args.Get(0) == nil {
@@ -151,6 +162,14 @@ func (m *MockUserRepository) GetUserByResetPasswordToken(ctx context.Context, to
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetUserByEmailVerificationToken(ctx context.Context, token string) (*models.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
@@ -219,6 +238,9 @@ func TestCreateUser(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotEqual(t, "Test1234!", tt.user.Password, "Password should be hashed")
+				assert.False(t, tt.user.EmailVerified)
+				assert.NotEmpty(t, tt.user.EmailVerificationToken)
+				assert.NotNil(t, tt.user.EmailVerificationExpires)
 			}
 		})
 	}
@@ -264,11 +286,66 @@ func TestUpdateUser(t *testing.T) {
 		Password: "Test1234!",
 	}
 
-	mockRepo.On("UpdateUser", ctx, user).Return(nil)
+	stored := &models.User{ID: "123", Name: "Old Name", Email: "old@example.com", Password: "old-hash", IsAdmin: true, EmailVerified: true}
+	mockRepo.On("GetUser", ctx, "123").Return(stored, nil)
 	mockRepo.On("GetUserByEmail", ctx, "updated@example.com").Return(nil, nil)
+	mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *models.User) bool {
+		return u.ID == "123" && u.Name == "Updated Name" && u.Email == "updated@example.com" && u.IsAdmin &&
+			bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("Test1234!")) == nil &&
+			// The new email has to be verified again.
+			!u.EmailVerified && u.EmailVerificationToken != "" && u.EmailVerificationExpires != nil
+	})).Return(nil)
 
 	err := service.UpdateUser(ctx, "123", user)
 	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserKeepsPasswordWhenOmitted(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	stored := &models.User{ID: "123", Name: "Old Name", Email: "same@example.com", Password: "old-hash", EmailVerified: true}
+	mockRepo.On("GetUser", ctx, "123").Return(stored, nil)
+	mockRepo.On("GetUserByEmail", ctx, "same@example.com").Return(stored, nil)
+	mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *models.User) bool {
+		return u.Name == "New Name" && u.Password == "old-hash" && u.EmailVerified && u.EmailVerificationToken == ""
+	})).Return(nil)
+
+	err := service.UpdateUser(ctx, "123", &models.User{Name: "New Name", Email: "same@example.com"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchUserEmailRequiresVerification(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	stored := &models.User{ID: "123", Email: "old@example.com", EmailVerified: true}
+	mockRepo.On("GetUser", ctx, "123").Return(stored, nil)
+	mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u *models.User) bool {
+		return u.Email == "new@example.com" && !u.EmailVerified && u.EmailVerificationToken != ""
+	})).Return(nil)
+
+	err := service.PatchUser(ctx, "123", map[string]interface{}{"email": "new@example.com"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserRejectsEmailOfAnotherUser(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	mockRepo.On("GetUser", ctx, "123").Return(&models.User{ID: "123", Email: "mine@example.com"}, nil)
+	mockRepo.On("GetUserByEmail", ctx, "taken@example.com").Return(&models.User{ID: "456", Email: "taken@example.com"}, nil)
+
+	err := service.UpdateUser(ctx, "123", &models.User{Name: "Me", Email: "taken@example.com"})
+	assert.ErrorIs(t, err, services.ErrEmailTaken)
+	assert.Contains(t, err.Error(), "already exists")
+	mockRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -308,6 +385,19 @@ func TestGetAllUsers(t *testing.T) {
 	assert.Equal(t, expectedUsers, users)
 }
 
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	expectedUsers := []*models.User{{ID: "1", Name: "Ann Smith", Email: "ann@example.com"}}
+	mockRepo.On("ListUsers", ctx, 1, services.MaxUserListLimit, "smith").Return(expectedUsers, int64(1), nil)
+
+	list, err := service.ListUsers(ctx, 0, 1000, " smith ")
+	assert.NoError(t, err)
+	assert.Equal(t, &services.UserList{Users: expectedUsers, Total: 1, Page: 1, Limit: services.MaxUserListLimit}, list)
+}
+
 func TestResetPassword(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := new(MockUserRepository)
@@ -336,6 +426,185 @@ func TestResetPassword(t *testing.T) {
 	})
 }
 
+// recordingMailer captures sent messages and returns err from every Send. It is safe to use
+// from the goroutine ForgotPassword sends from.
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []email.Message
+	err  error
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg email.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+// messages returns a copy of the messages sent so far.
+func (m *recordingMailer) messages() []email.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]email.Message(nil), m.sent...)
+}
+
+func TestForgotPassword(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends reset link", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mailer := &recordingMailer{}
+		service := services.NewUserService(mockRepo)
+		service.Mailer = mailer
+		service.PasswordResetURL = "https://app.example.com/reset"
+		user := &models.User{ID: "123", Email: "cook@example.com"}
+		mockRepo.On("GetUserByEmail", ctx, "cook@example.com").Return(user, nil)
+		mockRepo.On("UpdateUser", ctx, user).Return(nil)
+
+		err := service.ForgotPassword(ctx, "cook@example.com")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, user.ResetPasswordToken)
+		// The email is sent in the background.
+		assert.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, time.Millisecond)
+		if sent := mailer.messages(); assert.Len(t, sent, 1) {
+			assert.Equal(t, "cook@example.com", sent[0].To)
+			assert.Contains(t, sent[0].Body, "https://app.example.com/reset?token="+user.ResetPasswordToken)
+		}
+	})
+
+	t.Run("unknown email sends nothing", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mailer := &recordingMailer{}
+		service := services.NewUserService(mockRepo)
+		service.Mailer = mailer
+		mockRepo.On("GetUserByEmail", ctx, "nobody@example.com").Return(nil, nil)
+
+		err := service.ForgotPassword(ctx, "nobody@example.com")
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.messages())
+	})
+
+	t.Run("send failure is not reported", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := services.NewUserService(mockRepo)
+		service.Mailer = &recordingMailer{err: errors.New("smtp down")}
+		user := &models.User{ID: "123", Email: "cook@example.com"}
+		mockRepo.On("GetUserByEmail", ctx, "cook@example.com").Return(user, nil)
+		mockRepo.On("UpdateUser", ctx, user).Return(nil)
+
+		err := service.ForgotPassword(ctx, "cook@example.com")
+		assert.NoError(t, err)
+	})
+}
+
+func TestVerificationEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sent on signup", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mailer := &recordingMailer{}
+		service := services.NewUserService(mockRepo)
+		service.Mailer = mailer
+		service.EmailVerificationURL = "https://api.example.com/v1/users/verify-email"
+		mockRepo.On("GetUserByEmail", ctx, "cook@example.com").Return(nil, nil)
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*models.User")).Return(nil)
+
+		user := &models.User{Name: "Cook", Email: "cook@example.com", Password: "Test1234!"}
+		err := service.CreateUser(ctx, user)
+		assert.NoError(t, err)
+		assert.Len(t, user.EmailVerificationToken, 64)
+		// The email is sent in the background.
+		assert.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, time.Millisecond)
+		if sent := mailer.messages(); assert.Len(t, sent, 1) {
+			assert.Equal(t, "cook@example.com", sent[0].To)
+			assert.Contains(t, sent[0].Body, "https://api.example.com/v1/users/verify-email/"+user.EmailVerificationToken)
+		}
+	})
+
+	t.Run("sent to the new address on an email change", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mailer := &recordingMailer{}
+		service := services.NewUserService(mockRepo)
+		service.Mailer = mailer
+		stored := &models.User{ID: "123", Email: "old@example.com", Password: "old-hash", EmailVerified: true}
+		mockRepo.On("GetUser", ctx, "123").Return(stored, nil)
+		mockRepo.On("GetUserByEmail", ctx, "new@example.com").Return(nil, nil)
+		mockRepo.On("UpdateUser", ctx, stored).Return(nil)
+
+		err := service.UpdateUser(ctx, "123", &models.User{Name: "Cook", Email: "new@example.com"})
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, time.Millisecond)
+		if sent := mailer.messages(); assert.Len(t, sent, 1) {
+			assert.Equal(t, "new@example.com", sent[0].To)
+			assert.Contains(t, sent[0].Body, "/"+stored.EmailVerificationToken)
+		}
+	})
+
+	t.Run("not sent when the email is unchanged", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mailer := &recordingMailer{}
+		service := services.NewUserService(mockRepo)
+		service.Mailer = mailer
+		stored := &models.User{ID: "123", Email: "same@example.com", Password: "old-hash", EmailVerified: true}
+		mockRepo.On("GetUser", ctx, "123").Return(stored, nil)
+		mockRepo.On("GetUserByEmail", ctx, "same@example.com").Return(stored, nil)
+		mockRepo.On("UpdateUser", ctx, stored).Return(nil)
+
+		err := service.UpdateUser(ctx, "123", &models.User{Name: "Cook", Email: "same@example.com"})
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.messages())
+	})
+
+	t.Run("send failure does not fail the signup", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := services.NewUserService(mockRepo)
+		service.Mailer = &recordingMailer{err: errors.New("smtp down")}
+		mockRepo.On("GetUserByEmail", ctx, "cook@example.com").Return(nil, nil)
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("*models.User")).Return(nil)
+
+		err := service.CreateUser(ctx, &models.User{Name: "Cook", Email: "cook@example.com", Password: "Test1234!"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := services.NewUserService(mockRepo)
+
+	t.Run("valid token", func(t *testing.T) {
+		expiry := time.Now().Add(time.Hour)
+		user := &models.User{ID: "123", EmailVerificationToken: "valid-token", EmailVerificationExpires: &expiry}
+		mockRepo.On("GetUserByEmailVerificationToken", ctx, "valid-token").Return(user, nil).Once()
+		mockRepo.On("UpdateUser", ctx, user).Return(nil).Once()
+
+		err := service.VerifyEmail(ctx, "valid-token")
+		assert.NoError(t, err)
+		assert.True(t, user.EmailVerified)
+		assert.Empty(t, user.EmailVerificationToken)
+		assert.Nil(t, user.EmailVerificationExpires)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		expiry := time.Now().Add(-time.Minute)
+		user := &models.User{ID: "123", EmailVerificationToken: "stale-token", EmailVerificationExpires: &expiry}
+		mockRepo.On("GetUserByEmailVerificationToken", ctx, "stale-token").Return(user, nil).Once()
+
+		err := service.VerifyEmail(ctx, "stale-token")
+		assert.ErrorIs(t, err, services.ErrVerificationTokenExpired)
+		assert.False(t, user.EmailVerified)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockRepo.On("GetUserByEmailVerificationToken", ctx, "unknown-token").Return(nil, nil).Once()
+
+		err := service.VerifyEmail(ctx, "unknown-token")
+		assert.ErrorIs(t, err, services.ErrInvalidVerificationToken)
+	})
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUserService_EdgeCases(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := services.NewUserService(mockRepo)
@@ -55,7 +55,7 @@ func NewSecurityTestSuite(logger *zap.Logger) *SecurityTestSuite {
 // CheckSecurityHeaders checks for required security headers in HTTP responses.
 // It verifies that all necessary security headers are present and properly configured.
 // Required headers include X-Frame-Options, X-Content-Type-Options, X-XSS-Protection,
-// Strict-Transport-Security, and Content-Security-Policy.
+// Strict-Transport-Security, Content-Security-Policy, Referrer-Policy and Permissions-Policy.
 func (s *SecurityTestSuite) CheckSecurityHeaders(t *testing.T, url string) {
 	resp, err := s.client.Get(url)
 	require.NoError(t, err)
@@ -67,6 +67,8 @@ func (s *SecurityTestSuite) CheckSecurityHeaders(t *testing.T, url string) {
 		"X-XSS-Protection":          "1; mode=block",
 		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
 		"Content-Security-Policy":   "default-src 'self'",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Permissions-Policy":        "geolocation=(), microphone=(), camera=()",
 	}
 
 	for header, expectedValue := range requiredHeaders {
@@ -102,9 +102,9 @@ func AssertErrorResponse(t *testing.T, rr *httptest.ResponseRecorder, expectedSt
 func CreateTestLogger() *logging.Logger {
 	config := logging.LogConfig{
 		LogDir:            "logs",
-		MaxSize:           10,
+		MaxSizeMB:         10,
 		MaxBackups:        3,
-		MaxAge:            28,
+		MaxAgeDays:        28,
 		Compress:          false,
 		LogLevel:          "debug",
 		RequestIDHeader:   "X-Request-ID",